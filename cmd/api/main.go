@@ -1,21 +1,75 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
 	"log"
+	"net/http"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/datasets"
+	address_controllers "github.com/high-effort-low-stress/go-bank-api/internal/addresses/controllers"
+	address_services "github.com/high-effort-low-stress/go-bank-api/internal/addresses/services"
+	auth_controllers "github.com/high-effort-low-stress/go-bank-api/internal/auth/controllers"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	auth_repositories "github.com/high-effort-low-stress/go-bank-api/internal/auth/repositories"
+	auth_services "github.com/high-effort-low-stress/go-bank-api/internal/auth/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/tokens"
+	consent_controllers "github.com/high-effort-low-stress/go-bank-api/internal/consents/controllers"
+	consent_middlewares "github.com/high-effort-low-stress/go-bank-api/internal/consents/middlewares"
+	consent_repositories "github.com/high-effort-low-stress/go-bank-api/internal/consents/repositories"
+	consent_services "github.com/high-effort-low-stress/go-bank-api/internal/consents/services"
 	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	idempotency_middlewares "github.com/high-effort-low-stress/go-bank-api/internal/idempotency/middlewares"
+	idempotency_repositories "github.com/high-effort-low-stress/go-bank-api/internal/idempotency/repositories"
+	idempotency_workers "github.com/high-effort-low-stress/go-bank-api/internal/idempotency/workers"
+	ledger_repositories "github.com/high-effort-low-stress/go-bank-api/internal/ledger/repositories"
+	ledger_services "github.com/high-effort-low-stress/go-bank-api/internal/ledger/services"
 	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/controllers"
 	onboarding_repositories "github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
 	onboarding_services "github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	onboarding_workers "github.com/high-effort-low-stress/go-bank-api/internal/onboarding/workers"
+	outbox_controllers "github.com/high-effort-low-stress/go-bank-api/internal/outbox/controllers"
+	outbox_repositories "github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	outbox_services "github.com/high-effort-low-stress/go-bank-api/internal/outbox/services"
+	outbox_workers "github.com/high-effort-low-stress/go-bank-api/internal/outbox/workers"
+	pix_controllers "github.com/high-effort-low-stress/go-bank-api/internal/pix/controllers"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/dict"
+	pix_repositories "github.com/high-effort-low-stress/go-bank-api/internal/pix/repositories"
+	pix_services "github.com/high-effort-low-stress/go-bank-api/internal/pix/services"
+	pix_workers "github.com/high-effort-low-stress/go-bank-api/internal/pix/workers"
+	privacy_controllers "github.com/high-effort-low-stress/go-bank-api/internal/privacy/controllers"
+	privacy_repositories "github.com/high-effort-low-stress/go-bank-api/internal/privacy/repositories"
+	privacy_services "github.com/high-effort-low-stress/go-bank-api/internal/privacy/services"
+	privacy_workers "github.com/high-effort-low-stress/go-bank-api/internal/privacy/workers"
+	"github.com/high-effort-low-stress/go-bank-api/internal/storage"
+	transfer_controllers "github.com/high-effort-low-stress/go-bank-api/internal/transfers/controllers"
+	transfer_repositories "github.com/high-effort-low-stress/go-bank-api/internal/transfers/repositories"
+	transfer_services "github.com/high-effort-low-stress/go-bank-api/internal/transfers/services"
 	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
 	user_services "github.com/high-effort-low-stress/go-bank-api/internal/users/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"github.com/high-effort-low-stress/go-bank-api/templates"
 	"github.com/joho/godotenv"
 )
 
-var PORT_ENV = "PORT"
+var (
+	PORT_ENV             = "PORT"
+	ADMIN_API_KEY_ENV    = "ADMIN_API_KEY"
+	BLOB_STORAGE_DIR_ENV = "BLOB_STORAGE_DIR"
+	CEP_DATASET_PATH_ENV = "CEP_DATASET_PATH"
+	TRUSTED_PROXIES_ENV  = "TRUSTED_PROXIES"
+)
+
+const shutdownTimeout = 10 * time.Second
 
 func main() {
 	err := godotenv.Load()
@@ -23,38 +77,300 @@ func main() {
 		log.Default().Printf("Error loading .env file")
 	}
 
+	keyring, err := fieldcrypto.KeyringFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load the field encryption keyring: %v", err)
+	}
+	fieldcrypto.SetKeyring(keyring)
+
 	database.Connect()
 	db := database.DB
 
-	emailService, err := notification.NewEmailService()
+	emailTemplates, err := notification.NewTemplateRegistry(templates.Emails())
+	if err != nil {
+		log.Fatalf("Failed to load e-mail templates: %v", err)
+	}
+
+	emailService, err := notification.NewEmailService(emailTemplates)
 	if err != nil {
 		log.Fatalf("Failed to initialize EmailService: %v", err)
 	}
 
+	// Ainda não há provedor de SMS/WhatsApp integrado: os códigos de verificação só aparecem no log.
+	smsSender := notification.NewStubSMSSender()
+
+	pixISPB, err := dict.ParticipantISPBFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load the Pix participant: %v", err)
+	}
+
+	// Ainda não há conexão com o DICT do Banco Central: as chaves ficam em um diretório em memória,
+	// que se perde ao reiniciar.
+	pixDirectory := dict.NewMemoryDirectory(dict.DefaultResolutionPeriod)
+
+	blobStore, err := storage.NewLocalBlobStore(os.Getenv(BLOB_STORAGE_DIR_ENV))
+	if err != nil {
+		log.Fatalf("Failed to initialize BlobStore: %v", err)
+	}
+
+	cepResolver, err := newCEPResolver(os.Getenv(CEP_DATASET_PATH_ENV))
+	if err != nil {
+		log.Fatalf("Failed to load CEP dataset: %v", err)
+	}
+
+	accessTokenManager, err := tokens.NewAccessTokenManagerFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to initialize AccessTokenManager: %v", err)
+	}
+
+	retentionPeriod, err := privacy_services.RetentionPeriodFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load the PII retention period: %v", err)
+	}
+
 	// Dependencies
+	transactor := database.NewTransactor(db)
 	onboardingRequestRepository := onboarding_repositories.NewOnboardingRequestRepository(db)
+	legalRepresentativeRepository := onboarding_repositories.NewLegalRepresentativeRepository(db)
+	kycDocumentRepository := onboarding_repositories.NewKYCDocumentRepository(db)
 	userRepository := user_repositories.NewUserRepository(db)
+	sessionRepository := auth_repositories.NewSessionRepository(db)
+	passwordResetRepository := auth_repositories.NewPasswordResetRepository(db)
+	outboxRepository := outbox_repositories.NewOutboxRepository(db)
+	legalDocumentRepository := consent_repositories.NewLegalDocumentRepository(db)
+	consentRepository := consent_repositories.NewConsentRepository(db)
+	deletionRequestRepository := privacy_repositories.NewDeletionRequestRepository(db)
+	ledgerAccountRepository := ledger_repositories.NewLedgerAccountRepository(db)
+	journalEntryRepository := ledger_repositories.NewJournalEntryRepository(db)
+	transferRepository := transfer_repositories.NewTransferRepository(db)
+	idempotencyKeyRepository := idempotency_repositories.NewIdempotencyKeyRepository(db)
+	pixKeyRepository := pix_repositories.NewPixKeyRepository(db)
+	pixClaimRepository := pix_repositories.NewPixClaimRepository(db)
 
-	onboardingService := onboarding_services.NewOnboardingService(onboardingRequestRepository, emailService, nil)
+	onboardingService := onboarding_services.NewOnboardingService(transactor, onboardingRequestRepository, outboxRepository)
 	verifyEmailTokenService := onboarding_services.NewVerifyEmailTokenService(onboardingRequestRepository)
 	createUserService := user_services.NewCreateUserService(userRepository)
-	completeOnboardingService := onboarding_services.NewCompleteOnboardingService(onboardingRequestRepository, createUserService)
-	onboardingController := controllers.NewOnboardingController(onboardingService, verifyEmailTokenService, completeOnboardingService)
+	consentService := consent_services.NewConsentService(legalDocumentRepository, consentRepository, userRepository)
+	legalDocumentService := consent_services.NewLegalDocumentService(legalDocumentRepository)
+	consentController := consent_controllers.NewConsentController(legalDocumentService, consentService)
+	completeOnboardingService := onboarding_services.NewCompleteOnboardingService(transactor, onboardingRequestRepository, legalRepresentativeRepository, createUserService, consentService)
+	resendVerificationService := onboarding_services.NewResendVerificationService(transactor, onboardingRequestRepository, outboxRepository)
+	phoneVerificationService := onboarding_services.NewPhoneVerificationService(transactor, onboardingRequestRepository, smsSender)
+	onboardingController := controllers.NewOnboardingController(onboardingService, verifyEmailTokenService, completeOnboardingService, resendVerificationService, phoneVerificationService)
+	businessOnboardingService := onboarding_services.NewBusinessOnboardingService(transactor, onboardingRequestRepository, legalRepresentativeRepository, userRepository, outboxRepository)
+	representativeApprovalService := onboarding_services.NewRepresentativeApprovalService(transactor, onboardingRequestRepository, legalRepresentativeRepository, userRepository)
+	businessOnboardingController := controllers.NewBusinessOnboardingController(businessOnboardingService, representativeApprovalService)
+	kycDocumentService := onboarding_services.NewKYCDocumentService(transactor, onboardingRequestRepository, kycDocumentRepository, blobStore)
+	kycReviewService := onboarding_services.NewKYCReviewService(transactor, onboardingRequestRepository, kycDocumentRepository, outboxRepository, blobStore)
+	kycController := controllers.NewKYCController(kycDocumentService, kycReviewService)
+	sessionService := auth_services.NewSessionService(sessionRepository, userRepository, accessTokenManager)
+	loginService := auth_services.NewLoginService(userRepository, sessionService)
+	passwordResetService := auth_services.NewPasswordResetService(transactor, passwordResetRepository, userRepository, outboxRepository)
+	authController := auth_controllers.NewAuthController(loginService, sessionService, passwordResetService)
+	outboxAdminService := outbox_services.NewOutboxAdminService(outboxRepository)
+	outboxController := outbox_controllers.NewOutboxController(outboxAdminService)
+	addressController := address_controllers.NewAddressController(cepResolver)
+	dataExportService := privacy_services.NewDataExportService(userRepository, onboardingRequestRepository, consentRepository)
+	pixKeyService := pix_services.NewPixKeyService(transactor, pixKeyRepository, pixClaimRepository, userRepository, outboxRepository, smsSender, pixDirectory, pixISPB)
+	deletionService := privacy_services.NewDeletionService(transactor, deletionRequestRepository, userRepository, onboardingRequestRepository, kycDocumentRepository, legalRepresentativeRepository, sessionRepository, outboxRepository, idempotencyKeyRepository, pixKeyRepository, pixKeyService, blobStore, retentionPeriod)
+	privacyController := privacy_controllers.NewPrivacyController(dataExportService, deletionService)
+	ledgerService := ledger_services.NewLedgerService(transactor, ledgerAccountRepository, journalEntryRepository)
+	transferService := transfer_services.NewTransferService(transactor, transferRepository, userRepository, ledgerAccountRepository, ledgerService, outboxRepository)
+	transferController := transfer_controllers.NewTransferController(transferService)
+	pixClaimService := pix_services.NewPixClaimService(transactor, pixKeyRepository, pixClaimRepository, userRepository, outboxRepository, pixDirectory, pixISPB)
+	pixController := pix_controllers.NewPixController(pixKeyService, pixClaimService)
+
+	adminAPIKey := os.Getenv(ADMIN_API_KEY_ENV)
+	if adminAPIKey == "" {
+		log.Printf("%s not set, admin routes will reject every request", ADMIN_API_KEY_ENV)
+	}
+
+	// Rotas que alteram dados aceitam o header Idempotency-Key. As rotas de /auth ficam de fora:
+	// suas respostas levam tokens de sessão, que não devem ser gravados no banco. As chaves são
+	// separadas pelo usuário, pela chave de administrador ou, nas rotas públicas, pela própria
+	// requisição, para que a repetição feita de outra rede seja reconhecida.
+	idempotent := idempotency_middlewares.IdempotencyKey(idempotencyKeyRepository, idempotency_middlewares.UserScope, idempotency_middlewares.DefaultMaxBody)
+	adminIdempotent := idempotency_middlewares.IdempotencyKey(idempotencyKeyRepository, idempotency_middlewares.AdminScope, idempotency_middlewares.DefaultMaxBody)
+	publicIdempotent := idempotency_middlewares.IdempotencyKey(idempotencyKeyRepository, idempotency_middlewares.RequestScope, idempotency_middlewares.DefaultMaxBody)
+	uploadIdempotent := idempotency_middlewares.IdempotencyKey(idempotencyKeyRepository, idempotency_middlewares.RequestScope, controllers.MaxKYCUploadBody)
 
 	server := gin.Default()
+	// Sem proxies confiáveis, o gin aceitaria o X-Forwarded-For de qualquer cliente, e o IP
+	// gravado nos aceites de termos, nas sessões e nas solicitações poderia ser forjado.
+	if err := server.SetTrustedProxies(trustedProxies(os.Getenv(TRUSTED_PROXIES_ENV))); err != nil {
+		log.Fatalf("Invalid %s: %v", TRUSTED_PROXIES_ENV, err)
+	}
 
 	apiV1 := server.Group("/api/v1")
 	{
 		onboarding := apiV1.Group("/onboarding")
 		{
-			onboarding.POST("/start", onboardingController.StartOnboarding)
-			onboarding.POST("/verify", onboardingController.VerifyEmail)
-			onboarding.POST("/complete", onboardingController.CompleteOnboarding)
+			onboarding.POST("/start", publicIdempotent, onboardingController.StartOnboarding)
+			onboarding.POST("/verify", publicIdempotent, onboardingController.VerifyEmail)
+			onboarding.POST("/complete", publicIdempotent, onboardingController.CompleteOnboarding)
+			onboarding.POST("/resend", publicIdempotent, onboardingController.ResendVerificationEmail)
+			onboarding.POST("/phone/send-code", publicIdempotent, onboardingController.SendPhoneCode)
+			onboarding.POST("/phone/verify", publicIdempotent, onboardingController.VerifyPhoneCode)
+			onboarding.POST("/:id/documents", uploadIdempotent, kycController.UploadDocument)
+
+			business := onboarding.Group("/business")
+			{
+				business.POST("/start", publicIdempotent, businessOnboardingController.StartBusinessOnboarding)
+
+				approvals := business.Group("", middlewares.RequireAuth(accessTokenManager, sessionService), consent_middlewares.FlagPendingConsents(consentService))
+				{
+					approvals.GET("/approvals", businessOnboardingController.ListPendingApprovals)
+					approvals.POST("/:id/approve", idempotent, businessOnboardingController.ApproveBusinessOnboarding)
+					approvals.POST("/:id/decline", idempotent, businessOnboardingController.DeclineBusinessOnboarding)
+				}
+			}
+		}
+
+		auth := apiV1.Group("/auth")
+		{
+			auth.POST("/login", authController.Login)
+			auth.POST("/refresh", authController.Refresh)
+			auth.POST("/password/forgot", authController.ForgotPassword)
+			auth.POST("/password/reset", authController.ResetPassword)
+
+			sessions := auth.Group("/sessions", middlewares.RequireAuth(accessTokenManager, sessionService), consent_middlewares.FlagPendingConsents(consentService))
+			{
+				sessions.GET("", authController.ListSessions)
+				sessions.DELETE("", authController.RevokeAllSessions)
+				sessions.DELETE("/:id", authController.RevokeSession)
+			}
+		}
+
+		me := apiV1.Group("/me", middlewares.RequireAuth(accessTokenManager, sessionService), consent_middlewares.FlagPendingConsents(consentService))
+		{
+			me.GET("/consents", consentController.ListHistory)
+			me.GET("/consents/pending", consentController.ListPending)
+			me.POST("/consents", idempotent, consentController.Accept)
+			me.GET("/data-export", privacyController.ExportData)
+			me.POST("/deletion-request", idempotent, privacyController.RequestDeletion)
+		}
+
+		transfers := apiV1.Group("/transfers", middlewares.RequireAuth(accessTokenManager, sessionService), consent_middlewares.FlagPendingConsents(consentService))
+		{
+			transfers.POST("", idempotent, transferController.CreateTransfer)
+		}
+
+		pix := apiV1.Group("/pix", middlewares.RequireAuth(accessTokenManager, sessionService), consent_middlewares.FlagPendingConsents(consentService))
+		{
+			pix.GET("/keys", pixController.ListKeys)
+			pix.POST("/keys", idempotent, pixController.RegisterKey)
+			pix.POST("/keys/:id/confirm", idempotent, pixController.ConfirmKey)
+			pix.DELETE("/keys/:id", idempotent, pixController.DeleteKey)
+			pix.GET("/lookup", pixController.LookupKey)
+			pix.GET("/claims", pixController.ListClaims)
+			pix.POST("/claims/:id/confirm", idempotent, pixController.ConfirmClaim)
+			pix.POST("/claims/:id/cancel", idempotent, pixController.CancelClaim)
+		}
+
+		legal := apiV1.Group("/legal")
+		{
+			legal.GET("/documents", consentController.ListCurrentDocuments)
+		}
+
+		addresses := apiV1.Group("/addresses")
+		{
+			addresses.GET("/cep/:cep", addressController.GetByCEP)
+		}
+
+		admin := apiV1.Group("/admin", middlewares.RequireAdminKey(adminAPIKey))
+		{
+			admin.GET("/outbox/failed", outboxController.ListFailed)
+			admin.POST("/outbox/:id/retry", adminIdempotent, outboxController.RetryMessage)
+			admin.GET("/onboarding/reviews", kycController.ListReviews)
+			admin.GET("/onboarding/:id/documents", kycController.ListDocuments)
+			admin.GET("/onboarding/:id/documents/:documentId", kycController.DownloadDocument)
+			admin.POST("/onboarding/:id/approve", adminIdempotent, kycController.ApproveReview)
+			admin.POST("/onboarding/:id/reject", adminIdempotent, kycController.RejectReview)
+			admin.POST("/legal/documents", adminIdempotent, consentController.PublishDocument)
 		}
 	}
 
-	err = server.Run(os.Getenv(PORT_ENV))
-	if err != nil {
-		log.Fatalf("Failed to start server: %v", err)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	expirySweeper := onboarding_workers.NewExpirySweeper(onboardingRequestRepository, onboarding_workers.DefaultSweepInterval, onboarding_workers.DefaultSweepBatchSize)
+	expirySweeper.Start(ctx)
+
+	emailDispatcher := outbox_workers.NewEmailDispatcher(outboxRepository, emailService, outbox_workers.DefaultDispatchInterval, outbox_workers.DefaultDispatchBatchSize)
+	emailDispatcher.Start(ctx)
+
+	anonymizationWorker := privacy_workers.NewAnonymizationWorker(deletionService, privacy_workers.DefaultAnonymizationInterval, privacy_workers.DefaultAnonymizationBatchSize)
+	anonymizationWorker.Start(ctx)
+
+	idempotencyKeySweeper := idempotency_workers.NewKeySweeper(idempotencyKeyRepository, idempotency_workers.DefaultKeySweepInterval, idempotency_workers.DefaultKeySweepBatchSize)
+	idempotencyKeySweeper.Start(ctx)
+
+	pixClaimSyncWorker := pix_workers.NewClaimSyncWorker(pixClaimService, pix_workers.DefaultClaimSyncInterval)
+	pixClaimSyncWorker.Start(ctx)
+
+	httpServer := &http.Server{
+		Addr:    serverAddress(os.Getenv(PORT_ENV)),
+		Handler: server,
+	}
+
+	go func() {
+		if err := httpServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatalf("Failed to start server: %v", err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutting down server...")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		log.Printf("Failed to shutdown server gracefully: %v", err)
+	}
+	expirySweeper.Stop()
+	emailDispatcher.Stop()
+	anonymizationWorker.Stop()
+	idempotencyKeySweeper.Stop()
+	pixClaimSyncWorker.Stop()
+
+	log.Println("Server stopped")
+}
+
+// newCEPResolver usa a base de CEPs embutida no binário, a menos que outra seja informada.
+func newCEPResolver(datasetPath string) (address_services.CEPResolver, error) {
+	var dataset io.Reader = bytes.NewReader(datasets.CEPs())
+	if datasetPath != "" {
+		file, err := os.Open(datasetPath)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		dataset = file
+	}
+
+	return address_services.NewLocalCEPResolver(dataset)
+}
+
+// trustedProxies lê a lista de IPs ou CIDRs separados por vírgula. Vazia, nenhum proxy é
+// confiável e o IP do cliente é o da conexão.
+func trustedProxies(value string) []string {
+	var proxies []string
+	for _, proxy := range strings.Split(value, ",") {
+		if proxy = strings.TrimSpace(proxy); proxy != "" {
+			proxies = append(proxies, proxy)
+		}
+	}
+	return proxies
+}
+
+// serverAddress mantém a compatibilidade com o gin.Run, que aceita tanto "8080" quanto ":8080".
+func serverAddress(port string) string {
+	if port == "" {
+		return ":8080"
+	}
+	if !strings.Contains(port, ":") {
+		return ":" + port
 	}
+	return port
 }
@@ -0,0 +1,78 @@
+// Command reencrypt regrava as colunas cifradas com a chave ativa do keyring. Deve ser executado
+// depois de cada rotação de chave (e uma vez após a migração 18, para cifrar os dados antigos).
+// Cobre os dados pessoais, os representantes legais, o outbox e as chaves Pix (migração 22).
+// As chaves anteriores só podem sair de FIELD_ENCRYPTION_KEYS depois que ele terminar.
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	onboarding_models "github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	outbox_models "github.com/high-effort-low-stress/go-bank-api/internal/outbox/models"
+	pix_models "github.com/high-effort-low-stress/go-bank-api/internal/pix/models"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"github.com/joho/godotenv"
+)
+
+// piiColumns são as colunas cifradas de cada tabela; e-mail, documento e celular têm blind index.
+// Nome da mãe e celular são cifrados desde a migração 27.
+var piiColumns = []fieldcrypto.EncryptedColumn{
+	{Name: "full_name"},
+	{Name: "email", IndexColumn: "email_index"},
+	{Name: "document_number", IndexColumn: "document_number_index"},
+	{Name: "mother_name"},
+	{Name: "phone_number", IndexColumn: "phone_number_index"},
+}
+
+// representativeColumns são as colunas cifradas dos representantes legais (migração 25).
+var representativeColumns = []fieldcrypto.EncryptedColumn{
+	{Name: "full_name"},
+	{Name: "document_number", IndexColumn: "document_number_index"},
+}
+
+// outboxColumns são as colunas cifradas do outbox (migração 23).
+var outboxColumns = []fieldcrypto.EncryptedColumn{
+	{Name: "recipient", IndexColumn: "recipient_index"},
+	{Name: "payload"},
+}
+
+// pixKeyColumns são as colunas cifradas das chaves Pix (migração 22).
+var pixKeyColumns = []fieldcrypto.EncryptedColumn{
+	{Name: "key_value", IndexColumn: "key_index"},
+}
+
+func main() {
+	batchSize := flag.Int("batch-size", 500, "linhas regravadas por transação")
+	flag.Parse()
+
+	if err := godotenv.Load(); err != nil {
+		log.Default().Printf("Error loading .env file")
+	}
+
+	keyring, err := fieldcrypto.KeyringFromEnv()
+	if err != nil {
+		log.Fatalf("Failed to load the field encryption keyring: %v", err)
+	}
+
+	database.Connect()
+
+	tables := []fieldcrypto.Table{
+		{Name: user_models.User{}.TableName(), Columns: piiColumns},
+		{Name: onboarding_models.OnboardingRequest{}.TableName(), Columns: piiColumns},
+		{Name: onboarding_models.LegalRepresentative{}.TableName(), Columns: representativeColumns},
+		{Name: outbox_models.OutboxMessage{}.TableName(), Columns: outboxColumns},
+		{Name: pix_models.PixKey{}.TableName(), Columns: pixKeyColumns},
+	}
+
+	for _, table := range tables {
+		rewritten, err := fieldcrypto.Reencrypt(context.Background(), database.DB, keyring, table, *batchSize)
+		if err != nil {
+			log.Fatalf("Failed to re-encrypt %s after %d rows: %v", table.Name, rewritten, err)
+		}
+		log.Printf("Re-encrypted %d rows of %s with key %s", rewritten, table.Name, keyring.ActiveKeyID())
+	}
+}
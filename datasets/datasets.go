@@ -0,0 +1,15 @@
+// Package datasets embeds the reference data the API needs to work offline, such as the
+// CEP table used to autofill addresses.
+package datasets
+
+import _ "embed"
+
+//go:embed ceps.csv
+var ceps []byte
+
+// CEPs devolve a tabela de CEPs embutida, em CSV separado por ";" com as colunas
+// cep, logradouro, bairro, cidade e uf. É uma amostra: em produção, aponte CEP_DATASET_PATH
+// para a base completa exportada no mesmo formato.
+func CEPs() []byte {
+	return ceps
+}
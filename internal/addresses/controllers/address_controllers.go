@@ -0,0 +1,46 @@
+// Package controllers define the HTTP handlers for address lookups.
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/addresses/services"
+)
+
+type AddressController struct {
+	cepResolver services.CEPResolver
+}
+
+func NewAddressController(cepResolver services.CEPResolver) *AddressController {
+	return &AddressController{cepResolver: cepResolver}
+}
+
+// GetByCEP devolve o endereço de um CEP para o app preencher o formulário automaticamente.
+func (ctrl *AddressController) GetByCEP(c *gin.Context) {
+	address, err := ctrl.cepResolver.Resolve(c.Request.Context(), c.Param("cep"))
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{
+			"cep":          address.CEP,
+			"street":       address.Street,
+			"neighborhood": address.Neighborhood,
+			"city":         address.City,
+			"state":        address.State,
+		})
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidCEP) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrCEPNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	log.Printf("Error resolving CEP: %v", err)
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "ocorreu um erro inesperado"})
+}
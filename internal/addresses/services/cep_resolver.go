@@ -0,0 +1,27 @@
+// Package services define the address lookups used to autofill customer forms.
+package services
+
+import (
+	"context"
+	"errors"
+)
+
+var (
+	ErrInvalidCEP  = errors.New("cep inválido")
+	ErrCEPNotFound = errors.New("cep não encontrado")
+)
+
+// CEPAddress é o endereço associado a um CEP. Número e complemento são sempre informados pelo cliente.
+type CEPAddress struct {
+	CEP          string
+	Street       string
+	Neighborhood string
+	City         string
+	State        string
+}
+
+// CEPResolver consulta o endereço de um CEP. A implementação padrão usa uma base local e
+// funciona sem rede; uma consulta a um serviço externo pode implementar a mesma interface.
+type CEPResolver interface {
+	Resolve(ctx context.Context, cep string) (*CEPAddress, error)
+}
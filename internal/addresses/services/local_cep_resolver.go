@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/validators"
+)
+
+const cepDatasetColumns = 5
+
+type localCEPResolver struct {
+	addresses map[string]CEPAddress
+}
+
+// NewLocalCEPResolver carrega em memória uma base de CEPs em CSV separado por ";", com cabeçalho
+// e as colunas cep, logradouro, bairro, cidade e uf.
+func NewLocalCEPResolver(dataset io.Reader) (CEPResolver, error) {
+	reader := csv.NewReader(dataset)
+	reader.Comma = ';'
+	reader.FieldsPerRecord = cepDatasetColumns
+
+	if _, err := reader.Read(); err != nil {
+		return nil, fmt.Errorf("failed to read CEP dataset header: %w", err)
+	}
+
+	addresses := make(map[string]CEPAddress)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CEP dataset: %w", err)
+		}
+
+		cep := validators.NormalizeCEP(record[0])
+		state := strings.ToUpper(strings.TrimSpace(record[4]))
+		// Um CEP fora da faixa da UF indica uma base corrompida ou com as colunas trocadas.
+		if validators.UFForCEP(cep) != state {
+			line, _ := reader.FieldPos(0)
+			return nil, fmt.Errorf("invalid CEP %q for state %q on line %d of the dataset", record[0], record[4], line)
+		}
+
+		addresses[cep] = CEPAddress{
+			CEP:          cep,
+			Street:       strings.TrimSpace(record[1]),
+			Neighborhood: strings.TrimSpace(record[2]),
+			City:         strings.TrimSpace(record[3]),
+			State:        state,
+		}
+	}
+
+	return &localCEPResolver{addresses: addresses}, nil
+}
+
+func (r *localCEPResolver) Resolve(_ context.Context, cep string) (*CEPAddress, error) {
+	cep = validators.NormalizeCEP(cep)
+	if !validators.IsValidCEP(cep) {
+		return nil, ErrInvalidCEP
+	}
+
+	address, ok := r.addresses[cep]
+	if !ok {
+		return nil, ErrCEPNotFound
+	}
+	return &address, nil
+}
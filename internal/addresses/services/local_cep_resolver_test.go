@@ -0,0 +1,64 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/datasets"
+	"github.com/high-effort-low-stress/go-bank-api/internal/addresses/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalCEPResolver_Resolve(t *testing.T) {
+	resolver, err := services.NewLocalCEPResolver(strings.NewReader(
+		"cep;logradouro;bairro;cidade;uf\n" +
+			"01310-100;Avenida Paulista;Bela Vista;São Paulo;sp\n",
+	))
+	require.NoError(t, err)
+
+	t.Run("Found", func(t *testing.T) {
+		address, err := resolver.Resolve(context.Background(), "01310-100")
+
+		require.NoError(t, err)
+		assert.Equal(t, &services.CEPAddress{
+			CEP:          "01310100",
+			Street:       "Avenida Paulista",
+			Neighborhood: "Bela Vista",
+			City:         "São Paulo",
+			State:        "SP",
+		}, address)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		_, err := resolver.Resolve(context.Background(), "20010000")
+		assert.ErrorIs(t, err, services.ErrCEPNotFound)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := resolver.Resolve(context.Background(), "123")
+		assert.ErrorIs(t, err, services.ErrInvalidCEP)
+	})
+}
+
+func TestNewLocalCEPResolver_InvalidDataset(t *testing.T) {
+	_, err := services.NewLocalCEPResolver(strings.NewReader("cep;logradouro;bairro;cidade;uf\n00000000;Rua;Bairro;Cidade;SP\n"))
+	assert.Error(t, err)
+
+	_, err = services.NewLocalCEPResolver(strings.NewReader("cep;logradouro;bairro;cidade;uf\n01310100;Avenida Paulista;Bela Vista;São Paulo;RJ\n"))
+	assert.Error(t, err)
+
+	_, err = services.NewLocalCEPResolver(strings.NewReader("cep;logradouro;bairro;cidade;uf\n01310100;Avenida Paulista\n"))
+	assert.Error(t, err)
+}
+
+func TestNewLocalCEPResolver_EmbeddedDataset(t *testing.T) {
+	resolver, err := services.NewLocalCEPResolver(bytes.NewReader(datasets.CEPs()))
+	require.NoError(t, err)
+
+	address, err := resolver.Resolve(context.Background(), "01001000")
+	require.NoError(t, err)
+	assert.Equal(t, "SP", address.State)
+}
@@ -0,0 +1,221 @@
+// Package controllers define the HTTP handlers for user authentication.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/http_helpers"
+)
+
+type LoginRequest struct {
+	Login    string `json:"login" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refreshToken" binding:"required"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token           string `json:"token" binding:"required"`
+	Password        string `json:"password" binding:"required,min=8"`
+	ConfirmPassword string `json:"confirmPassword" binding:"required,min=8"`
+}
+
+type AuthController struct {
+	loginService         services.LoginService
+	sessionService       services.SessionService
+	passwordResetService services.PasswordResetService
+}
+
+func NewAuthController(
+	loginService services.LoginService,
+	sessionService services.SessionService,
+	passwordResetService services.PasswordResetService,
+) *AuthController {
+	return &AuthController{
+		loginService:         loginService,
+		sessionService:       sessionService,
+		passwordResetService: passwordResetService,
+	}
+}
+
+func (ctrl *AuthController) Login(c *gin.Context) {
+	var req LoginRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	tokens, err := ctrl.loginService.Execute(c.Request.Context(), req.Login, req.Password, clientInfo(c))
+	if err == nil {
+		c.JSON(http.StatusOK, tokenPairResponse(tokens))
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidCredentials) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrUserBlocked) || errors.Is(err, services.ErrUserInactive) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func (ctrl *AuthController) Refresh(c *gin.Context) {
+	var req RefreshRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	tokens, err := ctrl.sessionService.Refresh(c.Request.Context(), req.RefreshToken, clientInfo(c))
+	if err == nil {
+		c.JSON(http.StatusOK, tokenPairResponse(tokens))
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidRefreshToken) || errors.Is(err, services.ErrRefreshTokenReused) {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrUserBlocked) || errors.Is(err, services.ErrUserInactive) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func (ctrl *AuthController) ListSessions(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+	currentSessionID := middlewares.GetSessionID(c)
+
+	sessions, err := ctrl.sessionService.ListActive(c.Request.Context(), userPublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, gin.H{
+			"id":         session.FamilyID,
+			"device":     session.UserAgent,
+			"ipAddress":  session.IPAddress,
+			"startedAt":  session.StartedAt,
+			"lastSeenAt": session.LastSeenAt,
+			"expiresAt":  session.ExpiresAt,
+			"current":    session.FamilyID == currentSessionID,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"sessions": response})
+}
+
+func (ctrl *AuthController) RevokeSession(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	err := ctrl.sessionService.Revoke(c.Request.Context(), userPublicID, c.Param("id"))
+	if err == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	if errors.Is(err, services.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func (ctrl *AuthController) RevokeAllSessions(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	err := ctrl.sessionService.RevokeAll(c.Request.Context(), userPublicID)
+	if err == nil {
+		c.Status(http.StatusNoContent)
+		return
+	}
+
+	if errors.Is(err, services.ErrSessionNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func (ctrl *AuthController) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	if err := ctrl.passwordResetService.RequestReset(c.Request.Context(), req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Se o e-mail estiver cadastrado, você receberá as instruções para redefinir sua senha."})
+}
+
+func (ctrl *AuthController) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err := ctrl.passwordResetService.ResetPassword(c.Request.Context(), req.Token, req.Password, req.ConfirmPassword)
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Senha redefinida com sucesso."})
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidResetToken) || errors.Is(err, services.ErrPasswordsDoNotMatch) || errors.Is(err, services.ErrWeakPassword) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrExpiredResetToken) {
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func clientInfo(c *gin.Context) services.ClientInfo {
+	return services.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	}
+}
+
+func tokenPairResponse(tokens *services.TokenPair) gin.H {
+	return gin.H{
+		"sessionId":             tokens.SessionID,
+		"tokenType":             "Bearer",
+		"accessToken":           tokens.AccessToken,
+		"expiresAt":             tokens.AccessTokenExpiresAt,
+		"refreshToken":          tokens.RefreshToken,
+		"refreshTokenExpiresAt": tokens.RefreshTokenExpiresAt,
+	}
+}
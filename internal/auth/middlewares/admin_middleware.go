@@ -0,0 +1,25 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminKeyHeader é o header em que o back-office envia a chave de administrador.
+const AdminKeyHeader = "X-Admin-Key"
+
+// RequireAdminKey protege as rotas de back-office com uma chave compartilhada.
+// Se a chave não estiver configurada, todas as requisições são recusadas.
+func RequireAdminKey(apiKey string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		provided := c.GetHeader(AdminKeyHeader)
+		if apiKey == "" || subtle.ConstantTimeCompare([]byte(provided), []byte(apiKey)) != 1 {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "chave de administrador inválida"})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,47 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAdminRouter(apiKey string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/admin", middlewares.RequireAdminKey(apiKey), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestRequireAdminKey(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiKey     string
+		header     string
+		statusCode int
+	}{
+		{"Should allow requests with the configured key", "admin-secret", "admin-secret", http.StatusOK},
+		{"Should reject requests without a key", "admin-secret", "", http.StatusUnauthorized},
+		{"Should reject requests with a wrong key", "admin-secret", "wrong", http.StatusUnauthorized},
+		{"Should reject everything when no key is configured", "", "", http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/admin", nil)
+			if tt.header != "" {
+				req.Header.Set(middlewares.AdminKeyHeader, tt.header)
+			}
+			newAdminRouter(tt.apiKey).ServeHTTP(w, req)
+
+			assert.Equal(t, tt.statusCode, w.Code)
+		})
+	}
+}
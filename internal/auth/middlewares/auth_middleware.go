@@ -0,0 +1,68 @@
+// Package middlewares provides Gin middlewares for authenticating requests.
+package middlewares
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/tokens"
+)
+
+const (
+	// UserPublicIDKey é a chave do contexto do Gin onde o PublicID do usuário autenticado é armazenado.
+	UserPublicIDKey = "userPublicID"
+	// SessionIDKey é a chave do contexto do Gin onde o ID da sessão do access token é armazenado.
+	SessionIDKey = "sessionID"
+)
+
+// RequireAuth exige um access token válido no header "Authorization: Bearer <token>", que a
+// sessão que o emitiu não tenha sido revogada nem expirado e que o usuário continue ativo.
+func RequireAuth(tokenManager tokens.AccessTokenManager, sessionService services.SessionService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader("Authorization")
+		scheme, token, found := strings.Cut(header, " ")
+		if !found || !strings.EqualFold(scheme, "Bearer") || token == "" {
+			c.Header("WWW-Authenticate", "Bearer")
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "token de acesso não informado"})
+			return
+		}
+
+		claims, err := tokenManager.Parse(token)
+		if err != nil || claims.SessionID == "" {
+			rejectToken(c)
+			return
+		}
+
+		active, err := sessionService.IsActive(c.Request.Context(), claims.SessionID)
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+			return
+		}
+		if !active {
+			rejectToken(c)
+			return
+		}
+
+		c.Set(UserPublicIDKey, claims.Subject)
+		c.Set(SessionIDKey, claims.SessionID)
+		c.Next()
+	}
+}
+
+func rejectToken(c *gin.Context) {
+	c.Header("WWW-Authenticate", `Bearer error="invalid_token"`)
+	c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": tokens.ErrInvalidAccessToken.Error()})
+}
+
+// GetUserPublicID retorna o PublicID do usuário autenticado pelo RequireAuth.
+func GetUserPublicID(c *gin.Context) (string, bool) {
+	userPublicID := c.GetString(UserPublicIDKey)
+	return userPublicID, userPublicID != ""
+}
+
+// GetSessionID retorna o ID da sessão do access token usado na requisição.
+func GetSessionID(c *gin.Context) string {
+	return c.GetString(SessionIDKey)
+}
@@ -0,0 +1,101 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/tokens"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRouter(tokenManager tokens.AccessTokenManager, sessionService *mocks.MockSessionService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/protected", middlewares.RequireAuth(tokenManager, sessionService), func(c *gin.Context) {
+		userPublicID, _ := middlewares.GetUserPublicID(c)
+		c.String(http.StatusOK, userPublicID)
+	})
+	return router
+}
+
+func TestRequireAuth(t *testing.T) {
+	tokenManager := tokens.NewAccessTokenManager([]byte("a-very-long-secret-used-only-in-tests"), time.Minute)
+	sessionService := new(mocks.MockSessionService)
+	sessionService.On("IsActive", "01JAX5V3M8R7Q2D4F6H8J0K2L4").Return(true, nil)
+	sessionService.On("IsActive", "revoked-session").Return(false, nil)
+	sessionService.On("IsActive", "unavailable-session").Return(false, services.ErrInternalServer)
+	router := newRouter(tokenManager, sessionService)
+
+	t.Run("Should allow requests with a valid bearer token", func(t *testing.T) {
+		token, _, err := tokenManager.Generate("01JAX5T0K1D6S0ZB7W3Q8YV2NM", "01JAX5V3M8R7Q2D4F6H8J0K2L4")
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "01JAX5T0K1D6S0ZB7W3Q8YV2NM", w.Body.String())
+	})
+
+	t.Run("Should reject requests without a token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Should reject requests with an invalid token", func(t *testing.T) {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer invalid-token")
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+	t.Run("Should reject tokens of revoked sessions", func(t *testing.T) {
+		token, _, err := tokenManager.Generate("01JAX5T0K1D6S0ZB7W3Q8YV2NM", "revoked-session")
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
+
+	t.Run("Should reject tokens without a session", func(t *testing.T) {
+		token, _, err := tokenManager.Generate("01JAX5T0K1D6S0ZB7W3Q8YV2NM", "")
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+		sessionService.AssertNotCalled(t, "IsActive", "")
+	})
+
+	t.Run("Should fail closed when the session cannot be checked", func(t *testing.T) {
+		token, _, err := tokenManager.Generate("01JAX5T0K1D6S0ZB7W3Q8YV2NM", "unavailable-session")
+		require.NoError(t, err)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodGet, "/protected", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		router.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusInternalServerError, w.Code)
+	})
+}
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"time"
+
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+)
+
+// PasswordResetToken guarda apenas o hash do token enviado por e-mail para redefinição de senha.
+type PasswordResetToken struct {
+	ID        int64            `gorm:"primaryKey;autoIncrement;column:id"`
+	UserID    int64            `gorm:"not null"`
+	User      user_models.User `gorm:"foreignKey:UserID"`
+	TokenHash string           `gorm:"type:varchar(64);unique;not null"`
+	ExpiresAt time.Time        `gorm:"not null"`
+	UsedAt    *time.Time
+	CreatedAt time.Time `gorm:"autoCreateTime"`
+}
+
+func (PasswordResetToken) TableName() string {
+	return "user.password_reset_tokens"
+}
@@ -0,0 +1,45 @@
+// Package models define the data structures for user authentication sessions.
+package models
+
+import (
+	"time"
+
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// Session representa um refresh token emitido para um usuário.
+// Cada rotação cria uma nova linha na mesma família (FamilyID), que é o identificador
+// da sessão exposto ao cliente. Apenas a linha mais recente da família fica ativa.
+type Session struct {
+	ID               int64            `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID         string           `gorm:"type:varchar(26);unique;not null"`
+	UserID           int64            `gorm:"not null"`
+	User             user_models.User `gorm:"foreignKey:UserID"`
+	FamilyID         string           `gorm:"type:varchar(26);not null"`
+	RefreshTokenHash string           `gorm:"type:varchar(64);unique;not null"`
+	UserAgent        string           `gorm:"type:varchar(512);not null"`
+	IPAddress        string           `gorm:"type:varchar(45);not null"`
+	StartedAt        time.Time        `gorm:"not null"`
+	LastSeenAt       time.Time        `gorm:"not null"`
+	ExpiresAt        time.Time        `gorm:"not null"`
+	RotatedAt        *time.Time
+	RevokedAt        *time.Time
+	CreatedAt        time.Time `gorm:"autoCreateTime"`
+	UpdatedAt        time.Time `gorm:"autoUpdateTime"`
+}
+
+func (Session) TableName() string {
+	return "user.sessions"
+}
+
+func (s *Session) BeforeCreate(_ *gorm.DB) (err error) {
+	s.PublicID = ulid.Make().String()
+	return
+}
+
+// IsActive indica se o refresh token desta linha ainda pode ser usado.
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RotatedAt == nil && s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
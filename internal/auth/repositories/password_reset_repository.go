@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"gorm.io/gorm"
+)
+
+// ErrResetTokenAlreadyUsed indica que o token foi consumido por outra requisição.
+var ErrResetTokenAlreadyUsed = errors.New("password reset token already used")
+
+type PasswordResetRepository interface {
+	Create(ctx context.Context, token *models.PasswordResetToken) error
+	FindByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error)
+	ResetPassword(ctx context.Context, token *models.PasswordResetToken, passwordHash string) error
+	WithTx(tx *gorm.DB) PasswordResetRepository
+}
+
+type passwordResetRepository struct {
+	db *gorm.DB
+}
+
+func NewPasswordResetRepository(db *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: db}
+}
+
+func (r *passwordResetRepository) Create(ctx context.Context, token *models.PasswordResetToken) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Create(token).Error
+}
+
+func (r *passwordResetRepository) FindByTokenHash(ctx context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var token models.PasswordResetToken
+	result := r.db.WithContext(ctx).Preload("User").Where("token_hash = ?", tokenHash).First(&token)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &token, nil
+}
+
+// ResetPassword consome o token, troca o hash da senha, invalida os demais tokens pendentes
+// e revoga todas as sessões do usuário em uma única transação.
+func (r *passwordResetRepository) ResetPassword(ctx context.Context, token *models.PasswordResetToken, passwordHash string) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	now := time.Now()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.PasswordResetToken{}).
+			Where("id = ? AND used_at IS NULL", token.ID).
+			Update("used_at", now)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrResetTokenAlreadyUsed
+		}
+
+		if err := tx.Model(&user_models.User{}).
+			Where("id = ?", token.UserID).
+			Update("password_hash", passwordHash).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.PasswordResetToken{}).
+			Where("user_id = ? AND used_at IS NULL", token.UserID).
+			Update("used_at", now).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&models.Session{}).
+			Where("user_id = ? AND revoked_at IS NULL", token.UserID).
+			Update("revoked_at", now).Error
+	})
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *passwordResetRepository) WithTx(tx *gorm.DB) PasswordResetRepository {
+	return &passwordResetRepository{db: tx}
+}
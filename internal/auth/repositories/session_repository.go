@@ -0,0 +1,148 @@
+// Package repositories define the data access layer for authentication sessions.
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"gorm.io/gorm"
+)
+
+// ErrSessionAlreadyRotated indica que outro refresh consumiu o mesmo token concorrentemente.
+var ErrSessionAlreadyRotated = errors.New("session already rotated")
+
+type SessionRepository interface {
+	Create(ctx context.Context, session *models.Session) error
+	FindByRefreshTokenHash(ctx context.Context, tokenHash string) (*models.Session, error)
+	Rotate(ctx context.Context, current *models.Session, next *models.Session) error
+	ListActiveByUserID(ctx context.Context, userID int64) ([]models.Session, error)
+	FindActiveFamily(ctx context.Context, familyID string, now time.Time) (*models.Session, error)
+	RevokeFamily(ctx context.Context, familyID string) error
+	RevokeUserFamily(ctx context.Context, userID int64, familyID string) (bool, error)
+	RevokeAllByUserID(ctx context.Context, userID int64) error
+	AnonymizeByUserID(ctx context.Context, userID int64) error
+	WithTx(tx *gorm.DB) SessionRepository
+}
+
+type sessionRepository struct {
+	db *gorm.DB
+}
+
+func NewSessionRepository(db *gorm.DB) SessionRepository {
+	return &sessionRepository{db: db}
+}
+
+func (r *sessionRepository) Create(ctx context.Context, session *models.Session) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Create(session).Error
+}
+
+func (r *sessionRepository) FindByRefreshTokenHash(ctx context.Context, tokenHash string) (*models.Session, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var session models.Session
+	result := r.db.WithContext(ctx).Preload("User").Where("refresh_token_hash = ?", tokenHash).First(&session)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+// Rotate marca a sessão atual como rotacionada e cria a próxima na mesma transação.
+// A atualização é condicional para que dois refreshes concorrentes não rotacionem o mesmo token.
+func (r *sessionRepository) Rotate(ctx context.Context, current *models.Session, next *models.Session) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&models.Session{}).
+			Where("id = ? AND rotated_at IS NULL AND revoked_at IS NULL", current.ID).
+			Update("rotated_at", time.Now())
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrSessionAlreadyRotated
+		}
+
+		return tx.Create(next).Error
+	})
+}
+
+func (r *sessionRepository) ListActiveByUserID(ctx context.Context, userID int64) ([]models.Session, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var sessions []models.Session
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > ?", userID, time.Now()).
+		Order("last_seen_at DESC").
+		Find(&sessions)
+	return sessions, result.Error
+}
+
+// FindActiveFamily busca, com o usuário, a sessão da família que ainda tem um refresh token
+// válido, ou seja, não foi revogada nem expirou.
+func (r *sessionRepository) FindActiveFamily(ctx context.Context, familyID string, now time.Time) (*models.Session, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var session models.Session
+	result := r.db.WithContext(ctx).Preload("User").
+		Where("family_id = ? AND rotated_at IS NULL AND revoked_at IS NULL AND expires_at > ?", familyID, now).
+		First(&session)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &session, nil
+}
+
+func (r *sessionRepository) RevokeFamily(ctx context.Context, familyID string) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// RevokeUserFamily revoga uma sessão do usuário e informa se ela existia.
+func (r *sessionRepository) RevokeUserFamily(ctx context.Context, userID int64, familyID string) (bool, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("user_id = ? AND family_id = ? AND revoked_at IS NULL", userID, familyID).
+		Update("revoked_at", time.Now())
+	return result.RowsAffected > 0, result.Error
+}
+
+func (r *sessionRepository) RevokeAllByUserID(ctx context.Context, userID int64) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Update("revoked_at", time.Now()).Error
+}
+
+// AnonymizeByUserID apaga o IP e o user agent de todas as sessões do usuário, inclusive as antigas.
+func (r *sessionRepository) AnonymizeByUserID(ctx context.Context, userID int64) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Model(&models.Session{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]any{"ip_address": "", "user_agent": ""}).Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *sessionRepository) WithTx(tx *gorm.DB) SessionRepository {
+	return &sessionRepository{db: tx}
+}
@@ -0,0 +1,104 @@
+// Package services define the business logic for user authentication.
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/validators"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCredentials = errors.New("cpf, e-mail ou senha inválidos")
+	ErrUserBlocked        = errors.New("usuário bloqueado")
+	ErrUserInactive       = errors.New("usuário inativo")
+	ErrInternalServer     = errors.New("ocorreu um erro inesperado")
+)
+
+var nonDigits = regexp.MustCompile("[^0-9]+")
+
+// dummyPasswordHash é usado para que logins com usuários inexistentes levem o mesmo tempo
+// que logins com senha incorreta, evitando a enumeração de usuários.
+var (
+	dummyPasswordHash     string
+	dummyPasswordHashOnce sync.Once
+)
+
+type LoginService interface {
+	Execute(ctx context.Context, login, password string, client ClientInfo) (*TokenPair, error)
+}
+
+type loginService struct {
+	userRepo       repositories.UserRepository
+	sessionService SessionService
+}
+
+func NewLoginService(userRepo repositories.UserRepository, sessionService SessionService) LoginService {
+	return &loginService{userRepo: userRepo, sessionService: sessionService}
+}
+
+func (s *loginService) Execute(ctx context.Context, login, password string, client ClientInfo) (*TokenPair, error) {
+	user, err := s.findUser(ctx, strings.TrimSpace(login))
+	if err != nil {
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Error finding user for login: %v", err)
+			return nil, ErrInternalServer
+		}
+		_, _ = crypto.VerifyPassword(password, getDummyPasswordHash())
+		return nil, ErrInvalidCredentials
+	}
+
+	match, err := crypto.VerifyPassword(password, user.PasswordHash)
+	if err != nil {
+		log.Printf("Error verifying password hash for user %s: %v", user.PublicID, err)
+		return nil, ErrInternalServer
+	}
+	if !match {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := checkUserStatus(user); err != nil {
+		return nil, err
+	}
+
+	return s.sessionService.Start(ctx, user, client)
+}
+
+// findUser busca o usuário pelo CPF quando o login é um CPF válido, e pelo e-mail caso contrário.
+func (s *loginService) findUser(ctx context.Context, login string) (*models.User, error) {
+	if validators.IsValidCPF(login) {
+		return s.userRepo.FindByDocument(ctx, nonDigits.ReplaceAllString(login, ""))
+	}
+	return s.userRepo.FindByEmail(ctx, login)
+}
+
+// checkUserStatus impede que usuários bloqueados ou inativos obtenham novos tokens.
+func checkUserStatus(user *models.User) error {
+	switch user.Status {
+	case models.StatusBlocked:
+		return ErrUserBlocked
+	case models.StatusInactive:
+		return ErrUserInactive
+	}
+	return nil
+}
+
+func getDummyPasswordHash() string {
+	dummyPasswordHashOnce.Do(func() {
+		hash, err := crypto.HashPassword("dummy-password")
+		if err != nil {
+			log.Printf("Error generating dummy password hash: %v", err)
+			return
+		}
+		dummyPasswordHash = hash
+	})
+	return dummyPasswordHash
+}
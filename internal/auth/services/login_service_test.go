@@ -0,0 +1,132 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const (
+	validPassword = "StrongPassword123!"
+	validCPF      = "68219090081"
+)
+
+var client = services.ClientInfo{UserAgent: "GoBankApp/1.0 (iOS 18)", IPAddress: "203.0.113.10"}
+
+func newUser(t *testing.T, status models.UserStatus) *models.User {
+	t.Helper()
+	hash, err := crypto.HashPassword(validPassword)
+	require.NoError(t, err)
+
+	return &models.User{
+		PublicID:       "01JAX5T0K1D6S0ZB7W3Q8YV2NM",
+		Email:          "john@example.com",
+		DocumentNumber: validCPF,
+		PasswordHash:   hash,
+		Status:         status,
+	}
+}
+
+func TestLoginService_Execute_SuccessWithCPF(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSessions := new(mocks.MockSessionService)
+	service := services.NewLoginService(mockRepo, mockSessions)
+
+	user := newUser(t, models.StatusActive)
+	tokens := &services.TokenPair{AccessToken: "access-token", RefreshToken: "refresh-token"}
+
+	mockRepo.On("FindByDocument", validCPF).Return(user, nil)
+	mockSessions.On("Start", user, client).Return(tokens, nil)
+
+	result, err := service.Execute(context.Background(), "682.190.900-81", validPassword, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, tokens, result)
+	mockRepo.AssertExpectations(t)
+	mockSessions.AssertExpectations(t)
+}
+
+func TestLoginService_Execute_SuccessWithEmail(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSessions := new(mocks.MockSessionService)
+	service := services.NewLoginService(mockRepo, mockSessions)
+
+	user := newUser(t, models.StatusActive)
+
+	mockRepo.On("FindByEmail", user.Email).Return(user, nil)
+	mockSessions.On("Start", user, client).Return(&services.TokenPair{}, nil)
+
+	_, err := service.Execute(context.Background(), user.Email, validPassword, client)
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "FindByDocument", mock.Anything)
+}
+
+func TestLoginService_Execute_UserNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	service := services.NewLoginService(mockRepo, nil)
+
+	mockRepo.On("FindByEmail", "unknown@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.Execute(context.Background(), "unknown@example.com", validPassword, client)
+
+	assert.Equal(t, services.ErrInvalidCredentials, err)
+}
+
+func TestLoginService_Execute_WrongPassword(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	mockSessions := new(mocks.MockSessionService)
+	service := services.NewLoginService(mockRepo, mockSessions)
+
+	mockRepo.On("FindByDocument", validCPF).Return(newUser(t, models.StatusActive), nil)
+
+	_, err := service.Execute(context.Background(), validCPF, "WrongPassword123!", client)
+
+	assert.Equal(t, services.ErrInvalidCredentials, err)
+	mockSessions.AssertNotCalled(t, "Start", mock.Anything, mock.Anything)
+}
+
+func TestLoginService_Execute_BlockedAndInactiveUsers(t *testing.T) {
+	tests := []struct {
+		status   models.UserStatus
+		expected error
+	}{
+		{models.StatusBlocked, services.ErrUserBlocked},
+		{models.StatusInactive, services.ErrUserInactive},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			mockRepo := new(mocks.MockUserRepository)
+			mockSessions := new(mocks.MockSessionService)
+			service := services.NewLoginService(mockRepo, mockSessions)
+
+			mockRepo.On("FindByDocument", validCPF).Return(newUser(t, tt.status), nil)
+
+			_, err := service.Execute(context.Background(), validCPF, validPassword, client)
+
+			assert.Equal(t, tt.expected, err)
+			mockSessions.AssertNotCalled(t, "Start", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestLoginService_Execute_DatabaseError(t *testing.T) {
+	mockRepo := new(mocks.MockUserRepository)
+	service := services.NewLoginService(mockRepo, nil)
+
+	mockRepo.On("FindByDocument", validCPF).Return(nil, errors.New("db error"))
+
+	_, err := service.Execute(context.Background(), validCPF, validPassword, client)
+
+	assert.Equal(t, services.ErrInternalServer, err)
+}
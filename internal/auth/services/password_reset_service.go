@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	outbox_repositories "github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/validators"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidResetToken   = errors.New("token de redefinição inválido ou já utilizado")
+	ErrExpiredResetToken   = errors.New("token de redefinição expirado")
+	ErrPasswordsDoNotMatch = errors.New("as senhas não coincidem")
+	ErrWeakPassword        = errors.New("a senha não atende aos critérios de segurança")
+)
+
+const passwordResetTokenTTL = 30 * time.Minute
+
+var websiteResetPasswordURL = "reset-password"
+
+type PasswordResetService interface {
+	RequestReset(ctx context.Context, email string) error
+	ResetPassword(ctx context.Context, token, password, confirmPassword string) error
+}
+
+type passwordResetService struct {
+	transactor database.Transactor
+	resetRepo  repositories.PasswordResetRepository
+	userRepo   user_repositories.UserRepository
+	outboxRepo outbox_repositories.OutboxRepository
+}
+
+func NewPasswordResetService(
+	transactor database.Transactor,
+	resetRepo repositories.PasswordResetRepository,
+	userRepo user_repositories.UserRepository,
+	outboxRepo outbox_repositories.OutboxRepository,
+) PasswordResetService {
+	return &passwordResetService{transactor: transactor, resetRepo: resetRepo, userRepo: userRepo, outboxRepo: outboxRepo}
+}
+
+// RequestReset envia o link de redefinição quando o e-mail pertence a um usuário ativo.
+// O retorno é o mesmo para e-mails inexistentes, para não revelar quem é cliente.
+func (s *passwordResetService) RequestReset(ctx context.Context, email string) error {
+	user, err := s.userRepo.FindByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		log.Printf("Error finding user for password reset: %v", err)
+		return ErrInternalServer
+	}
+
+	if user.Status != user_models.StatusActive {
+		return nil
+	}
+
+	rawToken, hashedToken, err := crypto.GenerateVerificationToken()
+	if err != nil {
+		log.Printf("Error generating password reset token: %v", err)
+		return ErrInternalServer
+	}
+
+	resetToken := &models.PasswordResetToken{
+		UserID:    user.ID,
+		TokenHash: hashedToken,
+		ExpiresAt: time.Now().Add(passwordResetTokenTTL),
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.resetRepo.WithTx(tx).Create(ctx, resetToken); err != nil {
+			return err
+		}
+		return s.outboxRepo.WithTx(tx).EnqueueEmail(ctx, newPasswordResetEmail(user, rawToken))
+	})
+	if err != nil {
+		log.Printf("Error creating password reset token: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *passwordResetService) ResetPassword(ctx context.Context, token, password, confirmPassword string) error {
+	if password != confirmPassword {
+		return ErrPasswordsDoNotMatch
+	}
+
+	if !validators.ValidatePasswordPattern(password) {
+		return ErrWeakPassword
+	}
+
+	resetToken, err := s.resetRepo.FindByTokenHash(ctx, crypto.HashTokenSHA256(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvalidResetToken
+		}
+		log.Printf("Error finding password reset token: %v", err)
+		return ErrInternalServer
+	}
+
+	if resetToken.UsedAt != nil {
+		return ErrInvalidResetToken
+	}
+
+	if time.Now().After(resetToken.ExpiresAt) {
+		return ErrExpiredResetToken
+	}
+
+	passwordHash, err := crypto.HashPassword(password)
+	if err != nil {
+		log.Printf("Error hashing password: %v", err)
+		return ErrInternalServer
+	}
+
+	if err := s.resetRepo.ResetPassword(ctx, resetToken, passwordHash); err != nil {
+		if errors.Is(err, repositories.ErrResetTokenAlreadyUsed) {
+			return ErrInvalidResetToken
+		}
+		log.Printf("Error resetting password: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+// newPasswordResetEmail monta o e-mail com o link de redefinição do token informado, no idioma do
+// usuário.
+func newPasswordResetEmail(user *user_models.User, rawToken string) *notification.EmailRequest {
+	resetLink := fmt.Sprintf("%s/%s?token=%s", os.Getenv("WEBSITE_BASE_URL"), websiteResetPasswordURL, rawToken)
+
+	templateData := struct {
+		FullName  string
+		ResetLink string
+	}{
+		FullName:  user.FullName,
+		ResetLink: resetLink,
+	}
+
+	return &notification.EmailRequest{
+		From:         os.Getenv("EMAIL_FROM"),
+		To:           user.Email,
+		TemplateID:   notification.TemplatePasswordReset,
+		Locale:       user.Locale,
+		TemplateData: templateData,
+	}
+}
@@ -0,0 +1,120 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestPasswordResetService_RequestReset_Success(t *testing.T) {
+	mockResetRepo := new(mocks.MockPasswordResetRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockOutbox := new(mocks.MockOutboxRepository)
+	transactor := new(mocks.MockTransactor)
+	service := services.NewPasswordResetService(transactor, mockResetRepo, mockUserRepo, mockOutbox)
+
+	user := &user_models.User{ID: 10, FullName: "John Doe", Email: "john@example.com", Status: user_models.StatusActive}
+
+	mockUserRepo.On("FindByEmail", user.Email).Return(user, nil)
+	mockResetRepo.On("Create", mock.MatchedBy(func(token *models.PasswordResetToken) bool {
+		return token.UserID == user.ID && token.TokenHash != "" && token.ExpiresAt.After(time.Now())
+	})).Return(nil)
+	mockOutbox.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.To == user.Email && req.TemplateID == notification.TemplatePasswordReset
+	})).Return(nil)
+
+	err := service.RequestReset(context.Background(), user.Email)
+
+	assert.NoError(t, err)
+	mockResetRepo.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
+	assert.Equal(t, 1, transactor.Calls)
+}
+
+func TestPasswordResetService_RequestReset_UnknownEmail(t *testing.T) {
+	mockResetRepo := new(mocks.MockPasswordResetRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockOutbox := new(mocks.MockOutboxRepository)
+	transactor := new(mocks.MockTransactor)
+	service := services.NewPasswordResetService(transactor, mockResetRepo, mockUserRepo, mockOutbox)
+
+	mockUserRepo.On("FindByEmail", "unknown@example.com").Return(nil, gorm.ErrRecordNotFound)
+
+	err := service.RequestReset(context.Background(), "unknown@example.com")
+
+	assert.NoError(t, err)
+	mockResetRepo.AssertNotCalled(t, "Create", mock.Anything)
+	mockOutbox.AssertNotCalled(t, "EnqueueEmail", mock.Anything)
+}
+
+func TestPasswordResetService_ResetPassword_Success(t *testing.T) {
+	mockResetRepo := new(mocks.MockPasswordResetRepository)
+	service := services.NewPasswordResetService(nil, mockResetRepo, nil, nil)
+
+	token := "reset-token"
+	resetToken := &models.PasswordResetToken{ID: 1, UserID: 10, ExpiresAt: time.Now().Add(10 * time.Minute)}
+
+	mockResetRepo.On("FindByTokenHash", crypto.HashTokenSHA256(token)).Return(resetToken, nil)
+	mockResetRepo.On("ResetPassword", resetToken, mock.MatchedBy(func(hash string) bool {
+		match, _ := crypto.VerifyPassword(validPassword, hash)
+		return match
+	})).Return(nil)
+
+	err := service.ResetPassword(context.Background(), token, validPassword, validPassword)
+
+	assert.NoError(t, err)
+	mockResetRepo.AssertExpectations(t)
+}
+
+func TestPasswordResetService_ResetPassword_Validation(t *testing.T) {
+	service := services.NewPasswordResetService(nil, nil, nil, nil)
+
+	assert.Equal(t, services.ErrPasswordsDoNotMatch, service.ResetPassword(context.Background(), "token", validPassword, "Other123!"))
+	assert.Equal(t, services.ErrWeakPassword, service.ResetPassword(context.Background(), "token", "123", "123"))
+}
+
+func TestPasswordResetService_ResetPassword_InvalidTokens(t *testing.T) {
+	usedAt := time.Now()
+
+	tests := []struct {
+		name     string
+		token    *models.PasswordResetToken
+		findErr  error
+		resetErr error
+		expected error
+	}{
+		{"Unknown token", nil, gorm.ErrRecordNotFound, nil, services.ErrInvalidResetToken},
+		{"Used token", &models.PasswordResetToken{UsedAt: &usedAt, ExpiresAt: time.Now().Add(time.Minute)}, nil, nil, services.ErrInvalidResetToken},
+		{"Expired token", &models.PasswordResetToken{ExpiresAt: time.Now().Add(-time.Minute)}, nil, nil, services.ErrExpiredResetToken},
+		{"Concurrently used token", &models.PasswordResetToken{ExpiresAt: time.Now().Add(time.Minute)}, nil, repositories.ErrResetTokenAlreadyUsed, services.ErrInvalidResetToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockResetRepo := new(mocks.MockPasswordResetRepository)
+			service := services.NewPasswordResetService(nil, mockResetRepo, nil, nil)
+
+			if tt.token == nil {
+				mockResetRepo.On("FindByTokenHash", mock.Anything).Return(nil, tt.findErr)
+			} else {
+				mockResetRepo.On("FindByTokenHash", mock.Anything).Return(tt.token, nil)
+			}
+			mockResetRepo.On("ResetPassword", mock.Anything, mock.Anything).Return(tt.resetErr)
+
+			err := service.ResetPassword(context.Background(), "token", validPassword, validPassword)
+
+			assert.Equal(t, tt.expected, err)
+		})
+	}
+}
@@ -0,0 +1,273 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/tokens"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("refresh token inválido ou expirado")
+	ErrRefreshTokenReused  = errors.New("refresh token reutilizado, a sessão foi encerrada")
+	ErrSessionNotFound     = errors.New("sessão não encontrada")
+)
+
+const refreshTokenTTL = 30 * 24 * time.Hour
+
+// ClientInfo identifica o dispositivo que está iniciando ou renovando a sessão.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type TokenPair struct {
+	SessionID             string
+	AccessToken           string
+	AccessTokenExpiresAt  time.Time
+	RefreshToken          string
+	RefreshTokenExpiresAt time.Time
+}
+
+type SessionService interface {
+	Start(ctx context.Context, user *user_models.User, client ClientInfo) (*TokenPair, error)
+	Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*TokenPair, error)
+	ListActive(ctx context.Context, userPublicID string) ([]models.Session, error)
+	IsActive(ctx context.Context, sessionID string) (bool, error)
+	Revoke(ctx context.Context, userPublicID, sessionID string) error
+	RevokeAll(ctx context.Context, userPublicID string) error
+}
+
+type sessionService struct {
+	sessionRepo  repositories.SessionRepository
+	userRepo     user_repositories.UserRepository
+	tokenManager tokens.AccessTokenManager
+}
+
+func NewSessionService(
+	sessionRepo repositories.SessionRepository,
+	userRepo user_repositories.UserRepository,
+	tokenManager tokens.AccessTokenManager,
+) SessionService {
+	return &sessionService{sessionRepo: sessionRepo, userRepo: userRepo, tokenManager: tokenManager}
+}
+
+func (s *sessionService) Start(ctx context.Context, user *user_models.User, client ClientInfo) (*TokenPair, error) {
+	now := time.Now()
+
+	rawToken, hashedToken, err := crypto.GenerateVerificationToken()
+	if err != nil {
+		log.Printf("Error generating refresh token: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	session := &models.Session{
+		UserID:           user.ID,
+		FamilyID:         ulid.Make().String(),
+		RefreshTokenHash: hashedToken,
+		UserAgent:        truncate(client.UserAgent, 512),
+		IPAddress:        client.IPAddress,
+		StartedAt:        now,
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(refreshTokenTTL),
+	}
+
+	if err := s.sessionRepo.Create(ctx, session); err != nil {
+		log.Printf("Error creating session: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return s.issueTokens(user, session, rawToken)
+}
+
+// Refresh troca um refresh token por um novo par de tokens. O token apresentado é
+// invalidado e, se ele já tiver sido usado antes, toda a família é revogada, pois
+// isso indica que o token vazou.
+func (s *sessionService) Refresh(ctx context.Context, refreshToken string, client ClientInfo) (*TokenPair, error) {
+	now := time.Now()
+
+	session, err := s.sessionRepo.FindByRefreshTokenHash(ctx, crypto.HashTokenSHA256(refreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidRefreshToken
+		}
+		log.Printf("Error finding session by refresh token hash: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	if session.RevokedAt != nil || !now.Before(session.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+
+	if session.RotatedAt != nil {
+		return nil, s.revokeReusedFamily(ctx, session)
+	}
+
+	if err := checkUserStatus(&session.User); err != nil {
+		if revokeErr := s.sessionRepo.RevokeFamily(ctx, session.FamilyID); revokeErr != nil {
+			log.Printf("Error revoking session family %s: %v", session.FamilyID, revokeErr)
+		}
+		return nil, err
+	}
+
+	rawToken, hashedToken, err := crypto.GenerateVerificationToken()
+	if err != nil {
+		log.Printf("Error generating refresh token: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	next := &models.Session{
+		UserID:           session.UserID,
+		FamilyID:         session.FamilyID,
+		RefreshTokenHash: hashedToken,
+		UserAgent:        truncate(client.UserAgent, 512),
+		IPAddress:        client.IPAddress,
+		StartedAt:        session.StartedAt,
+		LastSeenAt:       now,
+		ExpiresAt:        now.Add(refreshTokenTTL),
+	}
+
+	if err := s.sessionRepo.Rotate(ctx, session, next); err != nil {
+		if errors.Is(err, repositories.ErrSessionAlreadyRotated) {
+			return nil, s.revokeReusedFamily(ctx, session)
+		}
+		log.Printf("Error rotating session: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return s.issueTokens(&session.User, next, rawToken)
+}
+
+func (s *sessionService) ListActive(ctx context.Context, userPublicID string) ([]models.Session, error) {
+	user, err := s.findUser(ctx, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.sessionRepo.ListActiveByUserID(ctx, user.ID)
+	if err != nil {
+		log.Printf("Error listing sessions: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return sessions, nil
+}
+
+// IsActive indica se a sessão de um access token continua valendo. Uma sessão revogada (logout,
+// troca de senha, pedido de exclusão) invalida na hora os access tokens já emitidos. Se o usuário
+// não estiver mais ativo (bloqueado, por exemplo), a sessão é revogada, como no refresh.
+func (s *sessionService) IsActive(ctx context.Context, sessionID string) (bool, error) {
+	session, err := s.sessionRepo.FindActiveFamily(ctx, sessionID, time.Now())
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return false, nil
+		}
+		log.Printf("Error checking session: %v", err)
+		return false, ErrInternalServer
+	}
+
+	if err := checkUserStatus(&session.User); err != nil {
+		if revokeErr := s.sessionRepo.RevokeFamily(ctx, session.FamilyID); revokeErr != nil {
+			log.Printf("Error revoking session family %s: %v", session.FamilyID, revokeErr)
+		}
+		return false, nil
+	}
+	return true, nil
+}
+
+func (s *sessionService) Revoke(ctx context.Context, userPublicID, sessionID string) error {
+	user, err := s.findUser(ctx, userPublicID)
+	if err != nil {
+		return err
+	}
+
+	found, err := s.sessionRepo.RevokeUserFamily(ctx, user.ID, sessionID)
+	if err != nil {
+		log.Printf("Error revoking session: %v", err)
+		return ErrInternalServer
+	}
+	if !found {
+		return ErrSessionNotFound
+	}
+
+	return nil
+}
+
+func (s *sessionService) RevokeAll(ctx context.Context, userPublicID string) error {
+	user, err := s.findUser(ctx, userPublicID)
+	if err != nil {
+		return err
+	}
+
+	if err := s.sessionRepo.RevokeAllByUserID(ctx, user.ID); err != nil {
+		log.Printf("Error revoking all sessions: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *sessionService) issueTokens(user *user_models.User, session *models.Session, rawRefreshToken string) (*TokenPair, error) {
+	accessToken, accessExpiresAt, err := s.tokenManager.Generate(user.PublicID, session.FamilyID)
+	if err != nil {
+		log.Printf("Error generating access token: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return &TokenPair{
+		SessionID:             session.FamilyID,
+		AccessToken:           accessToken,
+		AccessTokenExpiresAt:  accessExpiresAt,
+		RefreshToken:          rawRefreshToken,
+		RefreshTokenExpiresAt: session.ExpiresAt,
+	}, nil
+}
+
+func (s *sessionService) revokeReusedFamily(ctx context.Context, session *models.Session) error {
+	log.Printf("SECURITY: Refresh token reuse detected for session family %s, revoking it", session.FamilyID)
+	if err := s.sessionRepo.RevokeFamily(ctx, session.FamilyID); err != nil {
+		log.Printf("Error revoking session family %s: %v", session.FamilyID, err)
+		return ErrInternalServer
+	}
+	return ErrRefreshTokenReused
+}
+
+func (s *sessionService) findUser(ctx context.Context, userPublicID string) (*user_models.User, error) {
+	user, err := s.userRepo.FindByPublicID(ctx, userPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionNotFound
+		}
+		log.Printf("Error finding user by public ID: %v", err)
+		return nil, ErrInternalServer
+	}
+	return user, nil
+}
+
+// truncate limita value a maxLength bytes sem cortar um caractere pela metade, o que o Postgres
+// recusaria como UTF-8 inválido. Bytes inválidos na entrada viram U+FFFD pelo mesmo motivo.
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength && utf8.ValidString(value) {
+		return value
+	}
+
+	var builder strings.Builder
+	for _, r := range value {
+		if builder.Len()+utf8.RuneLen(r) > maxLength {
+			break
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
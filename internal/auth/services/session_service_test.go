@@ -0,0 +1,267 @@
+package services_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newSession(refreshToken string) *models.Session {
+	return &models.Session{
+		ID:               1,
+		UserID:           10,
+		User:             user_models.User{ID: 10, PublicID: "01JAX5T0K1D6S0ZB7W3Q8YV2NM", Status: user_models.StatusActive},
+		FamilyID:         "01JAX5V3M8R7Q2D4F6H8J0K2L4",
+		RefreshTokenHash: crypto.HashTokenSHA256(refreshToken),
+		StartedAt:        time.Now().Add(-24 * time.Hour),
+		ExpiresAt:        time.Now().Add(24 * time.Hour),
+	}
+}
+
+func TestSessionService_Start(t *testing.T) {
+	mockRepo := new(mocks.MockSessionRepository)
+	mockTokens := new(mocks.MockAccessTokenManager)
+	service := services.NewSessionService(mockRepo, nil, mockTokens)
+
+	user := &user_models.User{ID: 10, PublicID: "01JAX5T0K1D6S0ZB7W3Q8YV2NM"}
+	var created *models.Session
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.Session")).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*models.Session)
+	}).Return(nil)
+	mockTokens.On("Generate", user.PublicID, mock.AnythingOfType("string")).Return("access-token", time.Now(), nil)
+
+	tokens, err := service.Start(context.Background(), user, client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, "access-token", tokens.AccessToken)
+	assert.Equal(t, created.FamilyID, tokens.SessionID)
+	assert.Equal(t, crypto.HashTokenSHA256(tokens.RefreshToken), created.RefreshTokenHash)
+	assert.Equal(t, client.UserAgent, created.UserAgent)
+	assert.Equal(t, client.IPAddress, created.IPAddress)
+}
+
+func TestSessionService_Refresh_RotatesToken(t *testing.T) {
+	mockRepo := new(mocks.MockSessionRepository)
+	mockTokens := new(mocks.MockAccessTokenManager)
+	service := services.NewSessionService(mockRepo, nil, mockTokens)
+
+	current := newSession("refresh-token")
+
+	mockRepo.On("FindByRefreshTokenHash", current.RefreshTokenHash).Return(current, nil)
+	mockRepo.On("Rotate", current, mock.MatchedBy(func(next *models.Session) bool {
+		return next.FamilyID == current.FamilyID && next.RefreshTokenHash != current.RefreshTokenHash && next.StartedAt.Equal(current.StartedAt)
+	})).Return(nil)
+	mockTokens.On("Generate", current.User.PublicID, current.FamilyID).Return("access-token", time.Now(), nil)
+
+	tokens, err := service.Refresh(context.Background(), "refresh-token", client)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, "refresh-token", tokens.RefreshToken)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSessionService_Refresh_ReuseRevokesFamily(t *testing.T) {
+	mockRepo := new(mocks.MockSessionRepository)
+	service := services.NewSessionService(mockRepo, nil, nil)
+
+	rotatedAt := time.Now().Add(-1 * time.Minute)
+	current := newSession("old-token")
+	current.RotatedAt = &rotatedAt
+
+	mockRepo.On("FindByRefreshTokenHash", current.RefreshTokenHash).Return(current, nil)
+	mockRepo.On("RevokeFamily", current.FamilyID).Return(nil)
+
+	_, err := service.Refresh(context.Background(), "old-token", client)
+
+	assert.Equal(t, services.ErrRefreshTokenReused, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSessionService_Refresh_ConcurrentRotationRevokesFamily(t *testing.T) {
+	mockRepo := new(mocks.MockSessionRepository)
+	service := services.NewSessionService(mockRepo, nil, nil)
+
+	current := newSession("refresh-token")
+
+	mockRepo.On("FindByRefreshTokenHash", current.RefreshTokenHash).Return(current, nil)
+	mockRepo.On("Rotate", current, mock.Anything).Return(repositories.ErrSessionAlreadyRotated)
+	mockRepo.On("RevokeFamily", current.FamilyID).Return(nil)
+
+	_, err := service.Refresh(context.Background(), "refresh-token", client)
+
+	assert.Equal(t, services.ErrRefreshTokenReused, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSessionService_Refresh_InvalidTokens(t *testing.T) {
+	revokedAt := time.Now()
+
+	tests := []struct {
+		name    string
+		session *models.Session
+		findErr error
+	}{
+		{"Unknown token", nil, gorm.ErrRecordNotFound},
+		{"Revoked session", func() *models.Session {
+			s := newSession("token")
+			s.RevokedAt = &revokedAt
+			return s
+		}(), nil},
+		{"Expired session", func() *models.Session {
+			s := newSession("token")
+			s.ExpiresAt = time.Now().Add(-1 * time.Minute)
+			return s
+		}(), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockSessionRepository)
+			service := services.NewSessionService(mockRepo, nil, nil)
+
+			if tt.session == nil {
+				mockRepo.On("FindByRefreshTokenHash", mock.Anything).Return(nil, tt.findErr)
+			} else {
+				mockRepo.On("FindByRefreshTokenHash", mock.Anything).Return(tt.session, nil)
+			}
+
+			_, err := service.Refresh(context.Background(), "token", client)
+
+			assert.Equal(t, services.ErrInvalidRefreshToken, err)
+			mockRepo.AssertNotCalled(t, "Rotate", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestSessionService_Refresh_BlockedUser(t *testing.T) {
+	mockRepo := new(mocks.MockSessionRepository)
+	service := services.NewSessionService(mockRepo, nil, nil)
+
+	current := newSession("refresh-token")
+	current.User.Status = user_models.StatusBlocked
+
+	mockRepo.On("FindByRefreshTokenHash", current.RefreshTokenHash).Return(current, nil)
+	mockRepo.On("RevokeFamily", current.FamilyID).Return(nil)
+
+	_, err := service.Refresh(context.Background(), "refresh-token", client)
+
+	assert.Equal(t, services.ErrUserBlocked, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSessionService_Revoke(t *testing.T) {
+	user := &user_models.User{ID: 10, PublicID: "01JAX5T0K1D6S0ZB7W3Q8YV2NM"}
+
+	t.Run("Should revoke an existing session", func(t *testing.T) {
+		mockRepo := new(mocks.MockSessionRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		service := services.NewSessionService(mockRepo, mockUserRepo, nil)
+
+		mockUserRepo.On("FindByPublicID", user.PublicID).Return(user, nil)
+		mockRepo.On("RevokeUserFamily", user.ID, "session-id").Return(true, nil)
+
+		err := service.Revoke(context.Background(), user.PublicID, "session-id")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("Should return not found for sessions of other users", func(t *testing.T) {
+		mockRepo := new(mocks.MockSessionRepository)
+		mockUserRepo := new(mocks.MockUserRepository)
+		service := services.NewSessionService(mockRepo, mockUserRepo, nil)
+
+		mockUserRepo.On("FindByPublicID", user.PublicID).Return(user, nil)
+		mockRepo.On("RevokeUserFamily", user.ID, "other-session").Return(false, nil)
+
+		err := service.Revoke(context.Background(), user.PublicID, "other-session")
+
+		assert.Equal(t, services.ErrSessionNotFound, err)
+	})
+}
+
+func TestSessionService_RevokeAll(t *testing.T) {
+	mockRepo := new(mocks.MockSessionRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	service := services.NewSessionService(mockRepo, mockUserRepo, nil)
+
+	user := &user_models.User{ID: 10, PublicID: "01JAX5T0K1D6S0ZB7W3Q8YV2NM"}
+	mockUserRepo.On("FindByPublicID", user.PublicID).Return(user, nil)
+	mockRepo.On("RevokeAllByUserID", user.ID).Return(nil)
+
+	err := service.RevokeAll(context.Background(), user.PublicID)
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestSessionService_IsActive(t *testing.T) {
+	mockRepo := new(mocks.MockSessionRepository)
+	service := services.NewSessionService(mockRepo, nil, nil)
+
+	blocked := newSession("blocked-token")
+	blocked.FamilyID = "blocked-session"
+	blocked.User.Status = user_models.StatusBlocked
+
+	mockRepo.On("FindActiveFamily", "active-session").Return(newSession("refresh-token"), nil)
+	mockRepo.On("FindActiveFamily", "revoked-session").Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindActiveFamily", "blocked-session").Return(blocked, nil)
+	mockRepo.On("FindActiveFamily", "broken-session").Return(nil, gorm.ErrInvalidDB)
+	mockRepo.On("RevokeFamily", "blocked-session").Return(nil)
+
+	active, err := service.IsActive(context.Background(), "active-session")
+	assert.NoError(t, err)
+	assert.True(t, active)
+
+	active, err = service.IsActive(context.Background(), "revoked-session")
+	assert.NoError(t, err)
+	assert.False(t, active)
+
+	active, err = service.IsActive(context.Background(), "blocked-session")
+	assert.NoError(t, err)
+	assert.False(t, active, "a blocked user's access tokens must stop working")
+	mockRepo.AssertCalled(t, "RevokeFamily", "blocked-session")
+
+	_, err = service.IsActive(context.Background(), "broken-session")
+	assert.ErrorIs(t, err, services.ErrInternalServer)
+}
+
+func TestSessionService_Start_TruncatesTheUserAgentOnARuneBoundary(t *testing.T) {
+	mockRepo := new(mocks.MockSessionRepository)
+	mockTokens := new(mocks.MockAccessTokenManager)
+	service := services.NewSessionService(mockRepo, nil, mockTokens)
+
+	user := &user_models.User{ID: 10, PublicID: "01JAX5T0K1D6S0ZB7W3Q8YV2NM"}
+	var created *models.Session
+
+	mockRepo.On("Create", mock.AnythingOfType("*models.Session")).Run(func(args mock.Arguments) {
+		created = args.Get(0).(*models.Session)
+	}).Return(nil)
+	mockTokens.On("Generate", user.PublicID, mock.AnythingOfType("string")).Return("access-token", time.Now(), nil)
+
+	// 511 bytes ASCII seguidos de "ç" (2 bytes): o corte em 512 bytes cairia no meio do caractere.
+	userAgent := strings.Repeat("a", 511) + "ção"
+	_, err := service.Start(context.Background(), user, services.ClientInfo{UserAgent: userAgent, IPAddress: client.IPAddress})
+
+	require.NoError(t, err)
+	assert.True(t, utf8.ValidString(created.UserAgent))
+	assert.Equal(t, strings.Repeat("a", 511), created.UserAgent)
+
+	_, err = service.Start(context.Background(), user, services.ClientInfo{UserAgent: "GoBank\xff/1.0", IPAddress: client.IPAddress})
+
+	require.NoError(t, err)
+	assert.Equal(t, "GoBank\uFFFD/1.0", created.UserAgent)
+}
@@ -0,0 +1,103 @@
+// Package tokens provides the generation and validation of signed JWT access tokens.
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	issuer                = "go-bank-api"
+	defaultAccessTokenTTL = 15 * time.Minute
+)
+
+var ErrInvalidAccessToken = errors.New("token de acesso inválido ou expirado")
+
+// Claims são as informações carregadas no access token. O Subject é o PublicID do usuário
+// e SessionID identifica a sessão (família de refresh tokens) que originou o token.
+type Claims struct {
+	SessionID string `json:"sid,omitempty"`
+	jwt.RegisteredClaims
+}
+
+type AccessTokenManager interface {
+	Generate(userPublicID, sessionID string) (token string, expiresAt time.Time, err error)
+	Parse(token string) (*Claims, error)
+}
+
+type jwtAccessTokenManager struct {
+	secret []byte
+	ttl    time.Duration
+	now    func() time.Time
+}
+
+func NewAccessTokenManager(secret []byte, ttl time.Duration) AccessTokenManager {
+	return &jwtAccessTokenManager{secret: secret, ttl: ttl, now: time.Now}
+}
+
+// NewAccessTokenManagerFromEnv lê JWT_SECRET (obrigatório) e JWT_ACCESS_TOKEN_TTL (opcional, ex: "15m").
+func NewAccessTokenManagerFromEnv() (AccessTokenManager, error) {
+	secret := os.Getenv("JWT_SECRET")
+	if secret == "" {
+		return nil, fmt.Errorf("JWT_SECRET environment variable not set")
+	}
+	if len(secret) < 32 {
+		return nil, fmt.Errorf("JWT_SECRET must be at least 32 characters long")
+	}
+
+	ttl := defaultAccessTokenTTL
+	if rawTTL := os.Getenv("JWT_ACCESS_TOKEN_TTL"); rawTTL != "" {
+		parsed, err := time.ParseDuration(rawTTL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid JWT_ACCESS_TOKEN_TTL: %w", err)
+		}
+		ttl = parsed
+	}
+
+	return NewAccessTokenManager([]byte(secret), ttl), nil
+}
+
+func (m *jwtAccessTokenManager) Generate(userPublicID, sessionID string) (string, time.Time, error) {
+	now := m.now()
+	expiresAt := now.Add(m.ttl)
+
+	claims := Claims{
+		SessionID: sessionID,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    issuer,
+			Subject:   userPublicID,
+			IssuedAt:  jwt.NewNumericDate(now),
+			NotBefore: jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	signed, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(m.secret)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return signed, expiresAt, nil
+}
+
+func (m *jwtAccessTokenManager) Parse(token string) (*Claims, error) {
+	claims := &Claims{}
+
+	parsed, err := jwt.ParseWithClaims(token, claims, func(_ *jwt.Token) (any, error) {
+		return m.secret, nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}),
+		jwt.WithIssuer(issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithTimeFunc(m.now),
+	)
+	if err != nil || !parsed.Valid || claims.Subject == "" {
+		return nil, ErrInvalidAccessToken
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,58 @@
+package tokens_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/tokens"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var secret = []byte("a-very-long-secret-used-only-in-tests")
+
+func TestAccessTokenManager_GenerateAndParse(t *testing.T) {
+	manager := tokens.NewAccessTokenManager(secret, 15*time.Minute)
+
+	token, expiresAt, err := manager.Generate("01JAX5T0K1D6S0ZB7W3Q8YV2NM", "01JAX5V3M8R7Q2D4F6H8J0K2L4")
+	require.NoError(t, err)
+	assert.NotEmpty(t, token)
+	assert.WithinDuration(t, time.Now().Add(15*time.Minute), expiresAt, 5*time.Second)
+
+	claims, err := manager.Parse(token)
+	require.NoError(t, err)
+	assert.Equal(t, "01JAX5T0K1D6S0ZB7W3Q8YV2NM", claims.Subject)
+	assert.Equal(t, "01JAX5V3M8R7Q2D4F6H8J0K2L4", claims.SessionID)
+}
+
+func TestAccessTokenManager_Parse_Expired(t *testing.T) {
+	manager := tokens.NewAccessTokenManager(secret, -1*time.Minute)
+
+	token, _, err := manager.Generate("user", "session")
+	require.NoError(t, err)
+
+	_, err = manager.Parse(token)
+	assert.ErrorIs(t, err, tokens.ErrInvalidAccessToken)
+}
+
+func TestAccessTokenManager_Parse_WrongSecret(t *testing.T) {
+	token, _, err := tokens.NewAccessTokenManager([]byte("another-secret-with-enough-length!!"), time.Minute).Generate("user", "session")
+	require.NoError(t, err)
+
+	_, err = tokens.NewAccessTokenManager(secret, time.Minute).Parse(token)
+	assert.ErrorIs(t, err, tokens.ErrInvalidAccessToken)
+}
+
+func TestAccessTokenManager_Parse_RejectsNoneAlgorithm(t *testing.T) {
+	unsigned := jwt.NewWithClaims(jwt.SigningMethodNone, jwt.RegisteredClaims{
+		Issuer:    "go-bank-api",
+		Subject:   "user",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute)),
+	})
+	token, err := unsigned.SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+
+	_, err = tokens.NewAccessTokenManager(secret, time.Minute).Parse(token)
+	assert.ErrorIs(t, err, tokens.ErrInvalidAccessToken)
+}
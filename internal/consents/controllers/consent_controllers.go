@@ -0,0 +1,169 @@
+// Package controllers define the HTTP handlers for legal documents and customer consents.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/http_helpers"
+)
+
+type AcceptConsentsRequest struct {
+	DocumentIDs []string `json:"documentIds" binding:"required,min=1,dive,required"`
+}
+
+type PublishLegalDocumentRequest struct {
+	Kind        string     `json:"kind" binding:"required,oneof=TERMS_OF_USE PRIVACY_POLICY"`
+	Version     string     `json:"version" binding:"required,max=20"`
+	URL         string     `json:"url" binding:"required,url,max=512"`
+	PublishedAt *time.Time `json:"publishedAt"`
+}
+
+type ConsentController struct {
+	documentService services.LegalDocumentService
+	consentService  services.ConsentService
+}
+
+func NewConsentController(documentService services.LegalDocumentService, consentService services.ConsentService) *ConsentController {
+	return &ConsentController{documentService: documentService, consentService: consentService}
+}
+
+func (ctrl *ConsentController) ListCurrentDocuments(c *gin.Context) {
+	documents, err := ctrl.documentService.ListCurrent(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"documents": documentsResponse(documents)})
+}
+
+func (ctrl *ConsentController) ListPending(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	documents, err := ctrl.consentService.ListPending(c.Request.Context(), userPublicID)
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{"documents": documentsResponse(documents)})
+		return
+	}
+
+	if errors.Is(err, services.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func (ctrl *ConsentController) ListHistory(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	consents, err := ctrl.consentService.ListHistory(c.Request.Context(), userPublicID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(consents))
+	for _, consent := range consents {
+		response = append(response, gin.H{
+			"id":         consent.PublicID,
+			"document":   documentResponse(consent.LegalDocument),
+			"source":     consent.Source,
+			"ipAddress":  consent.IPAddress,
+			"userAgent":  consent.UserAgent,
+			"acceptedAt": consent.AcceptedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"consents": response})
+}
+
+func (ctrl *ConsentController) Accept(c *gin.Context) {
+	var req AcceptConsentsRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	err := ctrl.consentService.Accept(c.Request.Context(), userPublicID, req.DocumentIDs, services.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Aceite registrado com sucesso."})
+		return
+	}
+
+	if errors.Is(err, services.ErrDocumentNotCurrent) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func (ctrl *ConsentController) PublishDocument(c *gin.Context) {
+	var req PublishLegalDocumentRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	document, err := ctrl.documentService.Publish(c.Request.Context(), &services.PublishRequest{
+		Kind:        models.LegalDocumentKind(req.Kind),
+		Version:     req.Version,
+		URL:         req.URL,
+		PublishedAt: req.PublishedAt,
+	})
+	if err == nil {
+		c.JSON(http.StatusCreated, documentResponse(*document))
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidDocumentKind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrVersionAlreadyPublished) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func documentsResponse(documents []models.LegalDocument) []gin.H {
+	response := make([]gin.H, 0, len(documents))
+	for _, document := range documents {
+		response = append(response, documentResponse(document))
+	}
+	return response
+}
+
+func documentResponse(document models.LegalDocument) gin.H {
+	return gin.H{
+		"id":          document.PublicID,
+		"kind":        document.Kind,
+		"version":     document.Version,
+		"url":         document.URL,
+		"publishedAt": document.PublishedAt,
+	}
+}
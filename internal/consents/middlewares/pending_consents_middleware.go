@@ -0,0 +1,45 @@
+// Package middlewares provides Gin middlewares for prompting users about legal document updates.
+package middlewares
+
+import (
+	"log"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	auth_middlewares "github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/services"
+)
+
+// ConsentRequiredHeader lista, separados por vírgula, os tipos de documento com uma versão
+// vigente que o usuário autenticado ainda não aceitou. O app usa o header para exibir o novo
+// texto e chamar POST /me/consents.
+const ConsentRequiredHeader = "X-Consent-Required"
+
+// FlagPendingConsents deve vir depois do RequireAuth. Ele não bloqueia a requisição: uma falha
+// ao consultar os aceites só é registrada no log.
+func FlagPendingConsents(consentService services.ConsentService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userPublicID, ok := auth_middlewares.GetUserPublicID(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		pending, err := consentService.ListPending(c.Request.Context(), userPublicID)
+		if err != nil {
+			log.Printf("Error checking pending consents for user %s: %v", userPublicID, err)
+			c.Next()
+			return
+		}
+
+		if len(pending) > 0 {
+			kinds := make([]string, 0, len(pending))
+			for _, document := range pending {
+				kinds = append(kinds, string(document.Kind))
+			}
+			c.Header(ConsentRequiredHeader, strings.Join(kinds, ","))
+		}
+
+		c.Next()
+	}
+}
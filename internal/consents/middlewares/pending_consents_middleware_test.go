@@ -0,0 +1,67 @@
+package middlewares_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	auth_middlewares "github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/models"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func newConsentRouter(consentService *mocks.MockConsentService, userPublicID string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/me", func(c *gin.Context) {
+		if userPublicID != "" {
+			c.Set(auth_middlewares.UserPublicIDKey, userPublicID)
+		}
+		c.Next()
+	}, middlewares.FlagPendingConsents(consentService), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestFlagPendingConsents(t *testing.T) {
+	tests := []struct {
+		name       string
+		pending    []models.LegalDocument
+		err        error
+		wantHeader string
+	}{
+		{"Should list the kinds pending acceptance", []models.LegalDocument{{Kind: models.KindPrivacyPolicy}, {Kind: models.KindTermsOfUse}}, nil, "PRIVACY_POLICY,TERMS_OF_USE"},
+		{"Should not set the header when everything was accepted", []models.LegalDocument{}, nil, ""},
+		{"Should not block the request when the lookup fails", nil, errors.New("db error"), ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			consentService := new(mocks.MockConsentService)
+			consentService.On("ListPending", "user-public-id").Return(tt.pending, tt.err)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodGet, "/me", nil)
+			newConsentRouter(consentService, "user-public-id").ServeHTTP(w, req)
+
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantHeader, w.Header().Get(middlewares.ConsentRequiredHeader))
+		})
+	}
+}
+
+func TestFlagPendingConsents_Unauthenticated(t *testing.T) {
+	consentService := new(mocks.MockConsentService)
+
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(http.MethodGet, "/me", nil)
+	newConsentRouter(consentService, "").ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	consentService.AssertNotCalled(t, "ListPending", "")
+}
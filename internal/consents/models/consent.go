@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// ConsentSource indica em que momento o cliente aceitou o documento.
+type ConsentSource string
+
+const (
+	SourceOnboarding   ConsentSource = "ONBOARDING"
+	SourceReacceptance ConsentSource = "REACCEPTANCE"
+)
+
+// Consent é a prova de que um usuário aceitou uma versão de um documento jurídico, com o IP e o
+// user agent de onde o aceite partiu. As linhas nunca são alteradas.
+type Consent struct {
+	ID              int64         `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID        string        `gorm:"type:varchar(26);unique;not null"`
+	UserID          int64         `gorm:"not null"`
+	LegalDocumentID int64         `gorm:"not null"`
+	LegalDocument   LegalDocument `gorm:"foreignKey:LegalDocumentID"`
+	Source          ConsentSource `gorm:"type:varchar(20);not null"`
+	IPAddress       string        `gorm:"type:varchar(45);not null"`
+	UserAgent       string        `gorm:"type:varchar(512);not null"`
+	AcceptedAt      time.Time     `gorm:"not null"`
+	CreatedAt       time.Time     `gorm:"autoCreateTime"`
+}
+
+func (Consent) TableName() string {
+	return "legal.consents"
+}
+
+func (c *Consent) BeforeCreate(_ *gorm.DB) (err error) {
+	c.PublicID = ulid.Make().String()
+	return
+}
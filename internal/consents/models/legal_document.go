@@ -0,0 +1,48 @@
+// Package models define the data structures for legal documents and customer consents (LGPD).
+package models
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// LegalDocumentKind identifica o documento jurídico que o cliente precisa aceitar.
+type LegalDocumentKind string
+
+const (
+	KindTermsOfUse    LegalDocumentKind = "TERMS_OF_USE"
+	KindPrivacyPolicy LegalDocumentKind = "PRIVACY_POLICY"
+)
+
+// LegalDocument é uma versão publicada de um documento jurídico. O texto fica em URL, que deve
+// ser imutável por versão. A versão vigente de cada tipo é a de PublishedAt mais recente.
+type LegalDocument struct {
+	ID          int64             `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID    string            `gorm:"type:varchar(26);unique;not null"`
+	Kind        LegalDocumentKind `gorm:"type:varchar(20);not null"`
+	Version     string            `gorm:"type:varchar(20);not null"`
+	URL         string            `gorm:"type:varchar(512);not null;column:url"`
+	PublishedAt time.Time         `gorm:"not null"`
+	CreatedAt   time.Time         `gorm:"autoCreateTime"`
+}
+
+func (LegalDocument) TableName() string {
+	return "legal.legal_documents"
+}
+
+func (d *LegalDocument) BeforeCreate(_ *gorm.DB) (err error) {
+	d.PublicID = ulid.Make().String()
+	return
+}
+
+// FindKind retorna o documento do tipo informado, ou nil se ele não estiver na lista.
+func FindKind(documents []LegalDocument, kind LegalDocumentKind) *LegalDocument {
+	for i := range documents {
+		if documents[i].Kind == kind {
+			return &documents[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ConsentRepository interface {
+	Create(ctx context.Context, consents []*models.Consent) error
+	ListByUserID(ctx context.Context, userID int64) ([]models.Consent, error)
+	WithTx(tx *gorm.DB) ConsentRepository
+}
+
+type consentRepository struct {
+	db *gorm.DB
+}
+
+func NewConsentRepository(db *gorm.DB) ConsentRepository {
+	return &consentRepository{db: db}
+}
+
+// Create grava os aceites ignorando os documentos que o usuário já aceitou, para que um aceite
+// repetido não sobrescreva a prova original.
+func (r *consentRepository) Create(ctx context.Context, consents []*models.Consent) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).
+		Omit(clause.Associations).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}, {Name: "legal_document_id"}}, DoNothing: true}).
+		Create(consents).Error
+}
+
+// ListByUserID retorna o histórico de aceites do usuário, do mais recente para o mais antigo.
+func (r *consentRepository) ListByUserID(ctx context.Context, userID int64) ([]models.Consent, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var consents []models.Consent
+	result := r.db.WithContext(ctx).
+		Preload("LegalDocument").
+		Where("user_id = ?", userID).
+		Order("accepted_at DESC, id DESC").
+		Find(&consents)
+	return consents, result.Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *consentRepository) WithTx(tx *gorm.DB) ConsentRepository {
+	return &consentRepository{db: tx}
+}
@@ -0,0 +1,86 @@
+// Package repositories define the data access layer for legal documents and consents.
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"gorm.io/gorm"
+)
+
+type LegalDocumentRepository interface {
+	Create(ctx context.Context, document *models.LegalDocument) error
+	FindByKindAndVersion(ctx context.Context, kind models.LegalDocumentKind, version string) (*models.LegalDocument, error)
+	ListCurrent(ctx context.Context, now time.Time) ([]models.LegalDocument, error)
+	ListPendingByUserID(ctx context.Context, userID int64, now time.Time) ([]models.LegalDocument, error)
+	WithTx(tx *gorm.DB) LegalDocumentRepository
+}
+
+type legalDocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewLegalDocumentRepository(db *gorm.DB) LegalDocumentRepository {
+	return &legalDocumentRepository{db: db}
+}
+
+func (r *legalDocumentRepository) Create(ctx context.Context, document *models.LegalDocument) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Create(document).Error
+}
+
+func (r *legalDocumentRepository) FindByKindAndVersion(ctx context.Context, kind models.LegalDocumentKind, version string) (*models.LegalDocument, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var document models.LegalDocument
+	result := r.db.WithContext(ctx).Where("kind = ? AND version = ?", kind, version).First(&document)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &document, nil
+}
+
+// ListCurrent retorna a versão vigente de cada tipo de documento. Versões com publicação
+// agendada para o futuro só passam a valer em PublishedAt.
+func (r *legalDocumentRepository) ListCurrent(ctx context.Context, now time.Time) ([]models.LegalDocument, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var documents []models.LegalDocument
+	result := r.db.WithContext(ctx).
+		Raw(`SELECT DISTINCT ON (kind) * FROM legal.legal_documents
+			WHERE published_at <= ?
+			ORDER BY kind, published_at DESC, id DESC`, now).
+		Scan(&documents)
+	return documents, result.Error
+}
+
+// ListPendingByUserID retorna as versões vigentes que o usuário ainda não aceitou.
+func (r *legalDocumentRepository) ListPendingByUserID(ctx context.Context, userID int64, now time.Time) ([]models.LegalDocument, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var documents []models.LegalDocument
+	result := r.db.WithContext(ctx).
+		Raw(`SELECT current.* FROM (
+				SELECT DISTINCT ON (kind) * FROM legal.legal_documents
+				WHERE published_at <= ?
+				ORDER BY kind, published_at DESC, id DESC
+			) AS current
+			WHERE NOT EXISTS (
+				SELECT 1 FROM legal.consents c WHERE c.legal_document_id = current.id AND c.user_id = ?
+			)
+			ORDER BY current.kind`, now, userID).
+		Scan(&documents)
+	return documents, result.Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *legalDocumentRepository) WithTx(tx *gorm.DB) LegalDocumentRepository {
+	return &legalDocumentRepository{db: tx}
+}
@@ -0,0 +1,192 @@
+// Package services define the business logic for legal documents and customer consents (LGPD).
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/repositories"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrOutdatedTermsVersion = errors.New("a versão dos termos de uso aceita não é a vigente")
+	ErrDocumentNotCurrent   = errors.New("o documento informado não é uma versão vigente")
+	ErrUserNotFound         = errors.New("usuário não encontrado")
+	ErrInternalServer       = errors.New("ocorreu um erro inesperado")
+)
+
+// ClientInfo identifica o dispositivo de onde partiu o aceite.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type ConsentService interface {
+	AcceptAtOnboarding(ctx context.Context, userID int64, termsVersion string, client ClientInfo) error
+	Accept(ctx context.Context, userPublicID string, documentIDs []string, client ClientInfo) error
+	ListPending(ctx context.Context, userPublicID string) ([]models.LegalDocument, error)
+	ListHistory(ctx context.Context, userPublicID string) ([]models.Consent, error)
+	WithTx(tx *gorm.DB) ConsentService
+}
+
+type consentService struct {
+	documentRepo repositories.LegalDocumentRepository
+	consentRepo  repositories.ConsentRepository
+	userRepo     user_repositories.UserRepository
+}
+
+func NewConsentService(
+	documentRepo repositories.LegalDocumentRepository,
+	consentRepo repositories.ConsentRepository,
+	userRepo user_repositories.UserRepository,
+) ConsentService {
+	return &consentService{documentRepo: documentRepo, consentRepo: consentRepo, userRepo: userRepo}
+}
+
+// AcceptAtOnboarding registra o aceite de todos os documentos vigentes ao concluir o cadastro.
+// termsVersion é a versão dos termos exibida ao cliente; se uma nova versão foi publicada
+// enquanto ele preenchia o formulário, o aceite é recusado para que ele leia a nova.
+func (s *consentService) AcceptAtOnboarding(ctx context.Context, userID int64, termsVersion string, client ClientInfo) error {
+	current, err := s.documentRepo.ListCurrent(ctx, time.Now())
+	if err != nil {
+		log.Printf("Error listing current legal documents: %v", err)
+		return ErrInternalServer
+	}
+
+	terms := models.FindKind(current, models.KindTermsOfUse)
+	if terms == nil {
+		log.Printf("No terms of use published, onboarding cannot record consent")
+		return ErrInternalServer
+	}
+	if terms.Version != termsVersion {
+		return ErrOutdatedTermsVersion
+	}
+
+	return s.record(ctx, userID, current, models.SourceOnboarding, client)
+}
+
+// Accept registra o aceite, pelo usuário autenticado, de versões publicadas depois do seu cadastro.
+// Apenas versões vigentes podem ser aceitas.
+func (s *consentService) Accept(ctx context.Context, userPublicID string, documentIDs []string, client ClientInfo) error {
+	user, err := s.findUser(ctx, userPublicID)
+	if err != nil {
+		return err
+	}
+
+	current, err := s.documentRepo.ListCurrent(ctx, time.Now())
+	if err != nil {
+		log.Printf("Error listing current legal documents: %v", err)
+		return ErrInternalServer
+	}
+
+	accepted := make([]models.LegalDocument, 0, len(documentIDs))
+	for _, documentID := range documentIDs {
+		document := findPublicID(current, documentID)
+		if document == nil {
+			return ErrDocumentNotCurrent
+		}
+		accepted = append(accepted, *document)
+	}
+
+	return s.record(ctx, user.ID, accepted, models.SourceReacceptance, client)
+}
+
+// ListPending retorna as versões vigentes que o usuário ainda precisa aceitar.
+func (s *consentService) ListPending(ctx context.Context, userPublicID string) ([]models.LegalDocument, error) {
+	user, err := s.findUser(ctx, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := s.documentRepo.ListPendingByUserID(ctx, user.ID, time.Now())
+	if err != nil {
+		log.Printf("Error listing pending legal documents: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return documents, nil
+}
+
+func (s *consentService) ListHistory(ctx context.Context, userPublicID string) ([]models.Consent, error) {
+	user, err := s.findUser(ctx, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	consents, err := s.consentRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		log.Printf("Error listing consents: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return consents, nil
+}
+
+// WithTx retorna uma cópia do serviço que grava os aceites na transação informada.
+func (s *consentService) WithTx(tx *gorm.DB) ConsentService {
+	return &consentService{
+		documentRepo: s.documentRepo.WithTx(tx),
+		consentRepo:  s.consentRepo.WithTx(tx),
+		userRepo:     s.userRepo.WithTx(tx),
+	}
+}
+
+func (s *consentService) record(ctx context.Context, userID int64, documents []models.LegalDocument, source models.ConsentSource, client ClientInfo) error {
+	if len(documents) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	consents := make([]*models.Consent, 0, len(documents))
+	for _, document := range documents {
+		consents = append(consents, &models.Consent{
+			UserID:          userID,
+			LegalDocumentID: document.ID,
+			Source:          source,
+			IPAddress:       client.IPAddress,
+			UserAgent:       truncate(client.UserAgent, 512),
+			AcceptedAt:      now,
+		})
+	}
+
+	if err := s.consentRepo.Create(ctx, consents); err != nil {
+		log.Printf("Error recording consents: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *consentService) findUser(ctx context.Context, userPublicID string) (*user_models.User, error) {
+	user, err := s.userRepo.FindByPublicID(ctx, userPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		log.Printf("Error finding user by public ID: %v", err)
+		return nil, ErrInternalServer
+	}
+	return user, nil
+}
+
+func findPublicID(documents []models.LegalDocument, publicID string) *models.LegalDocument {
+	for i := range documents {
+		if documents[i].PublicID == publicID {
+			return &documents[i]
+		}
+	}
+	return nil
+}
+
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength {
+		return value
+	}
+	return value[:maxLength]
+}
@@ -0,0 +1,152 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+var currentDocuments = []models.LegalDocument{
+	{ID: 3, PublicID: "01K7Z8Y2M4P6Q8R0S2T4V6W8X3", Kind: models.KindPrivacyPolicy, Version: "1.1"},
+	{ID: 4, PublicID: "01K7Z8Y2M4P6Q8R0S2T4V6W8X4", Kind: models.KindTermsOfUse, Version: "2.0"},
+}
+
+var client = services.ClientInfo{UserAgent: "GoBank/1.0", IPAddress: "203.0.113.10"}
+
+func newConsentService() (services.ConsentService, *mocks.MockLegalDocumentRepository, *mocks.MockConsentRepository, *mocks.MockUserRepository) {
+	documentRepo := new(mocks.MockLegalDocumentRepository)
+	consentRepo := new(mocks.MockConsentRepository)
+	userRepo := new(mocks.MockUserRepository)
+	return services.NewConsentService(documentRepo, consentRepo, userRepo), documentRepo, consentRepo, userRepo
+}
+
+func TestConsentService_AcceptAtOnboarding_RecordsEveryCurrentDocument(t *testing.T) {
+	service, documentRepo, consentRepo, _ := newConsentService()
+
+	documentRepo.On("ListCurrent").Return(currentDocuments, nil)
+	consentRepo.On("Create", mock.MatchedBy(func(consents []*models.Consent) bool {
+		if len(consents) != 2 {
+			return false
+		}
+		for _, consent := range consents {
+			if consent.UserID != 42 || consent.Source != models.SourceOnboarding ||
+				consent.IPAddress != client.IPAddress || consent.UserAgent != client.UserAgent || consent.AcceptedAt.IsZero() {
+				return false
+			}
+		}
+		return consents[0].LegalDocumentID == 3 && consents[1].LegalDocumentID == 4
+	})).Return(nil)
+
+	err := service.AcceptAtOnboarding(context.Background(), 42, "2.0", client)
+
+	assert.NoError(t, err)
+	consentRepo.AssertExpectations(t)
+}
+
+func TestConsentService_AcceptAtOnboarding_OutdatedVersion(t *testing.T) {
+	service, documentRepo, consentRepo, _ := newConsentService()
+
+	documentRepo.On("ListCurrent").Return(currentDocuments, nil)
+
+	err := service.AcceptAtOnboarding(context.Background(), 42, "1.0", client)
+
+	assert.ErrorIs(t, err, services.ErrOutdatedTermsVersion)
+	consentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestConsentService_AcceptAtOnboarding_NoTermsPublished(t *testing.T) {
+	service, documentRepo, _, _ := newConsentService()
+
+	documentRepo.On("ListCurrent").Return([]models.LegalDocument{currentDocuments[0]}, nil)
+
+	err := service.AcceptAtOnboarding(context.Background(), 42, "2.0", client)
+
+	assert.ErrorIs(t, err, services.ErrInternalServer)
+}
+
+func TestConsentService_Accept(t *testing.T) {
+	service, documentRepo, consentRepo, userRepo := newConsentService()
+
+	userRepo.On("FindByPublicID", "user-public-id").Return(&user_models.User{ID: 42}, nil)
+	documentRepo.On("ListCurrent").Return(currentDocuments, nil)
+	consentRepo.On("Create", mock.MatchedBy(func(consents []*models.Consent) bool {
+		return len(consents) == 1 && consents[0].LegalDocumentID == 4 && consents[0].Source == models.SourceReacceptance
+	})).Return(nil)
+
+	err := service.Accept(context.Background(), "user-public-id", []string{"01K7Z8Y2M4P6Q8R0S2T4V6W8X4"}, client)
+
+	assert.NoError(t, err)
+	consentRepo.AssertExpectations(t)
+}
+
+func TestConsentService_Accept_DocumentNotCurrent(t *testing.T) {
+	service, documentRepo, consentRepo, userRepo := newConsentService()
+
+	userRepo.On("FindByPublicID", "user-public-id").Return(&user_models.User{ID: 42}, nil)
+	documentRepo.On("ListCurrent").Return(currentDocuments, nil)
+
+	err := service.Accept(context.Background(), "user-public-id", []string{"01K7Z8Y2M4P6Q8R0S2T4V6W8X4", "superseded-version"}, client)
+
+	assert.ErrorIs(t, err, services.ErrDocumentNotCurrent)
+	consentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestConsentService_ListPending(t *testing.T) {
+	service, documentRepo, _, userRepo := newConsentService()
+
+	userRepo.On("FindByPublicID", "user-public-id").Return(&user_models.User{ID: 42}, nil)
+	documentRepo.On("ListPendingByUserID", int64(42)).Return([]models.LegalDocument{currentDocuments[1]}, nil)
+
+	documents, err := service.ListPending(context.Background(), "user-public-id")
+
+	assert.NoError(t, err)
+	assert.Len(t, documents, 1)
+	assert.Equal(t, models.KindTermsOfUse, documents[0].Kind)
+}
+
+func TestConsentService_ListHistory(t *testing.T) {
+	tests := []struct {
+		name    string
+		userErr error
+		listErr error
+		wantErr error
+	}{
+		{"Should return the user's consents", nil, nil, nil},
+		{"Should fail when the user does not exist", gorm.ErrRecordNotFound, nil, services.ErrUserNotFound},
+		{"Should hide repository errors", nil, errors.New("db error"), services.ErrInternalServer},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, _, consentRepo, userRepo := newConsentService()
+
+			if tt.userErr != nil {
+				userRepo.On("FindByPublicID", "user-public-id").Return(nil, tt.userErr)
+			} else {
+				userRepo.On("FindByPublicID", "user-public-id").Return(&user_models.User{ID: 42}, nil)
+			}
+			if tt.listErr != nil {
+				consentRepo.On("ListByUserID", int64(42)).Return(nil, tt.listErr)
+			} else {
+				consentRepo.On("ListByUserID", int64(42)).Return([]models.Consent{{UserID: 42, LegalDocumentID: 4}}, nil)
+			}
+
+			consents, err := service.ListHistory(context.Background(), "user-public-id")
+
+			if tt.wantErr != nil {
+				assert.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Len(t, consents, 1)
+		})
+	}
+}
@@ -0,0 +1,87 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidDocumentKind     = errors.New("tipo de documento inválido")
+	ErrVersionAlreadyPublished = errors.New("esta versão do documento já foi publicada")
+)
+
+// PublishRequest descreve uma nova versão de documento. Sem PublishedAt, ela passa a valer
+// imediatamente.
+type PublishRequest struct {
+	Kind        models.LegalDocumentKind
+	Version     string
+	URL         string
+	PublishedAt *time.Time
+}
+
+type LegalDocumentService interface {
+	ListCurrent(ctx context.Context) ([]models.LegalDocument, error)
+	Publish(ctx context.Context, request *PublishRequest) (*models.LegalDocument, error)
+}
+
+type legalDocumentService struct {
+	documentRepo repositories.LegalDocumentRepository
+}
+
+func NewLegalDocumentService(documentRepo repositories.LegalDocumentRepository) LegalDocumentService {
+	return &legalDocumentService{documentRepo: documentRepo}
+}
+
+// ListCurrent retorna a versão vigente de cada documento, que o app exibe antes do aceite.
+func (s *legalDocumentService) ListCurrent(ctx context.Context) ([]models.LegalDocument, error) {
+	documents, err := s.documentRepo.ListCurrent(ctx, time.Now())
+	if err != nil {
+		log.Printf("Error listing current legal documents: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return documents, nil
+}
+
+// Publish cadastra uma nova versão. A partir de PublishedAt, os usuários que não a aceitaram
+// passam a ser solicitados a aceitá-la.
+func (s *legalDocumentService) Publish(ctx context.Context, request *PublishRequest) (*models.LegalDocument, error) {
+	if request.Kind != models.KindTermsOfUse && request.Kind != models.KindPrivacyPolicy {
+		return nil, ErrInvalidDocumentKind
+	}
+
+	_, err := s.documentRepo.FindByKindAndVersion(ctx, request.Kind, request.Version)
+	if err == nil {
+		return nil, ErrVersionAlreadyPublished
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Error finding legal document: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	publishedAt := time.Now()
+	if request.PublishedAt != nil {
+		publishedAt = *request.PublishedAt
+	}
+
+	document := &models.LegalDocument{
+		Kind:        request.Kind,
+		Version:     request.Version,
+		URL:         request.URL,
+		PublishedAt: publishedAt,
+	}
+
+	if err := s.documentRepo.Create(ctx, document); err != nil {
+		log.Printf("Error creating legal document: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	log.Printf("Legal document %s version %s published, effective at %s", document.Kind, document.Version, document.PublishedAt.Format(time.RFC3339))
+	return document, nil
+}
@@ -0,0 +1,56 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/services"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestLegalDocumentService_Publish(t *testing.T) {
+	documentRepo := new(mocks.MockLegalDocumentRepository)
+	service := services.NewLegalDocumentService(documentRepo)
+
+	effectiveAt := time.Date(2026, time.November, 1, 0, 0, 0, 0, time.UTC)
+	documentRepo.On("FindByKindAndVersion", models.KindTermsOfUse, "2.0").Return(nil, gorm.ErrRecordNotFound)
+	documentRepo.On("Create", mock.MatchedBy(func(document *models.LegalDocument) bool {
+		return document.Kind == models.KindTermsOfUse && document.Version == "2.0" && document.PublishedAt.Equal(effectiveAt)
+	})).Return(nil)
+
+	document, err := service.Publish(context.Background(), &services.PublishRequest{
+		Kind:        models.KindTermsOfUse,
+		Version:     "2.0",
+		URL:         "https://gobank.com.br/legal/termos-de-uso/2.0",
+		PublishedAt: &effectiveAt,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "2.0", document.Version)
+	documentRepo.AssertExpectations(t)
+}
+
+func TestLegalDocumentService_Publish_AlreadyPublished(t *testing.T) {
+	documentRepo := new(mocks.MockLegalDocumentRepository)
+	service := services.NewLegalDocumentService(documentRepo)
+
+	documentRepo.On("FindByKindAndVersion", models.KindPrivacyPolicy, "1.0").Return(&models.LegalDocument{}, nil)
+
+	_, err := service.Publish(context.Background(), &services.PublishRequest{Kind: models.KindPrivacyPolicy, Version: "1.0"})
+
+	assert.ErrorIs(t, err, services.ErrVersionAlreadyPublished)
+	documentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestLegalDocumentService_Publish_InvalidKind(t *testing.T) {
+	service := services.NewLegalDocumentService(new(mocks.MockLegalDocumentRepository))
+
+	_, err := service.Publish(context.Background(), &services.PublishRequest{Kind: "COOKIES", Version: "1.0"})
+
+	assert.ErrorIs(t, err, services.ErrInvalidDocumentKind)
+}
@@ -25,5 +25,7 @@ func Connect() {
 		log.Fatal("Falha ao conectar ao banco de dados")
 	}
 
+	LoadQueryTimeout()
+
 	log.Println("Conexão com o banco de dados estabelecida.")
 }
@@ -0,0 +1,34 @@
+package database
+
+import (
+	"context"
+	"log"
+	"os"
+	"time"
+)
+
+const defaultQueryTimeout = 5 * time.Second
+
+var queryTimeout = defaultQueryTimeout
+
+// LoadQueryTimeout lê DB_QUERY_TIMEOUT (ex: "5s"). Valores ausentes ou inválidos mantêm o padrão.
+func LoadQueryTimeout() {
+	raw := os.Getenv("DB_QUERY_TIMEOUT")
+	if raw == "" {
+		return
+	}
+
+	timeout, err := time.ParseDuration(raw)
+	if err != nil || timeout <= 0 {
+		log.Printf("Invalid DB_QUERY_TIMEOUT %q, using %s", raw, defaultQueryTimeout)
+		return
+	}
+
+	queryTimeout = timeout
+}
+
+// WithQueryTimeout limita o tempo de uma consulta, para que um Postgres lento não prenda
+// as goroutines dos handlers indefinidamente. O prazo do contexto pai continua valendo se for menor.
+func WithQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(ctx, queryTimeout)
+}
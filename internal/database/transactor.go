@@ -0,0 +1,27 @@
+package database
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// Transactor executa uma unidade de trabalho em uma única transação do banco de dados.
+// Os repositórios participam dela através de seus métodos WithTx.
+type Transactor interface {
+	WithinTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error
+}
+
+type gormTransactor struct {
+	db *gorm.DB
+}
+
+func NewTransactor(db *gorm.DB) Transactor {
+	return &gormTransactor{db: db}
+}
+
+// WithinTransaction faz o commit se fn retornar nil e o rollback caso contrário.
+// A transação é desfeita se ctx for cancelado antes do commit.
+func (t *gormTransactor) WithinTransaction(ctx context.Context, fn func(tx *gorm.DB) error) error {
+	return t.db.WithContext(ctx).Transaction(fn)
+}
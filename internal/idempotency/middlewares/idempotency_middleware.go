@@ -0,0 +1,233 @@
+// Package middlewares provides the Gin middleware that honors the Idempotency-Key header.
+package middlewares
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	auth_middlewares "github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/idempotency/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/idempotency/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"gorm.io/gorm"
+)
+
+const (
+	// IdempotencyKeyHeader é o header com a chave gerada pelo cliente (ex: um UUID) para cada
+	// operação. Repetições da mesma operação devem reenviar a mesma chave.
+	IdempotencyKeyHeader = "Idempotency-Key"
+	// ReplayedHeader marca as respostas devolvidas a partir de uma execução anterior.
+	ReplayedHeader = "Idempotent-Replayed"
+)
+
+const (
+	// KeyTTL é o tempo durante o qual a resposta de uma chave é repetida.
+	KeyTTL = 24 * time.Hour
+	// maxKeyLength é o tamanho da coluna idempotency_key.
+	maxKeyLength = 255
+	// DefaultMaxBody é o corpo máximo das rotas JSON. O corpo é lido por inteiro para calcular o
+	// fingerprint; acima do limite da rota a requisição é recusada antes de chegar ao handler.
+	DefaultMaxBody = 1 << 20
+	// fingerprintContextKey guarda o fingerprint da requisição para o RequestScope.
+	fingerprintContextKey = "idempotencyFingerprint"
+)
+
+// Scope identifica quem envia a requisição, para que clientes diferentes não compartilhem chaves.
+type Scope func(c *gin.Context) (string, error)
+
+// UserScope separa as chaves pelo usuário autenticado. Exige o RequireAuth antes na rota.
+func UserScope(c *gin.Context) (string, error) {
+	userPublicID, ok := auth_middlewares.GetUserPublicID(c)
+	if !ok {
+		return "", errors.New("idempotency: route without an authenticated user")
+	}
+	return userPublicID, nil
+}
+
+// AdminScope separa as chaves pela credencial de administrador. Só o blind index da chave é
+// gravado.
+func AdminScope(c *gin.Context) (string, error) {
+	return blindScope("admin:", c.GetHeader(auth_middlewares.AdminKeyHeader))
+}
+
+// RequestScope separa as chaves das rotas públicas pela própria requisição (método, caminho e
+// corpo), já que não há quem a assine. Uma repetição vinda de outro IP, depois de o cliente trocar
+// de rede, cai no mesmo escopo e recebe a resposta gravada. A mesma chave com outro corpo cai em
+// outro escopo e é executada como uma operação nova.
+func RequestScope(c *gin.Context) (string, error) {
+	return "request:" + c.GetString(fingerprintContextKey), nil
+}
+
+func blindScope(prefix, value string) (string, error) {
+	index, err := fieldcrypto.BlindIndex(value)
+	if err != nil {
+		return "", err
+	}
+	return prefix + index, nil
+}
+
+// IdempotencyKey deve vir depois da autenticação da rota, para que scope encontre quem a chama.
+// Requisições sem o header seguem normalmente. Na primeira requisição com uma chave, a resposta é
+// gravada; repetições com o mesmo método, caminho e corpo recebem a resposta gravada sem executar
+// o handler, e a mesma chave com outra requisição é recusada com 422. Respostas 5xx não são
+// gravadas, para que o cliente possa tentar de novo com a mesma chave. maxBody deve ser o limite
+// de corpo da própria rota.
+func IdempotencyKey(repo repositories.IdempotencyKeyRepository, scope Scope, maxBody int64) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		key := c.GetHeader(IdempotencyKeyHeader)
+		if key == "" || !isMutating(c.Request.Method) {
+			c.Next()
+			return
+		}
+		if len(key) > maxKeyLength {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "O header Idempotency-Key deve ter no máximo 255 caracteres."})
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxBody))
+		if err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, gin.H{"error": "O corpo da requisição é grande demais."})
+				return
+			}
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Corpo da requisição inválido"})
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		requestFingerprint := fingerprint(c.Request.Method, c.Request.URL.Path, body)
+		c.Set(fingerprintContextKey, requestFingerprint)
+
+		requestScope, err := scope(c)
+		if err != nil {
+			log.Printf("Error resolving idempotency key scope: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "ocorreu um erro inesperado"})
+			return
+		}
+
+		now := time.Now()
+		record := &models.IdempotencyKey{
+			Scope:       requestScope,
+			Key:         key,
+			Fingerprint: requestFingerprint,
+			Status:      models.KeyProcessing,
+			ExpiresAt:   now.Add(KeyTTL),
+		}
+
+		reserved, err := repo.Reserve(c.Request.Context(), record, now)
+		if err != nil {
+			log.Printf("Error reserving idempotency key: %v", err)
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "ocorreu um erro inesperado"})
+			return
+		}
+		if !reserved {
+			replay(c, repo, record)
+			return
+		}
+
+		execute(c, repo, record)
+	}
+}
+
+// execute roda o handler gravando a resposta. Se o handler não terminar (panic) ou responder
+// com 5xx, a reserva é desfeita.
+func execute(c *gin.Context, repo repositories.IdempotencyKeyRepository, record *models.IdempotencyKey) {
+	// A operação já foi executada: a resposta é gravada mesmo que o cliente tenha desconectado.
+	ctx := context.WithoutCancel(c.Request.Context())
+	recorder := &responseRecorder{ResponseWriter: c.Writer}
+	c.Writer = recorder
+
+	completed := false
+	defer func() {
+		if !completed {
+			if err := repo.Release(ctx, record); err != nil {
+				log.Printf("Error releasing idempotency key: %v", err)
+			}
+		}
+	}()
+
+	c.Next()
+
+	if recorder.Status() >= http.StatusInternalServerError {
+		return
+	}
+
+	record.ResponseStatus = recorder.Status()
+	record.ResponseContentType = recorder.Header().Get("Content-Type")
+	record.ResponseBody = recorder.body.Bytes()
+	if err := repo.Complete(ctx, record); err != nil {
+		log.Printf("Error storing the response of idempotency key: %v", err)
+		return
+	}
+	completed = true
+}
+
+// replay devolve a resposta gravada para a chave, ou o motivo de não poder devolvê-la.
+func replay(c *gin.Context, repo repositories.IdempotencyKeyRepository, record *models.IdempotencyKey) {
+	stored, err := repo.Find(c.Request.Context(), record.Scope, record.Key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			// A reserva foi desfeita entre o INSERT e a leitura: a primeira requisição falhou.
+			c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Uma requisição com esta chave de idempotência ainda está em processamento."})
+			return
+		}
+		log.Printf("Error finding idempotency key: %v", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "ocorreu um erro inesperado"})
+		return
+	}
+
+	if stored.Fingerprint != record.Fingerprint {
+		c.AbortWithStatusJSON(http.StatusUnprocessableEntity, gin.H{"error": "A chave de idempotência já foi usada em uma requisição diferente."})
+		return
+	}
+	if stored.Status != models.KeyCompleted {
+		c.AbortWithStatusJSON(http.StatusConflict, gin.H{"error": "Uma requisição com esta chave de idempotência ainda está em processamento."})
+		return
+	}
+
+	c.Header(ReplayedHeader, "true")
+	c.Data(stored.ResponseStatus, stored.ResponseContentType, stored.ResponseBody)
+	c.Abort()
+}
+
+func isMutating(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
+
+// fingerprint identifica a requisição pelo método, caminho e corpo. O caminho inclui os
+// parâmetros da rota, então a mesma chave em outro recurso também é recusada.
+func fingerprint(method, path string, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(method + " " + path + "\n"))
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// responseRecorder copia o corpo da resposta enquanto ele é enviado ao cliente.
+type responseRecorder struct {
+	gin.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(data []byte) (int, error) {
+	r.body.Write(data)
+	return r.ResponseWriter.Write(data)
+}
+
+func (r *responseRecorder) WriteString(data string) (int, error) {
+	r.body.WriteString(data)
+	return r.ResponseWriter.WriteString(data)
+}
@@ -0,0 +1,292 @@
+package middlewares_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	auth_middlewares "github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/idempotency/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/idempotency/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+// newIdempotentRouter monta uma rota que responde status e conta quantas vezes o handler rodou.
+func newIdempotentRouter(repo *mocks.MockIdempotencyKeyRepository, status int, calls *int) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/transfers", func(c *gin.Context) {
+		c.Set(auth_middlewares.UserPublicIDKey, "user-public-id")
+		c.Next()
+	}, middlewares.IdempotencyKey(repo, middlewares.UserScope, middlewares.DefaultMaxBody), func(c *gin.Context) {
+		*calls++
+		c.JSON(status, gin.H{"id": "transfer-1"})
+	})
+	router.GET("/transfers", middlewares.IdempotencyKey(repo, middlewares.UserScope, middlewares.DefaultMaxBody), func(c *gin.Context) {
+		*calls++
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func send(router *gin.Engine, method, body, key string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	req, _ := http.NewRequest(method, "/transfers", strings.NewReader(body))
+	if key != "" {
+		req.Header.Set(middlewares.IdempotencyKeyHeader, key)
+	}
+	router.ServeHTTP(w, req)
+	return w
+}
+
+func reservedKey(key *models.IdempotencyKey) bool {
+	return key.Scope == "user-public-id" && key.Key == "key-1" && key.Status == models.KeyProcessing &&
+		len(key.Fingerprint) == 64 && time.Until(key.ExpiresAt) > 23*time.Hour
+}
+
+func TestIdempotencyKey_StoresTheFirstResponse(t *testing.T) {
+	repo := new(mocks.MockIdempotencyKeyRepository)
+	var calls int
+
+	repo.On("Reserve", mock.MatchedBy(reservedKey), mock.AnythingOfType("time.Time")).Return(true, nil)
+	repo.On("Complete", mock.MatchedBy(func(key *models.IdempotencyKey) bool {
+		return key.ResponseStatus == http.StatusCreated &&
+			strings.HasPrefix(key.ResponseContentType, "application/json") &&
+			string(key.ResponseBody) == `{"id":"transfer-1"}`
+	})).Return(nil)
+
+	w := send(newIdempotentRouter(repo, http.StatusCreated, &calls), http.MethodPost, `{"amount":"10.00"}`, "key-1")
+
+	assert.Equal(t, http.StatusCreated, w.Code)
+	assert.Equal(t, 1, calls)
+	assert.Empty(t, w.Header().Get(middlewares.ReplayedHeader))
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Release", mock.Anything)
+}
+
+func TestIdempotencyKey_ReplaysIdenticalRetries(t *testing.T) {
+	repo := new(mocks.MockIdempotencyKeyRepository)
+	var calls int
+	stored := &models.IdempotencyKey{
+		Status:              models.KeyCompleted,
+		ResponseStatus:      http.StatusAccepted,
+		ResponseContentType: "application/json; charset=utf-8",
+		ResponseBody:        []byte(`{"message":"original"}`),
+	}
+
+	repo.On("Reserve", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		stored.Fingerprint = args.Get(0).(*models.IdempotencyKey).Fingerprint
+	}).Return(false, nil)
+	repo.On("Find", "user-public-id", "key-1").Return(stored, nil)
+
+	w := send(newIdempotentRouter(repo, http.StatusConflict, &calls), http.MethodPost, `{"amount":"10.00"}`, "key-1")
+
+	assert.Equal(t, http.StatusAccepted, w.Code)
+	assert.JSONEq(t, `{"message":"original"}`, w.Body.String())
+	assert.Equal(t, "true", w.Header().Get(middlewares.ReplayedHeader))
+	assert.Equal(t, 0, calls, "the handler must not run again")
+}
+
+func TestIdempotencyKey_RejectsReusedKeys(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     *models.IdempotencyKey
+		sameBody   bool
+		wantStatus int
+	}{
+		{"Should refuse the key with a different body", &models.IdempotencyKey{Status: models.KeyCompleted}, false, http.StatusUnprocessableEntity},
+		{"Should refuse retries while the first request runs", &models.IdempotencyKey{Status: models.KeyProcessing}, true, http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := new(mocks.MockIdempotencyKeyRepository)
+			var calls int
+
+			repo.On("Reserve", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+				tt.stored.Fingerprint = "another-request"
+				if tt.sameBody {
+					tt.stored.Fingerprint = args.Get(0).(*models.IdempotencyKey).Fingerprint
+				}
+			}).Return(false, nil)
+			repo.On("Find", "user-public-id", "key-1").Return(tt.stored, nil)
+
+			w := send(newIdempotentRouter(repo, http.StatusCreated, &calls), http.MethodPost, `{"amount":"10.00"}`, "key-1")
+
+			assert.Equal(t, tt.wantStatus, w.Code)
+			assert.Equal(t, 0, calls)
+		})
+	}
+}
+
+func TestIdempotencyKey_ReleasesTheKeyOnServerErrors(t *testing.T) {
+	repo := new(mocks.MockIdempotencyKeyRepository)
+	var calls int
+
+	repo.On("Reserve", mock.Anything, mock.Anything).Return(true, nil)
+	repo.On("Release", mock.MatchedBy(reservedKey)).Return(nil)
+
+	w := send(newIdempotentRouter(repo, http.StatusInternalServerError, &calls), http.MethodPost, `{}`, "key-1")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	repo.AssertExpectations(t)
+	repo.AssertNotCalled(t, "Complete", mock.Anything)
+}
+
+func TestIdempotencyKey_PassesThrough(t *testing.T) {
+	repo := new(mocks.MockIdempotencyKeyRepository)
+	var calls int
+	router := newIdempotentRouter(repo, http.StatusCreated, &calls)
+
+	assert.Equal(t, http.StatusCreated, send(router, http.MethodPost, `{}`, "").Code, "requests without the header")
+	assert.Equal(t, http.StatusOK, send(router, http.MethodGet, "", "key-1").Code, "safe methods")
+	assert.Equal(t, 2, calls)
+	repo.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyKey_RejectsLongKeys(t *testing.T) {
+	repo := new(mocks.MockIdempotencyKeyRepository)
+	var calls int
+
+	w := send(newIdempotentRouter(repo, http.StatusCreated, &calls), http.MethodPost, `{}`, strings.Repeat("k", 256))
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestIdempotencyKey_ReleasedBeforeTheLookup(t *testing.T) {
+	repo := new(mocks.MockIdempotencyKeyRepository)
+	var calls int
+
+	repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil)
+	repo.On("Find", "user-public-id", "key-1").Return(nil, gorm.ErrRecordNotFound)
+
+	w := send(newIdempotentRouter(repo, http.StatusCreated, &calls), http.MethodPost, `{}`, "key-1")
+
+	assert.Equal(t, http.StatusConflict, w.Code)
+	assert.Equal(t, 0, calls)
+}
+
+func TestIdempotencyKey_RejectsBodiesAboveTheRouteLimit(t *testing.T) {
+	repo := new(mocks.MockIdempotencyKeyRepository)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/transfers", func(c *gin.Context) {
+		c.Set(auth_middlewares.UserPublicIDKey, "user-public-id")
+		c.Next()
+	}, middlewares.IdempotencyKey(repo, middlewares.UserScope, 8), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	w := send(router, http.MethodPost, `{"amount":"10.00"}`, "key-1")
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	repo.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+}
+
+func TestIdempotencyKey_SeparatesAnonymousAndAdminCallers(t *testing.T) {
+	keyring, err := fieldcrypto.NewKeyring(map[string][]byte{"k1": []byte("0123456789abcdef0123456789abcdef")}, "k1", []byte("index-key-0123456789abcdef012345"))
+	require.NoError(t, err)
+	fieldcrypto.SetKeyring(keyring)
+	t.Cleanup(func() { fieldcrypto.SetKeyring(nil) })
+
+	repo := new(mocks.MockIdempotencyKeyRepository)
+	var scopes []string
+	repo.On("Reserve", mock.Anything, mock.Anything).Run(func(args mock.Arguments) {
+		scopes = append(scopes, args.Get(0).(*models.IdempotencyKey).Scope)
+	}).Return(true, nil)
+	repo.On("Complete", mock.Anything).Return(nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	handler := func(c *gin.Context) { c.Status(http.StatusAccepted) }
+	router.POST("/onboarding/start", middlewares.IdempotencyKey(repo, middlewares.RequestScope, middlewares.DefaultMaxBody), handler)
+	router.POST("/admin/retry", middlewares.IdempotencyKey(repo, middlewares.AdminScope, middlewares.DefaultMaxBody), handler)
+
+	call := func(path, body, adminKey string) {
+		req, _ := http.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set(middlewares.IdempotencyKeyHeader, "key-1")
+		if adminKey != "" {
+			req.Header.Set(auth_middlewares.AdminKeyHeader, adminKey)
+		}
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+	call("/onboarding/start", `{"email":"john@example.com"}`, "")
+	call("/onboarding/start", `{"email":"john@example.com"}`, "")
+	call("/onboarding/start", `{"email":"jane@example.com"}`, "")
+	call("/admin/retry", `{}`, "admin-secret")
+	call("/admin/retry", `{}`, "other-secret")
+
+	require.Len(t, scopes, 5)
+	assert.Equal(t, scopes[0], scopes[1], "the same request keeps its key")
+	assert.NotEqual(t, scopes[0], scopes[2])
+	assert.NotEqual(t, scopes[3], scopes[4])
+	assert.NotEqual(t, scopes[0], scopes[3])
+	for _, scope := range scopes {
+		assert.NotEmpty(t, scope)
+		assert.NotContains(t, scope, "example.com")
+		assert.NotContains(t, scope, "secret")
+		assert.LessOrEqual(t, len(scope), 80)
+	}
+}
+
+func TestIdempotencyKey_ReplaysPublicRetriesFromAnotherIP(t *testing.T) {
+	repo := new(mocks.MockIdempotencyKeyRepository)
+	var calls int
+	stored := &models.IdempotencyKey{}
+
+	repo.On("Reserve", mock.Anything, mock.Anything).Return(true, nil).Once()
+	repo.On("Complete", mock.Anything).Run(func(args mock.Arguments) {
+		*stored = *args.Get(0).(*models.IdempotencyKey)
+		stored.Status = models.KeyCompleted
+	}).Return(nil)
+	repo.On("Reserve", mock.Anything, mock.Anything).Return(false, nil)
+	repo.On("Find", mock.Anything, "key-1").Return(stored, nil)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/onboarding/start", middlewares.IdempotencyKey(repo, middlewares.RequestScope, middlewares.DefaultMaxBody), func(c *gin.Context) {
+		calls++
+		c.JSON(http.StatusAccepted, gin.H{"message": "original"})
+	})
+
+	call := func(remoteAddr string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPost, "/onboarding/start", strings.NewReader(`{"email":"john@example.com"}`))
+		req.RemoteAddr = remoteAddr
+		req.Header.Set(middlewares.IdempotencyKeyHeader, "key-1")
+		router.ServeHTTP(w, req)
+		return w
+	}
+	first := call("203.0.113.1:4000")
+	retry := call("198.51.100.7:4000")
+
+	assert.Equal(t, http.StatusAccepted, first.Code)
+	assert.Equal(t, http.StatusAccepted, retry.Code)
+	assert.JSONEq(t, `{"message":"original"}`, retry.Body.String())
+	assert.Equal(t, "true", retry.Header().Get(middlewares.ReplayedHeader))
+	assert.Equal(t, 1, calls, "the retry from another network must not run the handler again")
+	repo.AssertCalled(t, "Find", stored.Scope, "key-1")
+}
+
+func TestIdempotencyKey_UserScopeRequiresAuthentication(t *testing.T) {
+	repo := new(mocks.MockIdempotencyKeyRepository)
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.POST("/transfers", middlewares.IdempotencyKey(repo, middlewares.UserScope, middlewares.DefaultMaxBody), func(c *gin.Context) {
+		c.Status(http.StatusCreated)
+	})
+
+	w := send(router, http.MethodPost, `{}`, "key-1")
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	repo.AssertNotCalled(t, "Reserve", mock.Anything, mock.Anything)
+}
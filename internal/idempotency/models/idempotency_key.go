@@ -0,0 +1,43 @@
+// Package models define the stored responses of requests sent with an Idempotency-Key header.
+package models
+
+import (
+	"time"
+)
+
+// KeyStatus define os possíveis status de uma chave de idempotência.
+type KeyStatus string
+
+const (
+	// KeyProcessing indica que a primeira requisição com a chave ainda não terminou.
+	KeyProcessing KeyStatus = "PROCESSING"
+	// KeyCompleted indica que a resposta foi gravada e pode ser repetida.
+	KeyCompleted KeyStatus = "COMPLETED"
+)
+
+// IdempotencyKey guarda a resposta da primeira requisição feita com uma chave. Scope identifica
+// quem a enviou (o ID público do usuário, ou o blind index da chave de administrador ou do IP de
+// origem), para que clientes diferentes não compartilhem chaves; Fingerprint identifica o método,
+// o caminho e o corpo da requisição.
+type IdempotencyKey struct {
+	ID                  int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	Scope               string    `gorm:"type:varchar(80);not null;default:''"`
+	Key                 string    `gorm:"type:varchar(255);not null;column:idempotency_key"`
+	Fingerprint         string    `gorm:"type:varchar(64);not null"`
+	Status              KeyStatus `gorm:"type:varchar(20);not null;default:'PROCESSING'"`
+	ResponseStatus      int       `gorm:"column:response_status"`
+	ResponseContentType string    `gorm:"type:varchar(255);column:response_content_type"`
+	ResponseBody        []byte    `gorm:"type:bytea;column:response_body"`
+	ExpiresAt           time.Time `gorm:"not null"`
+	CreatedAt           time.Time `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime"`
+}
+
+func (IdempotencyKey) TableName() string {
+	return "idempotency.keys"
+}
+
+// IsExpired indica se a chave já pode ser reutilizada por outra requisição.
+func (k *IdempotencyKey) IsExpired(now time.Time) bool {
+	return !now.Before(k.ExpiresAt)
+}
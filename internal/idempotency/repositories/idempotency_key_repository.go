@@ -0,0 +1,122 @@
+// Package repositories define the data access layer for idempotency keys.
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/idempotency/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type IdempotencyKeyRepository interface {
+	Reserve(ctx context.Context, key *models.IdempotencyKey, now time.Time) (bool, error)
+	Find(ctx context.Context, scope, key string) (*models.IdempotencyKey, error)
+	Complete(ctx context.Context, key *models.IdempotencyKey) error
+	Release(ctx context.Context, key *models.IdempotencyKey) error
+	DeleteExpired(ctx context.Context, now time.Time, batchSize int) (int64, error)
+	DeleteByScope(ctx context.Context, scope string) error
+	WithTx(tx *gorm.DB) IdempotencyKeyRepository
+}
+
+type idempotencyKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewIdempotencyKeyRepository(db *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: db}
+}
+
+// Reserve grava a chave como PROCESSING se ela ainda não existir ou já tiver expirado. Retorna
+// false quando outra requisição com a mesma chave, ainda válida, chegou antes. O INSERT ... ON
+// CONFLICT garante que só uma de duas requisições simultâneas consiga a reserva.
+func (r *idempotencyKeyRepository) Reserve(ctx context.Context, key *models.IdempotencyKey, now time.Time) (bool, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "scope"}, {Name: "idempotency_key"}},
+			DoUpdates: clause.AssignmentColumns([]string{
+				"fingerprint", "status", "response_status", "response_content_type", "response_body", "expires_at", "created_at", "updated_at",
+			}),
+			Where: clause.Where{Exprs: []clause.Expression{clause.Expr{SQL: `"keys".expires_at <= ?`, Vars: []any{now}}}},
+		}).
+		Create(key)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected == 1, nil
+}
+
+func (r *idempotencyKeyRepository) Find(ctx context.Context, scope, key string) (*models.IdempotencyKey, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var idempotencyKey models.IdempotencyKey
+	result := r.db.WithContext(ctx).Where("scope = ? AND idempotency_key = ?", scope, key).First(&idempotencyKey)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &idempotencyKey, nil
+}
+
+// Complete grava a resposta da requisição que reservou a chave.
+func (r *idempotencyKeyRepository) Complete(ctx context.Context, key *models.IdempotencyKey) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Model(&models.IdempotencyKey{}).
+		Where("scope = ? AND idempotency_key = ? AND fingerprint = ?", key.Scope, key.Key, key.Fingerprint).
+		Updates(map[string]any{
+			"status":                models.KeyCompleted,
+			"response_status":       key.ResponseStatus,
+			"response_content_type": key.ResponseContentType,
+			"response_body":         key.ResponseBody,
+		}).Error
+}
+
+// Release apaga uma reserva que não chegou a gravar resposta, liberando a chave para uma nova
+// tentativa.
+func (r *idempotencyKeyRepository) Release(ctx context.Context, key *models.IdempotencyKey) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).
+		Where("scope = ? AND idempotency_key = ? AND status = ?", key.Scope, key.Key, models.KeyProcessing).
+		Delete(&models.IdempotencyKey{}).Error
+}
+
+// DeleteExpired apaga até batchSize chaves expiradas, retornando quantas foram apagadas.
+func (r *idempotencyKeyRepository) DeleteExpired(ctx context.Context, now time.Time, batchSize int) (int64, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Exec(`
+		DELETE FROM idempotency.keys
+		WHERE id IN (
+			SELECT id FROM idempotency.keys
+			WHERE expires_at <= ?
+			ORDER BY expires_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)`,
+		now, batchSize,
+	)
+	return result.RowsAffected, result.Error
+}
+
+// DeleteByScope apaga todas as chaves de um escopo, com as respostas guardadas, expiradas ou não.
+func (r *idempotencyKeyRepository) DeleteByScope(ctx context.Context, scope string) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Where("scope = ?", scope).Delete(&models.IdempotencyKey{}).Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *idempotencyKeyRepository) WithTx(tx *gorm.DB) IdempotencyKeyRepository {
+	return &idempotencyKeyRepository{db: tx}
+}
@@ -0,0 +1,87 @@
+// Package workers defines the background jobs of the idempotency keys.
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/idempotency/repositories"
+)
+
+const (
+	DefaultKeySweepInterval  = 1 * time.Hour
+	DefaultKeySweepBatchSize = 1000
+)
+
+// KeySweeper apaga periodicamente as chaves de idempotência expiradas. Chaves expiradas já são
+// ignoradas ao reservar uma nova requisição; o sweeper só evita que a tabela cresça sem limite.
+type KeySweeper struct {
+	repo      repositories.IdempotencyKeyRepository
+	interval  time.Duration
+	batchSize int
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewKeySweeper(repo repositories.IdempotencyKeyRepository, interval time.Duration, batchSize int) *KeySweeper {
+	return &KeySweeper{repo: repo, interval: interval, batchSize: batchSize}
+}
+
+// Start inicia o sweeper em background. Ele roda uma vez imediatamente e depois a cada intervalo.
+func (w *KeySweeper) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := w.RunOnce(ctx); err != nil {
+				log.Printf("Error deleting expired idempotency keys: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop sinaliza o fim do sweeper e aguarda o lote em andamento terminar.
+func (w *KeySweeper) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// RunOnce apaga lotes de chaves até não restar nenhuma expirada ou o contexto ser cancelado.
+func (w *KeySweeper) RunOnce(ctx context.Context) (int64, error) {
+	var total int64
+
+	for ctx.Err() == nil {
+		deleted, err := w.repo.DeleteExpired(ctx, time.Now(), w.batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += deleted
+		if deleted < int64(w.batchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Deleted %d expired idempotency keys", total)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,39 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/idempotency/workers"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestKeySweeper_RunOnce_ProcessesBatchesUntilEmpty(t *testing.T) {
+	mockRepo := new(mocks.MockIdempotencyKeyRepository)
+	sweeper := workers.NewKeySweeper(mockRepo, time.Hour, 2)
+
+	mockRepo.On("DeleteExpired", mock.AnythingOfType("time.Time"), 2).Return(int64(2), nil).Once()
+	mockRepo.On("DeleteExpired", mock.AnythingOfType("time.Time"), 2).Return(int64(0), nil).Once()
+
+	total, err := sweeper.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	mockRepo.AssertNumberOfCalls(t, "DeleteExpired", 2)
+}
+
+func TestKeySweeper_RunOnce_StopsOnError(t *testing.T) {
+	mockRepo := new(mocks.MockIdempotencyKeyRepository)
+	sweeper := workers.NewKeySweeper(mockRepo, time.Hour, 2)
+
+	mockRepo.On("DeleteExpired", mock.Anything, 2).Return(int64(0), errors.New("db error"))
+
+	_, err := sweeper.RunOnce(context.Background())
+
+	assert.Error(t, err)
+	mockRepo.AssertNumberOfCalls(t, "DeleteExpired", 1)
+}
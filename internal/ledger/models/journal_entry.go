@@ -0,0 +1,115 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/money"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrEmptyEntry      = errors.New("o lançamento precisa de ao menos duas partidas")
+	ErrZeroPosting     = errors.New("as partidas do lançamento não podem ter valor zero")
+	ErrNegativePosting = errors.New("as partidas do lançamento devem ter valor positivo")
+	ErrPostingOverflow = errors.New("a soma das partidas do lançamento excede o limite")
+	ErrUnbalancedEntry = errors.New("a soma dos débitos e créditos do lançamento não é zero")
+)
+
+// EntryKind classifica a origem de um lançamento.
+type EntryKind string
+
+const (
+	EntryDeposit  EntryKind = "DEPOSIT"
+	EntryTransfer EntryKind = "TRANSFER"
+)
+
+// JournalEntry é um lançamento contábil. Suas partidas somam zero: todo débito em uma conta tem
+// um crédito de mesmo valor em outra.
+type JournalEntry struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID    string    `gorm:"type:varchar(26);unique;not null"`
+	Kind        EntryKind `gorm:"type:varchar(30);not null"`
+	Description string    `gorm:"type:varchar(255);not null;default:''"`
+	Postings    []Posting `gorm:"foreignKey:JournalEntryID"`
+	CreatedAt   time.Time `gorm:"autoCreateTime"`
+}
+
+func (JournalEntry) TableName() string {
+	return "ledger.journal_entries"
+}
+
+func (e *JournalEntry) BeforeCreate(_ *gorm.DB) (err error) {
+	e.PublicID = ulid.Make().String()
+	return
+}
+
+// Posting é uma partida de um lançamento, em centavos: positiva para débito e negativa para crédito.
+type Posting struct {
+	ID              int64     `gorm:"primaryKey;autoIncrement;column:id"`
+	JournalEntryID  int64     `gorm:"not null;column:journal_entry_id"`
+	LedgerAccountID int64     `gorm:"not null;column:ledger_account_id"`
+	Amount          int64     `gorm:"not null"`
+	CreatedAt       time.Time `gorm:"autoCreateTime"`
+
+	// negative marca a partida criada por Debit ou Credit com valor negativo, que inverteria
+	// o sentido do lançamento.
+	negative bool
+}
+
+func (Posting) TableName() string {
+	return "ledger.postings"
+}
+
+// Debit cria uma partida de débito de amount centavos na conta.
+func Debit(ledgerAccountID, amount int64) Posting {
+	return Posting{LedgerAccountID: ledgerAccountID, Amount: amount, negative: amount < 0}
+}
+
+// Credit cria uma partida de crédito de amount centavos na conta.
+func Credit(ledgerAccountID, amount int64) Posting {
+	return Posting{LedgerAccountID: ledgerAccountID, Amount: -amount, negative: amount < 0}
+}
+
+// Validate garante a invariante das partidas dobradas antes de o lançamento ser gravado. As somas,
+// total e por conta, são verificadas contra estouro, para que valores extremos não se anulem e
+// pareçam balanceados nem cheguem a TotalsByAccount.
+func (e *JournalEntry) Validate() error {
+	if len(e.Postings) < 2 {
+		return ErrEmptyEntry
+	}
+
+	total := money.FromCentavos(0)
+	totals := make(map[int64]money.Money, len(e.Postings))
+	for _, posting := range e.Postings {
+		if posting.Amount == 0 {
+			return ErrZeroPosting
+		}
+		if posting.negative {
+			return ErrNegativePosting
+		}
+
+		amount := money.FromCentavos(posting.Amount)
+		var err, accountErr error
+		total, err = total.Add(amount)
+		totals[posting.LedgerAccountID], accountErr = totals[posting.LedgerAccountID].Add(amount)
+		if err != nil || accountErr != nil {
+			return ErrPostingOverflow
+		}
+	}
+
+	if !total.IsZero() {
+		return ErrUnbalancedEntry
+	}
+	return nil
+}
+
+// TotalsByAccount soma as partidas do lançamento por conta do razão.
+func (e *JournalEntry) TotalsByAccount() map[int64]int64 {
+	totals := make(map[int64]int64, len(e.Postings))
+	for _, posting := range e.Postings {
+		totals[posting.LedgerAccountID] += posting.Amount
+	}
+	return totals
+}
@@ -0,0 +1,48 @@
+package models_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJournalEntry_Validate(t *testing.T) {
+	tests := []struct {
+		name     string
+		postings []models.Posting
+		expected error
+	}{
+		{"balanced", []models.Posting{models.Debit(1, 1000), models.Credit(2, 1000)}, nil},
+		{"balanced split", []models.Posting{models.Debit(1, 1000), models.Credit(2, 600), models.Credit(3, 400)}, nil},
+		{"unbalanced", []models.Posting{models.Debit(1, 1000), models.Credit(2, 999)}, models.ErrUnbalancedEntry},
+		{"single posting", []models.Posting{models.Debit(1, 1000)}, models.ErrEmptyEntry},
+		{"no postings", nil, models.ErrEmptyEntry},
+		{"zero posting", []models.Posting{models.Debit(1, 0), models.Credit(2, 0)}, models.ErrZeroPosting},
+		{"negative debit", []models.Posting{models.Debit(1, -1000), models.Credit(2, -1000)}, models.ErrNegativePosting},
+		{"negative credit", []models.Posting{models.Credit(1, -1000), models.Debit(2, -1000)}, models.ErrNegativePosting},
+		{"minimum int64 credit", []models.Posting{models.Debit(1, math.MinInt64), models.Credit(2, math.MinInt64)}, models.ErrNegativePosting},
+		{"overflow", []models.Posting{models.Debit(1, math.MaxInt64), models.Debit(2, 1), models.Credit(3, math.MaxInt64), models.Credit(4, 1)}, models.ErrPostingOverflow},
+		{"account overflow", []models.Posting{models.Debit(1, math.MaxInt64), models.Credit(2, math.MaxInt64), models.Debit(1, 1), models.Credit(2, 1)}, models.ErrPostingOverflow},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry := &models.JournalEntry{Kind: models.EntryTransfer, Postings: tt.postings}
+			assert.ErrorIs(t, entry.Validate(), tt.expected)
+		})
+	}
+}
+
+func TestLedgerAccount_BalanceAfter(t *testing.T) {
+	customer := models.NewCustomerAccount(7, "122504005")
+	customer.Balance = 5000
+	settlement := &models.LedgerAccount{NormalBalance: models.NormalDebit, Balance: 5000}
+
+	assert.Equal(t, "CUSTOMER:122504005", customer.Code)
+	assert.Equal(t, int64(6000), customer.BalanceAfter(models.Credit(1, 1000).Amount), "credits increase customer balances")
+	assert.Equal(t, int64(4000), customer.BalanceAfter(models.Debit(1, 1000).Amount), "debits decrease customer balances")
+	assert.Equal(t, int64(6000), settlement.BalanceAfter(models.Debit(1, 1000).Amount), "debits increase asset balances")
+	assert.Equal(t, int64(2500), customer.BalanceFromPostings(-2500))
+}
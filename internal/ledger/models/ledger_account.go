@@ -0,0 +1,63 @@
+// Package models define the double-entry ledger: accounts, journal entries and postings.
+package models
+
+import (
+	"time"
+)
+
+// NormalBalance indica de que lado o saldo de uma conta cresce. As contas correntes dos clientes
+// são passivos do banco e crescem a crédito; a conta de liquidação é um ativo e cresce a débito.
+type NormalBalance string
+
+const (
+	NormalDebit  NormalBalance = "DEBIT"
+	NormalCredit NormalBalance = "CREDIT"
+)
+
+// SettlementAccountCode identifica a conta de liquidação, contrapartida do dinheiro que entra e
+// sai do banco.
+const SettlementAccountCode = "SYSTEM:SETTLEMENT"
+
+// LedgerAccount é uma conta do razão. Balance é um snapshot do saldo derivado das partidas, em
+// centavos, e Version implementa o lock otimista usado ao atualizá-lo.
+type LedgerAccount struct {
+	ID            int64         `gorm:"primaryKey;autoIncrement;column:id"`
+	Code          string        `gorm:"type:varchar(64);unique;not null"`
+	AccountID     *int64        `gorm:"unique;column:account_id"`
+	NormalBalance NormalBalance `gorm:"type:varchar(6);not null"`
+	AllowNegative bool          `gorm:"not null;default:false;column:allow_negative"`
+	Balance       int64         `gorm:"not null;default:0"`
+	Version       int64         `gorm:"not null;default:0"`
+	CreatedAt     time.Time     `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time     `gorm:"autoUpdateTime"`
+}
+
+func (LedgerAccount) TableName() string {
+	return "ledger.accounts"
+}
+
+// NewCustomerAccount cria a conta do razão de uma conta corrente.
+func NewCustomerAccount(accountID int64, accountNumber string) *LedgerAccount {
+	return &LedgerAccount{
+		Code:          "CUSTOMER:" + accountNumber,
+		AccountID:     &accountID,
+		NormalBalance: NormalCredit,
+	}
+}
+
+// BalanceAfter calcula o saldo da conta depois de somar as partidas informadas (débitos positivos,
+// créditos negativos), respeitando o lado em que o saldo cresce.
+func (a *LedgerAccount) BalanceAfter(postingsTotal int64) int64 {
+	if a.NormalBalance == NormalCredit {
+		return a.Balance - postingsTotal
+	}
+	return a.Balance + postingsTotal
+}
+
+// BalanceFromPostings converte a soma de todas as partidas da conta no seu saldo.
+func (a *LedgerAccount) BalanceFromPostings(postingsTotal int64) int64 {
+	if a.NormalBalance == NormalCredit {
+		return -postingsTotal
+	}
+	return postingsTotal
+}
@@ -0,0 +1,51 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/models"
+	"gorm.io/gorm"
+)
+
+type JournalEntryRepository interface {
+	Create(ctx context.Context, entry *models.JournalEntry) error
+	FindByPublicID(ctx context.Context, publicID string) (*models.JournalEntry, error)
+	WithTx(tx *gorm.DB) JournalEntryRepository
+}
+
+type journalEntryRepository struct {
+	db *gorm.DB
+}
+
+func NewJournalEntryRepository(db *gorm.DB) JournalEntryRepository {
+	return &journalEntryRepository{db: db}
+}
+
+// Create grava o lançamento com suas partidas.
+func (r *journalEntryRepository) Create(ctx context.Context, entry *models.JournalEntry) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *journalEntryRepository) FindByPublicID(ctx context.Context, publicID string) (*models.JournalEntry, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var entry models.JournalEntry
+	result := r.db.WithContext(ctx).
+		Preload("Postings", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Where("public_id = ?", publicID).
+		First(&entry)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &entry, nil
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *journalEntryRepository) WithTx(tx *gorm.DB) JournalEntryRepository {
+	return &journalEntryRepository{db: tx}
+}
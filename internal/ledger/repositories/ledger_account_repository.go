@@ -0,0 +1,132 @@
+// Package repositories define the data access layer for the double-entry ledger.
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrStaleBalance indica que o snapshot do saldo foi alterado por outra transação desde a leitura.
+var ErrStaleBalance = errors.New("ledger account balance was updated concurrently")
+
+type LedgerAccountRepository interface {
+	Create(ctx context.Context, account *models.LedgerAccount) error
+	FindByAccountID(ctx context.Context, accountID int64) (*models.LedgerAccount, error)
+	FindByCode(ctx context.Context, code string) (*models.LedgerAccount, error)
+	ListByIDs(ctx context.Context, ids []int64) ([]models.LedgerAccount, error)
+	LockByAccountIDs(ctx context.Context, accountIDs []int64) ([]models.LedgerAccount, error)
+	UpdateBalance(ctx context.Context, account *models.LedgerAccount, balance int64) error
+	SumPostings(ctx context.Context, ledgerAccountID int64) (int64, error)
+	WithTx(tx *gorm.DB) LedgerAccountRepository
+}
+
+type ledgerAccountRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerAccountRepository(db *gorm.DB) LedgerAccountRepository {
+	return &ledgerAccountRepository{db: db}
+}
+
+func (r *ledgerAccountRepository) Create(ctx context.Context, account *models.LedgerAccount) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Create(account).Error
+}
+
+func (r *ledgerAccountRepository) FindByAccountID(ctx context.Context, accountID int64) (*models.LedgerAccount, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var account models.LedgerAccount
+	result := r.db.WithContext(ctx).Where("account_id = ?", accountID).First(&account)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &account, nil
+}
+
+func (r *ledgerAccountRepository) FindByCode(ctx context.Context, code string) (*models.LedgerAccount, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var account models.LedgerAccount
+	result := r.db.WithContext(ctx).Where("code = ?", code).First(&account)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &account, nil
+}
+
+func (r *ledgerAccountRepository) ListByIDs(ctx context.Context, ids []int64) ([]models.LedgerAccount, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var accounts []models.LedgerAccount
+	result := r.db.WithContext(ctx).Where("id IN ?", ids).Order("id").Find(&accounts)
+	return accounts, result.Error
+}
+
+// LockByAccountIDs bloqueia (SELECT ... FOR UPDATE) as contas do razão das contas correntes
+// informadas até o fim da transação. As linhas são travadas sempre em ordem de id, para que duas
+// transferências em sentidos opostos entre as mesmas contas não entrem em deadlock. Deve ser usado
+// dentro de WithTx.
+func (r *ledgerAccountRepository) LockByAccountIDs(ctx context.Context, accountIDs []int64) ([]models.LedgerAccount, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var accounts []models.LedgerAccount
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("account_id IN ?", accountIDs).
+		Order("id").
+		Find(&accounts)
+	return accounts, result.Error
+}
+
+// UpdateBalance grava o novo snapshot do saldo se a versão lida ainda for a atual, incrementando-a.
+// Retorna ErrStaleBalance se outra transação atualizou a conta antes.
+func (r *ledgerAccountRepository) UpdateBalance(ctx context.Context, account *models.LedgerAccount, balance int64) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Model(&models.LedgerAccount{}).
+		Where("id = ? AND version = ?", account.ID, account.Version).
+		Updates(map[string]any{
+			"balance": balance,
+			"version": gorm.Expr("version + 1"),
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrStaleBalance
+	}
+
+	account.Balance = balance
+	account.Version++
+	return nil
+}
+
+// SumPostings soma todas as partidas da conta (débitos positivos, créditos negativos).
+func (r *ledgerAccountRepository) SumPostings(ctx context.Context, ledgerAccountID int64) (int64, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var total int64
+	result := r.db.WithContext(ctx).Model(&models.Posting{}).
+		Select("COALESCE(SUM(amount), 0)").
+		Where("ledger_account_id = ?", ledgerAccountID).
+		Scan(&total)
+	return total, result.Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *ledgerAccountRepository) WithTx(tx *gorm.DB) LedgerAccountRepository {
+	return &ledgerAccountRepository{db: tx}
+}
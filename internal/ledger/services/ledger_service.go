@@ -0,0 +1,179 @@
+// Package services define the business logic of the double-entry ledger.
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"slices"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLedgerAccountNotFound = errors.New("conta do razão não encontrada")
+	ErrInsufficientFunds     = errors.New("saldo insuficiente")
+	ErrConcurrentUpdate      = errors.New("o saldo foi alterado por outra operação, tente novamente")
+	ErrBalanceMismatch       = errors.New("o saldo da conta não confere com as partidas")
+	ErrInternalServer        = errors.New("ocorreu um erro inesperado")
+)
+
+// maxPostAttempts limita as novas tentativas de um lançamento que perdeu a corrida pelo snapshot
+// do saldo para outra transação.
+const maxPostAttempts = 3
+
+type LedgerService interface {
+	Post(ctx context.Context, entry *models.JournalEntry) error
+	Balance(ctx context.Context, accountID int64) (int64, error)
+	Reconcile(ctx context.Context, ledgerAccountID int64) error
+	WithTx(tx *gorm.DB) LedgerService
+}
+
+type ledgerService struct {
+	transactor  database.Transactor
+	accountRepo repositories.LedgerAccountRepository
+	entryRepo   repositories.JournalEntryRepository
+	tx          *gorm.DB
+}
+
+func NewLedgerService(
+	transactor database.Transactor,
+	accountRepo repositories.LedgerAccountRepository,
+	entryRepo repositories.JournalEntryRepository,
+) LedgerService {
+	return &ledgerService{transactor: transactor, accountRepo: accountRepo, entryRepo: entryRepo}
+}
+
+// Post grava o lançamento e atualiza o snapshot do saldo das contas envolvidas. Lançamentos
+// desbalanceados são recusados e contas de clientes não podem ficar negativas. Fora de WithTx,
+// o lançamento roda na própria transação e é repetido se outra transação alterar os saldos no
+// meio; dentro de WithTx, ErrConcurrentUpdate é devolvido para quem controla a transação.
+func (s *ledgerService) Post(ctx context.Context, entry *models.JournalEntry) error {
+	if err := entry.Validate(); err != nil {
+		return err
+	}
+
+	if s.tx != nil {
+		return s.postWithinTx(ctx, s.tx, entry)
+	}
+
+	for attempt := 1; attempt <= maxPostAttempts; attempt++ {
+		var postErr error
+		err := s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+			postErr = s.postWithinTx(ctx, tx, entry)
+			return postErr
+		})
+		if errors.Is(postErr, ErrConcurrentUpdate) {
+			continue
+		}
+		if postErr != nil {
+			return postErr
+		}
+		if err != nil {
+			log.Printf("Error committing journal entry: %v", err)
+			return ErrInternalServer
+		}
+		return nil
+	}
+
+	return ErrConcurrentUpdate
+}
+
+// postWithinTx atualiza os saldos antes de gravar o lançamento: se o snapshot estiver
+// desatualizado, nada foi inserido e a tentativa pode ser repetida do zero.
+func (s *ledgerService) postWithinTx(ctx context.Context, tx *gorm.DB, entry *models.JournalEntry) error {
+	totals := entry.TotalsByAccount()
+	ids := make([]int64, 0, len(totals))
+	for id := range totals {
+		ids = append(ids, id)
+	}
+	slices.Sort(ids)
+
+	accountRepo := s.accountRepo.WithTx(tx)
+	accounts, err := accountRepo.ListByIDs(ctx, ids)
+	if err != nil {
+		log.Printf("Error loading ledger accounts: %v", err)
+		return ErrInternalServer
+	}
+	if len(accounts) != len(ids) {
+		return ErrLedgerAccountNotFound
+	}
+
+	for i := range accounts {
+		account := &accounts[i]
+		balance := account.BalanceAfter(totals[account.ID])
+		if balance < 0 && !account.AllowNegative {
+			return ErrInsufficientFunds
+		}
+
+		if err := accountRepo.UpdateBalance(ctx, account, balance); err != nil {
+			if errors.Is(err, repositories.ErrStaleBalance) {
+				return ErrConcurrentUpdate
+			}
+			log.Printf("Error updating the balance of ledger account %d: %v", account.ID, err)
+			return ErrInternalServer
+		}
+	}
+
+	if err := s.entryRepo.WithTx(tx).Create(ctx, entry); err != nil {
+		log.Printf("Error creating journal entry: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+// Balance retorna o snapshot do saldo, em centavos, da conta corrente informada.
+func (s *ledgerService) Balance(ctx context.Context, accountID int64) (int64, error) {
+	account, err := s.repo().FindByAccountID(ctx, accountID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return 0, ErrLedgerAccountNotFound
+		}
+		log.Printf("Error finding ledger account of account %d: %v", accountID, err)
+		return 0, ErrInternalServer
+	}
+	return account.Balance, nil
+}
+
+// Reconcile compara o snapshot do saldo com o saldo derivado de todas as partidas da conta.
+func (s *ledgerService) Reconcile(ctx context.Context, ledgerAccountID int64) error {
+	accountRepo := s.repo()
+
+	accounts, err := accountRepo.ListByIDs(ctx, []int64{ledgerAccountID})
+	if err != nil {
+		log.Printf("Error loading ledger account %d: %v", ledgerAccountID, err)
+		return ErrInternalServer
+	}
+	if len(accounts) == 0 {
+		return ErrLedgerAccountNotFound
+	}
+
+	total, err := accountRepo.SumPostings(ctx, ledgerAccountID)
+	if err != nil {
+		log.Printf("Error summing postings of ledger account %d: %v", ledgerAccountID, err)
+		return ErrInternalServer
+	}
+
+	account := accounts[0]
+	if derived := account.BalanceFromPostings(total); derived != account.Balance {
+		log.Printf("Ledger account %d balance snapshot %d does not match the postings (%d)", account.ID, account.Balance, derived)
+		return ErrBalanceMismatch
+	}
+	return nil
+}
+
+// WithTx retorna uma cópia do serviço que executa suas operações na transação informada.
+func (s *ledgerService) WithTx(tx *gorm.DB) LedgerService {
+	return &ledgerService{transactor: s.transactor, accountRepo: s.accountRepo, entryRepo: s.entryRepo, tx: tx}
+}
+
+func (s *ledgerService) repo() repositories.LedgerAccountRepository {
+	if s.tx != nil {
+		return s.accountRepo.WithTx(s.tx)
+	}
+	return s.accountRepo
+}
@@ -0,0 +1,177 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/services"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func newLedgerService() (services.LedgerService, *mocks.MockTransactor, *mocks.MockLedgerAccountRepository, *mocks.MockJournalEntryRepository) {
+	transactor := new(mocks.MockTransactor)
+	accountRepo := new(mocks.MockLedgerAccountRepository)
+	entryRepo := new(mocks.MockJournalEntryRepository)
+	return services.NewLedgerService(transactor, accountRepo, entryRepo), transactor, accountRepo, entryRepo
+}
+
+func customerAccounts() []models.LedgerAccount {
+	return []models.LedgerAccount{
+		{ID: 1, NormalBalance: models.NormalCredit, Balance: 10000, Version: 3},
+		{ID: 2, NormalBalance: models.NormalCredit, Balance: 500, Version: 1},
+	}
+}
+
+func transferEntry(amount int64) *models.JournalEntry {
+	return &models.JournalEntry{
+		Kind:     models.EntryTransfer,
+		Postings: []models.Posting{models.Debit(1, amount), models.Credit(2, amount)},
+	}
+}
+
+func TestLedgerService_Post_Success(t *testing.T) {
+	service, transactor, accountRepo, entryRepo := newLedgerService()
+	entry := transferEntry(2500)
+
+	accountRepo.On("ListByIDs", []int64{1, 2}).Return(customerAccounts(), nil)
+	accountRepo.On("UpdateBalance", int64(1), int64(7500)).Return(nil)
+	accountRepo.On("UpdateBalance", int64(2), int64(3000)).Return(nil)
+	entryRepo.On("Create", entry).Return(nil)
+
+	err := service.Post(context.Background(), entry)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, transactor.Calls)
+	accountRepo.AssertExpectations(t)
+	entryRepo.AssertExpectations(t)
+}
+
+func TestLedgerService_Post_RejectsUnbalancedEntry(t *testing.T) {
+	service, transactor, accountRepo, entryRepo := newLedgerService()
+	entry := &models.JournalEntry{
+		Kind:     models.EntryTransfer,
+		Postings: []models.Posting{models.Debit(1, 2500), models.Credit(2, 2000)},
+	}
+
+	err := service.Post(context.Background(), entry)
+
+	assert.ErrorIs(t, err, models.ErrUnbalancedEntry)
+	assert.Equal(t, 0, transactor.Calls)
+	accountRepo.AssertNotCalled(t, "UpdateBalance", mock.Anything, mock.Anything)
+	entryRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestLedgerService_Post_InsufficientFunds(t *testing.T) {
+	service, _, accountRepo, entryRepo := newLedgerService()
+
+	accountRepo.On("ListByIDs", []int64{1, 2}).Return(customerAccounts(), nil)
+
+	err := service.Post(context.Background(), transferEntry(10001))
+
+	assert.ErrorIs(t, err, services.ErrInsufficientFunds)
+	entryRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestLedgerService_Post_AllowsNegativeSystemAccounts(t *testing.T) {
+	service, _, accountRepo, entryRepo := newLedgerService()
+	entry := &models.JournalEntry{
+		Kind:     models.EntryDeposit,
+		Postings: []models.Posting{models.Debit(1, 5000), models.Credit(9, 5000)},
+	}
+
+	accountRepo.On("ListByIDs", []int64{1, 9}).Return([]models.LedgerAccount{
+		{ID: 1, NormalBalance: models.NormalCredit, Balance: 10000},
+		{ID: 9, NormalBalance: models.NormalDebit, AllowNegative: true},
+	}, nil)
+	accountRepo.On("UpdateBalance", int64(1), int64(5000)).Return(nil)
+	accountRepo.On("UpdateBalance", int64(9), int64(-5000)).Return(nil)
+	entryRepo.On("Create", entry).Return(nil)
+
+	assert.NoError(t, service.Post(context.Background(), entry))
+}
+
+func TestLedgerService_Post_RetriesStaleBalances(t *testing.T) {
+	service, transactor, accountRepo, entryRepo := newLedgerService()
+	entry := transferEntry(2500)
+
+	accountRepo.On("ListByIDs", []int64{1, 2}).Return(customerAccounts(), nil)
+	accountRepo.On("UpdateBalance", int64(1), int64(7500)).Return(repositories.ErrStaleBalance).Once()
+	accountRepo.On("UpdateBalance", int64(1), int64(7500)).Return(nil).Once()
+	accountRepo.On("UpdateBalance", int64(2), int64(3000)).Return(nil).Once()
+	entryRepo.On("Create", entry).Return(nil).Once()
+
+	err := service.Post(context.Background(), entry)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, transactor.Calls)
+	entryRepo.AssertNumberOfCalls(t, "Create", 1)
+}
+
+func TestLedgerService_Post_GivesUpAfterRepeatedConflicts(t *testing.T) {
+	service, transactor, accountRepo, entryRepo := newLedgerService()
+
+	accountRepo.On("ListByIDs", []int64{1, 2}).Return(customerAccounts(), nil)
+	accountRepo.On("UpdateBalance", int64(1), int64(7500)).Return(repositories.ErrStaleBalance)
+
+	err := service.Post(context.Background(), transferEntry(2500))
+
+	assert.ErrorIs(t, err, services.ErrConcurrentUpdate)
+	assert.Equal(t, 3, transactor.Calls)
+	entryRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestLedgerService_Post_WithinCallerTransaction(t *testing.T) {
+	service, transactor, accountRepo, _ := newLedgerService()
+
+	accountRepo.On("ListByIDs", []int64{1, 2}).Return(customerAccounts(), nil)
+	accountRepo.On("UpdateBalance", int64(1), int64(7500)).Return(repositories.ErrStaleBalance)
+
+	err := service.WithTx(&gorm.DB{}).Post(context.Background(), transferEntry(2500))
+
+	assert.ErrorIs(t, err, services.ErrConcurrentUpdate, "the caller owns the transaction and decides whether to retry")
+	assert.Equal(t, 0, transactor.Calls)
+	accountRepo.AssertNumberOfCalls(t, "UpdateBalance", 1)
+}
+
+func TestLedgerService_Post_UnknownAccount(t *testing.T) {
+	service, _, accountRepo, _ := newLedgerService()
+
+	accountRepo.On("ListByIDs", []int64{1, 2}).Return(customerAccounts()[:1], nil)
+
+	err := service.Post(context.Background(), transferEntry(100))
+
+	assert.ErrorIs(t, err, services.ErrLedgerAccountNotFound)
+}
+
+func TestLedgerService_Balance(t *testing.T) {
+	service, _, accountRepo, _ := newLedgerService()
+
+	accountRepo.On("FindByAccountID", int64(7)).Return(&models.LedgerAccount{Balance: 4200}, nil)
+	accountRepo.On("FindByAccountID", int64(8)).Return(nil, gorm.ErrRecordNotFound)
+
+	balance, err := service.Balance(context.Background(), 7)
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4200), balance)
+
+	_, err = service.Balance(context.Background(), 8)
+	assert.ErrorIs(t, err, services.ErrLedgerAccountNotFound)
+}
+
+func TestLedgerService_Reconcile(t *testing.T) {
+	service, _, accountRepo, _ := newLedgerService()
+
+	accountRepo.On("ListByIDs", []int64{1}).Return([]models.LedgerAccount{{ID: 1, NormalBalance: models.NormalCredit, Balance: 10000}}, nil)
+	accountRepo.On("SumPostings", int64(1)).Return(int64(-10000), nil).Once()
+	accountRepo.On("SumPostings", int64(1)).Return(int64(-9000), nil).Once()
+	accountRepo.On("SumPostings", int64(1)).Return(int64(0), errors.New("db error")).Once()
+
+	assert.NoError(t, service.Reconcile(context.Background(), 1))
+	assert.ErrorIs(t, service.Reconcile(context.Background(), 1), services.ErrBalanceMismatch)
+	assert.ErrorIs(t, service.Reconcile(context.Background(), 1), services.ErrInternalServer)
+}
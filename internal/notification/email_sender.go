@@ -1,76 +1,61 @@
-// Package notification provides functionality for sending notifications, specifically via email using the Resend service.
+// Package notification provides functionality for sending notifications via e-mail and SMS.
+// The e-mail transport is chosen by configuration: Resend in production, SMTP, a local .eml
+// file sink for development, or an in-memory outbox for tests. SMS messages currently go
+// through a local stub that only logs them.
 package notification
 
 import (
-	"bytes"
+	"context"
 	"fmt"
-	"log"
 	"os"
-	"text/template"
+	"strings"
+	"time"
+)
+
+// SendTimeout limita o envio de e-mails feitos em background, que não herdam o prazo da requisição HTTP.
+const SendTimeout = 30 * time.Second
 
-	"github.com/resend/resend-go/v2"
+const (
+	TransportResend = "resend"
+	TransportSMTP   = "smtp"
+	TransportFile   = "file"
+	TransportMemory = "memory"
 )
 
+// EmailRequest é serializado em JSON no outbox, por isso TemplateData deve conter apenas
+// campos exportados; após a desserialização ele chega ao template como um map.
+// O assunto vem do template, no idioma de Locale (vazio usa o idioma padrão).
 type EmailRequest struct {
-	From         string
-	To           string
-	Subject      string
-	TemplatePath string
-	TemplateData any
+	From         string     `json:"from"`
+	To           string     `json:"to"`
+	TemplateID   TemplateID `json:"templateId"`
+	Locale       string     `json:"locale,omitempty"`
+	TemplateData any        `json:"templateData"`
 }
 
 type EmailService interface {
-	SendEmail(request *EmailRequest) error
+	SendEmail(ctx context.Context, request *EmailRequest) error
 }
 
-type resendEmailService struct {
-	client *resend.Client
-}
-
-func NewEmailService() (EmailService, error) {
-	apiKey := os.Getenv("RESEND_API_KEY")
-	if apiKey == "" {
-		return nil, fmt.Errorf("RESEND_API_KEY environment variable not set")
+// NewEmailService cria o transporte definido em EMAIL_TRANSPORT. Sem configuração, usa o Resend.
+func NewEmailService(templates *TemplateRegistry) (EmailService, error) {
+	transport := strings.ToLower(strings.TrimSpace(os.Getenv("EMAIL_TRANSPORT")))
+
+	switch transport {
+	case "", TransportResend:
+		return NewResendEmailService(os.Getenv("RESEND_API_KEY"), templates)
+	case TransportSMTP:
+		return NewSMTPEmailServiceFromEnv(templates)
+	case TransportFile:
+		return NewFileEmailService(os.Getenv("EMAIL_OUTPUT_DIR"), templates)
+	case TransportMemory:
+		return NewInMemoryEmailService(templates), nil
+	default:
+		return nil, fmt.Errorf("unknown EMAIL_TRANSPORT %q", transport)
 	}
-
-	return &resendEmailService{
-		client: resend.NewClient(apiKey),
-	}, nil
 }
 
-func (s *resendEmailService) SendEmail(request *EmailRequest) error {
-	body, err := s.buildEmailContent(request.TemplatePath, request.TemplateData)
-	if err != nil {
-		return fmt.Errorf("failed to build email content: %w", err)
-	}
-
-	params := &resend.SendEmailRequest{
-		From:    request.From,
-		To:      []string{request.To},
-		Subject: request.Subject,
-		Html:    body,
-	}
-
-	sent, err := s.client.Emails.Send(params)
-	if err != nil {
-		return fmt.Errorf("failed to send email: %w", err)
-	}
-
-	log.Printf("Verification email sent successfully to %s. Message ID: %s", params.To, sent.Id)
-	return nil
-}
-
-func (s *resendEmailService) buildEmailContent(templatePath string, templateData any) (string, error) {
-	mailTemplate, err := template.ParseFiles(templatePath)
-	if err != nil {
-		return "", fmt.Errorf("failed to parse email template: %w", err)
-	}
-
-	var body bytes.Buffer
-	err = mailTemplate.Execute(&body, templateData)
-	if err != nil {
-		return "", fmt.Errorf("failed to execute email template: %w", err)
-	}
-
-	return body.String(), nil
+// renderEmail executa o template do pedido no registro informado.
+func renderEmail(templates *TemplateRegistry, request *EmailRequest) (*RenderedEmail, error) {
+	return templates.Render(request.TemplateID, request.Locale, request.TemplateData)
 }
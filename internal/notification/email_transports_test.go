@@ -0,0 +1,184 @@
+package notification_test
+
+import (
+	"bufio"
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const welcomeTemplate notification.TemplateID = "welcome"
+
+func newRegistry(t *testing.T) *notification.TemplateRegistry {
+	registry, err := notification.NewTemplateRegistry(fstest.MapFS{
+		"pt-BR/welcome.html": {Data: []byte("<p>Olá, {{.FullName}}!</p>")},
+		"pt-BR/welcome.txt":  {Data: []byte(`{{define "subject"}}Bem-vindo ao GoBank{{end -}}` + "\nOlá, {{.FullName}}!")},
+	})
+	require.NoError(t, err)
+	return registry
+}
+
+func newRequest() *notification.EmailRequest {
+	return &notification.EmailRequest{
+		From:         "GoBank <no-reply@gobank.test>",
+		To:           "john@example.com",
+		TemplateID:   welcomeTemplate,
+		TemplateData: map[string]string{"FullName": "John Doe"},
+	}
+}
+
+func TestNewEmailService_SelectsTransport(t *testing.T) {
+	t.Run("Should default to Resend and require its API key", func(t *testing.T) {
+		t.Setenv("EMAIL_TRANSPORT", "")
+		t.Setenv("RESEND_API_KEY", "")
+
+		_, err := notification.NewEmailService(newRegistry(t))
+
+		assert.Error(t, err)
+	})
+
+	t.Run("Should build the in-memory outbox", func(t *testing.T) {
+		t.Setenv("EMAIL_TRANSPORT", "memory")
+
+		service, err := notification.NewEmailService(newRegistry(t))
+
+		require.NoError(t, err)
+		assert.IsType(t, &notification.InMemoryEmailService{}, service)
+	})
+
+	t.Run("Should reject unknown transports", func(t *testing.T) {
+		t.Setenv("EMAIL_TRANSPORT", "carrier-pigeon")
+
+		_, err := notification.NewEmailService(newRegistry(t))
+
+		assert.Error(t, err)
+	})
+}
+
+func TestInMemoryEmailService(t *testing.T) {
+	service := notification.NewInMemoryEmailService(newRegistry(t))
+
+	require.NoError(t, service.SendEmail(context.Background(), newRequest()))
+
+	emails := service.SentTo("john@example.com")
+	require.Len(t, emails, 1)
+	assert.Equal(t, "Bem-vindo ao GoBank", emails[0].Subject)
+	assert.Equal(t, welcomeTemplate, emails[0].TemplateID)
+	assert.Equal(t, "<p>Olá, John Doe!</p>", emails[0].HTML)
+	assert.Equal(t, "Olá, John Doe!", emails[0].Text)
+	assert.Empty(t, service.SentTo("other@example.com"))
+
+	service.Reset()
+	assert.Empty(t, service.Emails())
+}
+
+func TestFileEmailService_WritesEMLFiles(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "emails")
+	service, err := notification.NewFileEmailService(dir, newRegistry(t))
+	require.NoError(t, err)
+
+	require.NoError(t, service.SendEmail(context.Background(), newRequest()))
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.eml"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	content, err := os.ReadFile(files[0])
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "To: john@example.com\r\n")
+	assert.Contains(t, string(content), "Subject: Bem-vindo ao GoBank\r\n")
+	assert.Contains(t, string(content), "Content-Type: multipart/alternative;")
+	assert.Contains(t, string(content), "Content-Type: text/plain; charset=UTF-8")
+	assert.Contains(t, string(content), "Content-Type: text/html; charset=UTF-8")
+	assert.Contains(t, string(content), "<p>Ol=C3=A1, John Doe!</p>")
+}
+
+// fakeSMTPServer atende uma única conexão SMTP, sem TLS nem autenticação, e devolve os dados recebidos.
+func fakeSMTPServer(t *testing.T) (string, <-chan string) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	received := make(chan string, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		reader := bufio.NewReader(conn)
+		reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+
+		reply("220 localhost ESMTP")
+		var transcript strings.Builder
+		inData := false
+		for {
+			line, err := reader.ReadString('\n')
+			if err != nil {
+				return
+			}
+			if inData {
+				if line == ".\r\n" {
+					inData = false
+					reply("250 OK")
+					continue
+				}
+				transcript.WriteString(line)
+				continue
+			}
+
+			command := strings.ToUpper(strings.TrimSpace(line))
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				reply("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM"), strings.HasPrefix(command, "RCPT TO"):
+				transcript.WriteString(line)
+				reply("250 OK")
+			case command == "DATA":
+				inData = true
+				reply("354 End data with <CR><LF>.<CR><LF>")
+			case command == "QUIT":
+				reply("221 Bye")
+				received <- transcript.String()
+				return
+			default:
+				reply("502 Command not implemented")
+			}
+		}
+	}()
+
+	return listener.Addr().String(), received
+}
+
+func TestSMTPEmailService_DeliversMessage(t *testing.T) {
+	addr, received := fakeSMTPServer(t)
+	host, port, err := net.SplitHostPort(addr)
+	require.NoError(t, err)
+
+	service, err := notification.NewSMTPEmailService(host, port, "", "", newRegistry(t))
+	require.NoError(t, err)
+
+	request := newRequest()
+	request.From = "no-reply@gobank.test"
+	require.NoError(t, service.SendEmail(context.Background(), request))
+
+	transcript := <-received
+	assert.Contains(t, transcript, "MAIL FROM:<no-reply@gobank.test>")
+	assert.Contains(t, transcript, "RCPT TO:<john@example.com>")
+	assert.Contains(t, transcript, "Ol=C3=A1, John Doe!")
+}
+
+func TestSMTPEmailService_RequiresHost(t *testing.T) {
+	_, err := notification.NewSMTPEmailService("", "", "", "", nil)
+
+	assert.Error(t, err)
+}
@@ -0,0 +1,58 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+const defaultEmailOutputDir = "tmp/emails"
+
+// fileEmailService grava cada e-mail como um arquivo .eml, que pode ser aberto em qualquer
+// cliente de e-mail. Pensado para desenvolvimento local, sem conta no provedor.
+type fileEmailService struct {
+	dir       string
+	templates *TemplateRegistry
+}
+
+func NewFileEmailService(dir string, templates *TemplateRegistry) (EmailService, error) {
+	if dir == "" {
+		dir = defaultEmailOutputDir
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create email output directory: %w", err)
+	}
+
+	return &fileEmailService{dir: dir, templates: templates}, nil
+}
+
+func (s *fileEmailService) SendEmail(ctx context.Context, request *EmailRequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	email, err := renderEmail(s.templates, request)
+	if err != nil {
+		return fmt.Errorf("failed to build email content: %w", err)
+	}
+
+	now := time.Now()
+	message, err := buildMIMEMessage(request.From, request.To, email, now)
+	if err != nil {
+		return fmt.Errorf("failed to build email message: %w", err)
+	}
+
+	path := filepath.Join(s.dir, fmt.Sprintf("%s-%s.eml", now.Format("20060102T150405"), ulid.Make().String()))
+	if err := os.WriteFile(path, message, 0o600); err != nil {
+		return fmt.Errorf("failed to write email file: %w", err)
+	}
+
+	log.Printf("Email to %s written to %s", request.To, path)
+	return nil
+}
@@ -0,0 +1,86 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// SentEmail é um e-mail já renderizado, guardado pelo InMemoryEmailService.
+type SentEmail struct {
+	From       string
+	To         string
+	TemplateID TemplateID
+	Subject    string
+	HTML       string
+	Text       string
+	SentAt     time.Time
+}
+
+// InMemoryEmailService guarda os e-mails em memória para que os testes possam inspecioná-los.
+// É seguro para uso concorrente.
+type InMemoryEmailService struct {
+	templates *TemplateRegistry
+
+	mu     sync.Mutex
+	emails []SentEmail
+}
+
+func NewInMemoryEmailService(templates *TemplateRegistry) *InMemoryEmailService {
+	return &InMemoryEmailService{templates: templates}
+}
+
+func (s *InMemoryEmailService) SendEmail(ctx context.Context, request *EmailRequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	email, err := renderEmail(s.templates, request)
+	if err != nil {
+		return fmt.Errorf("failed to build email content: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.emails = append(s.emails, SentEmail{
+		From:       request.From,
+		To:         request.To,
+		TemplateID: request.TemplateID,
+		Subject:    email.Subject,
+		HTML:       email.HTML,
+		Text:       email.Text,
+		SentAt:     time.Now(),
+	})
+	return nil
+}
+
+// Emails devolve uma cópia dos e-mails enviados até agora, na ordem de envio.
+func (s *InMemoryEmailService) Emails() []SentEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]SentEmail(nil), s.emails...)
+}
+
+// SentTo devolve os e-mails enviados para o destinatário informado.
+func (s *InMemoryEmailService) SentTo(to string) []SentEmail {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var emails []SentEmail
+	for _, email := range s.emails {
+		if email.To == to {
+			emails = append(emails, email)
+		}
+	}
+	return emails
+}
+
+func (s *InMemoryEmailService) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.emails = nil
+}
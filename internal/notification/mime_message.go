@@ -0,0 +1,59 @@
+package notification
+
+import (
+	"bytes"
+	"fmt"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/textproto"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// buildMIMEMessage monta a mensagem no formato RFC 5322 usada pelo SMTP e pelo sink de arquivos,
+// com o texto puro e o HTML como partes de um multipart/alternative.
+func buildMIMEMessage(from, to string, email *RenderedEmail, date time.Time) ([]byte, error) {
+	var body bytes.Buffer
+	parts := multipart.NewWriter(&body)
+
+	if err := writeQuotedPrintablePart(parts, "text/plain; charset=UTF-8", email.Text); err != nil {
+		return nil, err
+	}
+	if err := writeQuotedPrintablePart(parts, "text/html; charset=UTF-8", email.HTML); err != nil {
+		return nil, err
+	}
+	if err := parts.Close(); err != nil {
+		return nil, err
+	}
+
+	var message bytes.Buffer
+	fmt.Fprintf(&message, "From: %s\r\n", from)
+	fmt.Fprintf(&message, "To: %s\r\n", to)
+	fmt.Fprintf(&message, "Subject: %s\r\n", mime.QEncoding.Encode("UTF-8", email.Subject))
+	fmt.Fprintf(&message, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	fmt.Fprintf(&message, "Message-ID: <%s@go-bank-api>\r\n", ulid.Make().String())
+	message.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&message, "Content-Type: multipart/alternative; boundary=%q\r\n", parts.Boundary())
+	message.WriteString("\r\n")
+	message.Write(body.Bytes())
+
+	return message.Bytes(), nil
+}
+
+func writeQuotedPrintablePart(parts *multipart.Writer, contentType, content string) error {
+	part, err := parts.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {contentType},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return err
+	}
+
+	writer := quotedprintable.NewWriter(part)
+	if _, err := writer.Write([]byte(content)); err != nil {
+		return err
+	}
+	return writer.Close()
+}
@@ -0,0 +1,49 @@
+package notification
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/resend/resend-go/v2"
+)
+
+type resendEmailService struct {
+	client    *resend.Client
+	templates *TemplateRegistry
+}
+
+func NewResendEmailService(apiKey string, templates *TemplateRegistry) (EmailService, error) {
+	if apiKey == "" {
+		return nil, errors.New("RESEND_API_KEY environment variable not set")
+	}
+
+	return &resendEmailService{
+		client:    resend.NewClient(apiKey),
+		templates: templates,
+	}, nil
+}
+
+func (s *resendEmailService) SendEmail(ctx context.Context, request *EmailRequest) error {
+	email, err := renderEmail(s.templates, request)
+	if err != nil {
+		return fmt.Errorf("failed to build email content: %w", err)
+	}
+
+	params := &resend.SendEmailRequest{
+		From:    request.From,
+		To:      []string{request.To},
+		Subject: email.Subject,
+		Html:    email.HTML,
+		Text:    email.Text,
+	}
+
+	sent, err := s.client.Emails.SendWithContext(ctx, params)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	log.Printf("Email sent successfully to %s. Message ID: %s", params.To, sent.Id)
+	return nil
+}
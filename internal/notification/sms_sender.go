@@ -0,0 +1,78 @@
+package notification
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// SMSChannel é o canal usado para entregar a mensagem ao celular.
+type SMSChannel string
+
+const (
+	ChannelSMS      SMSChannel = "sms"
+	ChannelWhatsApp SMSChannel = "whatsapp"
+)
+
+// SMSRequest é uma mensagem de texto curta para um celular no formato E.164.
+type SMSRequest struct {
+	To      string
+	Body    string
+	Channel SMSChannel
+}
+
+type SMSSender interface {
+	SendSMS(ctx context.Context, request *SMSRequest) error
+}
+
+// SentSMS é uma mensagem registrada pelo StubSMSSender.
+type SentSMS struct {
+	To      string
+	Body    string
+	Channel SMSChannel
+	SentAt  time.Time
+}
+
+// StubSMSSender não fala com nenhum provedor: escreve a mensagem no log, para uso local,
+// e a guarda em memória para que os testes possam inspecioná-la. É seguro para uso concorrente.
+type StubSMSSender struct {
+	mu       sync.Mutex
+	messages []SentSMS
+}
+
+func NewStubSMSSender() *StubSMSSender {
+	return &StubSMSSender{}
+}
+
+func (s *StubSMSSender) SendSMS(ctx context.Context, request *SMSRequest) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	channel := request.Channel
+	if channel == "" {
+		channel = ChannelSMS
+	}
+
+	log.Printf("[sms-stub] %s to %s: %s", channel, request.To, request.Body)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages = append(s.messages, SentSMS{
+		To:      request.To,
+		Body:    request.Body,
+		Channel: channel,
+		SentAt:  time.Now(),
+	})
+	return nil
+}
+
+// Messages devolve uma cópia das mensagens enviadas até agora, na ordem de envio.
+func (s *StubSMSSender) Messages() []SentSMS {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]SentSMS(nil), s.messages...)
+}
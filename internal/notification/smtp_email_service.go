@@ -0,0 +1,125 @@
+package notification
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/smtp"
+	"os"
+	"time"
+)
+
+const defaultSMTPPort = "587"
+
+type smtpEmailService struct {
+	host      string
+	addr      string
+	username  string
+	password  string
+	templates *TemplateRegistry
+}
+
+// NewSMTPEmailService envia pelo servidor informado. Sem usuário, o envio é feito sem autenticação,
+// como em servidores locais no estilo MailHog.
+func NewSMTPEmailService(host, port, username, password string, templates *TemplateRegistry) (EmailService, error) {
+	if host == "" {
+		return nil, errors.New("SMTP_HOST environment variable not set")
+	}
+	if port == "" {
+		port = defaultSMTPPort
+	}
+
+	return &smtpEmailService{
+		host:      host,
+		addr:      net.JoinHostPort(host, port),
+		username:  username,
+		password:  password,
+		templates: templates,
+	}, nil
+}
+
+func NewSMTPEmailServiceFromEnv(templates *TemplateRegistry) (EmailService, error) {
+	return NewSMTPEmailService(
+		os.Getenv("SMTP_HOST"),
+		os.Getenv("SMTP_PORT"),
+		os.Getenv("SMTP_USERNAME"),
+		os.Getenv("SMTP_PASSWORD"),
+		templates,
+	)
+}
+
+func (s *smtpEmailService) SendEmail(ctx context.Context, request *EmailRequest) error {
+	email, err := renderEmail(s.templates, request)
+	if err != nil {
+		return fmt.Errorf("failed to build email content: %w", err)
+	}
+
+	message, err := buildMIMEMessage(request.From, request.To, email, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to build email message: %w", err)
+	}
+
+	if err := s.deliver(ctx, request.From, request.To, message); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	log.Printf("Email sent successfully to %s via SMTP", request.To)
+	return nil
+}
+
+// deliver faz a conversa SMTP respeitando o prazo de ctx, que o net/smtp não conhece.
+func (s *smtpEmailService) deliver(ctx context.Context, from, to string, message []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", s.addr)
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, s.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: s.host}); err != nil {
+			return err
+		}
+	}
+
+	if s.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.username, s.password, s.host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := writer.Write(message); err != nil {
+		return err
+	}
+	if err := writer.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
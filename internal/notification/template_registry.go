@@ -0,0 +1,171 @@
+package notification
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"io/fs"
+	"path"
+	"strings"
+	texttemplate "text/template"
+)
+
+// TemplateID identifica um e-mail, independentemente do idioma.
+type TemplateID string
+
+const (
+	TemplateVerificationEmail  TemplateID = "verification_email"
+	TemplatePasswordReset      TemplateID = "password_reset"
+	TemplateOnboardingApproved TemplateID = "onboarding_approved"
+	TemplateOnboardingRejected TemplateID = "onboarding_rejected"
+	TemplateTransferSent       TemplateID = "transfer_sent"
+	TemplateTransferReceived   TemplateID = "transfer_received"
+	TemplatePixKeyConfirmation TemplateID = "pix_key_confirmation"
+	TemplatePixClaimReceived   TemplateID = "pix_claim_received"
+)
+
+const (
+	LocalePtBR    = "pt-BR"
+	LocaleEn      = "en"
+	DefaultLocale = LocalePtBR
+)
+
+// supportedLocales são os idiomas com templates em templates/emails.
+var supportedLocales = []string{LocalePtBR, LocaleEn}
+
+// subjectTemplateName é o bloco definido no template de texto com o assunto do e-mail.
+const subjectTemplateName = "subject"
+
+var ErrTemplateNotFound = errors.New("email template not found")
+
+// RenderedEmail é o conteúdo final de um e-mail: o HTML e sua alternativa em texto puro.
+type RenderedEmail struct {
+	Subject string
+	HTML    string
+	Text    string
+}
+
+type templateKey struct {
+	id     TemplateID
+	locale string
+}
+
+type emailTemplate struct {
+	html *htmltemplate.Template
+	text *texttemplate.Template
+}
+
+// TemplateRegistry guarda os templates de e-mail já compilados, indexados por ID e idioma.
+// O HTML usa html/template, que escapa os dados inseridos; o texto puro e o assunto
+// ficam no arquivo .txt de mesmo nome.
+type TemplateRegistry struct {
+	templates map[templateKey]emailTemplate
+}
+
+// NewTemplateRegistry compila todos os templates de fsys, organizados em <locale>/<id>.html
+// e <locale>/<id>.txt. Todo template precisa existir no idioma padrão.
+func NewTemplateRegistry(fsys fs.FS) (*TemplateRegistry, error) {
+	htmlFiles, err := fs.Glob(fsys, "*/*.html")
+	if err != nil {
+		return nil, err
+	}
+
+	registry := &TemplateRegistry{templates: make(map[templateKey]emailTemplate)}
+	for _, htmlFile := range htmlFiles {
+		key := templateKey{
+			id:     TemplateID(strings.TrimSuffix(path.Base(htmlFile), ".html")),
+			locale: path.Dir(htmlFile),
+		}
+
+		htmlTemplate, err := htmltemplate.ParseFS(fsys, htmlFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse email template %s: %w", htmlFile, err)
+		}
+
+		textFile := strings.TrimSuffix(htmlFile, ".html") + ".txt"
+		textTemplate, err := texttemplate.ParseFS(fsys, textFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse plaintext email template %s: %w", textFile, err)
+		}
+		if textTemplate.Lookup(subjectTemplateName) == nil {
+			return nil, fmt.Errorf("plaintext email template %s does not define a %q block", textFile, subjectTemplateName)
+		}
+
+		registry.templates[key] = emailTemplate{
+			html: htmlTemplate.Option("missingkey=error"),
+			text: textTemplate.Option("missingkey=error"),
+		}
+	}
+
+	for key := range registry.templates {
+		if _, ok := registry.templates[templateKey{id: key.id, locale: DefaultLocale}]; !ok {
+			return nil, fmt.Errorf("email template %s has no %s version", key.id, DefaultLocale)
+		}
+	}
+
+	return registry, nil
+}
+
+// Render executa o template no idioma pedido, caindo para o idioma padrão quando ele não existe.
+func (r *TemplateRegistry) Render(id TemplateID, locale string, data any) (*RenderedEmail, error) {
+	tmpl, ok := r.templates[templateKey{id: id, locale: r.resolveLocale(id, locale)}]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrTemplateNotFound, id)
+	}
+
+	var subject, text, html bytes.Buffer
+	if err := tmpl.text.ExecuteTemplate(&subject, subjectTemplateName, data); err != nil {
+		return nil, fmt.Errorf("failed to execute email subject: %w", err)
+	}
+	if err := tmpl.text.Execute(&text, data); err != nil {
+		return nil, fmt.Errorf("failed to execute plaintext email template: %w", err)
+	}
+	if err := tmpl.html.Execute(&html, data); err != nil {
+		return nil, fmt.Errorf("failed to execute email template: %w", err)
+	}
+
+	return &RenderedEmail{
+		Subject: strings.TrimSpace(subject.String()),
+		HTML:    html.String(),
+		Text:    text.String(),
+	}, nil
+}
+
+// LocaleFromAcceptLanguage escolhe, na ordem do header Accept-Language, o primeiro idioma com
+// templates de e-mail ("en-US" vira en, "pt" vira pt-BR). Sem nenhum suportado, usa o padrão.
+// O resultado é gravado com o cliente e acompanha os e-mails enviados a ele.
+func LocaleFromAcceptLanguage(header string) string {
+	for _, tag := range strings.Split(header, ",") {
+		tag, _, _ = strings.Cut(tag, ";")
+		language, _, _ := strings.Cut(strings.TrimSpace(tag), "-")
+
+		for _, locale := range supportedLocales {
+			localeLanguage, _, _ := strings.Cut(locale, "-")
+			if strings.EqualFold(language, localeLanguage) {
+				return locale
+			}
+		}
+	}
+	return DefaultLocale
+}
+
+// resolveLocale aceita variações como "pt-br", "pt" ou "en-US" e devolve o idioma padrão
+// quando não há versão do template no idioma pedido.
+func (r *TemplateRegistry) resolveLocale(id TemplateID, locale string) string {
+	language, _, _ := strings.Cut(locale, "-")
+
+	for key := range r.templates {
+		if key.id == id && strings.EqualFold(key.locale, locale) {
+			return key.locale
+		}
+	}
+	for key := range r.templates {
+		keyLanguage, _, _ := strings.Cut(key.locale, "-")
+		if key.id == id && language != "" && strings.EqualFold(keyLanguage, language) {
+			return key.locale
+		}
+	}
+
+	return DefaultLocale
+}
@@ -0,0 +1,137 @@
+package notification_test
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/templates"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplateRegistry_EmbeddedTemplates(t *testing.T) {
+	registry, err := notification.NewTemplateRegistry(templates.Emails())
+	require.NoError(t, err)
+
+	tests := []struct {
+		id   notification.TemplateID
+		data map[string]string
+	}{
+		{notification.TemplateVerificationEmail, map[string]string{"FullName": "John Doe", "VerificationLink": "https://gobank.test/verify?token=abc"}},
+		{notification.TemplatePasswordReset, map[string]string{"FullName": "John Doe", "ResetLink": "https://gobank.test/reset-password?token=abc"}},
+		{notification.TemplateOnboardingApproved, map[string]string{"FullName": "John Doe", "CompletionLink": "https://gobank.test/complete-onboarding?token=abc"}},
+		{notification.TemplateOnboardingRejected, map[string]string{"FullName": "John Doe", "Reason": "documento ilegível"}},
+		{notification.TemplateTransferSent, map[string]string{"FullName": "John Doe", "Amount": "R$ 1.234,56", "CounterpartyName": "Jane Roe", "CounterpartyAccount": "0001 / 12250400-5", "Description": "aluguel", "TransferID": "01J0000000000000000000000", "Date": "18/10/2026 10:30"}},
+		{notification.TemplateTransferReceived, map[string]string{"FullName": "John Doe", "Amount": "R$ 1.234,56", "CounterpartyName": "Jane Roe", "CounterpartyAccount": "0001 / 12250400-5", "Description": "aluguel", "TransferID": "01J0000000000000000000000", "Date": "18/10/2026 10:30"}},
+		{notification.TemplatePixKeyConfirmation, map[string]string{"FullName": "John Doe", "Key": "john@example.com", "Code": "123456", "Minutes": "10"}},
+		{notification.TemplatePixClaimReceived, map[string]string{"FullName": "John Doe", "Key": "john@example.com", "ClaimType": "OWNERSHIP", "Deadline": "25/10/2026 10:30"}},
+	}
+
+	for _, tt := range tests {
+		for _, locale := range []string{notification.LocalePtBR, notification.LocaleEn} {
+			t.Run(string(tt.id)+"/"+locale, func(t *testing.T) {
+				email, err := registry.Render(tt.id, locale, tt.data)
+
+				require.NoError(t, err)
+				assert.NotEmpty(t, email.Subject)
+				assert.Contains(t, email.HTML, "John Doe")
+				assert.Contains(t, email.Text, "John Doe")
+				assert.NotContains(t, email.Text, "<")
+			})
+		}
+	}
+}
+
+func TestLocaleFromAcceptLanguage(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected string
+	}{
+		{"", notification.LocalePtBR},
+		{"pt-BR,pt;q=0.9,en;q=0.8", notification.LocalePtBR},
+		{"en-US,en;q=0.9", notification.LocaleEn},
+		{"EN", notification.LocaleEn},
+		{"fr-FR, en;q=0.5", notification.LocaleEn},
+		{"fr-FR,de;q=0.8", notification.LocalePtBR},
+		{"pt-PT", notification.LocalePtBR},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			assert.Equal(t, tt.expected, notification.LocaleFromAcceptLanguage(tt.header))
+		})
+	}
+}
+
+func TestTemplateRegistry_Render(t *testing.T) {
+	registry, err := notification.NewTemplateRegistry(fstest.MapFS{
+		"pt-BR/greeting.html": {Data: []byte("<p>Olá, {{.Name}}</p>")},
+		"pt-BR/greeting.txt":  {Data: []byte(`{{define "subject"}}Olá{{end -}}` + "\nOlá, {{.Name}}")},
+		"en/greeting.html":    {Data: []byte("<p>Hi, {{.Name}}</p>")},
+		"en/greeting.txt":     {Data: []byte(`{{define "subject"}}Hi{{end -}}` + "\nHi, {{.Name}}")},
+	})
+	require.NoError(t, err)
+
+	t.Run("Should escape data in the HTML body", func(t *testing.T) {
+		email, err := registry.Render("greeting", "pt-BR", map[string]string{"Name": "<script>alert(1)</script>"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "<p>Olá, &lt;script&gt;alert(1)&lt;/script&gt;</p>", email.HTML)
+		assert.Equal(t, "Olá, <script>alert(1)</script>", email.Text)
+	})
+
+	t.Run("Should match locale variants", func(t *testing.T) {
+		email, err := registry.Render("greeting", "en-US", map[string]string{"Name": "John"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Hi", email.Subject)
+		assert.Equal(t, "<p>Hi, John</p>", email.HTML)
+	})
+
+	t.Run("Should fall back to the default locale", func(t *testing.T) {
+		email, err := registry.Render("greeting", "es", map[string]string{"Name": "Juan"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "Olá", email.Subject)
+	})
+
+	t.Run("Should fail on unknown templates", func(t *testing.T) {
+		_, err := registry.Render("unknown", "pt-BR", nil)
+
+		assert.ErrorIs(t, err, notification.ErrTemplateNotFound)
+	})
+
+	t.Run("Should fail on missing data", func(t *testing.T) {
+		_, err := registry.Render("greeting", "pt-BR", map[string]string{})
+
+		assert.Error(t, err)
+	})
+}
+
+func TestNewTemplateRegistry_Validation(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+	}{
+		{"Missing plaintext body", fstest.MapFS{
+			"pt-BR/greeting.html": {Data: []byte("<p>Olá</p>")},
+		}},
+		{"Missing subject", fstest.MapFS{
+			"pt-BR/greeting.html": {Data: []byte("<p>Olá</p>")},
+			"pt-BR/greeting.txt":  {Data: []byte("Olá")},
+		}},
+		{"Missing default locale", fstest.MapFS{
+			"en/greeting.html": {Data: []byte("<p>Hi</p>")},
+			"en/greeting.txt":  {Data: []byte(`{{define "subject"}}Hi{{end}}Hi`)},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := notification.NewTemplateRegistry(tt.files)
+
+			assert.Error(t, err)
+		})
+	}
+}
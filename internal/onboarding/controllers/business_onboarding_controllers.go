@@ -0,0 +1,146 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/http_helpers"
+)
+
+type StartBusinessOnboardingRequest struct {
+	CNPJ            string         `json:"cnpj" binding:"required,alphanum,len=14"`
+	LegalName       string         `json:"legalName" binding:"required"`
+	TradeName       string         `json:"tradeName"`
+	Email           string         `json:"email" binding:"required,email"`
+	Phone           string         `json:"phone" binding:"required,e164"`
+	Address         AddressRequest `json:"address" binding:"required"`
+	Representatives []string       `json:"representatives" binding:"required,min=1,dive,numeric,len=11"`
+}
+
+type DeclineBusinessOnboardingRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
+
+type BusinessOnboardingController struct {
+	businessOnboardingService     services.BusinessOnboardingService
+	representativeApprovalService services.RepresentativeApprovalService
+}
+
+func NewBusinessOnboardingController(
+	businessOnboardingService services.BusinessOnboardingService,
+	representativeApprovalService services.RepresentativeApprovalService,
+) *BusinessOnboardingController {
+	return &BusinessOnboardingController{
+		businessOnboardingService:     businessOnboardingService,
+		representativeApprovalService: representativeApprovalService,
+	}
+}
+
+func (ctrl *BusinessOnboardingController) StartBusinessOnboarding(c *gin.Context) {
+	var req StartBusinessOnboardingRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	publicID, err := ctrl.businessOnboardingService.StartOnboardingProcess(c.Request.Context(), &services.StartBusinessServiceRequest{
+		CNPJ:                    req.CNPJ,
+		LegalName:               req.LegalName,
+		TradeName:               req.TradeName,
+		Email:                   req.Email,
+		PhoneNumber:             req.Phone,
+		RepresentativeDocuments: req.Representatives,
+		Locale:                  notification.LocaleFromAcceptLanguage(c.GetHeader("Accept-Language")),
+	})
+	if err == nil {
+		c.JSON(http.StatusAccepted, gin.H{"id": publicID, "message": "O e-mail de verificação está sendo enviado."})
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidCNPJ) || errors.Is(err, services.ErrInvalidPhone) || errors.Is(err, services.ErrInvalidAddress) || errors.Is(err, services.ErrInvalidRepresentatives) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrRepresentativeNotCustomer) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrBusinessExists) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func (ctrl *BusinessOnboardingController) ListPendingApprovals(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	representatives, err := ctrl.representativeApprovalService.ListPending(c.Request.Context(), userPublicID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(representatives))
+	for _, representative := range representatives {
+		response = append(response, gin.H{
+			"id":        representative.OnboardingRequest.PublicID,
+			"cnpj":      representative.OnboardingRequest.DocumentNumber,
+			"legalName": representative.OnboardingRequest.FullName,
+			"tradeName": representative.OnboardingRequest.TradeName,
+			"createdAt": representative.OnboardingRequest.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"approvals": response})
+}
+
+func (ctrl *BusinessOnboardingController) ApproveBusinessOnboarding(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	err := ctrl.representativeApprovalService.Approve(c.Request.Context(), userPublicID, c.Param("id"))
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Aprovação registrada com sucesso."})
+		return
+	}
+
+	respondRepresentativeDecisionError(c, err)
+}
+
+func (ctrl *BusinessOnboardingController) DeclineBusinessOnboarding(c *gin.Context) {
+	var req DeclineBusinessOnboardingRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	err := ctrl.representativeApprovalService.Decline(c.Request.Context(), userPublicID, c.Param("id"), req.Reason)
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "A abertura da conta foi recusada."})
+		return
+	}
+
+	respondRepresentativeDecisionError(c, err)
+}
+
+func respondRepresentativeDecisionError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrRequestNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrRequestClosed) || errors.Is(err, services.ErrRepresentativeAlreadyDecided) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
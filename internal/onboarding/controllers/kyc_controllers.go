@@ -0,0 +1,242 @@
+package controllers
+
+import (
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/http_helpers"
+)
+
+// MaxKYCUploadBody limita o corpo multipart: o arquivo mais os demais campos do formulário.
+const MaxKYCUploadBody = services.MaxKYCDocumentSize + 1<<20
+
+type UploadKYCDocumentRequest struct {
+	Token        string `form:"token" binding:"required"`
+	Kind         string `form:"kind" binding:"required,oneof=ID_FRONT ID_BACK SELFIE"`
+	DocumentType string `form:"documentType" binding:"omitempty,oneof=RG CNH"`
+}
+
+type ReviewDecisionRequest struct {
+	Reviewer string `json:"reviewer" binding:"required,max=80"`
+	Reason   string `json:"reason" binding:"max=500"`
+}
+
+type KYCController struct {
+	documentService services.KYCDocumentService
+	reviewService   services.KYCReviewService
+}
+
+func NewKYCController(documentService services.KYCDocumentService, reviewService services.KYCReviewService) *KYCController {
+	return &KYCController{documentService: documentService, reviewService: reviewService}
+}
+
+// UploadDocument recebe um arquivo multipart com os campos token, kind, documentType e file.
+func (ctrl *KYCController) UploadDocument(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, MaxKYCUploadBody)
+
+	var req UploadKYCDocumentRequest
+	if err := c.ShouldBind(&req); err != nil {
+		var validationErrors validator.ValidationErrors
+		if errors.As(err, &validationErrors) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Dados inválidos", "details": http_helpers.FormatValidationErrors(validationErrors)})
+			return
+		}
+		respondUploadBodyError(c, err)
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		respondUploadBodyError(c, err)
+		return
+	}
+
+	content, err := file.Open()
+	if err != nil {
+		log.Printf("Error opening uploaded KYC document: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+	defer content.Close()
+
+	result, err := ctrl.documentService.Upload(c.Request.Context(), &services.UploadKYCDocumentRequest{
+		RequestPublicID: c.Param("id"),
+		Token:           req.Token,
+		Kind:            models.KYCDocumentKind(req.Kind),
+		IDDocumentType:  models.IDDocumentType(req.DocumentType),
+		Content:         content,
+	})
+	if err == nil {
+		c.JSON(http.StatusCreated, gin.H{"id": result.DocumentPublicID, "status": result.Status})
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidToken) || errors.Is(err, services.ErrInvalidDocumentKind) || errors.Is(err, services.ErrInvalidIDDocumentType) || errors.Is(err, services.ErrRequestNotVerified) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrRequestNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrDocumentsAlreadySubmitted) || errors.Is(err, services.ErrRequestClosed) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrExpiredToken) {
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrDocumentTooLarge) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrUnsupportedDocument) {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func (ctrl *KYCController) ListReviews(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultReviewListLimit)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O parâmetro 'limit' deve ser um número."})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O parâmetro 'offset' deve ser um número."})
+		return
+	}
+
+	onboardingRequests, err := ctrl.reviewService.ListUnderReview(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+
+	// politicallyExposed destaca os clientes que se declararam PEP, que exigem diligência reforçada.
+	response := make([]gin.H, 0, len(onboardingRequests))
+	for _, onboardingRequest := range onboardingRequests {
+		response = append(response, gin.H{
+			"id":                 onboardingRequest.PublicID,
+			"customerType":       onboardingRequest.CustomerType,
+			"fullName":           onboardingRequest.FullName,
+			"documentNumber":     onboardingRequest.DocumentNumber,
+			"politicallyExposed": onboardingRequest.PoliticallyExposed,
+			"createdAt":          onboardingRequest.CreatedAt,
+			"submittedAt":        onboardingRequest.UpdatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"reviews": response})
+}
+
+func (ctrl *KYCController) ListDocuments(c *gin.Context) {
+	documents, err := ctrl.reviewService.ListDocuments(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+
+	response := make([]gin.H, 0, len(documents))
+	for _, document := range documents {
+		response = append(response, gin.H{
+			"id":           document.PublicID,
+			"kind":         document.Kind,
+			"documentType": document.IDDocumentType,
+			"contentType":  document.ContentType,
+			"size":         document.SizeBytes,
+			"sha256":       document.SHA256,
+			"uploadedAt":   document.UpdatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"documents": response})
+}
+
+func (ctrl *KYCController) DownloadDocument(c *gin.Context) {
+	document, content, err := ctrl.reviewService.OpenDocument(c.Request.Context(), c.Param("id"), c.Param("documentId"))
+	if err != nil {
+		respondReviewError(c, err)
+		return
+	}
+	defer content.Close()
+
+	c.DataFromReader(http.StatusOK, document.SizeBytes, document.ContentType, content, map[string]string{
+		"Cache-Control":          "no-store",
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+func (ctrl *KYCController) ApproveReview(c *gin.Context) {
+	var req ReviewDecisionRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err := ctrl.reviewService.Approve(c.Request.Context(), c.Param("id"), req.Reviewer)
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Documentação aprovada. O cliente receberá o link para concluir o cadastro."})
+		return
+	}
+
+	respondReviewError(c, err)
+}
+
+func (ctrl *KYCController) RejectReview(c *gin.Context) {
+	var req ReviewDecisionRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err := ctrl.reviewService.Reject(c.Request.Context(), c.Param("id"), req.Reviewer, req.Reason)
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Documentação reprovada. O cliente será avisado por e-mail."})
+		return
+	}
+
+	respondReviewError(c, err)
+}
+
+func respondReviewError(c *gin.Context, err error) {
+	if errors.Is(err, services.ErrReviewReasonRequired) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrRequestNotFound) || errors.Is(err, services.ErrDocumentNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrRequestNotUnderReview) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+// respondUploadBodyError trata falhas na leitura do formulário, como o arquivo ausente ou um corpo
+// maior que o permitido.
+func respondUploadBodyError(c *gin.Context, err error) {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": services.ErrDocumentTooLarge.Error()})
+		return
+	}
+
+	c.JSON(http.StatusBadRequest, gin.H{"error": "Envie o arquivo no campo 'file' de um formulário multipart."})
+}
@@ -5,39 +5,94 @@ import (
 	"errors"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
 	"github.com/high-effort-low-stress/go-bank-api/internal/utils/http_helpers"
 )
 
+// AddressRequest é o endereço informado no formulário. O nome Cep mantém a chave "cep" nas
+// mensagens de validação.
+type AddressRequest struct {
+	Cep          string `json:"cep" binding:"required"`
+	Street       string `json:"street" binding:"required,max=255"`
+	Number       string `json:"number" binding:"required,max=20"`
+	Complement   string `json:"complement" binding:"max=100"`
+	Neighborhood string `json:"neighborhood" binding:"required,max=100"`
+	City         string `json:"city" binding:"required,max=100"`
+	State        string `json:"state" binding:"required,len=2"`
+}
+
+// StartOnboardingRequest usa *bool em PoliticallyExposed para que "false" seja aceito e a
+// ausência do campo seja rejeitada: a declaração de PEP é obrigatória.
 type StartOnboardingRequest struct {
-	Document string `json:"document" binding:"required,numeric"`
-	FullName string `json:"fullName" binding:"required"`
-	Email    string `json:"email" binding:"required,email"`
+	Document           string         `json:"document" binding:"required,numeric"`
+	FullName           string         `json:"fullName" binding:"required"`
+	BirthDate          string         `json:"birthDate" binding:"required,datetime=2006-01-02"`
+	MotherName         string         `json:"motherName" binding:"required,max=255"`
+	PoliticallyExposed *bool          `json:"politicallyExposed" binding:"required"`
+	Email              string         `json:"email" binding:"required,email"`
+	Phone              string         `json:"phone" binding:"required,e164"`
+	Address            AddressRequest `json:"address" binding:"required"`
 }
 
 type CompleteOnboardingRequest struct {
-	Token           string `json:"token" binding:"required"`
-	Password        string `json:"password" binding:"required,min=8"`
-	ConfirmPassword string `json:"confirmPassword" binding:"required,min=8"`
+	Token                string `json:"token" binding:"required"`
+	Password             string `json:"password" binding:"required,min=8"`
+	ConfirmPassword      string `json:"confirmPassword" binding:"required,min=8"`
+	AcceptedTermsVersion string `json:"acceptedTermsVersion" binding:"required,max=20"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email" binding:"required,email"`
+}
+
+type SendPhoneCodeRequest struct {
+	Token   string `json:"token" binding:"required"`
+	Channel string `json:"channel" binding:"omitempty,oneof=sms whatsapp"`
+}
+
+type VerifyPhoneCodeRequest struct {
+	Token string `json:"token" binding:"required"`
+	Code  string `json:"code" binding:"required,numeric,len=6"`
 }
 
 type OnboardingController struct {
 	createOnboardingService   services.OnboardingService
 	verifyEmailTokenService   services.VerifyEmailTokenService
 	completeOnboardingService services.CompleteOnboardingService
+	resendVerificationService services.ResendVerificationService
+	phoneVerificationService  services.PhoneVerificationService
 }
 
 func NewOnboardingController(
 	createOnboardingService services.OnboardingService,
 	verifyEmailTokenService services.VerifyEmailTokenService,
 	completeOnboardingService services.CompleteOnboardingService,
+	resendVerificationService services.ResendVerificationService,
+	phoneVerificationService services.PhoneVerificationService,
 ) *OnboardingController {
 	return &OnboardingController{
 		createOnboardingService:   createOnboardingService,
 		verifyEmailTokenService:   verifyEmailTokenService,
 		completeOnboardingService: completeOnboardingService,
+		resendVerificationService: resendVerificationService,
+		phoneVerificationService:  phoneVerificationService,
+	}
+}
+
+func (req AddressRequest) toModel() user_models.Address {
+	return user_models.Address{
+		CEP:          req.Cep,
+		Street:       req.Street,
+		Number:       req.Number,
+		Complement:   req.Complement,
+		Neighborhood: req.Neighborhood,
+		City:         req.City,
+		State:        req.State,
 	}
 }
 
@@ -49,16 +104,33 @@ func (ctrl *OnboardingController) StartOnboarding(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.createOnboardingService.StartOnboardingProcess(req.Document, req.FullName, req.Email)
+	// O formato já foi validado pela tag datetime.
+	birthDate, _ := time.Parse(time.DateOnly, req.BirthDate)
+
+	publicID, err := ctrl.createOnboardingService.StartOnboardingProcess(c.Request.Context(), &services.StartServiceRequest{
+		Document:           req.Document,
+		FullName:           req.FullName,
+		BirthDate:          birthDate,
+		MotherName:         req.MotherName,
+		PoliticallyExposed: *req.PoliticallyExposed,
+		Email:              req.Email,
+		PhoneNumber:        req.Phone,
+		Address:            req.Address.toModel(),
+		Locale:             notification.LocaleFromAcceptLanguage(c.GetHeader("Accept-Language")),
+	})
 	if err == nil {
-		c.JSON(http.StatusAccepted, gin.H{"message": "O e-mail de verificação está sendo enviado."})
+		c.JSON(http.StatusAccepted, gin.H{"id": publicID, "message": "O e-mail de verificação está sendo enviado."})
 		return
 	}
 
-	if errors.Is(err, services.ErrInvalidCPF) {
+	if errors.Is(err, services.ErrInvalidCPF) || errors.Is(err, services.ErrInvalidBirth) || errors.Is(err, services.ErrInvalidPhone) || errors.Is(err, services.ErrInvalidAddress) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if errors.Is(err, services.ErrUnderage) {
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		return
+	}
 	if errors.Is(err, services.ErrUserExists) {
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		return
@@ -74,7 +146,7 @@ func (ctrl *OnboardingController) VerifyEmail(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.verifyEmailTokenService.Execute(token)
+	err := ctrl.verifyEmailTokenService.Execute(c.Request.Context(), token)
 
 	if err == nil {
 		c.JSON(http.StatusOK, gin.H{"message": "Email verificado com sucesso!"})
@@ -85,7 +157,7 @@ func (ctrl *OnboardingController) VerifyEmail(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if errors.Is(err, services.ErrAlreadyVerified) {
+	if errors.Is(err, services.ErrAlreadyVerified) || errors.Is(err, services.ErrRequestClosed) {
 		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		return
 	}
@@ -105,18 +177,32 @@ func (ctrl *OnboardingController) CompleteOnboarding(c *gin.Context) {
 		return
 	}
 
-	err := ctrl.completeOnboardingService.Execute(req.Token, req.Password, req.ConfirmPassword)
+	result, err := ctrl.completeOnboardingService.Execute(c.Request.Context(), &services.CompleteServiceRequest{
+		Token:                req.Token,
+		Password:             req.Password,
+		ConfirmPassword:      req.ConfirmPassword,
+		AcceptedTermsVersion: req.AcceptedTermsVersion,
+		UserAgent:            c.Request.UserAgent(),
+		IPAddress:            c.ClientIP(),
+	})
 	if err == nil {
-		c.JSON(http.StatusOK, gin.H{"message": "Cadastro concluído com sucesso."})
+		c.JSON(http.StatusOK, gin.H{
+			"message": "Cadastro concluído com sucesso.",
+			"account": gin.H{
+				"id":            result.AccountPublicID,
+				"agencyNumber":  result.AgencyNumber,
+				"accountNumber": result.AccountNumber,
+			},
+		})
 		return
 	}
 
-	if errors.Is(err, services.ErrInvalidToken) || errors.Is(err, services.ErrRequestNotVerified) || errors.Is(err, services.ErrPasswordsDoNotMatch) || errors.Is(err, services.ErrWeakPassword) {
+	if errors.Is(err, services.ErrInvalidToken) || errors.Is(err, services.ErrRequestNotVerified) || errors.Is(err, services.ErrRequestNotApproved) || errors.Is(err, services.ErrPhoneNotVerified) || errors.Is(err, services.ErrApprovalsPending) || errors.Is(err, services.ErrPasswordsDoNotMatch) || errors.Is(err, services.ErrWeakPassword) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-	if errors.Is(err, services.ErrAlreadyVerified) {
-		c.JSON(http.StatusConflict, gin.H{"error": services.ErrAlreadyVerified.Error()})
+	if errors.Is(err, services.ErrAlreadyVerified) || errors.Is(err, services.ErrRequestClosed) || errors.Is(err, services.ErrOutdatedTerms) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
 		return
 	}
 
@@ -128,3 +214,88 @@ func (ctrl *OnboardingController) CompleteOnboarding(c *gin.Context) {
 	log.Printf("Error completing onboarding: %v", err)
 	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
 }
+
+func (ctrl *OnboardingController) ResendVerificationEmail(c *gin.Context) {
+	var req ResendVerificationRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	// A resposta não diz se o e-mail tem uma solicitação pendente, para não revelar cadastros.
+	if err := ctrl.resendVerificationService.Execute(c.Request.Context(), req.Email); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+
+	c.JSON(http.StatusAccepted, gin.H{"message": "Se houver uma solicitação pendente para este e-mail, um novo e-mail de verificação será enviado."})
+}
+
+func (ctrl *OnboardingController) SendPhoneCode(c *gin.Context) {
+	var req SendPhoneCodeRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err := ctrl.phoneVerificationService.SendCode(c.Request.Context(), req.Token, notification.SMSChannel(req.Channel))
+	if err == nil {
+		c.JSON(http.StatusAccepted, gin.H{"message": "O código de verificação foi enviado para o seu celular."})
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidToken) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrPhoneAlreadyVerified) || errors.Is(err, services.ErrRequestClosed) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrExpiredToken) {
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrOTPCooldown) || errors.Is(err, services.ErrOTPLimitReached) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func (ctrl *OnboardingController) VerifyPhoneCode(c *gin.Context) {
+	var req VerifyPhoneCodeRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	err := ctrl.phoneVerificationService.VerifyCode(c.Request.Context(), req.Token, req.Code)
+	if err == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Celular verificado com sucesso!"})
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidToken) || errors.Is(err, services.ErrInvalidOTP) || errors.Is(err, services.ErrOTPNotRequested) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrPhoneAlreadyVerified) || errors.Is(err, services.ErrRequestClosed) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrExpiredToken) || errors.Is(err, services.ErrExpiredOTP) {
+		c.JSON(http.StatusGone, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrOTPAttemptsExceeded) {
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
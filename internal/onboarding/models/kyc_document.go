@@ -0,0 +1,68 @@
+package models
+
+import (
+	"time"
+
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// KYCDocumentKind identifica qual parte da documentação de identificação o arquivo contém.
+type KYCDocumentKind string
+
+const (
+	KYCDocumentFront  KYCDocumentKind = "ID_FRONT"
+	KYCDocumentBack   KYCDocumentKind = "ID_BACK"
+	KYCDocumentSelfie KYCDocumentKind = "SELFIE"
+)
+
+// RequiredKYCDocuments são os arquivos que precisam ser enviados antes da análise cadastral.
+var RequiredKYCDocuments = []KYCDocumentKind{KYCDocumentFront, KYCDocumentBack, KYCDocumentSelfie}
+
+// IDDocumentType é o documento de identidade fotografado na frente e no verso.
+type IDDocumentType string
+
+const (
+	IDDocumentRG  IDDocumentType = "RG"
+	IDDocumentCNH IDDocumentType = "CNH"
+)
+
+// KYCDocument registra um arquivo enviado para a análise cadastral. O conteúdo fica no BlobStore,
+// sob BlobKey; apenas o envio mais recente de cada tipo é mantido.
+type KYCDocument struct {
+	ID                  int64           `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID            string          `gorm:"type:varchar(26);unique;not null"`
+	OnboardingRequestID int64           `gorm:"not null"`
+	Kind                KYCDocumentKind `gorm:"type:varchar(10);not null"`
+	IDDocumentType      IDDocumentType  `gorm:"type:varchar(3);column:id_document_type"`
+	BlobKey             string          `gorm:"type:varchar(255);not null"`
+	ContentType         string          `gorm:"type:varchar(100);not null"`
+	SizeBytes           int64           `gorm:"not null"`
+	SHA256              string          `gorm:"type:varchar(64);not null;column:sha256"`
+	CreatedAt           time.Time       `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time       `gorm:"autoUpdateTime"`
+}
+
+func (KYCDocument) TableName() string {
+	return "onboarding.kyc_documents"
+}
+
+func (d *KYCDocument) BeforeCreate(_ *gorm.DB) (err error) {
+	d.PublicID = ulid.Make().String()
+	return
+}
+
+// HasAllKYCDocuments indica se todos os arquivos obrigatórios já foram enviados.
+func HasAllKYCDocuments(documents []KYCDocument) bool {
+	sent := make(map[KYCDocumentKind]bool, len(documents))
+	for _, document := range documents {
+		sent[document.Kind] = true
+	}
+
+	for _, kind := range RequiredKYCDocuments {
+		if !sent[kind] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"gorm.io/gorm"
+)
+
+// RepresentativeStatus define a decisão de um representante legal sobre a abertura da conta PJ.
+type RepresentativeStatus string
+
+const (
+	RepresentativePending  RepresentativeStatus = "PENDING"
+	RepresentativeApproved RepresentativeStatus = "APPROVED"
+	RepresentativeDeclined RepresentativeStatus = "DECLINED"
+)
+
+// ActorRepresentative identifica, no histórico, as transições disparadas por um representante legal.
+const ActorRepresentative = "representative"
+
+// LegalRepresentative liga uma solicitação de onboarding PJ a um cliente pessoa física que
+// responde pela empresa. A conta só é aberta depois que todos os representantes aprovarem.
+// Nome e CPF são cifrados; o CPF tem o blind index DocumentNumberIndex, calculado em BeforeSave.
+type LegalRepresentative struct {
+	ID                  int64                `gorm:"primaryKey;autoIncrement;column:id"`
+	OnboardingRequestID int64                `gorm:"not null"`
+	OnboardingRequest   *OnboardingRequest   `gorm:"foreignKey:OnboardingRequestID"`
+	UserID              int64                `gorm:"not null"`
+	DocumentNumber      string               `gorm:"type:text;not null;serializer:encrypted"`
+	DocumentNumberIndex string               `gorm:"type:varchar(64);not null;column:document_number_index"`
+	FullName            string               `gorm:"type:text;not null;serializer:encrypted"`
+	Status              RepresentativeStatus `gorm:"type:varchar(10);not null;default:'PENDING'"`
+	DecidedAt           *time.Time           `gorm:"column:decided_at"`
+	CreatedAt           time.Time            `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time            `gorm:"autoUpdateTime"`
+}
+
+func (LegalRepresentative) TableName() string {
+	return "onboarding.legal_representatives"
+}
+
+// Anonymize apaga o nome e o CPF do representante. A decisão é mantida no histórico da solicitação PJ.
+func (lr *LegalRepresentative) Anonymize() {
+	lr.FullName = ""
+	lr.DocumentNumber = ""
+}
+
+// BeforeSave recalcula o blind index a partir do CPF em claro.
+func (lr *LegalRepresentative) BeforeSave(_ *gorm.DB) (err error) {
+	lr.DocumentNumberIndex, err = fieldcrypto.BlindIndex(lr.DocumentNumber)
+	return
+}
+
+// AllRepresentativesApproved indica se há ao menos um representante e todos já aprovaram.
+func AllRepresentativesApproved(representatives []LegalRepresentative) bool {
+	if len(representatives) == 0 {
+		return false
+	}
+
+	for _, representative := range representatives {
+		if representative.Status != RepresentativeApproved {
+			return false
+		}
+	}
+	return true
+}
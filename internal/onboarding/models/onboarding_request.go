@@ -2,8 +2,12 @@
 package models
 
 import (
+	"slices"
 	"time"
 
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/otp"
 	"github.com/oklog/ulid/v2"
 	"gorm.io/gorm"
 )
@@ -12,24 +16,56 @@ import (
 type OnboardingStatus string
 
 const (
-	StatusPending   OnboardingStatus = "PENDING"
-	StatusVerified  OnboardingStatus = "VERIFIED"
-	StatusCompleted OnboardingStatus = "COMPLETED"
-	StatusFailed    OnboardingStatus = "EXPIRED"
+	StatusPending     OnboardingStatus = "PENDING"
+	StatusVerified    OnboardingStatus = "VERIFIED"
+	StatusUnderReview OnboardingStatus = "UNDER_REVIEW"
+	StatusApproved    OnboardingStatus = "APPROVED"
+	StatusCompleted   OnboardingStatus = "COMPLETED"
+	StatusExpired     OnboardingStatus = "EXPIRED"
+	StatusRejected    OnboardingStatus = "REJECTED"
+	StatusCancelled   OnboardingStatus = "CANCELLED"
 )
 
+// ExpirableStatuses são os status em que a solicitação depende do token do cliente para avançar
+// e, por isso, expira junto com ele. Em UNDER_REVIEW a solicitação aguarda a análise cadastral.
+var ExpirableStatuses = []OnboardingStatus{StatusPending, StatusVerified, StatusApproved}
+
 // OnboardingRequest representa a tabela onboarding_requests no banco de dados.
+// Em solicitações de pessoa jurídica, FullName guarda a razão social e DocumentNumber o CNPJ;
+// data de nascimento, nome da mãe e a declaração de PEP só existem para pessoa física.
+// Nome, e-mail, documento, nome da mãe e celular são gravados cifrados, com blind indexes para as
+// buscas por e-mail, documento e celular. Locale é o idioma dos e-mails, escolhido pelo
+// Accept-Language do início do onboarding.
 type OnboardingRequest struct {
-	ID                    int64            `gorm:"primaryKey;autoIncrement;column:id"`
-	PublicID              string           `gorm:"type:varchar(26);unique;not null"`
-	FullName              string           `gorm:"type:varchar(255);not null"`
-	Email                 string           `gorm:"type:varchar(255);unique;not null"`
-	DocumentNumber        string           `gorm:"type:varchar(11);unique;not null"`
-	VerificationTokenHash string           `gorm:"type:varchar(255);unique;not null"`
-	TokenExpiresAt        time.Time        `gorm:"not null"`
-	Status                OnboardingStatus `gorm:"type:varchar(20);not null;default:'PENDING'"`
-	CreatedAt             time.Time        `gorm:"autoCreateTime"`
-	UpdatedAt             time.Time        `gorm:"autoUpdateTime"`
+	ID                    int64                    `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID              string                   `gorm:"type:varchar(26);unique;not null"`
+	FullName              string                   `gorm:"type:text;not null;serializer:encrypted"`
+	Email                 string                   `gorm:"type:text;not null;serializer:encrypted"`
+	EmailIndex            string                   `gorm:"type:varchar(64);not null;column:email_index"`
+	DocumentNumber        string                   `gorm:"type:text;not null;serializer:encrypted"`
+	DocumentNumberIndex   string                   `gorm:"type:varchar(64);not null;column:document_number_index"`
+	CustomerType          user_models.CustomerType `gorm:"type:varchar(10);not null;default:'INDIVIDUAL';column:customer_type"`
+	TradeName             string                   `gorm:"type:varchar(255);not null;default:'';column:trade_name"`
+	BirthDate             *time.Time               `gorm:"type:date;column:birth_date"`
+	MotherName            string                   `gorm:"type:text;not null;default:'';column:mother_name;serializer:encrypted"`
+	PoliticallyExposed    bool                     `gorm:"not null;default:false;column:politically_exposed"`
+	PhoneNumber           string                   `gorm:"type:text;not null;column:phone_number;serializer:encrypted"`
+	PhoneNumberIndex      string                   `gorm:"type:varchar(64);not null;default:'';column:phone_number_index"`
+	PhoneVerifiedAt       *time.Time               `gorm:"column:phone_verified_at"`
+	PhoneOTPHash          string                   `gorm:"type:varchar(64);not null;default:'';column:phone_otp_hash"`
+	PhoneOTPExpiresAt     *time.Time               `gorm:"column:phone_otp_expires_at"`
+	PhoneOTPAttempts      int                      `gorm:"not null;default:0;column:phone_otp_attempts"`
+	PhoneOTPSendCount     int                      `gorm:"not null;default:0;column:phone_otp_send_count"`
+	LastOTPSentAt         *time.Time               `gorm:"column:last_otp_sent_at"`
+	Address               user_models.Address      `gorm:"embedded;embeddedPrefix:address_"`
+	Locale                string                   `gorm:"type:varchar(10);not null;default:'pt-BR';column:locale"`
+	VerificationTokenHash string                   `gorm:"type:varchar(255);unique;not null"`
+	TokenExpiresAt        time.Time                `gorm:"not null"`
+	Status                OnboardingStatus         `gorm:"type:varchar(20);not null;default:'PENDING'"`
+	ResendCount           int                      `gorm:"not null;default:0"`
+	LastEmailSentAt       *time.Time               `gorm:"column:last_email_sent_at"`
+	CreatedAt             time.Time                `gorm:"autoCreateTime"`
+	UpdatedAt             time.Time                `gorm:"autoUpdateTime"`
 }
 
 // TableName define o nome da tabela para o GORM.
@@ -37,7 +73,52 @@ func (OnboardingRequest) TableName() string {
 	return "onboarding.onboarding_requests"
 }
 
+// IsExpirable indica se a solicitação ainda aguarda o cliente e seu token já expirou.
+func (or *OnboardingRequest) IsExpirable(now time.Time) bool {
+	return slices.Contains(ExpirableStatuses, or.Status) && now.After(or.TokenExpiresAt)
+}
+
+// IsPhoneVerified indica se o celular da solicitação já foi confirmado com o código enviado por SMS.
+func (or *OnboardingRequest) IsPhoneVerified() bool {
+	return or.PhoneVerifiedAt != nil
+}
+
+// PhoneOTP expõe os campos do código de verificação do celular.
+func (or *OnboardingRequest) PhoneOTP() otp.Challenge {
+	return otp.Challenge{Hash: &or.PhoneOTPHash, ExpiresAt: &or.PhoneOTPExpiresAt, Attempts: &or.PhoneOTPAttempts}
+}
+
+// IsBusiness indica se a solicitação é de abertura de conta pessoa jurídica.
+func (or *OnboardingRequest) IsBusiness() bool {
+	return or.CustomerType == user_models.CustomerTypeBusiness
+}
+
+// Anonymize apaga os dados pessoais da solicitação, mantendo o status e o histórico de transições.
+func (or *OnboardingRequest) Anonymize() {
+	or.FullName = user_models.AnonymizedName
+	or.Email = user_models.AnonymizedEmail(or.PublicID)
+	or.DocumentNumber = ""
+	or.TradeName = ""
+	or.BirthDate = nil
+	or.MotherName = ""
+	or.PhoneNumber = ""
+	or.PhoneOTPHash = ""
+	or.Address = user_models.Address{}
+}
+
 func (or *OnboardingRequest) BeforeCreate(_ *gorm.DB) (err error) {
 	or.PublicID = ulid.Make().String()
 	return
 }
+
+// BeforeSave recalcula os blind indexes a partir dos valores em claro.
+func (or *OnboardingRequest) BeforeSave(_ *gorm.DB) (err error) {
+	if or.EmailIndex, err = fieldcrypto.BlindIndex(or.Email); err != nil {
+		return err
+	}
+	if or.DocumentNumberIndex, err = fieldcrypto.BlindIndex(or.DocumentNumber); err != nil {
+		return err
+	}
+	or.PhoneNumberIndex, err = fieldcrypto.BlindIndex(or.PhoneNumber)
+	return err
+}
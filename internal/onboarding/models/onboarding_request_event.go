@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// OnboardingRequestEvent registra cada mudança de status de uma solicitação de onboarding.
+type OnboardingRequestEvent struct {
+	ID                  int64            `gorm:"primaryKey;autoIncrement;column:id"`
+	OnboardingRequestID int64            `gorm:"not null"`
+	FromStatus          OnboardingStatus `gorm:"type:onboarding.request_status;not null"`
+	ToStatus            OnboardingStatus `gorm:"type:onboarding.request_status;not null"`
+	Actor               string           `gorm:"type:varchar(100);not null"`
+	Reason              string           `gorm:"type:text;not null"`
+	CreatedAt           time.Time        `gorm:"not null"`
+}
+
+func (OnboardingRequestEvent) TableName() string {
+	return "onboarding.onboarding_request_events"
+}
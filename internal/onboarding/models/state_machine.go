@@ -0,0 +1,73 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// ErrInvalidTransition é o erro base de todas as transições de status não permitidas.
+var ErrInvalidTransition = errors.New("transição de status inválida")
+
+// InvalidTransitionError descreve uma transição de status que a máquina de estados não permite.
+type InvalidTransitionError struct {
+	From OnboardingStatus
+	To   OnboardingStatus
+}
+
+func (e *InvalidTransitionError) Error() string {
+	return fmt.Sprintf("%s: %s -> %s", ErrInvalidTransition, e.From, e.To)
+}
+
+func (e *InvalidTransitionError) Is(target error) bool {
+	return target == ErrInvalidTransition
+}
+
+// Atores que podem disparar uma transição, registrados no histórico da solicitação.
+const (
+	ActorCustomer      = "customer"
+	ActorExpirySweeper = "system:expiry-sweeper"
+	ActorBackOffice    = "backoffice"
+)
+
+// allowedTransitions declara todas as transições válidas de uma solicitação de onboarding.
+// Depois do e-mail verificado, os documentos enviados levam a solicitação para a análise
+// cadastral (UNDER_REVIEW), e só uma solicitação aprovada pode ser concluída.
+// Status ausentes do mapa (COMPLETED, EXPIRED, REJECTED, CANCELLED) são finais.
+var allowedTransitions = map[OnboardingStatus][]OnboardingStatus{
+	StatusPending:     {StatusVerified, StatusExpired, StatusRejected, StatusCancelled},
+	StatusVerified:    {StatusUnderReview, StatusExpired, StatusRejected, StatusCancelled},
+	StatusUnderReview: {StatusApproved, StatusRejected, StatusCancelled},
+	StatusApproved:    {StatusCompleted, StatusExpired, StatusCancelled},
+}
+
+// CanTransitionTo indica se a máquina de estados permite sair de s para o status informado.
+func (s OnboardingStatus) CanTransitionTo(to OnboardingStatus) bool {
+	return slices.Contains(allowedTransitions[s], to)
+}
+
+// IsFinal indica se nenhum outro status pode ser alcançado a partir de s.
+func (s OnboardingStatus) IsFinal() bool {
+	return len(allowedTransitions[s]) == 0
+}
+
+// TransitionTo move a solicitação para o novo status e retorna o evento que deve ser
+// persistido junto com ela. A solicitação não é alterada se a transição for inválida.
+func (or *OnboardingRequest) TransitionTo(to OnboardingStatus, actor, reason string) (*OnboardingRequestEvent, error) {
+	if !or.Status.CanTransitionTo(to) {
+		return nil, &InvalidTransitionError{From: or.Status, To: to}
+	}
+
+	event := &OnboardingRequestEvent{
+		OnboardingRequestID: or.ID,
+		FromStatus:          or.Status,
+		ToStatus:            to,
+		Actor:               actor,
+		Reason:              reason,
+		CreatedAt:           time.Now(),
+	}
+	or.Status = to
+
+	return event, nil
+}
@@ -0,0 +1,86 @@
+package models_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOnboardingStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from     models.OnboardingStatus
+		to       models.OnboardingStatus
+		expected bool
+	}{
+		{models.StatusPending, models.StatusVerified, true},
+		{models.StatusVerified, models.StatusUnderReview, true},
+		{models.StatusUnderReview, models.StatusApproved, true},
+		{models.StatusUnderReview, models.StatusRejected, true},
+		{models.StatusApproved, models.StatusCompleted, true},
+		{models.StatusApproved, models.StatusExpired, true},
+		{models.StatusPending, models.StatusExpired, true},
+		{models.StatusVerified, models.StatusExpired, true},
+		{models.StatusPending, models.StatusRejected, true},
+		{models.StatusVerified, models.StatusCancelled, true},
+		{models.StatusPending, models.StatusCompleted, false},
+		{models.StatusVerified, models.StatusCompleted, false},
+		{models.StatusUnderReview, models.StatusCompleted, false},
+		{models.StatusUnderReview, models.StatusExpired, false},
+		{models.StatusVerified, models.StatusPending, false},
+		{models.StatusCompleted, models.StatusExpired, false},
+		{models.StatusExpired, models.StatusPending, false},
+		{models.StatusRejected, models.StatusVerified, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.from.CanTransitionTo(tt.to))
+		})
+	}
+}
+
+func TestOnboardingStatus_IsFinal(t *testing.T) {
+	assert.False(t, models.StatusPending.IsFinal())
+	assert.False(t, models.StatusVerified.IsFinal())
+	assert.False(t, models.StatusUnderReview.IsFinal())
+	assert.False(t, models.StatusApproved.IsFinal())
+	assert.True(t, models.StatusCompleted.IsFinal())
+	assert.True(t, models.StatusExpired.IsFinal())
+	assert.True(t, models.StatusRejected.IsFinal())
+	assert.True(t, models.StatusCancelled.IsFinal())
+}
+
+func TestOnboardingRequest_TransitionTo(t *testing.T) {
+	t.Run("Should change the status and return the event", func(t *testing.T) {
+		request := &models.OnboardingRequest{ID: 42, Status: models.StatusPending}
+
+		event, err := request.TransitionTo(models.StatusVerified, models.ActorCustomer, "e-mail verificado")
+
+		require.NoError(t, err)
+		assert.Equal(t, models.StatusVerified, request.Status)
+		assert.Equal(t, int64(42), event.OnboardingRequestID)
+		assert.Equal(t, models.StatusPending, event.FromStatus)
+		assert.Equal(t, models.StatusVerified, event.ToStatus)
+		assert.Equal(t, models.ActorCustomer, event.Actor)
+		assert.Equal(t, "e-mail verificado", event.Reason)
+		assert.False(t, event.CreatedAt.IsZero())
+	})
+
+	t.Run("Should return a typed error and keep the status on illegal moves", func(t *testing.T) {
+		request := &models.OnboardingRequest{Status: models.StatusPending}
+
+		event, err := request.TransitionTo(models.StatusCompleted, models.ActorCustomer, "")
+
+		assert.Nil(t, event)
+		assert.ErrorIs(t, err, models.ErrInvalidTransition)
+
+		var transitionErr *models.InvalidTransitionError
+		require.True(t, errors.As(err, &transitionErr))
+		assert.Equal(t, models.StatusPending, transitionErr.From)
+		assert.Equal(t, models.StatusCompleted, transitionErr.To)
+		assert.Equal(t, models.StatusPending, request.Status)
+	})
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"gorm.io/gorm"
+)
+
+type KYCDocumentRepository interface {
+	Create(ctx context.Context, document *models.KYCDocument) error
+	Update(ctx context.Context, document *models.KYCDocument) error
+	FindByRequestAndKind(ctx context.Context, onboardingRequestID int64, kind models.KYCDocumentKind) (*models.KYCDocument, error)
+	FindByRequestAndPublicID(ctx context.Context, onboardingRequestID int64, publicID string) (*models.KYCDocument, error)
+	ListByRequest(ctx context.Context, onboardingRequestID int64) ([]models.KYCDocument, error)
+	DeleteByRequest(ctx context.Context, onboardingRequestID int64) error
+	WithTx(tx *gorm.DB) KYCDocumentRepository
+}
+
+type kycDocumentRepository struct {
+	db *gorm.DB
+}
+
+func NewKYCDocumentRepository(db *gorm.DB) KYCDocumentRepository {
+	return &kycDocumentRepository{db: db}
+}
+
+func (r *kycDocumentRepository) Create(ctx context.Context, document *models.KYCDocument) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Create(document).Error
+}
+
+func (r *kycDocumentRepository) Update(ctx context.Context, document *models.KYCDocument) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Save(document).Error
+}
+
+func (r *kycDocumentRepository) FindByRequestAndKind(ctx context.Context, onboardingRequestID int64, kind models.KYCDocumentKind) (*models.KYCDocument, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var document models.KYCDocument
+	result := r.db.WithContext(ctx).
+		Where("onboarding_request_id = ? AND kind = ?", onboardingRequestID, kind).
+		First(&document)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &document, nil
+}
+
+func (r *kycDocumentRepository) FindByRequestAndPublicID(ctx context.Context, onboardingRequestID int64, publicID string) (*models.KYCDocument, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var document models.KYCDocument
+	result := r.db.WithContext(ctx).
+		Where("onboarding_request_id = ? AND public_id = ?", onboardingRequestID, publicID).
+		First(&document)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &document, nil
+}
+
+func (r *kycDocumentRepository) ListByRequest(ctx context.Context, onboardingRequestID int64) ([]models.KYCDocument, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var documents []models.KYCDocument
+	result := r.db.WithContext(ctx).
+		Where("onboarding_request_id = ?", onboardingRequestID).
+		Order("id").
+		Find(&documents)
+	return documents, result.Error
+}
+
+func (r *kycDocumentRepository) DeleteByRequest(ctx context.Context, onboardingRequestID int64) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Where("onboarding_request_id = ?", onboardingRequestID).Delete(&models.KYCDocument{}).Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *kycDocumentRepository) WithTx(tx *gorm.DB) KYCDocumentRepository {
+	return &kycDocumentRepository{db: tx}
+}
@@ -0,0 +1,104 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type LegalRepresentativeRepository interface {
+	Create(ctx context.Context, representatives []*models.LegalRepresentative) error
+	ListByRequest(ctx context.Context, onboardingRequestID int64) ([]models.LegalRepresentative, error)
+	ListPendingByUser(ctx context.Context, userID int64) ([]models.LegalRepresentative, error)
+	ListByUser(ctx context.Context, userID int64) ([]models.LegalRepresentative, error)
+	FindByRequestAndUserForUpdate(ctx context.Context, onboardingRequestID, userID int64) (*models.LegalRepresentative, error)
+	Update(ctx context.Context, representative *models.LegalRepresentative) error
+	WithTx(tx *gorm.DB) LegalRepresentativeRepository
+}
+
+type legalRepresentativeRepository struct {
+	db *gorm.DB
+}
+
+func NewLegalRepresentativeRepository(db *gorm.DB) LegalRepresentativeRepository {
+	return &legalRepresentativeRepository{db: db}
+}
+
+func (r *legalRepresentativeRepository) Create(ctx context.Context, representatives []*models.LegalRepresentative) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Create(representatives).Error
+}
+
+func (r *legalRepresentativeRepository) ListByRequest(ctx context.Context, onboardingRequestID int64) ([]models.LegalRepresentative, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var representatives []models.LegalRepresentative
+	result := r.db.WithContext(ctx).
+		Where("onboarding_request_id = ?", onboardingRequestID).
+		Order("id").
+		Find(&representatives)
+	return representatives, result.Error
+}
+
+// ListPendingByUser lista as aprovações que aguardam o cliente, com a solicitação PJ carregada.
+// Solicitações já encerradas não aparecem, mesmo que o representante não tenha decidido.
+func (r *legalRepresentativeRepository) ListPendingByUser(ctx context.Context, userID int64) ([]models.LegalRepresentative, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var representatives []models.LegalRepresentative
+	result := r.db.WithContext(ctx).
+		Joins("OnboardingRequest").
+		Where("legal_representatives.user_id = ? AND legal_representatives.status = ?", userID, models.RepresentativePending).
+		Where(`"OnboardingRequest".status IN ?`, []models.OnboardingStatus{models.StatusPending, models.StatusVerified, models.StatusUnderReview, models.StatusApproved}).
+		Order("legal_representatives.id").
+		Find(&representatives)
+	return representatives, result.Error
+}
+
+// ListByUser lista todas as solicitações PJ em que o cliente foi indicado como representante.
+func (r *legalRepresentativeRepository) ListByUser(ctx context.Context, userID int64) ([]models.LegalRepresentative, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var representatives []models.LegalRepresentative
+	result := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("id").
+		Find(&representatives)
+	return representatives, result.Error
+}
+
+// FindByRequestAndUserForUpdate bloqueia o registro do representante até o fim da transação.
+// Deve ser usado dentro de WithTx.
+func (r *legalRepresentativeRepository) FindByRequestAndUserForUpdate(ctx context.Context, onboardingRequestID, userID int64) (*models.LegalRepresentative, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var representative models.LegalRepresentative
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("onboarding_request_id = ? AND user_id = ?", onboardingRequestID, userID).
+		First(&representative)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &representative, nil
+}
+
+func (r *legalRepresentativeRepository) Update(ctx context.Context, representative *models.LegalRepresentative) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(representative).Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *legalRepresentativeRepository) WithTx(tx *gorm.DB) LegalRepresentativeRepository {
+	return &legalRepresentativeRepository{db: tx}
+}
@@ -2,15 +2,32 @@
 package repositories
 
 import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type OnboardingRequestRepository interface {
-	FindByDocumentOrEmail(document, email string) (*models.OnboardingRequest, error)
-	Create(onboardingRequest *models.OnboardingRequest) error
-	FindByVerificationTokenHash(tokenHash string) (*models.OnboardingRequest, error)
-	Update(onboardingRequest *models.OnboardingRequest) error
+	FindByDocumentOrEmail(ctx context.Context, document, email string) (*models.OnboardingRequest, error)
+	FindByEmail(ctx context.Context, email string) (*models.OnboardingRequest, error)
+	FindByEmailForUpdate(ctx context.Context, email string) (*models.OnboardingRequest, error)
+	Create(ctx context.Context, onboardingRequest *models.OnboardingRequest) error
+	FindByVerificationTokenHash(ctx context.Context, tokenHash string) (*models.OnboardingRequest, error)
+	FindByVerificationTokenHashForUpdate(ctx context.Context, tokenHash string) (*models.OnboardingRequest, error)
+	FindByPublicID(ctx context.Context, publicID string) (*models.OnboardingRequest, error)
+	FindByPublicIDForUpdate(ctx context.Context, publicID string) (*models.OnboardingRequest, error)
+	ListByStatus(ctx context.Context, status models.OnboardingStatus, limit, offset int) ([]models.OnboardingRequest, error)
+	ListByDocument(ctx context.Context, document string) ([]models.OnboardingRequest, error)
+	ListEventsByRequestIDs(ctx context.Context, onboardingRequestIDs []int64) ([]models.OnboardingRequestEvent, error)
+	Update(ctx context.Context, onboardingRequest *models.OnboardingRequest) error
+	SaveTransition(ctx context.Context, onboardingRequest *models.OnboardingRequest, event *models.OnboardingRequestEvent) error
+	ExpireStale(ctx context.Context, now time.Time, batchSize int) (int64, error)
+	WithTx(tx *gorm.DB) OnboardingRequestRepository
 }
 
 type onboardingRequestRepository struct {
@@ -21,9 +38,61 @@ func NewOnboardingRequestRepository(db *gorm.DB) OnboardingRequestRepository {
 	return &onboardingRequestRepository{db: db}
 }
 
-func (r *onboardingRequestRepository) FindByDocumentOrEmail(document, email string) (*models.OnboardingRequest, error) {
+func (r *onboardingRequestRepository) FindByDocumentOrEmail(ctx context.Context, document, email string) (*models.OnboardingRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	documentIndex, err := fieldcrypto.BlindIndex(document)
+	if err != nil {
+		return nil, err
+	}
+	emailIndex, err := fieldcrypto.BlindIndex(email)
+	if err != nil {
+		return nil, err
+	}
+
 	var onboardingRequest models.OnboardingRequest
-	result := r.db.Where("document_number = ? OR email = ?", document, email).First(&onboardingRequest)
+	result := r.db.WithContext(ctx).
+		Where("(document_number_index = ? OR email_index = ?) AND status <> ?", documentIndex, emailIndex, models.StatusExpired).
+		First(&onboardingRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &onboardingRequest, nil
+}
+
+func (r *onboardingRequestRepository) FindByEmail(ctx context.Context, email string) (*models.OnboardingRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	emailIndex, err := fieldcrypto.BlindIndex(email)
+	if err != nil {
+		return nil, err
+	}
+
+	var onboardingRequest models.OnboardingRequest
+	result := r.db.WithContext(ctx).Where("email_index = ? AND status <> ?", emailIndex, models.StatusExpired).First(&onboardingRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &onboardingRequest, nil
+}
+
+// FindByEmailForUpdate bloqueia a solicitação em aberto do e-mail até o fim da transação. Deve ser
+// usado dentro de WithTx.
+func (r *onboardingRequestRepository) FindByEmailForUpdate(ctx context.Context, email string) (*models.OnboardingRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	emailIndex, err := fieldcrypto.BlindIndex(email)
+	if err != nil {
+		return nil, err
+	}
+
+	var onboardingRequest models.OnboardingRequest
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("email_index = ? AND status <> ?", emailIndex, models.StatusExpired).
+		First(&onboardingRequest)
 	if result.Error != nil {
 		return nil, result.Error
 	}
@@ -31,21 +100,173 @@ func (r *onboardingRequestRepository) FindByDocumentOrEmail(document, email stri
 }
 
 // Create cria uma nova solicitação de onboarding no banco de dados.
-func (r *onboardingRequestRepository) Create(onboardingRequest *models.OnboardingRequest) error {
-	result := r.db.Create(onboardingRequest)
+func (r *onboardingRequestRepository) Create(ctx context.Context, onboardingRequest *models.OnboardingRequest) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Create(onboardingRequest)
 	return result.Error
 }
 
-func (r *onboardingRequestRepository) FindByVerificationTokenHash(tokenHash string) (*models.OnboardingRequest, error) {
+func (r *onboardingRequestRepository) FindByVerificationTokenHash(ctx context.Context, tokenHash string) (*models.OnboardingRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var onboardingRequest models.OnboardingRequest
+	result := r.db.WithContext(ctx).Where("verification_token_hash = ?", tokenHash).First(&onboardingRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &onboardingRequest, nil
+}
+
+// FindByVerificationTokenHashForUpdate bloqueia a linha (SELECT ... FOR UPDATE) até o fim da transação,
+// serializando operações concorrentes sobre a mesma solicitação. Deve ser usado dentro de WithTx.
+func (r *onboardingRequestRepository) FindByVerificationTokenHashForUpdate(ctx context.Context, tokenHash string) (*models.OnboardingRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var onboardingRequest models.OnboardingRequest
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("verification_token_hash = ?", tokenHash).
+		First(&onboardingRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &onboardingRequest, nil
+}
+
+func (r *onboardingRequestRepository) FindByPublicID(ctx context.Context, publicID string) (*models.OnboardingRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
 	var onboardingRequest models.OnboardingRequest
-	result := r.db.Where("verification_token_hash = ?", tokenHash).First(&onboardingRequest)
+	result := r.db.WithContext(ctx).Where("public_id = ?", publicID).First(&onboardingRequest)
 	if result.Error != nil {
 		return nil, result.Error
 	}
 	return &onboardingRequest, nil
 }
 
-func (r *onboardingRequestRepository) Update(onboardingRequest *models.OnboardingRequest) error {
-	result := r.db.Save(onboardingRequest)
+// FindByPublicIDForUpdate bloqueia a solicitação identificada pelo PublicID até o fim da transação.
+// Deve ser usado dentro de WithTx.
+func (r *onboardingRequestRepository) FindByPublicIDForUpdate(ctx context.Context, publicID string) (*models.OnboardingRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var onboardingRequest models.OnboardingRequest
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("public_id = ?", publicID).
+		First(&onboardingRequest)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &onboardingRequest, nil
+}
+
+// ListByStatus lista as solicitações no status informado, das mais antigas para as mais recentes.
+func (r *onboardingRequestRepository) ListByStatus(ctx context.Context, status models.OnboardingStatus, limit, offset int) ([]models.OnboardingRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var onboardingRequests []models.OnboardingRequest
+	result := r.db.WithContext(ctx).
+		Where("status = ?", status).
+		Order("updated_at, id").
+		Limit(limit).
+		Offset(offset).
+		Find(&onboardingRequests)
+	return onboardingRequests, result.Error
+}
+
+// ListByDocument lista todas as solicitações feitas com o CPF/CNPJ, inclusive as encerradas.
+func (r *onboardingRequestRepository) ListByDocument(ctx context.Context, document string) ([]models.OnboardingRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	documentIndex, err := fieldcrypto.BlindIndex(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var onboardingRequests []models.OnboardingRequest
+	result := r.db.WithContext(ctx).
+		Where("document_number_index = ?", documentIndex).
+		Order("created_at, id").
+		Find(&onboardingRequests)
+	return onboardingRequests, result.Error
+}
+
+func (r *onboardingRequestRepository) ListEventsByRequestIDs(ctx context.Context, onboardingRequestIDs []int64) ([]models.OnboardingRequestEvent, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var events []models.OnboardingRequestEvent
+	if len(onboardingRequestIDs) == 0 {
+		return events, nil
+	}
+
+	result := r.db.WithContext(ctx).
+		Where("onboarding_request_id IN ?", onboardingRequestIDs).
+		Order("created_at, id").
+		Find(&events)
+	return events, result.Error
+}
+
+func (r *onboardingRequestRepository) Update(ctx context.Context, onboardingRequest *models.OnboardingRequest) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Save(onboardingRequest)
 	return result.Error
 }
+
+// SaveTransition persiste a solicitação e o evento da mudança de status na mesma transação.
+func (r *onboardingRequestRepository) SaveTransition(ctx context.Context, onboardingRequest *models.OnboardingRequest, event *models.OnboardingRequestEvent) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(onboardingRequest).Error; err != nil {
+			return err
+		}
+
+		event.OnboardingRequestID = onboardingRequest.ID
+		return tx.Create(event).Error
+	})
+}
+
+// ExpireStale marca como EXPIRED até batchSize solicitações PENDING/VERIFIED/APPROVED com token vencido,
+// registrando o evento de cada transição. As linhas bloqueadas por outras transações são
+// ignoradas e ficam para a próxima execução.
+func (r *onboardingRequestRepository) ExpireStale(ctx context.Context, now time.Time, batchSize int) (int64, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Exec(`
+		WITH candidates AS (
+			SELECT id, status FROM onboarding.onboarding_requests
+			WHERE status IN ? AND token_expires_at < ?
+			ORDER BY id
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		), expired AS (
+			UPDATE onboarding.onboarding_requests r
+			SET status = ?, updated_at = ?
+			FROM candidates c
+			WHERE r.id = c.id
+			RETURNING r.id, c.status AS from_status
+		)
+		INSERT INTO onboarding.onboarding_request_events (onboarding_request_id, from_status, to_status, actor, reason, created_at)
+		SELECT id, from_status, ?, ?, ?, ? FROM expired`,
+		models.ExpirableStatuses, now, batchSize,
+		models.StatusExpired, now,
+		models.StatusExpired, models.ActorExpirySweeper, "token de verificação expirado", now,
+	)
+	return result.RowsAffected, result.Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *onboardingRequestRepository) WithTx(tx *gorm.DB) OnboardingRequestRepository {
+	return &onboardingRequestRepository{db: tx}
+}
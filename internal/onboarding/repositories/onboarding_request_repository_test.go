@@ -1,6 +1,7 @@
 package repositories_test
 
 import (
+	"bytes"
 	"context"
 	"log"
 	"os"
@@ -10,6 +11,7 @@ import (
 
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
 	"github.com/high-effort-low-stress/go-bank-api/testutil"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -22,6 +24,7 @@ import (
 
 var (
 	db                *gorm.DB
+	keyring           *fieldcrypto.Keyring
 	repo              repositories.OnboardingRequestRepository
 	postgresContainer *postgres.PostgresContainer
 	ctx               context.Context
@@ -33,13 +36,26 @@ func TestMain(m *testing.M) {
 	ctx = context.Background()
 	var err error
 
+	keyring, err = fieldcrypto.NewKeyring(
+		map[string][]byte{"test": bytes.Repeat([]byte{0x42}, 32)}, "test", bytes.Repeat([]byte{0x24}, 32),
+	)
+	if err != nil {
+		log.Fatalf("failed to build the field encryption keyring: %s", err)
+	}
+	fieldcrypto.SetKeyring(keyring)
+
 	dbName := "public"
 	dbUser := "postgres"
 	dbPassword := "password"
 
+	migrations, err := filepath.Glob(filepath.Join(sqldir, "migrations", "*.sql"))
+	if err != nil {
+		log.Fatalf("failed to list migrations: %s", err)
+	}
+
 	postgresContainer, err = postgres.Run(ctx,
 		"postgres:17.5-alpine",
-		postgres.WithInitScripts(filepath.Join(sqldir, "migrations", "01-create-onboarding.sql")),
+		postgres.WithInitScripts(migrations...),
 		postgres.WithDatabase(dbName),
 		postgres.WithUsername(dbUser),
 		postgres.WithPassword(dbPassword),
@@ -75,14 +91,26 @@ func TestOnboardingRequestCreate(t *testing.T) {
 			FullName:              "Integration Test User",
 			Email:                 "integration@example.com",
 			DocumentNumber:        "98765432100",
+			MotherName:            "Integration Test Mother",
+			PhoneNumber:           "+5511912345678",
 			VerificationTokenHash: "integration-test-hash",
 			TokenExpiresAt:        time.Now().Add(1 * time.Hour),
 			Status:                models.StatusPending,
 		}
-		err := repo.Create(requestToCreate)
+		err := repo.Create(context.Background(), requestToCreate)
 
 		require.NoError(t, err)
 		assert.NotEmpty(t, requestToCreate.PublicID, "PublicID should be set by BeforeCreate hook")
+
+		var stored struct {
+			MotherName       string
+			PhoneNumber      string
+			PhoneNumberIndex string
+		}
+		require.NoError(t, db.Raw("SELECT mother_name, phone_number, phone_number_index FROM onboarding.onboarding_requests WHERE public_id = ?", requestToCreate.PublicID).Scan(&stored).Error)
+		assert.True(t, fieldcrypto.IsEncrypted(stored.MotherName), "the mother's name must be stored encrypted")
+		assert.True(t, fieldcrypto.IsEncrypted(stored.PhoneNumber), "the phone number must be stored encrypted")
+		assert.Equal(t, keyring.BlindIndex("+5511912345678"), stored.PhoneNumberIndex)
 	})
 }
 
@@ -98,8 +126,26 @@ func TestOnboardingRequestFindByDocumentOrEmail(t *testing.T) {
 
 		db.Exec(insertDB)
 
-		foundRequest, _ := repo.FindByDocumentOrEmail(expectedDocument, "some-other-email@test.com")
-		foundRequest2, err := repo.FindByDocumentOrEmail("1234567810", expectedEmail)
+		// A fixture grava texto puro, como os dados anteriores à criptografia; o comando de
+		// recriptografia cifra as colunas e preenche os blind indexes usados na busca.
+		_, err = fieldcrypto.Reencrypt(context.Background(), db, keyring, fieldcrypto.Table{
+			Name: models.OnboardingRequest{}.TableName(),
+			Columns: []fieldcrypto.EncryptedColumn{
+				{Name: "full_name"},
+				{Name: "email", IndexColumn: "email_index"},
+				{Name: "document_number", IndexColumn: "document_number_index"},
+				{Name: "mother_name"},
+				{Name: "phone_number", IndexColumn: "phone_number_index"},
+			},
+		}, 100)
+		require.NoError(t, err)
+
+		var storedEmail string
+		require.NoError(t, db.Raw("SELECT email FROM onboarding.onboarding_requests WHERE public_id = ?", expectedPublicID).Scan(&storedEmail).Error)
+		assert.True(t, fieldcrypto.IsEncrypted(storedEmail), "the e-mail must be stored encrypted")
+
+		foundRequest, _ := repo.FindByDocumentOrEmail(context.Background(), expectedDocument, "some-other-email@test.com")
+		foundRequest2, err := repo.FindByDocumentOrEmail(context.Background(), "1234567810", "Jane.Doe@Example.com")
 
 		require.NoError(t, err)
 		assert.Equal(t, expectedEmail, foundRequest.Email)
@@ -111,3 +157,111 @@ func TestOnboardingRequestFindByDocumentOrEmail(t *testing.T) {
 		assert.Equal(t, expectedPublicID, foundRequest2.PublicID)
 	})
 }
+
+func TestOnboardingRequestSaveTransition(t *testing.T) {
+	t.Run("#method SaveTransition", func(t *testing.T) {
+		request := &models.OnboardingRequest{
+			FullName:              "Transition User",
+			Email:                 "transition@example.com",
+			DocumentNumber:        "52998224725",
+			VerificationTokenHash: "transition-test-hash",
+			TokenExpiresAt:        time.Now().Add(1 * time.Hour),
+			Status:                models.StatusPending,
+		}
+		require.NoError(t, repo.Create(context.Background(), request))
+
+		event, err := request.TransitionTo(models.StatusVerified, models.ActorCustomer, "e-mail verificado")
+		require.NoError(t, err)
+
+		err = repo.SaveTransition(context.Background(), request, event)
+		require.NoError(t, err)
+
+		var events []models.OnboardingRequestEvent
+		require.NoError(t, db.Where("onboarding_request_id = ?", request.ID).Find(&events).Error)
+		require.Len(t, events, 1)
+		assert.Equal(t, models.StatusPending, events[0].FromStatus)
+		assert.Equal(t, models.StatusVerified, events[0].ToStatus)
+		assert.Equal(t, models.ActorCustomer, events[0].Actor)
+	})
+}
+
+func TestOnboardingRequestExpireStale(t *testing.T) {
+	t.Run("#method ExpireStale", func(t *testing.T) {
+		stale := &models.OnboardingRequest{
+			FullName:              "Stale User",
+			Email:                 "stale@example.com",
+			DocumentNumber:        "11144477735",
+			VerificationTokenHash: "stale-test-hash",
+			TokenExpiresAt:        time.Now().Add(-1 * time.Hour),
+			Status:                models.StatusPending,
+		}
+		require.NoError(t, repo.Create(context.Background(), stale))
+
+		expired, err := repo.ExpireStale(context.Background(), time.Now(), 100)
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, expired, int64(1))
+
+		var reloaded models.OnboardingRequest
+		require.NoError(t, db.First(&reloaded, stale.ID).Error)
+		assert.Equal(t, models.StatusExpired, reloaded.Status)
+
+		_, err = repo.FindByDocumentOrEmail(context.Background(), stale.DocumentNumber, stale.Email)
+		assert.ErrorIs(t, err, gorm.ErrRecordNotFound, "expired requests must not block a new onboarding")
+
+		restarted := &models.OnboardingRequest{
+			FullName:              "Stale User",
+			Email:                 stale.Email,
+			DocumentNumber:        stale.DocumentNumber,
+			VerificationTokenHash: "restarted-test-hash",
+			TokenExpiresAt:        time.Now().Add(1 * time.Hour),
+			Status:                models.StatusPending,
+		}
+		assert.NoError(t, repo.Create(context.Background(), restarted))
+	})
+}
+
+func TestLegalRepresentativeListPendingByUser(t *testing.T) {
+	t.Run("#method ListPendingByUser", func(t *testing.T) {
+		representativeRepo := repositories.NewLegalRepresentativeRepository(db)
+
+		var userID int64
+		require.NoError(t, db.Raw(`
+			INSERT INTO "user".users (public_id, full_name, email, document_number, password_hash)
+			VALUES ('01JAX5T0K1D6S0ZB7W3Q8YV2NM', 'Maria Souza', 'maria@example.com', '68219090081', 'hash')
+			RETURNING id`).Scan(&userID).Error)
+
+		open := &models.OnboardingRequest{
+			FullName:              "Acme Comércio LTDA",
+			Email:                 "acme@example.com",
+			DocumentNumber:        "12ABC34501DE35",
+			CustomerType:          "BUSINESS",
+			VerificationTokenHash: "business-open-hash",
+			TokenExpiresAt:        time.Now().Add(1 * time.Hour),
+			Status:                models.StatusPending,
+		}
+		closed := &models.OnboardingRequest{
+			FullName:              "Closed LTDA",
+			Email:                 "closed@example.com",
+			DocumentNumber:        "11222333000181",
+			CustomerType:          "BUSINESS",
+			VerificationTokenHash: "business-closed-hash",
+			TokenExpiresAt:        time.Now().Add(1 * time.Hour),
+			Status:                models.StatusRejected,
+		}
+		require.NoError(t, repo.Create(context.Background(), open))
+		require.NoError(t, repo.Create(context.Background(), closed))
+
+		require.NoError(t, representativeRepo.Create(context.Background(), []*models.LegalRepresentative{
+			{OnboardingRequestID: open.ID, UserID: userID, DocumentNumber: "68219090081", FullName: "Maria Souza"},
+			{OnboardingRequestID: closed.ID, UserID: userID, DocumentNumber: "68219090081", FullName: "Maria Souza"},
+		}))
+
+		pending, err := representativeRepo.ListPendingByUser(context.Background(), userID)
+
+		require.NoError(t, err)
+		require.Len(t, pending, 1, "closed requests must not be listed")
+		require.NotNil(t, pending[0].OnboardingRequest)
+		assert.Equal(t, open.PublicID, pending[0].OnboardingRequest.PublicID)
+		assert.Equal(t, models.RepresentativePending, pending[0].Status)
+	})
+}
@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+	outbox_repositories "github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/validators"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidCNPJ               = errors.New("cnpj inválido")
+	ErrBusinessExists            = errors.New("o cnpj ou E-mail já está cadastrado")
+	ErrInvalidRepresentatives    = errors.New("informe de 1 a 5 representantes legais com CPFs válidos e distintos")
+	ErrRepresentativeNotCustomer = errors.New("todos os representantes legais devem ser clientes pessoa física ativos")
+)
+
+const maxLegalRepresentatives = 5
+
+// StartBusinessServiceRequest reúne os dados informados ao iniciar o onboarding de uma empresa.
+type StartBusinessServiceRequest struct {
+	CNPJ                    string
+	LegalName               string
+	TradeName               string
+	Email                   string
+	PhoneNumber             string
+	Address                 user_models.Address
+	RepresentativeDocuments []string
+	Locale                  string
+}
+
+type BusinessOnboardingService interface {
+	StartOnboardingProcess(ctx context.Context, request *StartBusinessServiceRequest) (string, error)
+}
+
+type businessOnboardingService struct {
+	transactor         database.Transactor
+	repo               repositories.OnboardingRequestRepository
+	representativeRepo repositories.LegalRepresentativeRepository
+	userRepo           user_repositories.UserRepository
+	outboxRepo         outbox_repositories.OutboxRepository
+}
+
+func NewBusinessOnboardingService(
+	transactor database.Transactor,
+	repo repositories.OnboardingRequestRepository,
+	representativeRepo repositories.LegalRepresentativeRepository,
+	userRepo user_repositories.UserRepository,
+	outboxRepo outbox_repositories.OutboxRepository,
+) BusinessOnboardingService {
+	return &businessOnboardingService{
+		transactor:         transactor,
+		repo:               repo,
+		representativeRepo: representativeRepo,
+		userRepo:           userRepo,
+		outboxRepo:         outboxRepo,
+	}
+}
+
+// StartOnboardingProcess abre uma solicitação PJ e registra seus representantes legais, que precisam
+// ser clientes pessoa física ativos. A verificação de e-mail e celular segue o fluxo da pessoa física;
+// a conclusão depende, além disso, da aprovação de todos os representantes.
+func (s *businessOnboardingService) StartOnboardingProcess(ctx context.Context, request *StartBusinessServiceRequest) (string, error) {
+	cnpj := validators.NormalizeCNPJ(request.CNPJ)
+	if !validators.IsValidCNPJ(cnpj) {
+		return "", ErrInvalidCNPJ
+	}
+
+	if !validators.IsValidBrazilianMobile(request.PhoneNumber) {
+		return "", ErrInvalidPhone
+	}
+
+	address, err := normalizeAddress(request.Address)
+	if err != nil {
+		return "", err
+	}
+
+	representatives, err := s.findRepresentatives(ctx, request.RepresentativeDocuments)
+	if err != nil {
+		return "", err
+	}
+
+	if err := ensureNoActiveRequest(ctx, s.repo, cnpj, request.Email); err != nil {
+		if errors.Is(err, ErrUserExists) {
+			return "", ErrBusinessExists
+		}
+		return "", err
+	}
+
+	rawToken, hashedToken, err := crypto.GenerateVerificationToken()
+	if err != nil {
+		log.Printf("Error generating verification token: %v", err)
+		return "", ErrInternalServer
+	}
+
+	now := time.Now()
+	newRequest := &models.OnboardingRequest{
+		FullName:              strings.TrimSpace(request.LegalName),
+		TradeName:             strings.TrimSpace(request.TradeName),
+		Email:                 request.Email,
+		DocumentNumber:        cnpj,
+		CustomerType:          user_models.CustomerTypeBusiness,
+		PhoneNumber:           request.PhoneNumber,
+		Address:               address,
+		Locale:                request.Locale,
+		VerificationTokenHash: hashedToken,
+		TokenExpiresAt:        now.Add(verificationTokenTTL),
+		Status:                models.StatusPending,
+		LastEmailSentAt:       &now,
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.repo.WithTx(tx).Create(ctx, newRequest); err != nil {
+			return err
+		}
+
+		for _, representative := range representatives {
+			representative.OnboardingRequestID = newRequest.ID
+		}
+		if err := s.representativeRepo.WithTx(tx).Create(ctx, representatives); err != nil {
+			return err
+		}
+
+		return s.outboxRepo.WithTx(tx).EnqueueEmail(ctx, newVerificationEmail(newRequest, rawToken))
+	})
+	if err != nil {
+		log.Printf("Error creating business onboarding request: %v", err)
+		return "", ErrInternalServer
+	}
+
+	log.Println("Business onboarding process started successfully")
+	return newRequest.PublicID, nil
+}
+
+// findRepresentatives valida a lista de CPFs e carrega o cliente de cada um deles.
+func (s *businessOnboardingService) findRepresentatives(ctx context.Context, documents []string) ([]*models.LegalRepresentative, error) {
+	if len(documents) == 0 || len(documents) > maxLegalRepresentatives {
+		return nil, ErrInvalidRepresentatives
+	}
+
+	seen := make(map[string]bool, len(documents))
+	representatives := make([]*models.LegalRepresentative, 0, len(documents))
+
+	for _, document := range documents {
+		if !validators.IsValidCPF(document) || seen[document] {
+			return nil, ErrInvalidRepresentatives
+		}
+		seen[document] = true
+
+		user, err := s.userRepo.FindByDocument(ctx, document)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrRepresentativeNotCustomer
+			}
+			log.Printf("Error finding legal representative: %v", err)
+			return nil, ErrInternalServer
+		}
+
+		if user.CustomerType != user_models.CustomerTypeIndividual || user.Status != user_models.StatusActive {
+			return nil, ErrRepresentativeNotCustomer
+		}
+
+		representatives = append(representatives, &models.LegalRepresentative{
+			UserID:         user.ID,
+			DocumentNumber: user.DocumentNumber,
+			FullName:       user.FullName,
+			Status:         models.RepresentativePending,
+		})
+	}
+
+	return representatives, nil
+}
@@ -0,0 +1,165 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const (
+	validCNPJ         = "12ABC34501DE35"
+	representativeCPF = "68219090081"
+	businessEmail     = "acme@example.com"
+)
+
+func newBusinessOnboardingService() (services.BusinessOnboardingService, *mocks.MockOnboardingRepository, *mocks.MockLegalRepresentativeRepository, *mocks.MockUserRepository, *mocks.MockOutboxRepository) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockRepresentativeRepo := new(mocks.MockLegalRepresentativeRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	mockOutbox := new(mocks.MockOutboxRepository)
+
+	service := services.NewBusinessOnboardingService(new(mocks.MockTransactor), mockRepo, mockRepresentativeRepo, mockUserRepo, mockOutbox)
+	return service, mockRepo, mockRepresentativeRepo, mockUserRepo, mockOutbox
+}
+
+func newStartBusinessRequest() *services.StartBusinessServiceRequest {
+	return &services.StartBusinessServiceRequest{
+		CNPJ:                    "12.abc.345/01de-35",
+		LegalName:               "Acme Comércio LTDA",
+		TradeName:               "Acme",
+		Email:                   businessEmail,
+		PhoneNumber:             validPhone,
+		Address:                 validAddress,
+		RepresentativeDocuments: []string{representativeCPF},
+	}
+}
+
+func TestBusinessOnboarding_StartOnboardingProcess_Success(t *testing.T) {
+	service, mockRepo, mockRepresentativeRepo, mockUserRepo, mockOutbox := newBusinessOnboardingService()
+
+	representative := &user_models.User{
+		ID:             42,
+		FullName:       "Maria Souza",
+		DocumentNumber: representativeCPF,
+		CustomerType:   user_models.CustomerTypeIndividual,
+		Status:         user_models.StatusActive,
+	}
+
+	mockUserRepo.On("FindByDocument", representativeCPF).Return(representative, nil)
+	mockRepo.On("FindByDocumentOrEmail", validCNPJ, businessEmail).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", mock.MatchedBy(func(req *models.OnboardingRequest) bool {
+		return req.IsBusiness() && req.DocumentNumber == validCNPJ && req.FullName == "Acme Comércio LTDA" && req.TradeName == "Acme"
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.OnboardingRequest).ID = 7
+	}).Return(nil)
+	mockRepresentativeRepo.On("Create", mock.MatchedBy(func(representatives []*models.LegalRepresentative) bool {
+		return len(representatives) == 1 &&
+			representatives[0].OnboardingRequestID == 7 &&
+			representatives[0].UserID == 42 &&
+			representatives[0].Status == models.RepresentativePending
+	})).Return(nil)
+	mockOutbox.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.To == businessEmail && req.TemplateID == notification.TemplateVerificationEmail
+	})).Return(nil)
+
+	_, err := service.StartOnboardingProcess(context.Background(), newStartBusinessRequest())
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepresentativeRepo.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
+}
+
+func TestBusinessOnboarding_StartOnboardingProcess_InvalidCNPJ(t *testing.T) {
+	service, mockRepo, _, mockUserRepo, _ := newBusinessOnboardingService()
+
+	request := newStartBusinessRequest()
+	request.CNPJ = "12ABC34501DE36"
+
+	_, err := service.StartOnboardingProcess(context.Background(), request)
+
+	assert.Equal(t, services.ErrInvalidCNPJ, err)
+	mockUserRepo.AssertNotCalled(t, "FindByDocument", mock.Anything)
+	mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestBusinessOnboarding_StartOnboardingProcess_InvalidRepresentatives(t *testing.T) {
+	tests := []struct {
+		name      string
+		documents []string
+	}{
+		{"No representatives", nil},
+		{"Invalid CPF", []string{"12345678900"}},
+		{"Duplicated CPF", []string{representativeCPF, representativeCPF}},
+		{"Too many representatives", []string{"1", "2", "3", "4", "5", "6"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo, _, mockUserRepo, _ := newBusinessOnboardingService()
+			mockUserRepo.On("FindByDocument", representativeCPF).Return(&user_models.User{
+				CustomerType: user_models.CustomerTypeIndividual,
+				Status:       user_models.StatusActive,
+			}, nil)
+
+			request := newStartBusinessRequest()
+			request.RepresentativeDocuments = tt.documents
+
+			_, err := service.StartOnboardingProcess(context.Background(), request)
+
+			assert.Equal(t, services.ErrInvalidRepresentatives, err)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestBusinessOnboarding_StartOnboardingProcess_RepresentativeNotCustomer(t *testing.T) {
+	tests := []struct {
+		name string
+		user *user_models.User
+		err  error
+	}{
+		{"Unknown CPF", nil, gorm.ErrRecordNotFound},
+		{"Inactive customer", &user_models.User{CustomerType: user_models.CustomerTypeIndividual, Status: user_models.StatusInactive}, nil},
+		{"Business customer", &user_models.User{CustomerType: user_models.CustomerTypeBusiness, Status: user_models.StatusActive}, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service, mockRepo, _, mockUserRepo, _ := newBusinessOnboardingService()
+			if tt.user == nil {
+				mockUserRepo.On("FindByDocument", representativeCPF).Return(nil, tt.err)
+			} else {
+				mockUserRepo.On("FindByDocument", representativeCPF).Return(tt.user, tt.err)
+			}
+
+			_, err := service.StartOnboardingProcess(context.Background(), newStartBusinessRequest())
+
+			assert.Equal(t, services.ErrRepresentativeNotCustomer, err)
+			mockRepo.AssertNotCalled(t, "Create", mock.Anything)
+		})
+	}
+}
+
+func TestBusinessOnboarding_StartOnboardingProcess_BusinessExists(t *testing.T) {
+	service, mockRepo, _, mockUserRepo, mockOutbox := newBusinessOnboardingService()
+
+	mockUserRepo.On("FindByDocument", representativeCPF).Return(&user_models.User{
+		CustomerType: user_models.CustomerTypeIndividual,
+		Status:       user_models.StatusActive,
+	}, nil)
+	mockRepo.On("FindByDocumentOrEmail", validCNPJ, businessEmail).Return(&models.OnboardingRequest{Status: models.StatusCompleted}, nil)
+
+	_, err := service.StartOnboardingProcess(context.Background(), newStartBusinessRequest())
+
+	assert.Equal(t, services.ErrBusinessExists, err)
+	mockOutbox.AssertNotCalled(t, "EnqueueEmail", mock.Anything)
+}
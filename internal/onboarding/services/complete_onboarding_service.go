@@ -1,10 +1,13 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"log"
 	"time"
 
+	consent_services "github.com/high-effort-low-stress/go-bank-api/internal/consents/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
 	user_services "github.com/high-effort-low-stress/go-bank-api/internal/users/services"
@@ -15,74 +18,182 @@ import (
 
 var (
 	ErrRequestNotVerified  = errors.New("a solicitação de onboarding não foi verificada")
+	ErrRequestNotApproved  = errors.New("a documentação da solicitação ainda não foi aprovada")
+	ErrPhoneNotVerified    = errors.New("o celular da solicitação não foi verificado")
+	ErrApprovalsPending    = errors.New("nem todos os representantes legais aprovaram a abertura da conta")
 	ErrPasswordsDoNotMatch = errors.New("as senhas não coincidem")
 	ErrWeakPassword        = errors.New("a senha não atende aos critérios de segurança")
+	ErrOutdatedTerms       = errors.New("a versão dos termos de uso aceita não é a vigente")
 )
 
+// CompleteServiceRequest contém a senha escolhida e o aceite dos termos feito no formulário.
+// UserAgent e IPAddress ficam registrados como prova do aceite.
+type CompleteServiceRequest struct {
+	Token                string
+	Password             string
+	ConfirmPassword      string
+	AcceptedTermsVersion string
+	UserAgent            string
+	IPAddress            string
+}
+
+// CompleteOnboardingResult contém os dados da conta aberta ao final do onboarding.
+type CompleteOnboardingResult struct {
+	AccountPublicID string
+	AgencyNumber    string
+	AccountNumber   string
+}
+
 type CompleteOnboardingService interface {
-	Execute(token, password, confirmPassword string) error
+	Execute(ctx context.Context, request *CompleteServiceRequest) (*CompleteOnboardingResult, error)
 }
 
 type completeOnboardingService struct {
-	onboardingRepo    repositories.OnboardingRequestRepository
-	createUserService user_services.CreateUserService
+	transactor         database.Transactor
+	onboardingRepo     repositories.OnboardingRequestRepository
+	representativeRepo repositories.LegalRepresentativeRepository
+	createUserService  user_services.CreateUserService
+	consentService     consent_services.ConsentService
 }
 
 func NewCompleteOnboardingService(
+	transactor database.Transactor,
 	onboardingRepo repositories.OnboardingRequestRepository,
+	representativeRepo repositories.LegalRepresentativeRepository,
 	createUserService user_services.CreateUserService,
+	consentService consent_services.ConsentService,
 ) CompleteOnboardingService {
-	return &completeOnboardingService{onboardingRepo: onboardingRepo, createUserService: createUserService}
+	return &completeOnboardingService{
+		transactor:         transactor,
+		onboardingRepo:     onboardingRepo,
+		representativeRepo: representativeRepo,
+		createUserService:  createUserService,
+		consentService:     consentService,
+	}
 }
 
-func (s *completeOnboardingService) Execute(token, password, confirmPassword string) error {
-	if password != confirmPassword {
-		return ErrPasswordsDoNotMatch
+// Execute cria o usuário e conclui a solicitação em uma única transação, desde que a documentação
+// tenha sido aprovada, o celular verificado e, para pessoa jurídica, todos os representantes tenham
+// aprovado. O aceite dos termos vigentes é gravado na mesma transação. A solicitação fica
+// bloqueada até o commit, então completes concorrentes com o mesmo token são serializados e
+// apenas o primeiro cria o usuário.
+func (s *completeOnboardingService) Execute(ctx context.Context, request *CompleteServiceRequest) (*CompleteOnboardingResult, error) {
+	if request.Password != request.ConfirmPassword {
+		return nil, ErrPasswordsDoNotMatch
+	}
+
+	if !validators.ValidatePasswordPattern(request.Password) {
+		return nil, ErrWeakPassword
 	}
 
-	if !validators.ValidatePasswordPattern(password) {
-		return ErrWeakPassword
+	var result *CompleteOnboardingResult
+	var completionErr error
+	err := s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		result, completionErr = s.completeWithinTx(ctx, tx, request)
+		return completionErr
+	})
+	if completionErr != nil {
+		return nil, completionErr
+	}
+	if err != nil {
+		log.Printf("Error committing onboarding completion: %v", err)
+		return nil, ErrInternalServer
 	}
 
-	hashedToken := crypto.HashTokenSHA256(token)
-	onboardingRequest, err := s.onboardingRepo.FindByVerificationTokenHash(hashedToken)
+	return result, nil
+}
+
+func (s *completeOnboardingService) completeWithinTx(ctx context.Context, tx *gorm.DB, request *CompleteServiceRequest) (*CompleteOnboardingResult, error) {
+	onboardingRepo := s.onboardingRepo.WithTx(tx)
+
+	onboardingRequest, err := onboardingRepo.FindByVerificationTokenHashForUpdate(ctx, crypto.HashTokenSHA256(request.Token))
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return ErrInvalidToken
+			return nil, ErrInvalidToken
 		}
 		log.Printf("Error finding onboarding request by token hash: %v", err)
-		return ErrInternalServer
+		return nil, ErrInternalServer
 	}
 
 	if time.Now().After(onboardingRequest.TokenExpiresAt) {
-		return ErrExpiredToken
+		return nil, ErrExpiredToken
 	}
 
 	if onboardingRequest.Status == models.StatusCompleted {
-		return ErrAlreadyVerified
+		return nil, ErrAlreadyVerified
+	}
+
+	if !onboardingRequest.Status.CanTransitionTo(models.StatusCompleted) {
+		switch onboardingRequest.Status {
+		case models.StatusPending:
+			return nil, ErrRequestNotVerified
+		case models.StatusVerified, models.StatusUnderReview:
+			return nil, ErrRequestNotApproved
+		}
+		return nil, ErrRequestClosed
 	}
 
-	if onboardingRequest.Status != models.StatusVerified {
-		return ErrRequestNotVerified
+	if !onboardingRequest.IsPhoneVerified() {
+		return nil, ErrPhoneNotVerified
 	}
 
-	_, _, err = s.createUserService.Execute(&user_services.CreateServiceRequest{
-		FullName:       onboardingRequest.FullName,
-		Email:          onboardingRequest.Email,
-		DocumentNumber: onboardingRequest.DocumentNumber,
-		Password:       password,
+	if onboardingRequest.IsBusiness() {
+		representatives, err := s.representativeRepo.WithTx(tx).ListByRequest(ctx, onboardingRequest.ID)
+		if err != nil {
+			log.Printf("Error listing legal representatives: %v", err)
+			return nil, ErrInternalServer
+		}
+		if !models.AllRepresentativesApproved(representatives) {
+			return nil, ErrApprovalsPending
+		}
+	}
+
+	user, account, err := s.createUserService.WithTx(tx).Execute(ctx, &user_services.CreateServiceRequest{
+		FullName:           onboardingRequest.FullName,
+		Email:              onboardingRequest.Email,
+		DocumentNumber:     onboardingRequest.DocumentNumber,
+		CustomerType:       onboardingRequest.CustomerType,
+		TradeName:          onboardingRequest.TradeName,
+		PhoneNumber:        onboardingRequest.PhoneNumber,
+		BirthDate:          onboardingRequest.BirthDate,
+		MotherName:         onboardingRequest.MotherName,
+		PoliticallyExposed: onboardingRequest.PoliticallyExposed,
+		Address:            onboardingRequest.Address,
+		Locale:             onboardingRequest.Locale,
+		Password:           request.Password,
 	})
 	if err != nil {
 		log.Printf("Error creating user and account: %v", err)
-		return ErrInternalServer
+		return nil, ErrInternalServer
+	}
+
+	err = s.consentService.WithTx(tx).AcceptAtOnboarding(ctx, user.ID, request.AcceptedTermsVersion, consent_services.ClientInfo{
+		UserAgent: request.UserAgent,
+		IPAddress: request.IPAddress,
+	})
+	if err != nil {
+		if errors.Is(err, consent_services.ErrOutdatedTermsVersion) {
+			return nil, ErrOutdatedTerms
+		}
+		log.Printf("Error recording onboarding consents: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	event, err := onboardingRequest.TransitionTo(models.StatusCompleted, models.ActorCustomer, "cadastro concluído")
+	if err != nil {
+		log.Printf("Error completing onboarding request: %v", err)
+		return nil, ErrInternalServer
 	}
 
-	onboardingRequest.Status = models.StatusCompleted
-	err = s.onboardingRepo.Update(onboardingRequest)
+	err = onboardingRepo.SaveTransition(ctx, onboardingRequest, event)
 	if err != nil {
 		log.Printf("Error updating onboarding request: %v", err)
-		return ErrInternalServer
+		return nil, ErrInternalServer
 	}
 
-	return nil
+	return &CompleteOnboardingResult{
+		AccountPublicID: account.PublicID,
+		AgencyNumber:    account.AgencyNumber,
+		AccountNumber:   account.FormattedAccountNumber(),
+	}, nil
 }
@@ -1,10 +1,12 @@
 package services_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
 
+	consent_services "github.com/high-effort-low-stress/go-bank-api/internal/consents/services"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
 	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
@@ -16,51 +18,111 @@ import (
 	"gorm.io/gorm"
 )
 
+func completeRequest(token, password, confirmPassword string) *services.CompleteServiceRequest {
+	return &services.CompleteServiceRequest{
+		Token:                token,
+		Password:             password,
+		ConfirmPassword:      confirmPassword,
+		AcceptedTermsVersion: "1.0",
+		UserAgent:            "GoBank/1.0",
+		IPAddress:            "203.0.113.10",
+	}
+}
+
 func TestCompleteOnboardingService_Execute_Success(t *testing.T) {
 	mockRepo := new(mocks.MockOnboardingRepository)
 	mockCreateUserSvc := new(mocks.MockCreateUserService)
-	service := services.NewCompleteOnboardingService(mockRepo, mockCreateUserSvc)
+	mockConsentSvc := new(mocks.MockConsentService)
+	mockTransactor := new(mocks.MockTransactor)
+	service := services.NewCompleteOnboardingService(mockTransactor, mockRepo, new(mocks.MockLegalRepresentativeRepository), mockCreateUserSvc, mockConsentSvc)
 
 	token := "valid-token"
 	password := "StrongPassword123!"
 	hashedToken := crypto.HashTokenSHA256(token)
+	phoneVerifiedAt := time.Now()
+	birthDate := time.Date(1990, time.May, 10, 0, 0, 0, 0, time.UTC)
 
 	request := &models.OnboardingRequest{
-		FullName:       "John Doe",
-		Email:          "john@example.com",
-		DocumentNumber: "12345678900",
-		Status:         models.StatusVerified,
-		TokenExpiresAt: time.Now().Add(1 * time.Hour),
+		FullName:           "John Doe",
+		BirthDate:          &birthDate,
+		MotherName:         "Maria Doe",
+		PoliticallyExposed: true,
+		Email:              "john@example.com",
+		DocumentNumber:     "12345678900",
+		PhoneNumber:        "+5511912345678",
+		Address:            user_models.Address{CEP: "01310100", Street: "Avenida Paulista", Number: "1000", Neighborhood: "Bela Vista", City: "São Paulo", State: "SP"},
+		Locale:             "en",
+		Status:             models.StatusApproved,
+		TokenExpiresAt:     time.Now().Add(1 * time.Hour),
+		PhoneVerifiedAt:    &phoneVerifiedAt,
 	}
 
-	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
+	mockRepo.On("FindByVerificationTokenHashForUpdate", hashedToken).Return(request, nil)
 	mockCreateUserSvc.On("Execute", mock.MatchedBy(func(req *user_services.CreateServiceRequest) bool {
-		return req.Email == request.Email && req.Password == password
-	})).Return(&user_models.User{}, &user_models.Account{}, nil)
-	mockRepo.On("Update", mock.AnythingOfType("*models.OnboardingRequest")).Return(nil)
-
-	err := service.Execute(token, password, password)
+		return req.Email == request.Email && req.PhoneNumber == request.PhoneNumber && req.Address == request.Address &&
+			req.BirthDate == request.BirthDate && req.MotherName == "Maria Doe" && req.PoliticallyExposed &&
+			req.Locale == "en" && req.Password == password
+	})).Return(&user_models.User{ID: 42}, &user_models.Account{
+		PublicID:      "01JAX5T0K1D6S0ZB7W3Q8YV2NM",
+		AgencyNumber:  "0001",
+		AccountNumber: "122504005",
+	}, nil)
+	mockConsentSvc.On("AcceptAtOnboarding", int64(42), "1.0", consent_services.ClientInfo{UserAgent: "GoBank/1.0", IPAddress: "203.0.113.10"}).Return(nil)
+	mockRepo.On("SaveTransition", mock.AnythingOfType("*models.OnboardingRequest"), mock.AnythingOfType("*models.OnboardingRequestEvent")).Return(nil)
+
+	result, err := service.Execute(context.Background(), completeRequest(token, password, password))
 
 	assert.NoError(t, err)
 	assert.Equal(t, models.StatusCompleted, request.Status)
+	assert.Equal(t, "01JAX5T0K1D6S0ZB7W3Q8YV2NM", result.AccountPublicID)
+	assert.Equal(t, "0001", result.AgencyNumber)
+	assert.Equal(t, "12250400-5", result.AccountNumber)
+	assert.Equal(t, 1, mockTransactor.Calls, "user creation, consent and request update must share a transaction")
 	mockRepo.AssertExpectations(t)
 	mockCreateUserSvc.AssertExpectations(t)
+	mockConsentSvc.AssertExpectations(t)
+}
+
+func TestCompleteOnboardingService_Execute_OutdatedTerms(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockCreateUserSvc := new(mocks.MockCreateUserService)
+	mockConsentSvc := new(mocks.MockConsentService)
+	service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockLegalRepresentativeRepository), mockCreateUserSvc, mockConsentSvc)
+
+	token := "token"
+	password := "StrongPassword123!"
+	phoneVerifiedAt := time.Now()
+	request := &models.OnboardingRequest{
+		Status:          models.StatusApproved,
+		TokenExpiresAt:  time.Now().Add(1 * time.Hour),
+		PhoneVerifiedAt: &phoneVerifiedAt,
+	}
+
+	mockRepo.On("FindByVerificationTokenHashForUpdate", crypto.HashTokenSHA256(token)).Return(request, nil)
+	mockCreateUserSvc.On("Execute", mock.Anything).Return(&user_models.User{ID: 42}, &user_models.Account{}, nil)
+	mockConsentSvc.On("AcceptAtOnboarding", int64(42), "1.0", mock.Anything).Return(consent_services.ErrOutdatedTermsVersion)
+
+	_, err := service.Execute(context.Background(), completeRequest(token, password, password))
+
+	assert.ErrorIs(t, err, services.ErrOutdatedTerms)
+	assert.Equal(t, models.StatusApproved, request.Status)
+	mockRepo.AssertNotCalled(t, "SaveTransition", mock.Anything, mock.Anything)
 }
 
 func TestCompleteOnboardingService_Execute_PasswordsDoNotMatch(t *testing.T) {
-	service := services.NewCompleteOnboardingService(nil, nil)
+	service := services.NewCompleteOnboardingService(nil, nil, nil, nil, nil)
 
-	err := service.Execute("token", "pass1", "pass2")
+	_, err := service.Execute(context.Background(), completeRequest("token", "pass1", "pass2"))
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrPasswordsDoNotMatch, err)
 }
 
 func TestCompleteOnboardingService_Execute_WeakPassword(t *testing.T) {
-	service := services.NewCompleteOnboardingService(nil, nil)
+	service := services.NewCompleteOnboardingService(nil, nil, nil, nil, nil)
 
 	// Senha curta e sem caracteres especiais
-	err := service.Execute("token", "123", "123")
+	_, err := service.Execute(context.Background(), completeRequest("token", "123", "123"))
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrWeakPassword, err)
@@ -68,15 +130,15 @@ func TestCompleteOnboardingService_Execute_WeakPassword(t *testing.T) {
 
 func TestCompleteOnboardingService_Execute_TokenNotFound(t *testing.T) {
 	mockRepo := new(mocks.MockOnboardingRepository)
-	service := services.NewCompleteOnboardingService(mockRepo, nil)
+	service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockLegalRepresentativeRepository), nil, new(mocks.MockConsentService))
 
 	token := "non-existent"
 	password := "StrongPassword123!"
 	hashedToken := crypto.HashTokenSHA256(token)
 
-	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("FindByVerificationTokenHashForUpdate", hashedToken).Return(nil, gorm.ErrRecordNotFound)
 
-	err := service.Execute(token, password, password)
+	_, err := service.Execute(context.Background(), completeRequest(token, password, password))
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrInvalidToken, err)
@@ -84,7 +146,7 @@ func TestCompleteOnboardingService_Execute_TokenNotFound(t *testing.T) {
 
 func TestCompleteOnboardingService_Execute_TokenExpired(t *testing.T) {
 	mockRepo := new(mocks.MockOnboardingRepository)
-	service := services.NewCompleteOnboardingService(mockRepo, nil)
+	service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockLegalRepresentativeRepository), nil, new(mocks.MockConsentService))
 
 	token := "expired"
 	password := "StrongPassword123!"
@@ -93,9 +155,9 @@ func TestCompleteOnboardingService_Execute_TokenExpired(t *testing.T) {
 	request := &models.OnboardingRequest{
 		TokenExpiresAt: time.Now().Add(-1 * time.Hour),
 	}
-	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
+	mockRepo.On("FindByVerificationTokenHashForUpdate", hashedToken).Return(request, nil)
 
-	err := service.Execute(token, password, password)
+	_, err := service.Execute(context.Background(), completeRequest(token, password, password))
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrExpiredToken, err)
@@ -103,7 +165,7 @@ func TestCompleteOnboardingService_Execute_TokenExpired(t *testing.T) {
 
 func TestCompleteOnboardingService_Execute_AlreadyCompleted(t *testing.T) {
 	mockRepo := new(mocks.MockOnboardingRepository)
-	service := services.NewCompleteOnboardingService(mockRepo, nil)
+	service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockLegalRepresentativeRepository), nil, new(mocks.MockConsentService))
 
 	token := "completed"
 	password := "StrongPassword123!"
@@ -113,9 +175,9 @@ func TestCompleteOnboardingService_Execute_AlreadyCompleted(t *testing.T) {
 		Status:         models.StatusCompleted,
 		TokenExpiresAt: time.Now().Add(1 * time.Hour),
 	}
-	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
+	mockRepo.On("FindByVerificationTokenHashForUpdate", hashedToken).Return(request, nil)
 
-	err := service.Execute(token, password, password)
+	_, err := service.Execute(context.Background(), completeRequest(token, password, password))
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrAlreadyVerified, err)
@@ -123,7 +185,7 @@ func TestCompleteOnboardingService_Execute_AlreadyCompleted(t *testing.T) {
 
 func TestCompleteOnboardingService_Execute_RequestNotVerified(t *testing.T) {
 	mockRepo := new(mocks.MockOnboardingRepository)
-	service := services.NewCompleteOnboardingService(mockRepo, nil)
+	service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockLegalRepresentativeRepository), nil, new(mocks.MockConsentService))
 
 	token := "pending"
 	password := "StrongPassword123!"
@@ -134,55 +196,180 @@ func TestCompleteOnboardingService_Execute_RequestNotVerified(t *testing.T) {
 		Status:         models.StatusPending,
 		TokenExpiresAt: time.Now().Add(1 * time.Hour),
 	}
-	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
+	mockRepo.On("FindByVerificationTokenHashForUpdate", hashedToken).Return(request, nil)
 
-	err := service.Execute(token, password, password)
+	_, err := service.Execute(context.Background(), completeRequest(token, password, password))
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrRequestNotVerified, err)
 }
 
+func TestCompleteOnboardingService_Execute_RequestNotApproved(t *testing.T) {
+	for _, status := range []models.OnboardingStatus{models.StatusVerified, models.StatusUnderReview} {
+		t.Run(string(status), func(t *testing.T) {
+			mockRepo := new(mocks.MockOnboardingRepository)
+			mockCreateUserSvc := new(mocks.MockCreateUserService)
+			service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockLegalRepresentativeRepository), mockCreateUserSvc, new(mocks.MockConsentService))
+
+			token := "not-approved"
+			password := "StrongPassword123!"
+			now := time.Now()
+
+			request := &models.OnboardingRequest{
+				Status:          status,
+				PhoneVerifiedAt: &now,
+				TokenExpiresAt:  now.Add(1 * time.Hour),
+			}
+			mockRepo.On("FindByVerificationTokenHashForUpdate", crypto.HashTokenSHA256(token)).Return(request, nil)
+
+			_, err := service.Execute(context.Background(), completeRequest(token, password, password))
+
+			assert.Equal(t, services.ErrRequestNotApproved, err)
+			mockCreateUserSvc.AssertNotCalled(t, "Execute", mock.Anything)
+		})
+	}
+}
+
+func TestCompleteOnboardingService_Execute_PhoneNotVerified(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockCreateUserSvc := new(mocks.MockCreateUserService)
+	service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockLegalRepresentativeRepository), mockCreateUserSvc, new(mocks.MockConsentService))
+
+	token := "phone-pending"
+	password := "StrongPassword123!"
+	hashedToken := crypto.HashTokenSHA256(token)
+
+	// Documentação aprovada, mas o código do celular ainda não foi confirmado
+	request := &models.OnboardingRequest{
+		Status:         models.StatusApproved,
+		TokenExpiresAt: time.Now().Add(1 * time.Hour),
+	}
+	mockRepo.On("FindByVerificationTokenHashForUpdate", hashedToken).Return(request, nil)
+
+	_, err := service.Execute(context.Background(), completeRequest(token, password, password))
+
+	assert.Equal(t, services.ErrPhoneNotVerified, err)
+	mockCreateUserSvc.AssertNotCalled(t, "Execute", mock.Anything)
+	mockRepo.AssertNotCalled(t, "SaveTransition", mock.Anything, mock.Anything)
+}
+
+func TestCompleteOnboardingService_Execute_BusinessApprovalsPending(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockRepresentativeRepo := new(mocks.MockLegalRepresentativeRepository)
+	mockCreateUserSvc := new(mocks.MockCreateUserService)
+	mockConsentSvc := new(mocks.MockConsentService)
+	service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, mockRepresentativeRepo, mockCreateUserSvc, mockConsentSvc)
+
+	token := "business-token"
+	password := "StrongPassword123!"
+	phoneVerifiedAt := time.Now()
+
+	request := &models.OnboardingRequest{
+		ID:              7,
+		CustomerType:    user_models.CustomerTypeBusiness,
+		Status:          models.StatusApproved,
+		TokenExpiresAt:  time.Now().Add(1 * time.Hour),
+		PhoneVerifiedAt: &phoneVerifiedAt,
+	}
+	mockRepo.On("FindByVerificationTokenHashForUpdate", crypto.HashTokenSHA256(token)).Return(request, nil)
+	mockRepresentativeRepo.On("ListByRequest", int64(7)).Return([]models.LegalRepresentative{
+		{Status: models.RepresentativeApproved},
+		{Status: models.RepresentativePending},
+	}, nil)
+
+	_, err := service.Execute(context.Background(), completeRequest(token, password, password))
+
+	assert.Equal(t, services.ErrApprovalsPending, err)
+	mockCreateUserSvc.AssertNotCalled(t, "Execute", mock.Anything)
+}
+
+func TestCompleteOnboardingService_Execute_BusinessApproved(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockRepresentativeRepo := new(mocks.MockLegalRepresentativeRepository)
+	mockCreateUserSvc := new(mocks.MockCreateUserService)
+	mockConsentSvc := new(mocks.MockConsentService)
+	service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, mockRepresentativeRepo, mockCreateUserSvc, mockConsentSvc)
+
+	token := "business-token"
+	password := "StrongPassword123!"
+	phoneVerifiedAt := time.Now()
+
+	request := &models.OnboardingRequest{
+		ID:              7,
+		FullName:        "Acme Comércio LTDA",
+		TradeName:       "Acme",
+		DocumentNumber:  "12ABC34501DE35",
+		CustomerType:    user_models.CustomerTypeBusiness,
+		Status:          models.StatusApproved,
+		TokenExpiresAt:  time.Now().Add(1 * time.Hour),
+		PhoneVerifiedAt: &phoneVerifiedAt,
+	}
+	mockRepo.On("FindByVerificationTokenHashForUpdate", crypto.HashTokenSHA256(token)).Return(request, nil)
+	mockRepresentativeRepo.On("ListByRequest", int64(7)).Return([]models.LegalRepresentative{
+		{Status: models.RepresentativeApproved},
+		{Status: models.RepresentativeApproved},
+	}, nil)
+	mockCreateUserSvc.On("Execute", mock.MatchedBy(func(req *user_services.CreateServiceRequest) bool {
+		return req.CustomerType == user_models.CustomerTypeBusiness && req.TradeName == "Acme" && req.DocumentNumber == "12ABC34501DE35"
+	})).Return(&user_models.User{ID: 9}, &user_models.Account{AccountNumber: "122504005"}, nil)
+	mockConsentSvc.On("AcceptAtOnboarding", int64(9), "1.0", mock.Anything).Return(nil)
+	mockRepo.On("SaveTransition", request, mock.Anything).Return(nil)
+
+	_, err := service.Execute(context.Background(), completeRequest(token, password, password))
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusCompleted, request.Status)
+	mockCreateUserSvc.AssertExpectations(t)
+}
+
 func TestCompleteOnboardingService_Execute_CreateUserFailure(t *testing.T) {
 	mockRepo := new(mocks.MockOnboardingRepository)
 	mockCreateUserSvc := new(mocks.MockCreateUserService)
-	service := services.NewCompleteOnboardingService(mockRepo, mockCreateUserSvc)
+	service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockLegalRepresentativeRepository), mockCreateUserSvc, new(mocks.MockConsentService))
 
 	token := "token"
 	password := "StrongPassword123!"
 	hashedToken := crypto.HashTokenSHA256(token)
+	phoneVerifiedAt := time.Now()
 
 	request := &models.OnboardingRequest{
-		Status:         models.StatusVerified,
-		TokenExpiresAt: time.Now().Add(1 * time.Hour),
+		Status:          models.StatusApproved,
+		TokenExpiresAt:  time.Now().Add(1 * time.Hour),
+		PhoneVerifiedAt: &phoneVerifiedAt,
 	}
 
-	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
+	mockRepo.On("FindByVerificationTokenHashForUpdate", hashedToken).Return(request, nil)
 	mockCreateUserSvc.On("Execute", mock.Anything).Return(nil, nil, errors.New("creation error"))
 
-	err := service.Execute(token, password, password)
+	_, err := service.Execute(context.Background(), completeRequest(token, password, password))
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrInternalServer, err)
+	mockRepo.AssertNotCalled(t, "SaveTransition", mock.Anything, mock.Anything)
 }
 
 func TestCompleteOnboardingService_Execute_UpdateRepoFailure(t *testing.T) {
 	mockRepo := new(mocks.MockOnboardingRepository)
 	mockCreateUserSvc := new(mocks.MockCreateUserService)
-	service := services.NewCompleteOnboardingService(mockRepo, mockCreateUserSvc)
+	mockConsentSvc := new(mocks.MockConsentService)
+	service := services.NewCompleteOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockLegalRepresentativeRepository), mockCreateUserSvc, mockConsentSvc)
 
 	token := "token"
 	password := "StrongPassword123!"
 	hashedToken := crypto.HashTokenSHA256(token)
+	phoneVerifiedAt := time.Now()
 	request := &models.OnboardingRequest{
-		Status:         models.StatusVerified,
-		TokenExpiresAt: time.Now().Add(1 * time.Hour),
+		Status:          models.StatusApproved,
+		TokenExpiresAt:  time.Now().Add(1 * time.Hour),
+		PhoneVerifiedAt: &phoneVerifiedAt,
 	}
 
-	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
+	mockRepo.On("FindByVerificationTokenHashForUpdate", hashedToken).Return(request, nil)
 	mockCreateUserSvc.On("Execute", mock.Anything).Return(&user_models.User{}, &user_models.Account{}, nil)
-	mockRepo.On("Update", mock.Anything).Return(errors.New("db error"))
+	mockConsentSvc.On("AcceptAtOnboarding", mock.Anything, mock.Anything, mock.Anything).Return(nil)
+	mockRepo.On("SaveTransition", mock.Anything, mock.Anything).Return(errors.New("db error"))
 
-	err := service.Execute(token, password, password)
+	_, err := service.Execute(context.Background(), completeRequest(token, password, password))
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrInternalServer, err)
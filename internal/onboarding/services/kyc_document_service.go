@@ -0,0 +1,264 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/storage"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidDocumentKind       = errors.New("tipo de arquivo inválido, use ID_FRONT, ID_BACK ou SELFIE")
+	ErrInvalidIDDocumentType     = errors.New("documento de identificação inválido, use RG ou CNH")
+	ErrDocumentTooLarge          = errors.New("o arquivo excede o tamanho máximo de 10 MB")
+	ErrUnsupportedDocument       = errors.New("formato de arquivo não suportado")
+	ErrDocumentsAlreadySubmitted = errors.New("os documentos desta solicitação já foram enviados para análise")
+)
+
+// MaxKYCDocumentSize é o tamanho máximo de cada arquivo enviado para a análise cadastral.
+const MaxKYCDocumentSize = 10 << 20
+
+// kycDocumentExtensions lista os formatos aceitos, identificados pelo conteúdo do arquivo e não
+// pelo nome ou cabeçalho informado pelo cliente. A selfie precisa ser uma foto.
+var kycDocumentExtensions = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"application/pdf": ".pdf",
+}
+
+// UploadKYCDocumentRequest reúne o arquivo enviado pelo cliente e a solicitação a que ele pertence.
+type UploadKYCDocumentRequest struct {
+	RequestPublicID string
+	Token           string
+	Kind            models.KYCDocumentKind
+	IDDocumentType  models.IDDocumentType
+	Content         io.Reader
+}
+
+// UploadKYCDocumentResult informa o arquivo gravado e o status da solicitação após o envio.
+type UploadKYCDocumentResult struct {
+	DocumentPublicID string
+	Status           models.OnboardingStatus
+}
+
+type KYCDocumentService interface {
+	Upload(ctx context.Context, request *UploadKYCDocumentRequest) (*UploadKYCDocumentResult, error)
+}
+
+type kycDocumentService struct {
+	transactor   database.Transactor
+	repo         repositories.OnboardingRequestRepository
+	documentRepo repositories.KYCDocumentRepository
+	blobStore    storage.BlobStore
+}
+
+func NewKYCDocumentService(
+	transactor database.Transactor,
+	repo repositories.OnboardingRequestRepository,
+	documentRepo repositories.KYCDocumentRepository,
+	blobStore storage.BlobStore,
+) KYCDocumentService {
+	return &kycDocumentService{
+		transactor:   transactor,
+		repo:         repo,
+		documentRepo: documentRepo,
+		blobStore:    blobStore,
+	}
+}
+
+// Upload grava um arquivo da documentação de identificação. Reenviar um tipo já enviado substitui
+// o arquivo anterior. Quando frente, verso e selfie estiverem presentes, a solicitação segue
+// para a análise cadastral e não aceita mais envios.
+func (s *kycDocumentService) Upload(ctx context.Context, request *UploadKYCDocumentRequest) (*UploadKYCDocumentResult, error) {
+	if !slices.Contains(models.RequiredKYCDocuments, request.Kind) {
+		return nil, ErrInvalidDocumentKind
+	}
+
+	if request.Kind != models.KYCDocumentSelfie && request.IDDocumentType != models.IDDocumentRG && request.IDDocumentType != models.IDDocumentCNH {
+		return nil, ErrInvalidIDDocumentType
+	}
+
+	// A rota é pública: o token e o status são conferidos antes de ler e gravar o arquivo, para que
+	// um pedido sem o token do e-mail não ocupe o armazenamento. A conferência se repete na
+	// transação, com a solicitação bloqueada.
+	onboardingRequest, err := s.repo.FindByPublicID(ctx, request.RequestPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRequestNotFound
+		}
+		log.Printf("Error finding onboarding request by public id: %v", err)
+		return nil, ErrInternalServer
+	}
+	if err := checkUploadAllowed(onboardingRequest, request.Token); err != nil {
+		return nil, err
+	}
+
+	content, err := io.ReadAll(io.LimitReader(request.Content, MaxKYCDocumentSize+1))
+	if err != nil {
+		log.Printf("Error reading KYC document: %v", err)
+		return nil, ErrInternalServer
+	}
+	if len(content) > MaxKYCDocumentSize {
+		return nil, ErrDocumentTooLarge
+	}
+
+	contentType := http.DetectContentType(content)
+	extension, ok := kycDocumentExtensions[contentType]
+	if len(content) == 0 || !ok || (request.Kind == models.KYCDocumentSelfie && contentType == "application/pdf") {
+		return nil, ErrUnsupportedDocument
+	}
+
+	checksum := sha256.Sum256(content)
+	document := &models.KYCDocument{
+		Kind:        request.Kind,
+		BlobKey:     fmt.Sprintf("kyc/%s/%s-%s%s", request.RequestPublicID, strings.ToLower(string(request.Kind)), ulid.Make(), extension),
+		ContentType: contentType,
+		SizeBytes:   int64(len(content)),
+		SHA256:      hex.EncodeToString(checksum[:]),
+	}
+	if request.Kind != models.KYCDocumentSelfie {
+		document.IDDocumentType = request.IDDocumentType
+	}
+
+	// O arquivo é gravado antes da transação para não manter a solicitação bloqueada durante a
+	// escrita. Se a transação falhar, o blob recém-gravado é removido.
+	if err := s.blobStore.Put(ctx, document.BlobKey, bytes.NewReader(content)); err != nil {
+		if errors.Is(err, storage.ErrInvalidBlobKey) {
+			return nil, ErrRequestNotFound
+		}
+		log.Printf("Error storing KYC document: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	var result *UploadKYCDocumentResult
+	var replacedBlobKey string
+	var uploadErr error
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		result, replacedBlobKey, uploadErr = s.uploadWithinTx(ctx, tx, request, document)
+		return uploadErr
+	})
+	if uploadErr == nil && err != nil {
+		log.Printf("Error committing KYC document: %v", err)
+		uploadErr = ErrInternalServer
+	}
+	if uploadErr != nil {
+		s.deleteBlob(ctx, document.BlobKey)
+		return nil, uploadErr
+	}
+
+	if replacedBlobKey != "" {
+		s.deleteBlob(ctx, replacedBlobKey)
+	}
+
+	return result, nil
+}
+
+func (s *kycDocumentService) uploadWithinTx(ctx context.Context, tx *gorm.DB, request *UploadKYCDocumentRequest, document *models.KYCDocument) (*UploadKYCDocumentResult, string, error) {
+	onboardingRepo := s.repo.WithTx(tx)
+	documentRepo := s.documentRepo.WithTx(tx)
+
+	onboardingRequest, err := onboardingRepo.FindByPublicIDForUpdate(ctx, request.RequestPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrRequestNotFound
+		}
+		log.Printf("Error finding onboarding request by public id: %v", err)
+		return nil, "", ErrInternalServer
+	}
+
+	if err := checkUploadAllowed(onboardingRequest, request.Token); err != nil {
+		return nil, "", err
+	}
+
+	document.OnboardingRequestID = onboardingRequest.ID
+
+	var replacedBlobKey string
+	existing, err := documentRepo.FindByRequestAndKind(ctx, onboardingRequest.ID, document.Kind)
+	switch {
+	case err == nil:
+		replacedBlobKey = existing.BlobKey
+		existing.IDDocumentType = document.IDDocumentType
+		existing.BlobKey = document.BlobKey
+		existing.ContentType = document.ContentType
+		existing.SizeBytes = document.SizeBytes
+		existing.SHA256 = document.SHA256
+		*document = *existing
+		err = documentRepo.Update(ctx, document)
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		err = documentRepo.Create(ctx, document)
+	}
+	if err != nil {
+		log.Printf("Error saving KYC document: %v", err)
+		return nil, "", ErrInternalServer
+	}
+
+	documents, err := documentRepo.ListByRequest(ctx, onboardingRequest.ID)
+	if err != nil {
+		log.Printf("Error listing KYC documents: %v", err)
+		return nil, "", ErrInternalServer
+	}
+
+	if models.HasAllKYCDocuments(documents) {
+		event, err := onboardingRequest.TransitionTo(models.StatusUnderReview, models.ActorCustomer, "documentos enviados para análise")
+		if err != nil {
+			return nil, "", ErrRequestClosed
+		}
+		if err := onboardingRepo.SaveTransition(ctx, onboardingRequest, event); err != nil {
+			log.Printf("Error sending onboarding request to review: %v", err)
+			return nil, "", ErrInternalServer
+		}
+	}
+
+	return &UploadKYCDocumentResult{
+		DocumentPublicID: document.PublicID,
+		Status:           onboardingRequest.Status,
+	}, replacedBlobKey, nil
+}
+
+// checkUploadAllowed confere se a solicitação aceita o envio de documentos. O token do e-mail
+// prova que quem envia os documentos é o dono da solicitação.
+func checkUploadAllowed(onboardingRequest *models.OnboardingRequest, token string) error {
+	if subtle.ConstantTimeCompare([]byte(crypto.HashTokenSHA256(token)), []byte(onboardingRequest.VerificationTokenHash)) != 1 {
+		return ErrInvalidToken
+	}
+
+	if time.Now().After(onboardingRequest.TokenExpiresAt) {
+		return ErrExpiredToken
+	}
+
+	switch onboardingRequest.Status {
+	case models.StatusVerified:
+		return nil
+	case models.StatusPending:
+		return ErrRequestNotVerified
+	case models.StatusUnderReview, models.StatusApproved:
+		return ErrDocumentsAlreadySubmitted
+	default:
+		return ErrRequestClosed
+	}
+}
+
+// deleteBlob remove um arquivo que deixou de ser referenciado. Uma falha aqui deixa apenas um
+// arquivo órfão e não afeta o envio.
+func (s *kycDocumentService) deleteBlob(ctx context.Context, key string) {
+	if err := s.blobStore.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+		log.Printf("Error deleting KYC document blob %s: %v", key, err)
+	}
+}
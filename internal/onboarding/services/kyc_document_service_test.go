@@ -0,0 +1,231 @@
+package services_test
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const (
+	kycRequestID = "01JAX5T0K1D6S0ZB7W3Q8YV2NR"
+	kycToken     = "kyc-token"
+)
+
+// pngHeader é suficiente para que http.DetectContentType identifique o arquivo como image/png.
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+type kycUploadFixture struct {
+	service      services.KYCDocumentService
+	repo         *mocks.MockOnboardingRepository
+	documentRepo *mocks.MockKYCDocumentRepository
+	blobStore    *mocks.MockBlobStore
+	request      *models.OnboardingRequest
+}
+
+func newKYCUploadFixture() *kycUploadFixture {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockDocumentRepo := new(mocks.MockKYCDocumentRepository)
+	mockBlobStore := new(mocks.MockBlobStore)
+
+	request := &models.OnboardingRequest{
+		ID:                    7,
+		PublicID:              kycRequestID,
+		VerificationTokenHash: crypto.HashTokenSHA256(kycToken),
+		TokenExpiresAt:        time.Now().Add(1 * time.Hour),
+		Status:                models.StatusVerified,
+	}
+	mockRepo.On("FindByPublicID", kycRequestID).Return(request, nil)
+	mockRepo.On("FindByPublicIDForUpdate", kycRequestID).Return(request, nil)
+
+	return &kycUploadFixture{
+		service:      services.NewKYCDocumentService(new(mocks.MockTransactor), mockRepo, mockDocumentRepo, mockBlobStore),
+		repo:         mockRepo,
+		documentRepo: mockDocumentRepo,
+		blobStore:    mockBlobStore,
+		request:      request,
+	}
+}
+
+func newUploadRequest(kind models.KYCDocumentKind, content []byte) *services.UploadKYCDocumentRequest {
+	return &services.UploadKYCDocumentRequest{
+		RequestPublicID: kycRequestID,
+		Token:           kycToken,
+		Kind:            kind,
+		IDDocumentType:  models.IDDocumentCNH,
+		Content:         bytes.NewReader(content),
+	}
+}
+
+func TestKYCDocumentService_Upload_Success(t *testing.T) {
+	f := newKYCUploadFixture()
+
+	f.blobStore.On("Put", mock.MatchedBy(func(key string) bool {
+		return strings.HasPrefix(key, "kyc/"+kycRequestID+"/id_front-") && strings.HasSuffix(key, ".png")
+	})).Return(nil)
+	f.documentRepo.On("FindByRequestAndKind", int64(7), models.KYCDocumentFront).Return(nil, gorm.ErrRecordNotFound)
+	f.documentRepo.On("Create", mock.MatchedBy(func(document *models.KYCDocument) bool {
+		return document.OnboardingRequestID == 7 &&
+			document.IDDocumentType == models.IDDocumentCNH &&
+			document.ContentType == "image/png" &&
+			document.SizeBytes == int64(len(pngHeader)) &&
+			len(document.SHA256) == 64
+	})).Return(nil)
+	f.documentRepo.On("ListByRequest", int64(7)).Return([]models.KYCDocument{{Kind: models.KYCDocumentFront}}, nil)
+
+	result, err := f.service.Upload(context.Background(), newUploadRequest(models.KYCDocumentFront, pngHeader))
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusVerified, result.Status)
+	f.documentRepo.AssertExpectations(t)
+	f.repo.AssertNotCalled(t, "SaveTransition", mock.Anything, mock.Anything)
+	f.blobStore.AssertNotCalled(t, "Delete", mock.Anything)
+}
+
+func TestKYCDocumentService_Upload_LastDocumentSendsToReview(t *testing.T) {
+	f := newKYCUploadFixture()
+
+	f.blobStore.On("Put", mock.Anything).Return(nil)
+	f.blobStore.On("Delete", "kyc/old-selfie.png").Return(nil)
+	f.documentRepo.On("FindByRequestAndKind", int64(7), models.KYCDocumentSelfie).Return(&models.KYCDocument{
+		ID:       3,
+		PublicID: "01JAX5T0K1D6S0ZB7W3Q8YV2NS",
+		Kind:     models.KYCDocumentSelfie,
+		BlobKey:  "kyc/old-selfie.png",
+	}, nil)
+	f.documentRepo.On("Update", mock.MatchedBy(func(document *models.KYCDocument) bool {
+		return document.ID == 3 && document.BlobKey != "kyc/old-selfie.png" && document.IDDocumentType == ""
+	})).Return(nil)
+	f.documentRepo.On("ListByRequest", int64(7)).Return([]models.KYCDocument{
+		{Kind: models.KYCDocumentFront},
+		{Kind: models.KYCDocumentBack},
+		{Kind: models.KYCDocumentSelfie},
+	}, nil)
+	f.repo.On("SaveTransition", f.request, mock.MatchedBy(func(event *models.OnboardingRequestEvent) bool {
+		return event.ToStatus == models.StatusUnderReview
+	})).Return(nil)
+
+	result, err := f.service.Upload(context.Background(), newUploadRequest(models.KYCDocumentSelfie, pngHeader))
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusUnderReview, result.Status)
+	assert.Equal(t, "01JAX5T0K1D6S0ZB7W3Q8YV2NS", result.DocumentPublicID)
+	f.repo.AssertExpectations(t)
+	f.blobStore.AssertExpectations(t)
+}
+
+func TestKYCDocumentService_Upload_InvalidContent(t *testing.T) {
+	tests := []struct {
+		name    string
+		kind    models.KYCDocumentKind
+		content []byte
+		err     error
+	}{
+		{"Unknown kind", models.KYCDocumentKind("PASSPORT"), pngHeader, services.ErrInvalidDocumentKind},
+		{"Empty file", models.KYCDocumentFront, nil, services.ErrUnsupportedDocument},
+		{"Plain text", models.KYCDocumentFront, []byte("not an image"), services.ErrUnsupportedDocument},
+		{"PDF selfie", models.KYCDocumentSelfie, []byte("%PDF-1.7\n"), services.ErrUnsupportedDocument},
+		{"Too large", models.KYCDocumentBack, append(pngHeader, make([]byte, services.MaxKYCDocumentSize)...), services.ErrDocumentTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newKYCUploadFixture()
+
+			_, err := f.service.Upload(context.Background(), newUploadRequest(tt.kind, tt.content))
+
+			assert.Equal(t, tt.err, err)
+			f.blobStore.AssertNotCalled(t, "Put", mock.Anything)
+		})
+	}
+}
+
+func TestKYCDocumentService_Upload_MissingIDDocumentType(t *testing.T) {
+	f := newKYCUploadFixture()
+	request := newUploadRequest(models.KYCDocumentBack, pngHeader)
+	request.IDDocumentType = ""
+
+	_, err := f.service.Upload(context.Background(), request)
+
+	assert.Equal(t, services.ErrInvalidIDDocumentType, err)
+}
+
+func TestKYCDocumentService_Upload_RejectedRequestState(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		status models.OnboardingStatus
+		err    error
+	}{
+		{"Wrong token", "other-token", models.StatusVerified, services.ErrInvalidToken},
+		{"E-mail not verified", kycToken, models.StatusPending, services.ErrRequestNotVerified},
+		{"Already under review", kycToken, models.StatusUnderReview, services.ErrDocumentsAlreadySubmitted},
+		{"Rejected", kycToken, models.StatusRejected, services.ErrRequestClosed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newKYCUploadFixture()
+			f.request.Status = tt.status
+
+			request := newUploadRequest(models.KYCDocumentFront, pngHeader)
+			request.Token = tt.token
+
+			_, err := f.service.Upload(context.Background(), request)
+
+			assert.Equal(t, tt.err, err)
+			f.blobStore.AssertNotCalled(t, "Put", mock.Anything)
+			f.repo.AssertNotCalled(t, "FindByPublicIDForUpdate", mock.Anything)
+		})
+	}
+}
+
+func TestKYCDocumentService_Upload_UnknownRequest(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockBlobStore := new(mocks.MockBlobStore)
+	mockRepo.On("FindByPublicID", kycRequestID).Return(nil, gorm.ErrRecordNotFound)
+	service := services.NewKYCDocumentService(new(mocks.MockTransactor), mockRepo, new(mocks.MockKYCDocumentRepository), mockBlobStore)
+
+	_, err := service.Upload(context.Background(), newUploadRequest(models.KYCDocumentFront, pngHeader))
+
+	assert.Equal(t, services.ErrRequestNotFound, err)
+	mockBlobStore.AssertNotCalled(t, "Put", mock.Anything)
+}
+
+func TestKYCDocumentService_Upload_StatusChangedWhileStoring(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockDocumentRepo := new(mocks.MockKYCDocumentRepository)
+	mockBlobStore := new(mocks.MockBlobStore)
+	request := &models.OnboardingRequest{
+		ID:                    7,
+		PublicID:              kycRequestID,
+		VerificationTokenHash: crypto.HashTokenSHA256(kycToken),
+		TokenExpiresAt:        time.Now().Add(1 * time.Hour),
+		Status:                models.StatusVerified,
+	}
+	locked := *request
+	locked.Status = models.StatusUnderReview
+	mockRepo.On("FindByPublicID", kycRequestID).Return(request, nil)
+	mockRepo.On("FindByPublicIDForUpdate", kycRequestID).Return(&locked, nil)
+	mockBlobStore.On("Put", mock.Anything).Return(nil)
+	var storedKey string
+	mockBlobStore.On("Delete", mock.Anything).Run(func(args mock.Arguments) {
+		storedKey = args.String(0)
+	}).Return(nil)
+	service := services.NewKYCDocumentService(new(mocks.MockTransactor), mockRepo, mockDocumentRepo, mockBlobStore)
+
+	_, err := service.Upload(context.Background(), newUploadRequest(models.KYCDocumentFront, pngHeader))
+
+	assert.Equal(t, services.ErrDocumentsAlreadySubmitted, err)
+	assert.True(t, strings.HasPrefix(storedKey, "kyc/"+kycRequestID+"/"), "the orphan blob must be deleted")
+	mockDocumentRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
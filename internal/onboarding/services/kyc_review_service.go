@@ -0,0 +1,258 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+	outbox_repositories "github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/storage"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRequestNotUnderReview = errors.New("a solicitação de onboarding não está em análise")
+	ErrDocumentNotFound      = errors.New("documento não encontrado")
+	ErrReviewReasonRequired  = errors.New("informe o motivo da reprovação")
+)
+
+const (
+	DefaultReviewListLimit = 50
+	MaxReviewListLimit     = 200
+)
+
+// completionTokenTTL é a validade do link enviado após a aprovação. É maior que a do e-mail de
+// verificação porque a análise pode terminar fora do horário em que o cliente está ativo.
+const completionTokenTTL = 72 * time.Hour
+
+var websiteCompleteURL = "complete-onboarding"
+
+type KYCReviewService interface {
+	ListUnderReview(ctx context.Context, limit, offset int) ([]models.OnboardingRequest, error)
+	ListDocuments(ctx context.Context, requestPublicID string) ([]models.KYCDocument, error)
+	OpenDocument(ctx context.Context, requestPublicID, documentPublicID string) (*models.KYCDocument, io.ReadCloser, error)
+	Approve(ctx context.Context, requestPublicID, reviewer string) error
+	Reject(ctx context.Context, requestPublicID, reviewer, reason string) error
+}
+
+type kycReviewService struct {
+	transactor   database.Transactor
+	repo         repositories.OnboardingRequestRepository
+	documentRepo repositories.KYCDocumentRepository
+	outboxRepo   outbox_repositories.OutboxRepository
+	blobStore    storage.BlobStore
+}
+
+func NewKYCReviewService(
+	transactor database.Transactor,
+	repo repositories.OnboardingRequestRepository,
+	documentRepo repositories.KYCDocumentRepository,
+	outboxRepo outbox_repositories.OutboxRepository,
+	blobStore storage.BlobStore,
+) KYCReviewService {
+	return &kycReviewService{
+		transactor:   transactor,
+		repo:         repo,
+		documentRepo: documentRepo,
+		outboxRepo:   outboxRepo,
+		blobStore:    blobStore,
+	}
+}
+
+// ListUnderReview devolve a fila de análise cadastral, começando pelas solicitações mais antigas.
+func (s *kycReviewService) ListUnderReview(ctx context.Context, limit, offset int) ([]models.OnboardingRequest, error) {
+	if limit <= 0 || limit > MaxReviewListLimit {
+		limit = DefaultReviewListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	onboardingRequests, err := s.repo.ListByStatus(ctx, models.StatusUnderReview, limit, offset)
+	if err != nil {
+		log.Printf("Error listing onboarding requests under review: %v", err)
+		return nil, ErrInternalServer
+	}
+	return onboardingRequests, nil
+}
+
+func (s *kycReviewService) ListDocuments(ctx context.Context, requestPublicID string) ([]models.KYCDocument, error) {
+	onboardingRequest, err := s.findRequest(ctx, requestPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	documents, err := s.documentRepo.ListByRequest(ctx, onboardingRequest.ID)
+	if err != nil {
+		log.Printf("Error listing KYC documents: %v", err)
+		return nil, ErrInternalServer
+	}
+	return documents, nil
+}
+
+// OpenDocument abre o conteúdo de um arquivo da solicitação. Quem chama deve fechar o reader.
+func (s *kycReviewService) OpenDocument(ctx context.Context, requestPublicID, documentPublicID string) (*models.KYCDocument, io.ReadCloser, error) {
+	onboardingRequest, err := s.findRequest(ctx, requestPublicID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	document, err := s.documentRepo.FindByRequestAndPublicID(ctx, onboardingRequest.ID, documentPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrDocumentNotFound
+		}
+		log.Printf("Error finding KYC document: %v", err)
+		return nil, nil, ErrInternalServer
+	}
+
+	content, err := s.blobStore.Get(ctx, document.BlobKey)
+	if err != nil {
+		log.Printf("Error opening KYC document blob %s: %v", document.BlobKey, err)
+		return nil, nil, ErrInternalServer
+	}
+
+	return document, content, nil
+}
+
+// Approve aprova a análise cadastral e envia ao cliente um novo link para concluir a abertura da
+// conta. O token anterior é substituído, pois já pode ter expirado durante a análise.
+func (s *kycReviewService) Approve(ctx context.Context, requestPublicID, reviewer string) error {
+	rawToken, hashedToken, err := crypto.GenerateVerificationToken()
+	if err != nil {
+		log.Printf("Error generating completion token: %v", err)
+		return ErrInternalServer
+	}
+
+	return s.review(ctx, requestPublicID, func(onboardingRequest *models.OnboardingRequest) (*models.OnboardingRequestEvent, *notification.EmailRequest, error) {
+		event, err := onboardingRequest.TransitionTo(models.StatusApproved, reviewActor(reviewer), "documentação aprovada")
+		if err != nil {
+			return nil, nil, err
+		}
+
+		onboardingRequest.VerificationTokenHash = hashedToken
+		onboardingRequest.TokenExpiresAt = time.Now().Add(completionTokenTTL)
+
+		completionLink := fmt.Sprintf("%s/%s?token=%s", os.Getenv("WEBSITE_BASE_URL"), websiteCompleteURL, rawToken)
+		return event, newReviewEmail(onboardingRequest, notification.TemplateOnboardingApproved, struct {
+			FullName       string
+			CompletionLink string
+		}{
+			FullName:       onboardingRequest.FullName,
+			CompletionLink: completionLink,
+		}), nil
+	})
+}
+
+// Reject encerra a solicitação. O motivo fica registrado no histórico e é enviado ao cliente.
+func (s *kycReviewService) Reject(ctx context.Context, requestPublicID, reviewer, reason string) error {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return ErrReviewReasonRequired
+	}
+
+	return s.review(ctx, requestPublicID, func(onboardingRequest *models.OnboardingRequest) (*models.OnboardingRequestEvent, *notification.EmailRequest, error) {
+		event, err := onboardingRequest.TransitionTo(models.StatusRejected, reviewActor(reviewer), reason)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return event, newReviewEmail(onboardingRequest, notification.TemplateOnboardingRejected, struct {
+			FullName string
+			Reason   string
+		}{
+			FullName: onboardingRequest.FullName,
+			Reason:   reason,
+		}), nil
+	})
+}
+
+// review aplica a decisão do analista a uma solicitação em análise e grava o e-mail ao cliente
+// na mesma transação da mudança de status.
+func (s *kycReviewService) review(ctx context.Context, requestPublicID string, decide func(*models.OnboardingRequest) (*models.OnboardingRequestEvent, *notification.EmailRequest, error)) error {
+	var reviewErr error
+	err := s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		reviewErr = s.reviewWithinTx(ctx, tx, requestPublicID, decide)
+		return reviewErr
+	})
+	if reviewErr != nil {
+		return reviewErr
+	}
+	if err != nil {
+		log.Printf("Error committing onboarding review: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *kycReviewService) reviewWithinTx(ctx context.Context, tx *gorm.DB, requestPublicID string, decide func(*models.OnboardingRequest) (*models.OnboardingRequestEvent, *notification.EmailRequest, error)) error {
+	onboardingRepo := s.repo.WithTx(tx)
+
+	onboardingRequest, err := onboardingRepo.FindByPublicIDForUpdate(ctx, requestPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRequestNotFound
+		}
+		log.Printf("Error finding onboarding request by public id: %v", err)
+		return ErrInternalServer
+	}
+
+	if onboardingRequest.Status != models.StatusUnderReview {
+		return ErrRequestNotUnderReview
+	}
+
+	event, emailRequest, err := decide(onboardingRequest)
+	if err != nil {
+		return ErrRequestNotUnderReview
+	}
+
+	if err := onboardingRepo.SaveTransition(ctx, onboardingRequest, event); err != nil {
+		log.Printf("Error saving onboarding review: %v", err)
+		return ErrInternalServer
+	}
+
+	if err := s.outboxRepo.WithTx(tx).EnqueueEmail(ctx, emailRequest); err != nil {
+		log.Printf("Error enqueuing onboarding review email: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *kycReviewService) findRequest(ctx context.Context, requestPublicID string) (*models.OnboardingRequest, error) {
+	onboardingRequest, err := s.repo.FindByPublicID(ctx, requestPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRequestNotFound
+		}
+		log.Printf("Error finding onboarding request by public id: %v", err)
+		return nil, ErrInternalServer
+	}
+	return onboardingRequest, nil
+}
+
+// reviewActor identifica o analista no histórico da solicitação.
+func reviewActor(reviewer string) string {
+	return models.ActorBackOffice + ":" + strings.TrimSpace(reviewer)
+}
+
+func newReviewEmail(onboardingRequest *models.OnboardingRequest, templateID notification.TemplateID, templateData any) *notification.EmailRequest {
+	return &notification.EmailRequest{
+		From:         os.Getenv("EMAIL_FROM"),
+		To:           onboardingRequest.Email,
+		TemplateID:   templateID,
+		Locale:       onboardingRequest.Locale,
+		TemplateData: templateData,
+	}
+}
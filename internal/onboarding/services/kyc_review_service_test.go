@@ -0,0 +1,127 @@
+package services_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type kycReviewFixture struct {
+	service      services.KYCReviewService
+	repo         *mocks.MockOnboardingRepository
+	documentRepo *mocks.MockKYCDocumentRepository
+	outbox       *mocks.MockOutboxRepository
+	blobStore    *mocks.MockBlobStore
+	request      *models.OnboardingRequest
+}
+
+func newKYCReviewFixture() *kycReviewFixture {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockDocumentRepo := new(mocks.MockKYCDocumentRepository)
+	mockOutbox := new(mocks.MockOutboxRepository)
+	mockBlobStore := new(mocks.MockBlobStore)
+
+	request := &models.OnboardingRequest{
+		ID:                    7,
+		PublicID:              kycRequestID,
+		FullName:              "John Doe",
+		Email:                 "john.doe@example.com",
+		VerificationTokenHash: "old-hash",
+		TokenExpiresAt:        time.Now().Add(-1 * time.Hour),
+		Status:                models.StatusUnderReview,
+	}
+	mockRepo.On("FindByPublicIDForUpdate", kycRequestID).Return(request, nil)
+
+	return &kycReviewFixture{
+		service:      services.NewKYCReviewService(new(mocks.MockTransactor), mockRepo, mockDocumentRepo, mockOutbox, mockBlobStore),
+		repo:         mockRepo,
+		documentRepo: mockDocumentRepo,
+		outbox:       mockOutbox,
+		blobStore:    mockBlobStore,
+		request:      request,
+	}
+}
+
+func TestKYCReviewService_Approve(t *testing.T) {
+	f := newKYCReviewFixture()
+
+	f.repo.On("SaveTransition", f.request, mock.MatchedBy(func(event *models.OnboardingRequestEvent) bool {
+		return event.ToStatus == models.StatusApproved && event.Actor == "backoffice:ana.silva"
+	})).Return(nil)
+	f.outbox.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.To == "john.doe@example.com" && req.TemplateID == notification.TemplateOnboardingApproved
+	})).Return(nil)
+
+	err := f.service.Approve(context.Background(), kycRequestID, "ana.silva")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusApproved, f.request.Status)
+	assert.NotEqual(t, "old-hash", f.request.VerificationTokenHash, "approval must rotate the token")
+	assert.True(t, f.request.TokenExpiresAt.After(time.Now().Add(48*time.Hour)))
+	f.repo.AssertExpectations(t)
+	f.outbox.AssertExpectations(t)
+}
+
+func TestKYCReviewService_Reject(t *testing.T) {
+	f := newKYCReviewFixture()
+
+	f.repo.On("SaveTransition", f.request, mock.MatchedBy(func(event *models.OnboardingRequestEvent) bool {
+		return event.ToStatus == models.StatusRejected && event.Reason == "documento ilegível"
+	})).Return(nil)
+	f.outbox.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.TemplateID == notification.TemplateOnboardingRejected
+	})).Return(nil)
+
+	err := f.service.Reject(context.Background(), kycRequestID, "ana.silva", " documento ilegível ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusRejected, f.request.Status)
+	assert.Equal(t, "old-hash", f.request.VerificationTokenHash)
+	f.outbox.AssertExpectations(t)
+}
+
+func TestKYCReviewService_RejectRequiresReason(t *testing.T) {
+	f := newKYCReviewFixture()
+
+	err := f.service.Reject(context.Background(), kycRequestID, "ana.silva", "  ")
+
+	assert.Equal(t, services.ErrReviewReasonRequired, err)
+	f.repo.AssertNotCalled(t, "FindByPublicIDForUpdate", mock.Anything)
+}
+
+func TestKYCReviewService_NotUnderReview(t *testing.T) {
+	f := newKYCReviewFixture()
+	f.request.Status = models.StatusVerified
+
+	err := f.service.Approve(context.Background(), kycRequestID, "ana.silva")
+
+	assert.Equal(t, services.ErrRequestNotUnderReview, err)
+	f.repo.AssertNotCalled(t, "SaveTransition", mock.Anything, mock.Anything)
+	f.outbox.AssertNotCalled(t, "EnqueueEmail", mock.Anything)
+}
+
+func TestKYCReviewService_OpenDocument(t *testing.T) {
+	f := newKYCReviewFixture()
+	f.repo.On("FindByPublicID", kycRequestID).Return(f.request, nil)
+	f.documentRepo.On("FindByRequestAndPublicID", int64(7), "doc-1").Return(&models.KYCDocument{BlobKey: "kyc/front.png"}, nil)
+	f.documentRepo.On("FindByRequestAndPublicID", int64(7), "doc-2").Return(nil, gorm.ErrRecordNotFound)
+	f.blobStore.On("Get", "kyc/front.png").Return(io.NopCloser(strings.NewReader("content")), nil)
+
+	document, content, err := f.service.OpenDocument(context.Background(), kycRequestID, "doc-1")
+	assert.NoError(t, err)
+	assert.Equal(t, "kyc/front.png", document.BlobKey)
+	assert.NoError(t, content.Close())
+
+	_, _, err = f.service.OpenDocument(context.Background(), kycRequestID, "doc-2")
+	assert.Equal(t, services.ErrDocumentNotFound, err)
+}
@@ -2,16 +2,20 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"log"
 	"os"
-	"sync"
+	"strings"
 	"time"
 
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
 	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+	outbox_repositories "github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
 	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
 	"github.com/high-effort-low-stress/go-bank-api/internal/utils/validators"
 
@@ -20,106 +24,185 @@ import (
 
 var (
 	ErrInvalidCPF     = errors.New("cpf inválido")
+	ErrInvalidPhone   = errors.New("número de celular inválido")
+	ErrInvalidAddress = errors.New("endereço inválido, confira o CEP e a UF informados")
+	ErrInvalidBirth   = errors.New("data de nascimento inválida")
+	ErrUnderage       = errors.New("é preciso ter 18 anos ou mais para abrir uma conta")
 	ErrUserExists     = errors.New("o cpf ou E-mail já está cadastrado")
 	ErrInternalServer = errors.New("ocorreu um erro inesperado")
 )
 
-var (
-	websiteVerifyURL              = "verify"
-	VerificationEmailTemplatePath = "templates/verification_email.html"
-	subject                       = "Bem-vindo ao GoBank! Confirme seu e-mail."
-)
+const verificationTokenTTL = 1 * time.Hour
+
+var websiteVerifyURL = "verify"
+
+// StartServiceRequest reúne os dados informados pelo cliente ao iniciar o onboarding.
+// PoliticallyExposed é a autodeclaração de pessoa politicamente exposta (PEP).
+type StartServiceRequest struct {
+	Document           string
+	FullName           string
+	BirthDate          time.Time
+	MotherName         string
+	PoliticallyExposed bool
+	Email              string
+	PhoneNumber        string
+	Address            user_models.Address
+	Locale             string
+}
 
 type OnboardingService interface {
-	StartOnboardingProcess(document, fullName, email string) error
+	StartOnboardingProcess(ctx context.Context, request *StartServiceRequest) (string, error)
 }
 
 type onboardingService struct {
-	repo     repositories.OnboardingRequestRepository
-	emailSvc notification.EmailService
-	wg       *sync.WaitGroup
+	transactor database.Transactor
+	repo       repositories.OnboardingRequestRepository
+	outboxRepo outbox_repositories.OutboxRepository
 }
 
-func NewOnboardingService(repo repositories.OnboardingRequestRepository, emailSvc notification.EmailService, wg *sync.WaitGroup) OnboardingService {
-	return &onboardingService{repo: repo, emailSvc: emailSvc, wg: wg}
+func NewOnboardingService(
+	transactor database.Transactor,
+	repo repositories.OnboardingRequestRepository,
+	outboxRepo outbox_repositories.OutboxRepository,
+) OnboardingService {
+	return &onboardingService{transactor: transactor, repo: repo, outboxRepo: outboxRepo}
 }
 
-func (s *onboardingService) StartOnboardingProcess(document, fullName, email string) error {
-	if !validators.IsValidCPF(document) {
-		return ErrInvalidCPF
+func (s *onboardingService) StartOnboardingProcess(ctx context.Context, request *StartServiceRequest) (string, error) {
+	if !validators.IsValidCPF(request.Document) {
+		return "", ErrInvalidCPF
+	}
+
+	now := time.Now()
+	if !validators.IsPlausibleBirthDate(request.BirthDate, now) {
+		return "", ErrInvalidBirth
+	}
+
+	if validators.AgeAt(request.BirthDate, now) < validators.MinimumAccountHolderAge {
+		return "", ErrUnderage
+	}
+
+	if !validators.IsValidBrazilianMobile(request.PhoneNumber) {
+		return "", ErrInvalidPhone
 	}
 
-	existingRequest, err := s.repo.FindByDocumentOrEmail(document, email)
-	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
-		log.Printf("Error checking for existing onboarding request: %v", err)
-		return ErrInternalServer
+	address, err := normalizeAddress(request.Address)
+	if err != nil {
+		return "", err
 	}
 
-	if existingRequest != nil {
-		return ErrUserExists
+	if err := ensureNoActiveRequest(ctx, s.repo, request.Document, request.Email); err != nil {
+		return "", err
 	}
 
 	rawToken, hashedToken, err := crypto.GenerateVerificationToken()
 	if err != nil {
 		log.Printf("Error generating verification token: %v", err)
-		return ErrInternalServer
+		return "", ErrInternalServer
 	}
 
 	newRequest := &models.OnboardingRequest{
-		FullName:              fullName,
-		Email:                 email,
-		DocumentNumber:        document,
+		FullName:              request.FullName,
+		BirthDate:             &request.BirthDate,
+		MotherName:            strings.TrimSpace(request.MotherName),
+		PoliticallyExposed:    request.PoliticallyExposed,
+		Email:                 request.Email,
+		DocumentNumber:        request.Document,
+		CustomerType:          user_models.CustomerTypeIndividual,
+		PhoneNumber:           request.PhoneNumber,
+		Address:               address,
+		Locale:                request.Locale,
 		VerificationTokenHash: hashedToken,
-		TokenExpiresAt:        time.Now().Add(1 * time.Hour),
+		TokenExpiresAt:        now.Add(verificationTokenTTL),
 		Status:                models.StatusPending,
+		LastEmailSentAt:       &now,
 	}
 
-	if err := s.repo.Create(newRequest); err != nil {
+	// A solicitação e o e-mail de verificação são gravados juntos: se o processo cair logo
+	// após o commit, o EmailDispatcher ainda envia o e-mail.
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		if err := s.repo.WithTx(tx).Create(ctx, newRequest); err != nil {
+			return err
+		}
+		return s.outboxRepo.WithTx(tx).EnqueueEmail(ctx, newVerificationEmail(newRequest, rawToken))
+	})
+	if err != nil {
 		log.Printf("Error creating onboarding request: %v", err)
-		return ErrInternalServer
+		return "", ErrInternalServer
 	}
 
-	if s.wg != nil {
-		s.wg.Add(1)
-	}
+	log.Println("Onboarding process started successfully")
+	return newRequest.PublicID, nil
+}
 
-	go func() {
-		if s.wg != nil {
-			defer s.wg.Done()
+// ensureNoActiveRequest retorna ErrUserExists se o documento ou e-mail já tiver uma solicitação em andamento.
+// Solicitações cujo token venceu antes de o sweeper passar por elas são expiradas aqui, liberando o recomeço.
+func ensureNoActiveRequest(ctx context.Context, repo repositories.OnboardingRequestRepository, document, email string) error {
+	for {
+		existingRequest, err := repo.FindByDocumentOrEmail(ctx, document, email)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
 		}
-		if err := s.sendEmail(fullName, email, rawToken); err != nil {
-			log.Printf("CRITICAL: Failed to send verification email to %s: %v", email, err)
+		if err != nil {
+			log.Printf("Error checking for existing onboarding request: %v", err)
+			return ErrInternalServer
 		}
-	}()
 
-	log.Println("Onboarding process started successfully")
-	return nil
+		if !existingRequest.IsExpirable(time.Now()) {
+			return ErrUserExists
+		}
+
+		event, err := existingRequest.TransitionTo(models.StatusExpired, models.ActorCustomer, "token expirado ao iniciar um novo onboarding")
+		if err != nil {
+			return ErrUserExists
+		}
+
+		if err := repo.SaveTransition(ctx, existingRequest, event); err != nil {
+			log.Printf("Error expiring previous onboarding request: %v", err)
+			return ErrInternalServer
+		}
+	}
 }
 
-func (s *onboardingService) sendEmail(fullName, email, rawToken string) error {
+// normalizeAddress remove espaços e a pontuação do CEP e confere se o CEP pertence à UF informada.
+func normalizeAddress(address user_models.Address) (user_models.Address, error) {
+	address.CEP = validators.NormalizeCEP(address.CEP)
+	address.Street = strings.TrimSpace(address.Street)
+	address.Number = strings.TrimSpace(address.Number)
+	address.Complement = strings.TrimSpace(address.Complement)
+	address.Neighborhood = strings.TrimSpace(address.Neighborhood)
+	address.City = strings.TrimSpace(address.City)
+	address.State = strings.ToUpper(strings.TrimSpace(address.State))
+
+	if address.Street == "" || address.Number == "" || address.Neighborhood == "" || address.City == "" {
+		return address, ErrInvalidAddress
+	}
+
+	if validators.UFForCEP(address.CEP) != address.State {
+		return address, ErrInvalidAddress
+	}
+
+	return address, nil
+}
+
+// newVerificationEmail monta o e-mail com o link de verificação do token informado, no idioma da
+// solicitação.
+func newVerificationEmail(onboardingRequest *models.OnboardingRequest, rawToken string) *notification.EmailRequest {
 	verificationLink := fmt.Sprintf("%s/%s?token=%s", os.Getenv("WEBSITE_BASE_URL"), websiteVerifyURL, rawToken)
 
 	templateData := struct {
 		FullName         string
-		Subject          string
 		VerificationLink string
 	}{
-		FullName:         fullName,
-		Subject:          subject,
+		FullName:         onboardingRequest.FullName,
 		VerificationLink: verificationLink,
 	}
 
-	emailRequest := &notification.EmailRequest{
+	return &notification.EmailRequest{
 		From:         os.Getenv("EMAIL_FROM"),
-		To:           email,
-		Subject:      subject,
-		TemplatePath: VerificationEmailTemplatePath,
+		To:           onboardingRequest.Email,
+		TemplateID:   notification.TemplateVerificationEmail,
+		Locale:       onboardingRequest.Locale,
 		TemplateData: templateData,
 	}
-
-	if err := s.emailSvc.SendEmail(emailRequest); err != nil {
-		return err
-	}
-
-	return nil
 }
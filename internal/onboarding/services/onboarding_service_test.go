@@ -1,46 +1,79 @@
 package services_test
 
 import (
-	"sync"
+	"context"
+	"errors"
 	"testing"
+	"time"
 
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
 	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"gorm.io/gorm"
 )
 
+const validPhone = "+5511912345678"
+
+var validBirthDate = time.Date(1990, time.May, 10, 0, 0, 0, 0, time.UTC)
+
+var validAddress = user_models.Address{
+	CEP:          "01310-100",
+	Street:       "Avenida Paulista",
+	Number:       "1000",
+	Neighborhood: "Bela Vista",
+	City:         "São Paulo",
+	State:        "sp",
+}
+
 func TestStartOnboardingProcess_Success(t *testing.T) {
 	mockRepo := new(mocks.MockOnboardingRepository)
-	mockEmailSvc := new(mocks.MockEmailService)
+	mockOutbox := new(mocks.MockOutboxRepository)
+	transactor := new(mocks.MockTransactor)
 
-	var wg sync.WaitGroup
-	service := services.NewOnboardingService(mockRepo, mockEmailSvc, &wg)
+	service := services.NewOnboardingService(transactor, mockRepo, mockOutbox)
 	fullName := "John Doe"
 	email := "john.doe@example.com"
 	validDocument := "68219090081"
 
 	mockRepo.On("FindByDocumentOrEmail", validDocument, email).Return(nil, gorm.ErrRecordNotFound)
-	mockRepo.On("Create", mock.AnythingOfType("*models.OnboardingRequest")).Return(nil)
-	mockEmailSvc.On("SendEmail", mock.AnythingOfType("*notification.EmailRequest")).Return(nil)
+	mockRepo.On("Create", mock.MatchedBy(func(req *models.OnboardingRequest) bool {
+		return req.PhoneNumber == validPhone && req.BirthDate.Equal(validBirthDate) && req.MotherName == "Maria Doe" && req.Address.CEP == "01310100" && req.Address.State == "SP" &&
+			req.Locale == notification.LocaleEn
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*models.OnboardingRequest).PublicID = "01JAX5T0K1D6S0ZB7W3Q8YV2NQ"
+	}).Return(nil)
+	mockOutbox.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.To == email && req.TemplateID == notification.TemplateVerificationEmail && req.Locale == notification.LocaleEn
+	})).Return(nil)
 
-	err := service.StartOnboardingProcess(validDocument, fullName, email)
+	publicID, err := service.StartOnboardingProcess(context.Background(), &services.StartServiceRequest{
+		Document:    validDocument,
+		FullName:    fullName,
+		Email:       email,
+		BirthDate:   validBirthDate,
+		MotherName:  "Maria Doe",
+		PhoneNumber: validPhone,
+		Address:     validAddress,
+		Locale:      notification.LocaleEn,
+	})
 
 	assert.NoError(t, err)
+	assert.Equal(t, "01JAX5T0K1D6S0ZB7W3Q8YV2NQ", publicID)
 	mockRepo.AssertExpectations(t)
-
-	wg.Wait()
-	mockEmailSvc.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
+	assert.Equal(t, 1, transactor.Calls)
 }
 
 func TestStartOnboardingProcess_UserAlreadyExists(t *testing.T) {
 	mockRepo := new(mocks.MockOnboardingRepository)
-	mockEmail := new(mocks.MockEmailService)
+	mockOutbox := new(mocks.MockOutboxRepository)
+	transactor := new(mocks.MockTransactor)
 
-	var wg sync.WaitGroup
-	service := services.NewOnboardingService(mockRepo, mockEmail, &wg)
+	service := services.NewOnboardingService(transactor, mockRepo, mockOutbox)
 
 	fullName := "Jane Doe"
 	email := "jane.doe@example.com"
@@ -49,32 +82,201 @@ func TestStartOnboardingProcess_UserAlreadyExists(t *testing.T) {
 	existingRequest := &models.OnboardingRequest{}
 	mockRepo.On("FindByDocumentOrEmail", validDocument, email).Return(existingRequest, nil)
 
-	err := service.StartOnboardingProcess(validDocument, fullName, email)
+	_, err := service.StartOnboardingProcess(context.Background(), &services.StartServiceRequest{
+		Document:    validDocument,
+		FullName:    fullName,
+		Email:       email,
+		BirthDate:   validBirthDate,
+		MotherName:  "Maria Doe",
+		PhoneNumber: validPhone,
+		Address:     validAddress,
+	})
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrUserExists, err)
 	mockRepo.AssertExpectations(t)
-
-	wg.Wait()
-	mockEmail.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
+	mockOutbox.AssertNotCalled(t, "EnqueueEmail", mock.Anything)
 }
 
 func TestStartOnboardingProcess_InvalidCPF(t *testing.T) {
 	mockRepo := new(mocks.MockOnboardingRepository)
-	mockEmail := new(mocks.MockEmailService)
-	var wg sync.WaitGroup
-	service := services.NewOnboardingService(mockRepo, mockEmail, &wg)
+	mockOutbox := new(mocks.MockOutboxRepository)
+	transactor := new(mocks.MockTransactor)
+	service := services.NewOnboardingService(transactor, mockRepo, mockOutbox)
 
 	fullName := "Invalid User"
 	email := "invalid@example.com"
 	invalidDocument := "123"
 
-	err := service.StartOnboardingProcess(invalidDocument, fullName, email)
+	_, err := service.StartOnboardingProcess(context.Background(), &services.StartServiceRequest{
+		Document:    invalidDocument,
+		FullName:    fullName,
+		Email:       email,
+		BirthDate:   validBirthDate,
+		MotherName:  "Maria Doe",
+		PhoneNumber: validPhone,
+		Address:     validAddress,
+	})
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrInvalidCPF, err)
 	mockRepo.AssertNotCalled(t, "FindByDocumentOrEmail")
+	mockOutbox.AssertNotCalled(t, "EnqueueEmail", mock.Anything)
+}
+
+func TestStartOnboardingProcess_InvalidPhone(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockOutbox := new(mocks.MockOutboxRepository)
+	transactor := new(mocks.MockTransactor)
+	service := services.NewOnboardingService(transactor, mockRepo, mockOutbox)
+
+	_, err := service.StartOnboardingProcess(context.Background(), &services.StartServiceRequest{
+		Document:    "68219090081",
+		FullName:    "Landline User",
+		Email:       "landline@example.com",
+		BirthDate:   validBirthDate,
+		MotherName:  "Maria Doe",
+		PhoneNumber: "+551132345678",
+	})
+
+	assert.Equal(t, services.ErrInvalidPhone, err)
+	mockRepo.AssertNotCalled(t, "FindByDocumentOrEmail")
+	mockOutbox.AssertNotCalled(t, "EnqueueEmail", mock.Anything)
+}
+
+func TestStartOnboardingProcess_BirthDate(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name      string
+		birthDate time.Time
+		err       error
+	}{
+		{"Turns 18 tomorrow", now.AddDate(-18, 0, 1), services.ErrUnderage},
+		{"Child", now.AddDate(-10, 0, 0), services.ErrUnderage},
+		{"Future date", now.AddDate(0, 0, 1), services.ErrInvalidBirth},
+		{"Typo in the year", time.Date(1099, time.May, 10, 0, 0, 0, 0, time.UTC), services.ErrInvalidBirth},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockOnboardingRepository)
+			service := services.NewOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockOutboxRepository))
+
+			_, err := service.StartOnboardingProcess(context.Background(), &services.StartServiceRequest{
+				Document:    "68219090081",
+				FullName:    "John Doe",
+				BirthDate:   tt.birthDate,
+				MotherName:  "Maria Doe",
+				Email:       "john.doe@example.com",
+				PhoneNumber: validPhone,
+				Address:     validAddress,
+			})
+
+			assert.Equal(t, tt.err, err)
+			mockRepo.AssertNotCalled(t, "FindByDocumentOrEmail", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestStartOnboardingProcess_InvalidAddress(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(address *user_models.Address)
+	}{
+		{"CEP from another state", func(address *user_models.Address) { address.State = "RJ" }},
+		{"Malformed CEP", func(address *user_models.Address) { address.CEP = "0131010" }},
+		{"Blank street", func(address *user_models.Address) { address.Street = "  " }},
+		{"Missing number", func(address *user_models.Address) { address.Number = "" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockOnboardingRepository)
+			service := services.NewOnboardingService(new(mocks.MockTransactor), mockRepo, new(mocks.MockOutboxRepository))
 
-	wg.Wait()
-	mockEmail.AssertNotCalled(t, "SendEmail", mock.Anything, mock.Anything, mock.Anything)
+			address := validAddress
+			tt.modify(&address)
+
+			_, err := service.StartOnboardingProcess(context.Background(), &services.StartServiceRequest{
+				Document:    "68219090081",
+				FullName:    "John Doe",
+				Email:       "john.doe@example.com",
+				BirthDate:   validBirthDate,
+				MotherName:  "Maria Doe",
+				PhoneNumber: validPhone,
+				Address:     address,
+			})
+
+			assert.Equal(t, services.ErrInvalidAddress, err)
+			mockRepo.AssertNotCalled(t, "FindByDocumentOrEmail", mock.Anything, mock.Anything)
+		})
+	}
+}
+
+func TestStartOnboardingProcess_RestartsExpiredRequest(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockOutbox := new(mocks.MockOutboxRepository)
+	transactor := new(mocks.MockTransactor)
+
+	service := services.NewOnboardingService(transactor, mockRepo, mockOutbox)
+	email := "john.doe@example.com"
+	validDocument := "68219090081"
+
+	staleRequest := &models.OnboardingRequest{
+		Status:         models.StatusPending,
+		TokenExpiresAt: time.Now().Add(-1 * time.Hour),
+	}
+
+	mockRepo.On("FindByDocumentOrEmail", validDocument, email).Return(staleRequest, nil).Once()
+	mockRepo.On("SaveTransition", staleRequest, mock.MatchedBy(func(event *models.OnboardingRequestEvent) bool {
+		return event.FromStatus == models.StatusPending && event.ToStatus == models.StatusExpired
+	})).Return(nil)
+	mockRepo.On("FindByDocumentOrEmail", validDocument, email).Return(nil, gorm.ErrRecordNotFound).Once()
+	mockRepo.On("Create", mock.AnythingOfType("*models.OnboardingRequest")).Return(nil)
+	mockOutbox.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.To == email && req.TemplateID == notification.TemplateVerificationEmail
+	})).Return(nil)
+
+	_, err := service.StartOnboardingProcess(context.Background(), &services.StartServiceRequest{
+		Document:    validDocument,
+		FullName:    "John Doe",
+		Email:       email,
+		BirthDate:   validBirthDate,
+		MotherName:  "Maria Doe",
+		PhoneNumber: validPhone,
+		Address:     validAddress,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.StatusExpired, staleRequest.Status)
+	mockRepo.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
+	assert.Equal(t, 1, transactor.Calls)
+}
+
+func TestStartOnboardingProcess_EnqueueFailure(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockOutbox := new(mocks.MockOutboxRepository)
+	transactor := new(mocks.MockTransactor)
+	service := services.NewOnboardingService(transactor, mockRepo, mockOutbox)
+	email := "john.doe@example.com"
+	validDocument := "68219090081"
+
+	mockRepo.On("FindByDocumentOrEmail", validDocument, email).Return(nil, gorm.ErrRecordNotFound)
+	mockRepo.On("Create", mock.AnythingOfType("*models.OnboardingRequest")).Return(nil)
+	mockOutbox.On("EnqueueEmail", mock.Anything).Return(errors.New("db error"))
+
+	_, err := service.StartOnboardingProcess(context.Background(), &services.StartServiceRequest{
+		Document:    validDocument,
+		FullName:    "John Doe",
+		Email:       email,
+		BirthDate:   validBirthDate,
+		MotherName:  "Maria Doe",
+		PhoneNumber: validPhone,
+		Address:     validAddress,
+	})
+
+	assert.Equal(t, services.ErrInternalServer, err)
+	mockRepo.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
 }
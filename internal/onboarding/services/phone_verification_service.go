@@ -0,0 +1,209 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/otp"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrPhoneAlreadyVerified = errors.New("o celular desta solicitação já foi verificado")
+	ErrOTPNotRequested      = errors.New("nenhum código ativo, solicite um novo código")
+	ErrInvalidOTP           = errors.New("código de verificação inválido")
+	ErrExpiredOTP           = errors.New("código de verificação expirado")
+	ErrOTPAttemptsExceeded  = errors.New("número máximo de tentativas atingido, solicite um novo código")
+	ErrOTPCooldown          = errors.New("aguarde alguns instantes antes de solicitar um novo código")
+	ErrOTPLimitReached      = errors.New("limite de envios de código atingido para esta solicitação")
+)
+
+// phoneOTP é o código enviado por SMS para confirmar o celular da solicitação.
+var phoneOTP = otp.Policy{Digits: 6, TTL: 5 * time.Minute, MaxAttempts: 5}
+
+const (
+	phoneOTPCooldown = 1 * time.Minute
+	phoneOTPMaxSends = 5
+)
+
+type PhoneVerificationService interface {
+	SendCode(ctx context.Context, token string, channel notification.SMSChannel) error
+	VerifyCode(ctx context.Context, token, code string) error
+}
+
+type phoneVerificationService struct {
+	transactor database.Transactor
+	repo       repositories.OnboardingRequestRepository
+	smsSender  notification.SMSSender
+}
+
+func NewPhoneVerificationService(
+	transactor database.Transactor,
+	repo repositories.OnboardingRequestRepository,
+	smsSender notification.SMSSender,
+) PhoneVerificationService {
+	return &phoneVerificationService{transactor: transactor, repo: repo, smsSender: smsSender}
+}
+
+// SendCode gera um novo código de 6 dígitos para o celular da solicitação, invalidando o anterior,
+// e o envia pelo canal escolhido. A solicitação é identificada pelo token do e-mail de verificação.
+// Os envios respeitam um intervalo mínimo e um limite por solicitação.
+func (s *phoneVerificationService) SendCode(ctx context.Context, token string, channel notification.SMSChannel) error {
+	code, err := phoneOTP.Generate()
+	if err != nil {
+		log.Printf("Error generating phone verification code: %v", err)
+		return ErrInternalServer
+	}
+
+	var phoneNumber string
+	var sendErr error
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		phoneNumber, sendErr = s.storeCodeWithinTx(ctx, tx, crypto.HashTokenSHA256(token), code)
+		return sendErr
+	})
+	if sendErr != nil {
+		return sendErr
+	}
+	if err != nil {
+		log.Printf("Error committing phone verification code: %v", err)
+		return ErrInternalServer
+	}
+
+	// O envio acontece após o commit para não manter a linha bloqueada durante a chamada ao provedor.
+	err = s.smsSender.SendSMS(ctx, &notification.SMSRequest{
+		To:      phoneNumber,
+		Body:    fmt.Sprintf("GoBank: seu código de verificação é %s. Ele expira em %d minutos. Não compartilhe este código.", code, int(phoneOTP.TTL.Minutes())),
+		Channel: channel,
+	})
+	if err != nil {
+		log.Printf("Error sending phone verification code: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *phoneVerificationService) storeCodeWithinTx(ctx context.Context, tx *gorm.DB, hashedToken, code string) (string, error) {
+	repo := s.repo.WithTx(tx)
+
+	onboardingRequest, err := s.findOpenRequestForUpdate(ctx, repo, hashedToken)
+	if err != nil {
+		return "", err
+	}
+
+	if onboardingRequest.PhoneOTPSendCount >= phoneOTPMaxSends {
+		return "", ErrOTPLimitReached
+	}
+
+	now := time.Now()
+	if onboardingRequest.LastOTPSentAt != nil && now.Sub(*onboardingRequest.LastOTPSentAt) < phoneOTPCooldown {
+		return "", ErrOTPCooldown
+	}
+
+	phoneOTP.Issue(onboardingRequest.PhoneOTP(), onboardingRequest.PublicID, code, now)
+	onboardingRequest.PhoneOTPSendCount++
+	onboardingRequest.LastOTPSentAt = &now
+
+	if err := repo.Update(ctx, onboardingRequest); err != nil {
+		log.Printf("Error storing phone verification code: %v", err)
+		return "", ErrInternalServer
+	}
+
+	return onboardingRequest.PhoneNumber, nil
+}
+
+// VerifyCode confere o código informado e marca o celular como verificado. Cada erro consome uma
+// tentativa; ao atingir o limite, é preciso solicitar um novo código.
+func (s *phoneVerificationService) VerifyCode(ctx context.Context, token, code string) error {
+	if len(code) != phoneOTP.Digits {
+		return ErrInvalidOTP
+	}
+
+	var verifyErr error
+	err := s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		verifyErr = s.verifyWithinTx(ctx, tx, crypto.HashTokenSHA256(token), code)
+		// A tentativa errada já foi contabilizada e precisa ser gravada mesmo com o código inválido.
+		if errors.Is(verifyErr, ErrInvalidOTP) {
+			return nil
+		}
+		return verifyErr
+	})
+	if verifyErr != nil {
+		return verifyErr
+	}
+	if err != nil {
+		log.Printf("Error committing phone verification: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *phoneVerificationService) verifyWithinTx(ctx context.Context, tx *gorm.DB, hashedToken, code string) error {
+	repo := s.repo.WithTx(tx)
+
+	onboardingRequest, err := s.findOpenRequestForUpdate(ctx, repo, hashedToken)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	switch err := phoneOTP.Verify(onboardingRequest.PhoneOTP(), onboardingRequest.PublicID, code, now); {
+	case errors.Is(err, otp.ErrNotIssued):
+		return ErrOTPNotRequested
+	case errors.Is(err, otp.ErrAttemptsExceeded):
+		return ErrOTPAttemptsExceeded
+	case errors.Is(err, otp.ErrExpired):
+		return ErrExpiredOTP
+	case errors.Is(err, otp.ErrMismatch):
+		if err := repo.Update(ctx, onboardingRequest); err != nil {
+			log.Printf("Error recording phone verification attempt: %v", err)
+			return ErrInternalServer
+		}
+		return ErrInvalidOTP
+	}
+
+	onboardingRequest.PhoneVerifiedAt = &now
+
+	if err := repo.Update(ctx, onboardingRequest); err != nil {
+		log.Printf("Error marking phone as verified: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+// findOpenRequestForUpdate bloqueia a solicitação do token e garante que ela ainda aceita a
+// verificação do celular.
+func (s *phoneVerificationService) findOpenRequestForUpdate(ctx context.Context, repo repositories.OnboardingRequestRepository, hashedToken string) (*models.OnboardingRequest, error) {
+	onboardingRequest, err := repo.FindByVerificationTokenHashForUpdate(ctx, hashedToken)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidToken
+		}
+		log.Printf("Error finding onboarding request by token hash: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	if time.Now().After(onboardingRequest.TokenExpiresAt) {
+		return nil, ErrExpiredToken
+	}
+
+	if onboardingRequest.Status.IsFinal() {
+		return nil, ErrRequestClosed
+	}
+
+	if onboardingRequest.IsPhoneVerified() {
+		return nil, ErrPhoneAlreadyVerified
+	}
+
+	return onboardingRequest, nil
+}
@@ -0,0 +1,199 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+var otpCodeRegex = regexp.MustCompile(`\d{6}`)
+
+func newPhoneVerificationRequest() *models.OnboardingRequest {
+	return &models.OnboardingRequest{
+		PublicID:       "01JAX5T0K1D6S0ZB7W3Q8YV2NM",
+		PhoneNumber:    "+5511912345678",
+		Status:         models.StatusVerified,
+		TokenExpiresAt: time.Now().Add(1 * time.Hour),
+	}
+}
+
+func TestPhoneVerificationService_SendAndVerifyCode(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	smsSender := notification.NewStubSMSSender()
+	service := services.NewPhoneVerificationService(new(mocks.MockTransactor), mockRepo, smsSender)
+
+	token := "valid-token"
+	request := newPhoneVerificationRequest()
+	mockRepo.On("FindByVerificationTokenHashForUpdate", crypto.HashTokenSHA256(token)).Return(request, nil)
+	mockRepo.On("Update", request).Return(nil)
+
+	err := service.SendCode(context.Background(), token, notification.ChannelWhatsApp)
+	require.NoError(t, err)
+
+	messages := smsSender.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, request.PhoneNumber, messages[0].To)
+	assert.Equal(t, notification.ChannelWhatsApp, messages[0].Channel)
+	assert.Equal(t, 1, request.PhoneOTPSendCount)
+	assert.NotEmpty(t, request.PhoneOTPHash)
+
+	code := otpCodeRegex.FindString(messages[0].Body)
+	require.NotEmpty(t, code)
+	assert.NotContains(t, request.PhoneOTPHash, code, "the code must be stored hashed")
+
+	err = service.VerifyCode(context.Background(), token, code)
+
+	assert.NoError(t, err)
+	assert.True(t, request.IsPhoneVerified())
+	assert.Empty(t, request.PhoneOTPHash)
+}
+
+func TestPhoneVerificationService_SendCode_Cooldown(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockSender := new(mocks.MockSMSSender)
+	service := services.NewPhoneVerificationService(new(mocks.MockTransactor), mockRepo, mockSender)
+
+	lastSentAt := time.Now().Add(-10 * time.Second)
+	request := newPhoneVerificationRequest()
+	request.LastOTPSentAt = &lastSentAt
+	mockRepo.On("FindByVerificationTokenHashForUpdate", mock.Anything).Return(request, nil)
+
+	err := service.SendCode(context.Background(), "token", notification.ChannelSMS)
+
+	assert.Equal(t, services.ErrOTPCooldown, err)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockSender.AssertNotCalled(t, "SendSMS", mock.Anything)
+}
+
+func TestPhoneVerificationService_SendCode_LimitReached(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockSender := new(mocks.MockSMSSender)
+	service := services.NewPhoneVerificationService(new(mocks.MockTransactor), mockRepo, mockSender)
+
+	request := newPhoneVerificationRequest()
+	request.PhoneOTPSendCount = 5
+	mockRepo.On("FindByVerificationTokenHashForUpdate", mock.Anything).Return(request, nil)
+
+	err := service.SendCode(context.Background(), "token", notification.ChannelSMS)
+
+	assert.Equal(t, services.ErrOTPLimitReached, err)
+	mockSender.AssertNotCalled(t, "SendSMS", mock.Anything)
+}
+
+func TestPhoneVerificationService_SendCode_AlreadyVerified(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	service := services.NewPhoneVerificationService(new(mocks.MockTransactor), mockRepo, new(mocks.MockSMSSender))
+
+	verifiedAt := time.Now()
+	request := newPhoneVerificationRequest()
+	request.PhoneVerifiedAt = &verifiedAt
+	mockRepo.On("FindByVerificationTokenHashForUpdate", mock.Anything).Return(request, nil)
+
+	err := service.SendCode(context.Background(), "token", notification.ChannelSMS)
+
+	assert.Equal(t, services.ErrPhoneAlreadyVerified, err)
+}
+
+func TestPhoneVerificationService_SendCode_InvalidToken(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	service := services.NewPhoneVerificationService(new(mocks.MockTransactor), mockRepo, new(mocks.MockSMSSender))
+
+	mockRepo.On("FindByVerificationTokenHashForUpdate", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+
+	err := service.SendCode(context.Background(), "unknown", notification.ChannelSMS)
+
+	assert.Equal(t, services.ErrInvalidToken, err)
+}
+
+func TestPhoneVerificationService_SendCode_SenderFailure(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockSender := new(mocks.MockSMSSender)
+	service := services.NewPhoneVerificationService(new(mocks.MockTransactor), mockRepo, mockSender)
+
+	request := newPhoneVerificationRequest()
+	mockRepo.On("FindByVerificationTokenHashForUpdate", mock.Anything).Return(request, nil)
+	mockRepo.On("Update", request).Return(nil)
+	mockSender.On("SendSMS", mock.Anything).Return(errors.New("provider unavailable"))
+
+	err := service.SendCode(context.Background(), "token", notification.ChannelSMS)
+
+	assert.Equal(t, services.ErrInternalServer, err)
+}
+
+func TestPhoneVerificationService_VerifyCode_WrongCodeConsumesAttempt(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	transactor := new(mocks.MockTransactor)
+	service := services.NewPhoneVerificationService(transactor, mockRepo, new(mocks.MockSMSSender))
+
+	expiresAt := time.Now().Add(5 * time.Minute)
+	request := newPhoneVerificationRequest()
+	request.PhoneOTPHash = crypto.HashTokenSHA256(request.PublicID + ":123456")
+	request.PhoneOTPExpiresAt = &expiresAt
+	mockRepo.On("FindByVerificationTokenHashForUpdate", mock.Anything).Return(request, nil)
+	mockRepo.On("Update", request).Return(nil)
+
+	err := service.VerifyCode(context.Background(), "token", "654321")
+
+	assert.Equal(t, services.ErrInvalidOTP, err)
+	assert.Equal(t, 1, request.PhoneOTPAttempts)
+	assert.False(t, request.IsPhoneVerified())
+	mockRepo.AssertCalled(t, "Update", request)
+}
+
+func TestPhoneVerificationService_VerifyCode_AttemptsExceeded(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	service := services.NewPhoneVerificationService(new(mocks.MockTransactor), mockRepo, new(mocks.MockSMSSender))
+
+	expiresAt := time.Now().Add(5 * time.Minute)
+	request := newPhoneVerificationRequest()
+	request.PhoneOTPHash = crypto.HashTokenSHA256(request.PublicID + ":123456")
+	request.PhoneOTPExpiresAt = &expiresAt
+	request.PhoneOTPAttempts = 5
+	mockRepo.On("FindByVerificationTokenHashForUpdate", mock.Anything).Return(request, nil)
+
+	// Mesmo o código correto é recusado depois de esgotadas as tentativas
+	err := service.VerifyCode(context.Background(), "token", "123456")
+
+	assert.Equal(t, services.ErrOTPAttemptsExceeded, err)
+	assert.False(t, request.IsPhoneVerified())
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestPhoneVerificationService_VerifyCode_Expired(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	service := services.NewPhoneVerificationService(new(mocks.MockTransactor), mockRepo, new(mocks.MockSMSSender))
+
+	expiresAt := time.Now().Add(-1 * time.Minute)
+	request := newPhoneVerificationRequest()
+	request.PhoneOTPHash = crypto.HashTokenSHA256(request.PublicID + ":123456")
+	request.PhoneOTPExpiresAt = &expiresAt
+	mockRepo.On("FindByVerificationTokenHashForUpdate", mock.Anything).Return(request, nil)
+
+	err := service.VerifyCode(context.Background(), "token", "123456")
+
+	assert.Equal(t, services.ErrExpiredOTP, err)
+	assert.False(t, request.IsPhoneVerified())
+}
+
+func TestPhoneVerificationService_VerifyCode_NotRequested(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	service := services.NewPhoneVerificationService(new(mocks.MockTransactor), mockRepo, new(mocks.MockSMSSender))
+
+	mockRepo.On("FindByVerificationTokenHashForUpdate", mock.Anything).Return(newPhoneVerificationRequest(), nil)
+
+	err := service.VerifyCode(context.Background(), "token", "123456")
+
+	assert.Equal(t, services.ErrOTPNotRequested, err)
+}
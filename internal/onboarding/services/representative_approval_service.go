@@ -0,0 +1,161 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"gorm.io/gorm"
+)
+
+var ErrRepresentativeAlreadyDecided = errors.New("você já registrou sua decisão sobre esta solicitação")
+
+type RepresentativeApprovalService interface {
+	ListPending(ctx context.Context, userPublicID string) ([]models.LegalRepresentative, error)
+	Approve(ctx context.Context, userPublicID, requestPublicID string) error
+	Decline(ctx context.Context, userPublicID, requestPublicID, reason string) error
+}
+
+type representativeApprovalService struct {
+	transactor         database.Transactor
+	repo               repositories.OnboardingRequestRepository
+	representativeRepo repositories.LegalRepresentativeRepository
+	userRepo           user_repositories.UserRepository
+}
+
+func NewRepresentativeApprovalService(
+	transactor database.Transactor,
+	repo repositories.OnboardingRequestRepository,
+	representativeRepo repositories.LegalRepresentativeRepository,
+	userRepo user_repositories.UserRepository,
+) RepresentativeApprovalService {
+	return &representativeApprovalService{
+		transactor:         transactor,
+		repo:               repo,
+		representativeRepo: representativeRepo,
+		userRepo:           userRepo,
+	}
+}
+
+// ListPending devolve as solicitações PJ em andamento que aguardam a decisão do cliente autenticado.
+func (s *representativeApprovalService) ListPending(ctx context.Context, userPublicID string) ([]models.LegalRepresentative, error) {
+	user, err := s.userRepo.FindByPublicID(ctx, userPublicID)
+	if err != nil {
+		log.Printf("Error finding user for pending approvals: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	representatives, err := s.representativeRepo.ListPendingByUser(ctx, user.ID)
+	if err != nil {
+		log.Printf("Error listing pending approvals: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return representatives, nil
+}
+
+// Approve registra a aprovação do representante. A conta só pode ser concluída quando todos aprovarem.
+func (s *representativeApprovalService) Approve(ctx context.Context, userPublicID, requestPublicID string) error {
+	return s.decide(ctx, userPublicID, requestPublicID, models.RepresentativeApproved, "")
+}
+
+// Decline registra a recusa do representante e rejeita a solicitação: basta um representante
+// recusar para que a conta não seja aberta.
+func (s *representativeApprovalService) Decline(ctx context.Context, userPublicID, requestPublicID, reason string) error {
+	return s.decide(ctx, userPublicID, requestPublicID, models.RepresentativeDeclined, reason)
+}
+
+func (s *representativeApprovalService) decide(ctx context.Context, userPublicID, requestPublicID string, decision models.RepresentativeStatus, reason string) error {
+	user, err := s.userRepo.FindByPublicID(ctx, userPublicID)
+	if err != nil {
+		log.Printf("Error finding user for representative decision: %v", err)
+		return ErrInternalServer
+	}
+
+	var decisionErr error
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		decisionErr = s.decideWithinTx(ctx, tx, user.ID, requestPublicID, decision, reason)
+		return decisionErr
+	})
+	if decisionErr != nil {
+		return decisionErr
+	}
+	if err != nil {
+		log.Printf("Error committing representative decision: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *representativeApprovalService) decideWithinTx(ctx context.Context, tx *gorm.DB, userID int64, requestPublicID string, decision models.RepresentativeStatus, reason string) error {
+	onboardingRepo := s.repo.WithTx(tx)
+	representativeRepo := s.representativeRepo.WithTx(tx)
+
+	onboardingRequest, err := onboardingRepo.FindByPublicIDForUpdate(ctx, requestPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRequestNotFound
+		}
+		log.Printf("Error finding onboarding request by public id: %v", err)
+		return ErrInternalServer
+	}
+
+	if !onboardingRequest.IsBusiness() {
+		return ErrRequestNotFound
+	}
+
+	// Quem não é representante da empresa recebe o mesmo erro de uma solicitação inexistente.
+	representative, err := representativeRepo.FindByRequestAndUserForUpdate(ctx, onboardingRequest.ID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRequestNotFound
+		}
+		log.Printf("Error finding legal representative: %v", err)
+		return ErrInternalServer
+	}
+
+	if onboardingRequest.Status.IsFinal() || onboardingRequest.IsExpirable(time.Now()) {
+		return ErrRequestClosed
+	}
+
+	if representative.Status != models.RepresentativePending {
+		return ErrRepresentativeAlreadyDecided
+	}
+
+	now := time.Now()
+	representative.Status = decision
+	representative.DecidedAt = &now
+
+	if err := representativeRepo.Update(ctx, representative); err != nil {
+		log.Printf("Error updating legal representative: %v", err)
+		return ErrInternalServer
+	}
+
+	if decision != models.RepresentativeDeclined {
+		return nil
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		reason = "abertura recusada por um representante legal"
+	}
+
+	event, err := onboardingRequest.TransitionTo(models.StatusRejected, models.ActorRepresentative, reason)
+	if err != nil {
+		return ErrRequestClosed
+	}
+
+	if err := onboardingRepo.SaveTransition(ctx, onboardingRequest, event); err != nil {
+		log.Printf("Error rejecting onboarding request: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
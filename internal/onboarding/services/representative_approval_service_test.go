@@ -0,0 +1,146 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+const (
+	representativePublicID = "01JAX5T0K1D6S0ZB7W3Q8YV2NM"
+	businessRequestID      = "01JAX5T0K1D6S0ZB7W3Q8YV2NP"
+)
+
+type approvalFixture struct {
+	service            services.RepresentativeApprovalService
+	repo               *mocks.MockOnboardingRepository
+	representativeRepo *mocks.MockLegalRepresentativeRepository
+	request            *models.OnboardingRequest
+	representative     *models.LegalRepresentative
+}
+
+func newApprovalFixture() *approvalFixture {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockRepresentativeRepo := new(mocks.MockLegalRepresentativeRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+
+	request := &models.OnboardingRequest{
+		ID:             7,
+		PublicID:       businessRequestID,
+		CustomerType:   user_models.CustomerTypeBusiness,
+		Status:         models.StatusVerified,
+		TokenExpiresAt: time.Now().Add(1 * time.Hour),
+	}
+	representative := &models.LegalRepresentative{
+		OnboardingRequestID: request.ID,
+		UserID:              42,
+		Status:              models.RepresentativePending,
+	}
+
+	mockUserRepo.On("FindByPublicID", representativePublicID).Return(&user_models.User{ID: 42}, nil)
+	mockRepo.On("FindByPublicIDForUpdate", businessRequestID).Return(request, nil)
+	mockRepresentativeRepo.On("FindByRequestAndUserForUpdate", request.ID, int64(42)).Return(representative, nil)
+
+	return &approvalFixture{
+		service:            services.NewRepresentativeApprovalService(new(mocks.MockTransactor), mockRepo, mockRepresentativeRepo, mockUserRepo),
+		repo:               mockRepo,
+		representativeRepo: mockRepresentativeRepo,
+		request:            request,
+		representative:     representative,
+	}
+}
+
+func TestRepresentativeApproval_Approve(t *testing.T) {
+	f := newApprovalFixture()
+	f.representativeRepo.On("Update", f.representative).Return(nil)
+
+	err := f.service.Approve(context.Background(), representativePublicID, businessRequestID)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RepresentativeApproved, f.representative.Status)
+	assert.NotNil(t, f.representative.DecidedAt)
+	assert.Equal(t, models.StatusVerified, f.request.Status, "approving must not change the request status")
+	f.repo.AssertNotCalled(t, "SaveTransition", mock.Anything, mock.Anything)
+}
+
+func TestRepresentativeApproval_DeclineRejectsRequest(t *testing.T) {
+	f := newApprovalFixture()
+	f.representativeRepo.On("Update", f.representative).Return(nil)
+	f.repo.On("SaveTransition", f.request, mock.MatchedBy(func(event *models.OnboardingRequestEvent) bool {
+		return event.ToStatus == models.StatusRejected && event.Actor == models.ActorRepresentative && event.Reason == "sociedade encerrada"
+	})).Return(nil)
+
+	err := f.service.Decline(context.Background(), representativePublicID, businessRequestID, " sociedade encerrada ")
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.RepresentativeDeclined, f.representative.Status)
+	assert.Equal(t, models.StatusRejected, f.request.Status)
+	f.repo.AssertExpectations(t)
+}
+
+func TestRepresentativeApproval_AlreadyDecided(t *testing.T) {
+	f := newApprovalFixture()
+	f.representative.Status = models.RepresentativeApproved
+
+	err := f.service.Approve(context.Background(), representativePublicID, businessRequestID)
+
+	assert.Equal(t, services.ErrRepresentativeAlreadyDecided, err)
+	f.representativeRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestRepresentativeApproval_RequestClosed(t *testing.T) {
+	f := newApprovalFixture()
+	f.request.Status = models.StatusRejected
+
+	err := f.service.Approve(context.Background(), representativePublicID, businessRequestID)
+
+	assert.Equal(t, services.ErrRequestClosed, err)
+	f.representativeRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestRepresentativeApproval_NotARepresentative(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockRepresentativeRepo := new(mocks.MockLegalRepresentativeRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	service := services.NewRepresentativeApprovalService(new(mocks.MockTransactor), mockRepo, mockRepresentativeRepo, mockUserRepo)
+
+	mockUserRepo.On("FindByPublicID", representativePublicID).Return(&user_models.User{ID: 99}, nil)
+	mockRepo.On("FindByPublicIDForUpdate", businessRequestID).Return(&models.OnboardingRequest{
+		ID:             7,
+		CustomerType:   user_models.CustomerTypeBusiness,
+		Status:         models.StatusPending,
+		TokenExpiresAt: time.Now().Add(1 * time.Hour),
+	}, nil)
+	mockRepresentativeRepo.On("FindByRequestAndUserForUpdate", int64(7), int64(99)).Return(nil, gorm.ErrRecordNotFound)
+
+	err := service.Approve(context.Background(), representativePublicID, businessRequestID)
+
+	assert.Equal(t, services.ErrRequestNotFound, err)
+}
+
+func TestRepresentativeApproval_IndividualRequestIsNotFound(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockRepresentativeRepo := new(mocks.MockLegalRepresentativeRepository)
+	mockUserRepo := new(mocks.MockUserRepository)
+	service := services.NewRepresentativeApprovalService(new(mocks.MockTransactor), mockRepo, mockRepresentativeRepo, mockUserRepo)
+
+	mockUserRepo.On("FindByPublicID", representativePublicID).Return(&user_models.User{ID: 42}, nil)
+	mockRepo.On("FindByPublicIDForUpdate", businessRequestID).Return(&models.OnboardingRequest{
+		ID:           7,
+		CustomerType: user_models.CustomerTypeIndividual,
+		Status:       models.StatusPending,
+	}, nil)
+
+	err := service.Approve(context.Background(), representativePublicID, businessRequestID)
+
+	assert.Equal(t, services.ErrRequestNotFound, err)
+	mockRepresentativeRepo.AssertNotCalled(t, "FindByRequestAndUserForUpdate", mock.Anything, mock.Anything)
+}
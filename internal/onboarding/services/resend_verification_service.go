@@ -0,0 +1,122 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+	outbox_repositories "github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrRequestNotFound    = errors.New("solicitação de onboarding não encontrada")
+	ErrRequestNotPending  = errors.New("o e-mail desta solicitação já foi verificado")
+	ErrResendCooldown     = errors.New("aguarde alguns instantes antes de solicitar um novo e-mail")
+	ErrResendLimitReached = errors.New("limite de reenvios atingido para esta solicitação")
+)
+
+const (
+	resendCooldown = 1 * time.Minute
+	maxResends     = 5
+)
+
+type ResendVerificationService interface {
+	Execute(ctx context.Context, email string) error
+}
+
+type resendVerificationService struct {
+	transactor database.Transactor
+	repo       repositories.OnboardingRequestRepository
+	outboxRepo outbox_repositories.OutboxRepository
+}
+
+func NewResendVerificationService(
+	transactor database.Transactor,
+	repo repositories.OnboardingRequestRepository,
+	outboxRepo outbox_repositories.OutboxRepository,
+) ResendVerificationService {
+	return &resendVerificationService{transactor: transactor, repo: repo, outboxRepo: outboxRepo}
+}
+
+// Execute gera um novo token de verificação para uma solicitação PENDING, invalidando o anterior,
+// e reenvia o e-mail. Os reenvios respeitam um intervalo mínimo e um limite por solicitação. A
+// resposta é a mesma quando o e-mail não tem solicitação, já foi verificado ou não pode receber
+// outro envio agora, para que a rota não revele quais e-mails estão cadastrados.
+func (s *resendVerificationService) Execute(ctx context.Context, email string) error {
+	var resendErr error
+	err := s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		resendErr = s.resendWithinTx(ctx, tx, strings.TrimSpace(email))
+		return resendErr
+	})
+	switch {
+	case errors.Is(resendErr, ErrRequestNotFound),
+		errors.Is(resendErr, ErrRequestNotPending),
+		errors.Is(resendErr, ErrResendCooldown),
+		errors.Is(resendErr, ErrResendLimitReached):
+		return nil
+	case resendErr != nil:
+		return resendErr
+	case err != nil:
+		log.Printf("Error committing verification email resend: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+// resendWithinTx bloqueia a solicitação antes de conferir o intervalo e o limite, para que
+// reenvios simultâneos não enviem mais de um e-mail.
+func (s *resendVerificationService) resendWithinTx(ctx context.Context, tx *gorm.DB, email string) error {
+	repo := s.repo.WithTx(tx)
+
+	onboardingRequest, err := repo.FindByEmailForUpdate(ctx, email)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRequestNotFound
+		}
+		log.Printf("Error finding onboarding request by email: %v", err)
+		return ErrInternalServer
+	}
+
+	if onboardingRequest.Status != models.StatusPending {
+		return ErrRequestNotPending
+	}
+
+	if onboardingRequest.ResendCount >= maxResends {
+		return ErrResendLimitReached
+	}
+
+	now := time.Now()
+	if onboardingRequest.LastEmailSentAt != nil && now.Sub(*onboardingRequest.LastEmailSentAt) < resendCooldown {
+		return ErrResendCooldown
+	}
+
+	rawToken, hashedToken, err := crypto.GenerateVerificationToken()
+	if err != nil {
+		log.Printf("Error generating verification token: %v", err)
+		return ErrInternalServer
+	}
+
+	onboardingRequest.VerificationTokenHash = hashedToken
+	onboardingRequest.TokenExpiresAt = now.Add(verificationTokenTTL)
+	onboardingRequest.ResendCount++
+	onboardingRequest.LastEmailSentAt = &now
+
+	if err := repo.Update(ctx, onboardingRequest); err != nil {
+		log.Printf("Error updating onboarding request for resend: %v", err)
+		return ErrInternalServer
+	}
+	if err := s.outboxRepo.WithTx(tx).EnqueueEmail(ctx, newVerificationEmail(onboardingRequest, rawToken)); err != nil {
+		log.Printf("Error enqueueing verification email resend: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
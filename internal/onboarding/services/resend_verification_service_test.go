@@ -0,0 +1,92 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/services"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+func TestResendVerificationService_Execute_Success(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	mockOutbox := new(mocks.MockOutboxRepository)
+	transactor := new(mocks.MockTransactor)
+	service := services.NewResendVerificationService(transactor, mockRepo, mockOutbox)
+
+	lastSentAt := time.Now().Add(-10 * time.Minute)
+	request := &models.OnboardingRequest{
+		FullName:              "John Doe",
+		Email:                 "john@example.com",
+		Status:                models.StatusPending,
+		VerificationTokenHash: "old-hash",
+		TokenExpiresAt:        time.Now().Add(-1 * time.Minute),
+		LastEmailSentAt:       &lastSentAt,
+	}
+
+	mockRepo.On("FindByEmailForUpdate", request.Email).Return(request, nil)
+	mockRepo.On("Update", request).Return(nil)
+	mockOutbox.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.To == request.Email
+	})).Return(nil)
+
+	err := service.Execute(context.Background(), request.Email)
+
+	assert.NoError(t, err)
+	assert.NotEqual(t, "old-hash", request.VerificationTokenHash)
+	assert.True(t, request.TokenExpiresAt.After(time.Now().Add(50*time.Minute)))
+	assert.Equal(t, 1, request.ResendCount)
+	assert.True(t, request.LastEmailSentAt.After(lastSentAt))
+
+	mockRepo.AssertExpectations(t)
+	mockOutbox.AssertExpectations(t)
+	assert.Equal(t, 1, transactor.Calls)
+}
+
+// As recusas respondem como um envio bem-sucedido, para não revelar quais e-mails têm solicitação.
+func TestResendVerificationService_Execute_SilentRejections(t *testing.T) {
+	recently := time.Now().Add(-10 * time.Second)
+	longAgo := time.Now().Add(-1 * time.Hour)
+
+	tests := []struct {
+		name     string
+		request  *models.OnboardingRequest
+		findErr  error
+		expected error
+	}{
+		{"Unknown email", nil, gorm.ErrRecordNotFound, nil},
+		{"Database error", nil, errors.New("db error"), services.ErrInternalServer},
+		{"Already verified", &models.OnboardingRequest{Status: models.StatusVerified}, nil, nil},
+		{"Completed", &models.OnboardingRequest{Status: models.StatusCompleted}, nil, nil},
+		{"Cooldown", &models.OnboardingRequest{Status: models.StatusPending, LastEmailSentAt: &recently}, nil, nil},
+		{"Limit reached", &models.OnboardingRequest{Status: models.StatusPending, ResendCount: 5, LastEmailSentAt: &longAgo}, nil, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockRepo := new(mocks.MockOnboardingRepository)
+			mockOutbox := new(mocks.MockOutboxRepository)
+			transactor := new(mocks.MockTransactor)
+			service := services.NewResendVerificationService(transactor, mockRepo, mockOutbox)
+
+			if tt.request == nil {
+				mockRepo.On("FindByEmailForUpdate", "john@example.com").Return(nil, tt.findErr)
+			} else {
+				mockRepo.On("FindByEmailForUpdate", "john@example.com").Return(tt.request, nil)
+			}
+
+			err := service.Execute(context.Background(), "john@example.com")
+
+			assert.Equal(t, tt.expected, err)
+			mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+			mockOutbox.AssertNotCalled(t, "EnqueueEmail", mock.Anything)
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package services
 
 import (
+	"context"
 	"errors"
 	"log"
 	"time"
@@ -15,10 +16,11 @@ var (
 	ErrInvalidToken    = errors.New("token inválido ou expirado")
 	ErrExpiredToken    = errors.New("token expirado")
 	ErrAlreadyVerified = errors.New("usuário já verificado")
+	ErrRequestClosed   = errors.New("a solicitação de onboarding foi encerrada")
 )
 
 type VerifyEmailTokenService interface {
-	Execute(token string) error
+	Execute(ctx context.Context, token string) error
 }
 
 type verifyEmailTokenService struct {
@@ -29,10 +31,10 @@ func NewVerifyEmailTokenService(repo repositories.OnboardingRequestRepository) V
 	return &verifyEmailTokenService{repo: repo}
 }
 
-func (s *verifyEmailTokenService) Execute(token string) error {
+func (s *verifyEmailTokenService) Execute(ctx context.Context, token string) error {
 	hashedToken := crypto.HashTokenSHA256(token)
 
-	onboardingRequest, err := s.repo.FindByVerificationTokenHash(hashedToken)
+	onboardingRequest, err := s.repo.FindByVerificationTokenHash(ctx, hashedToken)
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return ErrInvalidToken
@@ -49,12 +51,16 @@ func (s *verifyEmailTokenService) Execute(token string) error {
 		return ErrAlreadyVerified
 	}
 
-	if onboardingRequest.Status == models.StatusVerified {
+	if onboardingRequest.Status == models.StatusVerified || onboardingRequest.Status == models.StatusUnderReview || onboardingRequest.Status == models.StatusApproved {
 		return nil // Operational idempotency
 	}
 
-	onboardingRequest.Status = models.StatusVerified
-	if err := s.repo.Update(onboardingRequest); err != nil {
+	event, err := onboardingRequest.TransitionTo(models.StatusVerified, models.ActorCustomer, "e-mail verificado")
+	if err != nil {
+		return ErrRequestClosed
+	}
+
+	if err := s.repo.SaveTransition(ctx, onboardingRequest, event); err != nil {
 		log.Printf("Error updating onboarding request status: %v", err)
 		return ErrInternalServer
 	}
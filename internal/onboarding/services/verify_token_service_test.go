@@ -1,6 +1,7 @@
 package services_test
 
 import (
+	"context"
 	"errors"
 	"testing"
 	"time"
@@ -26,9 +27,9 @@ func TestVerifyEmailTokenService_Execute_Success(t *testing.T) {
 	}
 
 	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
-	mockRepo.On("Update", mock.AnythingOfType("*models.OnboardingRequest")).Return(nil)
+	mockRepo.On("SaveTransition", mock.AnythingOfType("*models.OnboardingRequest"), mock.AnythingOfType("*models.OnboardingRequestEvent")).Return(nil)
 
-	err := service.Execute(token)
+	err := service.Execute(context.Background(), token)
 
 	assert.NoError(t, err)
 	assert.Equal(t, models.StatusVerified, request.Status)
@@ -44,7 +45,7 @@ func TestVerifyEmailTokenService_Execute_TokenNotFound(t *testing.T) {
 
 	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(nil, gorm.ErrRecordNotFound)
 
-	err := service.Execute(token)
+	err := service.Execute(context.Background(), token)
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrInvalidToken, err)
@@ -64,7 +65,7 @@ func TestVerifyEmailTokenService_Execute_TokenExpired(t *testing.T) {
 
 	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
 
-	err := service.Execute(token)
+	err := service.Execute(context.Background(), token)
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrExpiredToken, err)
@@ -84,7 +85,7 @@ func TestVerifyEmailTokenService_Execute_AlreadyCompleted(t *testing.T) {
 
 	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
 
-	err := service.Execute(token)
+	err := service.Execute(context.Background(), token)
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrAlreadyVerified, err)
@@ -104,10 +105,10 @@ func TestVerifyEmailTokenService_Execute_AlreadyVerifiedIdempotency(t *testing.T
 
 	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
 
-	err := service.Execute(token)
+	err := service.Execute(context.Background(), token)
 
 	assert.NoError(t, err)
-	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+	mockRepo.AssertNotCalled(t, "SaveTransition", mock.Anything, mock.Anything)
 	mockRepo.AssertExpectations(t)
 }
 
@@ -120,7 +121,7 @@ func TestVerifyEmailTokenService_Execute_DatabaseErrorOnFind(t *testing.T) {
 
 	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(nil, errors.New("db error"))
 
-	err := service.Execute(token)
+	err := service.Execute(context.Background(), token)
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrInternalServer, err)
@@ -139,11 +140,31 @@ func TestVerifyEmailTokenService_Execute_DatabaseErrorOnUpdate(t *testing.T) {
 	}
 
 	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
-	mockRepo.On("Update", mock.AnythingOfType("*models.OnboardingRequest")).Return(errors.New("db error"))
+	mockRepo.On("SaveTransition", mock.AnythingOfType("*models.OnboardingRequest"), mock.AnythingOfType("*models.OnboardingRequestEvent")).Return(errors.New("db error"))
 
-	err := service.Execute(token)
+	err := service.Execute(context.Background(), token)
 
 	assert.Error(t, err)
 	assert.Equal(t, services.ErrInternalServer, err)
 	mockRepo.AssertExpectations(t)
 }
+
+func TestVerifyEmailTokenService_Execute_RequestClosed(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	service := services.NewVerifyEmailTokenService(mockRepo)
+
+	token := "cancelled-token"
+	hashedToken := crypto.HashTokenSHA256(token)
+	request := &models.OnboardingRequest{
+		Status:         models.StatusCancelled,
+		TokenExpiresAt: time.Now().Add(1 * time.Hour),
+	}
+
+	mockRepo.On("FindByVerificationTokenHash", hashedToken).Return(request, nil)
+
+	err := service.Execute(context.Background(), token)
+
+	assert.Equal(t, services.ErrRequestClosed, err)
+	assert.Equal(t, models.StatusCancelled, request.Status)
+	mockRepo.AssertNotCalled(t, "SaveTransition", mock.Anything, mock.Anything)
+}
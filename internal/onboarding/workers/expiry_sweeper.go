@@ -0,0 +1,87 @@
+// Package workers defines the background jobs of the onboarding process.
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+)
+
+const (
+	DefaultSweepInterval  = 1 * time.Minute
+	DefaultSweepBatchSize = 500
+)
+
+// ExpirySweeper marca periodicamente como EXPIRED as solicitações PENDING/VERIFIED/APPROVED cujo token venceu,
+// liberando o CPF e o e-mail para um novo onboarding.
+type ExpirySweeper struct {
+	repo      repositories.OnboardingRequestRepository
+	interval  time.Duration
+	batchSize int
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewExpirySweeper(repo repositories.OnboardingRequestRepository, interval time.Duration, batchSize int) *ExpirySweeper {
+	return &ExpirySweeper{repo: repo, interval: interval, batchSize: batchSize}
+}
+
+// Start inicia o sweeper em background. Ele roda uma vez imediatamente e depois a cada intervalo.
+func (w *ExpirySweeper) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := w.RunOnce(ctx); err != nil {
+				log.Printf("Error expiring stale onboarding requests: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop sinaliza o fim do sweeper e aguarda o lote em andamento terminar.
+func (w *ExpirySweeper) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// RunOnce expira lotes de solicitações até não restar nenhuma vencida ou o contexto ser cancelado.
+func (w *ExpirySweeper) RunOnce(ctx context.Context) (int64, error) {
+	var total int64
+
+	for ctx.Err() == nil {
+		expired, err := w.repo.ExpireStale(ctx, time.Now(), w.batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += expired
+		if expired < int64(w.batchSize) {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Expired %d stale onboarding requests", total)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,72 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/workers"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestExpirySweeper_RunOnce_ProcessesBatchesUntilEmpty(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	sweeper := workers.NewExpirySweeper(mockRepo, time.Minute, 2)
+
+	mockRepo.On("ExpireStale", mock.AnythingOfType("time.Time"), 2).Return(int64(2), nil).Twice()
+	mockRepo.On("ExpireStale", mock.AnythingOfType("time.Time"), 2).Return(int64(1), nil).Once()
+
+	total, err := sweeper.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	mockRepo.AssertNumberOfCalls(t, "ExpireStale", 3)
+}
+
+func TestExpirySweeper_RunOnce_StopsOnError(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	sweeper := workers.NewExpirySweeper(mockRepo, time.Minute, 2)
+
+	mockRepo.On("ExpireStale", mock.Anything, 2).Return(int64(0), errors.New("db error"))
+
+	_, err := sweeper.RunOnce(context.Background())
+
+	assert.Error(t, err)
+	mockRepo.AssertNumberOfCalls(t, "ExpireStale", 1)
+}
+
+func TestExpirySweeper_RunOnce_StopsWhenContextIsCancelled(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	sweeper := workers.NewExpirySweeper(mockRepo, time.Minute, 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	mockRepo.On("ExpireStale", mock.Anything, 2).Run(func(_ mock.Arguments) { cancel() }).Return(int64(2), nil)
+
+	total, err := sweeper.RunOnce(ctx)
+
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	mockRepo.AssertNumberOfCalls(t, "ExpireStale", 1)
+}
+
+func TestExpirySweeper_StartAndStop(t *testing.T) {
+	mockRepo := new(mocks.MockOnboardingRepository)
+	sweeper := workers.NewExpirySweeper(mockRepo, time.Hour, 2)
+
+	called := make(chan struct{}, 1)
+	mockRepo.On("ExpireStale", mock.Anything, 2).Run(func(_ mock.Arguments) {
+		select {
+		case called <- struct{}{}:
+		default:
+		}
+	}).Return(int64(0), nil)
+
+	sweeper.Start(context.Background())
+	<-called
+	sweeper.Stop()
+
+	mockRepo.AssertCalled(t, "ExpireStale", mock.Anything, 2)
+}
@@ -0,0 +1,73 @@
+// Package controllers define the HTTP handlers for the outbox back-office.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/services"
+)
+
+type OutboxController struct {
+	adminService services.OutboxAdminService
+}
+
+func NewOutboxController(adminService services.OutboxAdminService) *OutboxController {
+	return &OutboxController{adminService: adminService}
+}
+
+func (ctrl *OutboxController) ListFailed(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(services.DefaultListLimit)))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O parâmetro 'limit' deve ser um número."})
+		return
+	}
+
+	offset, err := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O parâmetro 'offset' deve ser um número."})
+		return
+	}
+
+	messages, err := ctrl.adminService.ListFailed(c.Request.Context(), limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+
+	response := make([]gin.H, 0, len(messages))
+	for _, message := range messages {
+		item := gin.H{
+			"id":        message.PublicID,
+			"recipient": message.Recipient,
+			"attempts":  message.Attempts,
+			"lastError": message.LastError,
+			"createdAt": message.CreatedAt,
+			"failedAt":  message.UpdatedAt,
+			"retryable": message.Payload != "",
+		}
+		if request, err := message.EmailRequest(); err == nil {
+			item["template"] = request.TemplateID
+		}
+		response = append(response, item)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"messages": response})
+}
+
+func (ctrl *OutboxController) RetryMessage(c *gin.Context) {
+	err := ctrl.adminService.Retry(c.Request.Context(), c.Param("id"))
+	if err == nil {
+		c.JSON(http.StatusAccepted, gin.H{"message": "Mensagem devolvida à fila de envio."})
+		return
+	}
+
+	if errors.Is(err, services.ErrMessageNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
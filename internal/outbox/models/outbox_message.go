@@ -0,0 +1,99 @@
+// Package models define the data structures for the transactional outbox.
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// ErrPayloadPurged indica que o payload já foi apagado e a mensagem não pode mais ser enviada.
+var ErrPayloadPurged = errors.New("outbox message payload was purged")
+
+// MessageStatus define os possíveis status de uma mensagem do outbox.
+type MessageStatus string
+
+const (
+	StatusPending    MessageStatus = "PENDING"
+	StatusProcessing MessageStatus = "PROCESSING"
+	StatusSent       MessageStatus = "SENT"
+	// StatusFailed é a dead letter: a mensagem esgotou as tentativas e só volta à fila por um administrador.
+	StatusFailed MessageStatus = "FAILED"
+)
+
+// DefaultMaxAttempts é o número de envios tentados antes de a mensagem ir para a dead letter.
+const DefaultMaxAttempts = 8
+
+// DeadLetterRetention é por quanto tempo o payload de uma mensagem da dead letter é mantido para
+// que um administrador possa reenviá-la. Depois disso ele é apagado, como o das mensagens enviadas.
+const DeadLetterRetention = 72 * time.Hour
+
+// OutboxMessage é um e-mail gravado na mesma transação da operação que o originou
+// e entregue depois pelo EmailDispatcher. O destinatário e o payload, que contém os dados do
+// template (links com tokens, códigos), são cifrados; o payload fica vazio depois do envio.
+type OutboxMessage struct {
+	ID             int64         `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID       string        `gorm:"type:varchar(26);unique;not null"`
+	Recipient      string        `gorm:"type:text;not null;serializer:encrypted"`
+	RecipientIndex string        `gorm:"type:varchar(64);not null;column:recipient_index"`
+	Payload        string        `gorm:"type:text;not null;serializer:encrypted"`
+	Status         MessageStatus `gorm:"type:varchar(20);not null;default:'PENDING'"`
+	Attempts       int           `gorm:"not null;default:0"`
+	MaxAttempts    int           `gorm:"not null"`
+	NextAttemptAt  time.Time     `gorm:"not null"`
+	LockedUntil    *time.Time    `gorm:"column:locked_until"`
+	ClaimToken     *string       `gorm:"type:varchar(26);column:claim_token"`
+	LastError      *string       `gorm:"column:last_error"`
+	SentAt         *time.Time    `gorm:"column:sent_at"`
+	CreatedAt      time.Time     `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time     `gorm:"autoUpdateTime"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox.messages"
+}
+
+func (m *OutboxMessage) BeforeCreate(_ *gorm.DB) (err error) {
+	m.PublicID = ulid.Make().String()
+	m.RecipientIndex, err = fieldcrypto.BlindIndex(m.Recipient)
+	return
+}
+
+// NewEmailMessage serializa o e-mail para ser enviado assim que possível.
+func NewEmailMessage(request *notification.EmailRequest) (*OutboxMessage, error) {
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxMessage{
+		Recipient:     request.To,
+		Payload:       string(payload),
+		Status:        StatusPending,
+		MaxAttempts:   DefaultMaxAttempts,
+		NextAttemptAt: time.Now(),
+	}, nil
+}
+
+// EmailRequest desserializa o e-mail guardado no payload.
+func (m *OutboxMessage) EmailRequest() (*notification.EmailRequest, error) {
+	if m.Payload == "" {
+		return nil, ErrPayloadPurged
+	}
+
+	var request notification.EmailRequest
+	if err := json.Unmarshal([]byte(m.Payload), &request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// IsExhausted indica se a tentativa em andamento é a última permitida.
+func (m *OutboxMessage) IsExhausted() bool {
+	return m.Attempts >= m.MaxAttempts
+}
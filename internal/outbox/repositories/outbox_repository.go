@@ -0,0 +1,213 @@
+// Package repositories defines the data access layer for the transactional outbox.
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// ErrLeaseLost indica que a mensagem não está mais reservada com o token informado: o lease
+// expirou e ela foi devolvida à fila ou reservada por outro worker.
+var ErrLeaseLost = errors.New("outbox message lease lost")
+
+type OutboxRepository interface {
+	EnqueueEmail(ctx context.Context, request *notification.EmailRequest) error
+	ClaimDue(ctx context.Context, now time.Time, batchSize int, lockedUntil time.Time) ([]models.OutboxMessage, error)
+	ExtendLease(ctx context.Context, id int64, claimToken string, lockedUntil time.Time) error
+	MarkSent(ctx context.Context, id int64, claimToken string, sentAt time.Time) error
+	MarkForRetry(ctx context.Context, id int64, claimToken string, lastError string, nextAttemptAt time.Time) error
+	MarkFailed(ctx context.Context, id int64, claimToken string, lastError string) error
+	ListFailed(ctx context.Context, limit, offset int) ([]models.OutboxMessage, error)
+	PurgeFailedPayloads(ctx context.Context, failedBefore time.Time) (int64, error)
+	Requeue(ctx context.Context, publicID string, now time.Time) (bool, error)
+	DeleteByRecipient(ctx context.Context, recipient string) error
+	WithTx(tx *gorm.DB) OutboxRepository
+}
+
+type outboxRepository struct {
+	db *gorm.DB
+}
+
+func NewOutboxRepository(db *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: db}
+}
+
+// EnqueueEmail grava o e-mail no outbox. Deve ser chamado via WithTx, na mesma transação
+// da operação que o originou, para que um nunca exista sem o outro.
+func (r *outboxRepository) EnqueueEmail(ctx context.Context, request *notification.EmailRequest) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	message, err := models.NewEmailMessage(request)
+	if err != nil {
+		return err
+	}
+
+	return r.db.WithContext(ctx).Create(message).Error
+}
+
+// ClaimDue reserva até batchSize mensagens prontas para envio, incluindo as que ficaram presas
+// em PROCESSING após a queda de um worker. As linhas bloqueadas por outros workers são ignoradas.
+// Cada reserva conta como uma tentativa, para que uma mensagem que derruba o worker não fique em loop,
+// e recebe um ClaimToken novo, exigido pelas atualizações seguintes.
+func (r *outboxRepository) ClaimDue(ctx context.Context, now time.Time, batchSize int, lockedUntil time.Time) ([]models.OutboxMessage, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var messages []models.OutboxMessage
+	result := r.db.WithContext(ctx).Raw(`
+		WITH due AS (
+			SELECT id FROM outbox.messages
+			WHERE (status = ? AND next_attempt_at <= ?) OR (status = ? AND locked_until < ?)
+			ORDER BY next_attempt_at
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE outbox.messages m
+		SET status = ?, attempts = m.attempts + 1, locked_until = ?, claim_token = ?, updated_at = ?
+		FROM due
+		WHERE m.id = due.id
+		RETURNING m.*`,
+		models.StatusPending, now, models.StatusProcessing, now, batchSize,
+		models.StatusProcessing, lockedUntil, ulid.Make().String(), now,
+	).Scan(&messages)
+	return messages, result.Error
+}
+
+// ExtendLease renova a reserva antes de cada envio, para que um lote demorado não deixe as últimas
+// mensagens voltarem à fila enquanto ainda serão enviadas. Retorna ErrLeaseLost se a mensagem já
+// foi reservada por outro worker; nesse caso ela não deve ser enviada.
+func (r *outboxRepository) ExtendLease(ctx context.Context, id int64, claimToken string, lockedUntil time.Time) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Model(&models.OutboxMessage{}).
+		Where("id = ? AND status = ? AND claim_token = ?", id, models.StatusProcessing, claimToken).
+		UpdateColumn("locked_until", lockedUntil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+// MarkSent registra o envio e apaga o payload, que não é mais necessário depois da entrega.
+func (r *outboxRepository) MarkSent(ctx context.Context, id int64, claimToken string, sentAt time.Time) error {
+	return r.release(ctx, id, claimToken, map[string]any{
+		"status":     models.StatusSent,
+		"payload":    "",
+		"sent_at":    sentAt,
+		"last_error": nil,
+	})
+}
+
+func (r *outboxRepository) MarkForRetry(ctx context.Context, id int64, claimToken string, lastError string, nextAttemptAt time.Time) error {
+	return r.release(ctx, id, claimToken, map[string]any{
+		"status":          models.StatusPending,
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastError,
+	})
+}
+
+func (r *outboxRepository) MarkFailed(ctx context.Context, id int64, claimToken string, lastError string) error {
+	return r.release(ctx, id, claimToken, map[string]any{
+		"status":     models.StatusFailed,
+		"last_error": lastError,
+	})
+}
+
+// release aplica o resultado de um envio e libera a reserva, desde que a mensagem ainda esteja
+// em PROCESSING com o token da reserva. Caso contrário retorna ErrLeaseLost sem alterar nada.
+func (r *outboxRepository) release(ctx context.Context, id int64, claimToken string, updates map[string]any) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	updates["locked_until"] = nil
+	updates["claim_token"] = nil
+
+	result := r.db.WithContext(ctx).Model(&models.OutboxMessage{}).
+		Where("id = ? AND status = ? AND claim_token = ?", id, models.StatusProcessing, claimToken).
+		Updates(updates)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrLeaseLost
+	}
+	return nil
+}
+
+func (r *outboxRepository) ListFailed(ctx context.Context, limit, offset int) ([]models.OutboxMessage, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var messages []models.OutboxMessage
+	result := r.db.WithContext(ctx).
+		Where("status = ?", models.StatusFailed).
+		Order("updated_at DESC").
+		Limit(limit).
+		Offset(offset).
+		Find(&messages)
+	return messages, result.Error
+}
+
+// PurgeFailedPayloads apaga o payload das mensagens que estão na dead letter desde antes de
+// failedBefore. Elas continuam listadas para o administrador, mas não podem mais ser reenviadas.
+// O updated_at é preservado, pois indica quando a mensagem foi para a dead letter.
+func (r *outboxRepository) PurgeFailedPayloads(ctx context.Context, failedBefore time.Time) (int64, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Model(&models.OutboxMessage{}).
+		Where("status = ? AND updated_at < ? AND payload <> ''", models.StatusFailed, failedBefore).
+		UpdateColumn("payload", "")
+	return result.RowsAffected, result.Error
+}
+
+// Requeue devolve uma mensagem da dead letter para a fila, zerando as tentativas, e informa se
+// ela existia com status FAILED e com o payload ainda disponível.
+func (r *outboxRepository) Requeue(ctx context.Context, publicID string, now time.Time) (bool, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	result := r.db.WithContext(ctx).Model(&models.OutboxMessage{}).
+		Where("public_id = ? AND status = ? AND payload <> ''", publicID, models.StatusFailed).
+		Updates(map[string]any{
+			"status":          models.StatusPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// DeleteByRecipient apaga todas as mensagens endereçadas ao e-mail, em qualquer status, localizando-as
+// pelo blind index do destinatário.
+func (r *outboxRepository) DeleteByRecipient(ctx context.Context, recipient string) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	recipientIndex, err := fieldcrypto.BlindIndex(recipient)
+	if err != nil {
+		return err
+	}
+	if recipientIndex == "" {
+		return nil
+	}
+
+	return r.db.WithContext(ctx).Where("recipient_index = ?", recipientIndex).Delete(&models.OutboxMessage{}).Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *outboxRepository) WithTx(tx *gorm.DB) OutboxRepository {
+	return &outboxRepository{db: tx}
+}
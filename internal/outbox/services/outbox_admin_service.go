@@ -0,0 +1,68 @@
+// Package services define the back-office operations over the transactional outbox.
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+)
+
+var (
+	ErrMessageNotFound = errors.New("mensagem não encontrada na fila de falhas ou com o conteúdo já expirado")
+	ErrInternalServer  = errors.New("ocorreu um erro inesperado")
+)
+
+const (
+	DefaultListLimit = 50
+	MaxListLimit     = 200
+)
+
+type OutboxAdminService interface {
+	ListFailed(ctx context.Context, limit, offset int) ([]models.OutboxMessage, error)
+	Retry(ctx context.Context, publicID string) error
+}
+
+type outboxAdminService struct {
+	repo repositories.OutboxRepository
+}
+
+func NewOutboxAdminService(repo repositories.OutboxRepository) OutboxAdminService {
+	return &outboxAdminService{repo: repo}
+}
+
+// ListFailed lista as mensagens da dead letter, das mais recentes para as mais antigas.
+func (s *outboxAdminService) ListFailed(ctx context.Context, limit, offset int) ([]models.OutboxMessage, error) {
+	if limit <= 0 || limit > MaxListLimit {
+		limit = DefaultListLimit
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	messages, err := s.repo.ListFailed(ctx, limit, offset)
+	if err != nil {
+		log.Printf("Error listing failed outbox messages: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return messages, nil
+}
+
+// Retry devolve uma mensagem da dead letter para a fila com as tentativas zeradas.
+func (s *outboxAdminService) Retry(ctx context.Context, publicID string) error {
+	found, err := s.repo.Requeue(ctx, publicID, time.Now())
+	if err != nil {
+		log.Printf("Error requeueing outbox message %s: %v", publicID, err)
+		return ErrInternalServer
+	}
+	if !found {
+		return ErrMessageNotFound
+	}
+
+	log.Printf("Outbox message %s requeued by an administrator", publicID)
+	return nil
+}
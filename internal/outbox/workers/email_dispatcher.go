@@ -0,0 +1,192 @@
+// Package workers defines the background jobs that deliver outbox messages.
+package workers
+
+import (
+	"context"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+)
+
+const (
+	DefaultDispatchInterval  = 5 * time.Second
+	DefaultDispatchBatchSize = 50
+
+	// claimLease é o tempo que uma mensagem fica reservada para um worker. A reserva é renovada
+	// antes de cada envio do lote; se o worker cair durante o envio, outro worker a assume
+	// depois desse prazo.
+	claimLease = 2 * notification.SendTimeout
+
+	baseRetryDelay = 30 * time.Second
+	maxRetryDelay  = 1 * time.Hour
+)
+
+// EmailDispatcher entrega os e-mails gravados no outbox, com backoff exponencial entre as
+// tentativas e dead letter após o limite de tentativas de cada mensagem.
+type EmailDispatcher struct {
+	repo      repositories.OutboxRepository
+	emailSvc  notification.EmailService
+	interval  time.Duration
+	batchSize int
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewEmailDispatcher(repo repositories.OutboxRepository, emailSvc notification.EmailService, interval time.Duration, batchSize int) *EmailDispatcher {
+	return &EmailDispatcher{repo: repo, emailSvc: emailSvc, interval: interval, batchSize: batchSize}
+}
+
+// Start inicia o dispatcher em background. Ele roda uma vez imediatamente e depois a cada intervalo.
+func (w *EmailDispatcher) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := w.RunOnce(ctx); err != nil {
+				log.Printf("Error dispatching outbox e-mails: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop sinaliza o fim do dispatcher e aguarda o envio em andamento terminar.
+func (w *EmailDispatcher) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// RunOnce apaga os payloads vencidos da dead letter e depois reserva e entrega lotes de mensagens
+// até não restar nenhuma pronta ou o contexto ser cancelado.
+func (w *EmailDispatcher) RunOnce(ctx context.Context) (int, error) {
+	var total int
+
+	purged, err := w.repo.PurgeFailedPayloads(ctx, time.Now().Add(-models.DeadLetterRetention))
+	if err != nil {
+		return total, err
+	}
+	if purged > 0 {
+		log.Printf("Purged the payload of %d dead-lettered outbox messages", purged)
+	}
+
+	for ctx.Err() == nil {
+		now := time.Now()
+		messages, err := w.repo.ClaimDue(ctx, now, w.batchSize, now.Add(claimLease))
+		if err != nil {
+			return total, err
+		}
+
+		for i := range messages {
+			w.deliver(ctx, &messages[i])
+		}
+
+		total += len(messages)
+		if len(messages) < w.batchSize {
+			break
+		}
+	}
+
+	return total, nil
+}
+
+// deliver envia uma mensagem reservada e registra o resultado. O envio e a atualização não são
+// interrompidos pelo cancelamento de ctx, para que o Stop não deixe mensagens presas em PROCESSING.
+func (w *EmailDispatcher) deliver(ctx context.Context, message *models.OutboxMessage) {
+	ctx = context.WithoutCancel(ctx)
+
+	request, err := message.EmailRequest()
+	if err != nil {
+		log.Printf("CRITICAL: Invalid payload in outbox message %s, moving it to dead letter: %v", message.PublicID, err)
+		w.markFailed(ctx, message, err)
+		return
+	}
+
+	// Enviar sem renovar a reserva duplicaria o e-mail se o lote demorou mais que claimLease e
+	// outro worker já reservou a mensagem.
+	if err := w.repo.ExtendLease(ctx, message.ID, claimToken(message), time.Now().Add(claimLease)); err != nil {
+		logReleaseError(message, "sent", err)
+		return
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, notification.SendTimeout)
+	err = w.emailSvc.SendEmail(sendCtx, request)
+	cancel()
+
+	if err == nil {
+		if err := w.repo.MarkSent(ctx, message.ID, claimToken(message), time.Now()); err != nil {
+			logReleaseError(message, "marked as sent", err)
+		}
+		return
+	}
+
+	if message.IsExhausted() {
+		log.Printf("CRITICAL: Outbox message %s failed after %d attempts, moving it to dead letter: %v", message.PublicID, message.Attempts, err)
+		w.markFailed(ctx, message, err)
+		return
+	}
+
+	nextAttemptAt := time.Now().Add(RetryDelay(message.Attempts))
+	log.Printf("Failed to send outbox message %s (attempt %d/%d), retrying at %s: %v",
+		message.PublicID, message.Attempts, message.MaxAttempts, nextAttemptAt.Format(time.RFC3339), err)
+	if err := w.repo.MarkForRetry(ctx, message.ID, claimToken(message), err.Error(), nextAttemptAt); err != nil {
+		logReleaseError(message, "rescheduled", err)
+	}
+}
+
+func (w *EmailDispatcher) markFailed(ctx context.Context, message *models.OutboxMessage, cause error) {
+	if err := w.repo.MarkFailed(ctx, message.ID, claimToken(message), cause.Error()); err != nil {
+		logReleaseError(message, "moved to dead letter", err)
+	}
+}
+
+func claimToken(message *models.OutboxMessage) string {
+	if message.ClaimToken == nil {
+		return ""
+	}
+	return *message.ClaimToken
+}
+
+// logReleaseError registra a falha ao gravar o resultado de um envio. A perda do lease não é um
+// erro do banco: o envio demorou mais que claimLease e outro worker já assumiu a mensagem.
+func logReleaseError(message *models.OutboxMessage, action string, err error) {
+	if errors.Is(err, repositories.ErrLeaseLost) {
+		log.Printf("Lease of outbox message %s expired before it could be %s, leaving it to the current claim", message.PublicID, action)
+		return
+	}
+	log.Printf("Error: outbox message %s could not be %s: %v", message.PublicID, action, err)
+}
+
+// RetryDelay calcula o intervalo antes da próxima tentativa: 30s, 1min, 2min, ... limitado a 1h.
+func RetryDelay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	if attempt > 8 { // a partir daqui o atraso já passa do teto, e o deslocamento poderia transbordar
+		return maxRetryDelay
+	}
+
+	delay := baseRetryDelay << (attempt - 1)
+	if delay > maxRetryDelay {
+		return maxRetryDelay
+	}
+	return delay
+}
@@ -0,0 +1,222 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/workers"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var claim = "claim-token"
+
+func newMessage(t *testing.T, id int64, attempts int) models.OutboxMessage {
+	message, err := models.NewEmailMessage(&notification.EmailRequest{
+		To:           "john@example.com",
+		TemplateID:   notification.TemplateVerificationEmail,
+		TemplateData: map[string]string{"FullName": "John Doe"},
+	})
+	require.NoError(t, err)
+
+	message.ID = id
+	message.PublicID = fmt.Sprintf("message-%d", id)
+	message.Attempts = attempts
+	message.ClaimToken = &claim
+	return *message
+}
+
+func TestEmailDispatcher_RunOnce_MarksSentMessages(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepository)
+	mockEmailSvc := new(mocks.MockEmailService)
+	dispatcher := workers.NewEmailDispatcher(mockRepo, mockEmailSvc, time.Minute, 10)
+	mockRepo.On("PurgeFailedPayloads", mock.Anything).Return(int64(0), nil)
+	mockRepo.On("ExtendLease", mock.Anything, claim, mock.Anything).Return(nil).Maybe()
+
+	mockRepo.On("ClaimDue", mock.Anything, 10, mock.Anything).Return([]models.OutboxMessage{newMessage(t, 1, 1)}, nil).Once()
+	mockEmailSvc.On("SendEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		data, ok := req.TemplateData.(map[string]any)
+		return req.To == "john@example.com" && ok && data["FullName"] == "John Doe"
+	})).Return(nil)
+	mockRepo.On("MarkSent", int64(1), claim, mock.AnythingOfType("time.Time")).Return(nil)
+
+	total, err := dispatcher.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	mockRepo.AssertExpectations(t)
+	mockEmailSvc.AssertExpectations(t)
+}
+
+func TestEmailDispatcher_RunOnce_SchedulesRetryWithBackoff(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepository)
+	mockEmailSvc := new(mocks.MockEmailService)
+	dispatcher := workers.NewEmailDispatcher(mockRepo, mockEmailSvc, time.Minute, 10)
+	mockRepo.On("PurgeFailedPayloads", mock.Anything).Return(int64(0), nil)
+	mockRepo.On("ExtendLease", mock.Anything, claim, mock.Anything).Return(nil).Maybe()
+
+	mockRepo.On("ClaimDue", mock.Anything, 10, mock.Anything).Return([]models.OutboxMessage{newMessage(t, 1, 3)}, nil).Once()
+	mockEmailSvc.On("SendEmail", mock.Anything).Return(errors.New("provider unavailable"))
+	mockRepo.On("MarkForRetry", int64(1), claim, "provider unavailable", mock.MatchedBy(func(next time.Time) bool {
+		delay := time.Until(next)
+		return delay > 110*time.Second && delay <= 2*time.Minute
+	})).Return(nil)
+
+	_, err := dispatcher.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "MarkFailed", mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEmailDispatcher_RunOnce_DeadLettersExhaustedMessages(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepository)
+	mockEmailSvc := new(mocks.MockEmailService)
+	dispatcher := workers.NewEmailDispatcher(mockRepo, mockEmailSvc, time.Minute, 10)
+	mockRepo.On("PurgeFailedPayloads", mock.Anything).Return(int64(0), nil)
+	mockRepo.On("ExtendLease", mock.Anything, claim, mock.Anything).Return(nil).Maybe()
+
+	mockRepo.On("ClaimDue", mock.Anything, 10, mock.Anything).
+		Return([]models.OutboxMessage{newMessage(t, 1, models.DefaultMaxAttempts)}, nil).Once()
+	mockEmailSvc.On("SendEmail", mock.Anything).Return(errors.New("mailbox unavailable"))
+	mockRepo.On("MarkFailed", int64(1), claim, "mailbox unavailable").Return(nil)
+
+	_, err := dispatcher.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockRepo.AssertNotCalled(t, "MarkForRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEmailDispatcher_RunOnce_IgnoresLostLease(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepository)
+	mockEmailSvc := new(mocks.MockEmailService)
+	dispatcher := workers.NewEmailDispatcher(mockRepo, mockEmailSvc, time.Minute, 10)
+	mockRepo.On("PurgeFailedPayloads", mock.Anything).Return(int64(0), nil)
+	mockRepo.On("ExtendLease", mock.Anything, claim, mock.Anything).Return(nil).Maybe()
+
+	mockRepo.On("ClaimDue", mock.Anything, 10, mock.Anything).Return([]models.OutboxMessage{newMessage(t, 1, 1)}, nil).Once()
+	mockEmailSvc.On("SendEmail", mock.Anything).Return(nil)
+	mockRepo.On("MarkSent", int64(1), claim, mock.Anything).Return(repositories.ErrLeaseLost)
+
+	total, err := dispatcher.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, total)
+	mockRepo.AssertExpectations(t)
+}
+
+// O lote demora mais que o lease: quando chega a vez da segunda mensagem, outro worker já a
+// reservou, e ela não pode ser enviada de novo.
+func TestEmailDispatcher_RunOnce_SkipsMessagesClaimedWhileTheBatchRuns(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepository)
+	mockEmailSvc := new(mocks.MockEmailService)
+	dispatcher := workers.NewEmailDispatcher(mockRepo, mockEmailSvc, time.Minute, 10)
+	mockRepo.On("PurgeFailedPayloads", mock.Anything).Return(int64(0), nil)
+
+	first, second := newMessage(t, 1, 1), newMessage(t, 2, 1)
+	mockRepo.On("ClaimDue", mock.Anything, 10, mock.Anything).Return([]models.OutboxMessage{first, second}, nil).Once()
+
+	var firstSent bool
+	mockRepo.On("ExtendLease", int64(1), claim, mock.Anything).Return(nil)
+	mockEmailSvc.On("SendEmail", mock.Anything).Run(func(mock.Arguments) { firstSent = true }).Return(nil).Once()
+	mockRepo.On("MarkSent", int64(1), claim, mock.AnythingOfType("time.Time")).Return(nil)
+	mockRepo.On("ExtendLease", int64(2), claim, mock.MatchedBy(func(lockedUntil time.Time) bool {
+		return firstSent && time.Until(lockedUntil) > 50*time.Second
+	})).Return(repositories.ErrLeaseLost)
+
+	total, err := dispatcher.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	mockRepo.AssertExpectations(t)
+	mockEmailSvc.AssertNumberOfCalls(t, "SendEmail", 1)
+	mockRepo.AssertNotCalled(t, "MarkSent", int64(2), mock.Anything, mock.Anything)
+	mockRepo.AssertNotCalled(t, "MarkForRetry", mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func TestEmailDispatcher_RunOnce_ClaimsBatchesUntilEmpty(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepository)
+	mockEmailSvc := new(mocks.MockEmailService)
+	dispatcher := workers.NewEmailDispatcher(mockRepo, mockEmailSvc, time.Minute, 2)
+	mockRepo.On("PurgeFailedPayloads", mock.Anything).Return(int64(0), nil)
+	mockRepo.On("ExtendLease", mock.Anything, claim, mock.Anything).Return(nil).Maybe()
+
+	mockRepo.On("ClaimDue", mock.Anything, 2, mock.Anything).
+		Return([]models.OutboxMessage{newMessage(t, 1, 1), newMessage(t, 2, 1)}, nil).Once()
+	mockRepo.On("ClaimDue", mock.Anything, 2, mock.Anything).Return([]models.OutboxMessage{}, nil).Once()
+	mockEmailSvc.On("SendEmail", mock.Anything).Return(nil)
+	mockRepo.On("MarkSent", mock.Anything, claim, mock.Anything).Return(nil)
+
+	total, err := dispatcher.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 2, total)
+	mockRepo.AssertNumberOfCalls(t, "ClaimDue", 2)
+	mockRepo.AssertNumberOfCalls(t, "MarkSent", 2)
+}
+
+func TestEmailDispatcher_RunOnce_StopsOnClaimError(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepository)
+	dispatcher := workers.NewEmailDispatcher(mockRepo, nil, time.Minute, 2)
+	mockRepo.On("PurgeFailedPayloads", mock.Anything).Return(int64(0), nil)
+	mockRepo.On("ExtendLease", mock.Anything, claim, mock.Anything).Return(nil).Maybe()
+
+	mockRepo.On("ClaimDue", mock.Anything, 2, mock.Anything).Return(nil, errors.New("db error"))
+
+	_, err := dispatcher.RunOnce(context.Background())
+
+	assert.Error(t, err)
+	mockRepo.AssertNumberOfCalls(t, "ClaimDue", 1)
+}
+
+func TestEmailDispatcher_RunOnce_PurgesExpiredDeadLetters(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepository)
+	dispatcher := workers.NewEmailDispatcher(mockRepo, nil, time.Minute, 2)
+
+	mockRepo.On("PurgeFailedPayloads", mock.MatchedBy(func(failedBefore time.Time) bool {
+		age := time.Since(failedBefore)
+		return age >= models.DeadLetterRetention && age < models.DeadLetterRetention+time.Minute
+	})).Return(int64(3), nil)
+	mockRepo.On("ClaimDue", mock.Anything, 2, mock.Anything).Return([]models.OutboxMessage{}, nil).Once()
+
+	_, err := dispatcher.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestEmailDispatcher_RunOnce_DeadLettersPurgedMessages(t *testing.T) {
+	mockRepo := new(mocks.MockOutboxRepository)
+	mockEmailSvc := new(mocks.MockEmailService)
+	dispatcher := workers.NewEmailDispatcher(mockRepo, mockEmailSvc, time.Minute, 10)
+	mockRepo.On("PurgeFailedPayloads", mock.Anything).Return(int64(0), nil)
+	mockRepo.On("ExtendLease", mock.Anything, claim, mock.Anything).Return(nil).Maybe()
+
+	message := newMessage(t, 1, 1)
+	message.Payload = ""
+	mockRepo.On("ClaimDue", mock.Anything, 10, mock.Anything).Return([]models.OutboxMessage{message}, nil).Once()
+	mockRepo.On("MarkFailed", int64(1), claim, models.ErrPayloadPurged.Error()).Return(nil)
+
+	_, err := dispatcher.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+	mockEmailSvc.AssertNotCalled(t, "SendEmail", mock.Anything)
+}
+
+func TestRetryDelay(t *testing.T) {
+	assert.Equal(t, 30*time.Second, workers.RetryDelay(1))
+	assert.Equal(t, 1*time.Minute, workers.RetryDelay(2))
+	assert.Equal(t, 4*time.Minute, workers.RetryDelay(4))
+	assert.Equal(t, 1*time.Hour, workers.RetryDelay(8))
+	assert.Equal(t, 1*time.Hour, workers.RetryDelay(100))
+}
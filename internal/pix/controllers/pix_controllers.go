@@ -0,0 +1,239 @@
+// Package controllers define the HTTP handlers for Pix keys and claims.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/http_helpers"
+)
+
+// RegisterKeyRequest é o corpo de POST /pix/keys. Key não é informada nas chaves aleatórias (EVP).
+type RegisterKeyRequest struct {
+	AccountID string `json:"accountId"`
+	KeyType   string `json:"keyType" binding:"required,oneof=CPF CNPJ EMAIL PHONE EVP"`
+	Key       string `json:"key" binding:"required_unless=KeyType EVP"`
+}
+
+// ConfirmKeyRequest é o corpo de POST /pix/keys/:id/confirm.
+type ConfirmKeyRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
+}
+
+type PixController struct {
+	keyService   services.PixKeyService
+	claimService services.PixClaimService
+}
+
+func NewPixController(keyService services.PixKeyService, claimService services.PixClaimService) *PixController {
+	return &PixController{keyService: keyService, claimService: claimService}
+}
+
+func (ctrl *PixController) RegisterKey(c *gin.Context) {
+	var req RegisterKeyRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	pixKey, err := ctrl.keyService.Register(c.Request.Context(), userPublicID, services.RegisterKeyInput{
+		AccountPublicID: req.AccountID,
+		KeyType:         models.KeyType(req.KeyType),
+		Key:             req.Key,
+	})
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	// 202 quando a chave ainda depende do código de confirmação ou da resposta do doador.
+	status := http.StatusCreated
+	if pixKey.Status != models.KeyActive {
+		status = http.StatusAccepted
+	}
+	c.JSON(status, keyResponse(pixKey))
+}
+
+func (ctrl *PixController) ConfirmKey(c *gin.Context) {
+	var req ConfirmKeyRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	pixKey, err := ctrl.keyService.Confirm(c.Request.Context(), userPublicID, c.Param("id"), req.Code)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, keyResponse(pixKey))
+}
+
+func (ctrl *PixController) DeleteKey(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	if err := ctrl.keyService.Delete(c.Request.Context(), userPublicID, c.Param("id")); err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+func (ctrl *PixController) ListKeys(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	keys, err := ctrl.keyService.List(c.Request.Context(), userPublicID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	items := make([]gin.H, len(keys))
+	for i := range keys {
+		items[i] = keyResponse(&keys[i])
+	}
+	c.JSON(http.StatusOK, gin.H{"keys": items})
+}
+
+// LookupKey consulta o titular de uma chave antes de um Pix: GET /pix/lookup?key=...
+func (ctrl *PixController) LookupKey(c *gin.Context) {
+	rawKey := c.Query("key")
+	if rawKey == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Informe a chave no parâmetro key."})
+		return
+	}
+
+	owner, err := ctrl.keyService.Lookup(c.Request.Context(), rawKey)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	response := gin.H{
+		"keyType":       owner.KeyType,
+		"key":           owner.Key,
+		"ownerName":     owner.OwnerName,
+		"ownerDocument": owner.OwnerDocument,
+		"ownerType":     owner.OwnerType,
+		"ispb":          owner.ParticipantISPB,
+		"agency":        owner.Branch,
+		"accountNumber": owner.AccountNumber,
+	}
+	if owner.AccountPublicID != "" {
+		response["accountId"] = owner.AccountPublicID
+	}
+	c.JSON(http.StatusOK, response)
+}
+
+func (ctrl *PixController) ListClaims(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	claims, err := ctrl.claimService.List(c.Request.Context(), userPublicID)
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	items := make([]gin.H, len(claims))
+	for i := range claims {
+		items[i] = claimResponse(&claims[i])
+	}
+	c.JSON(http.StatusOK, gin.H{"claims": items})
+}
+
+func (ctrl *PixController) ConfirmClaim(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	claim, err := ctrl.claimService.Confirm(c.Request.Context(), userPublicID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, claimResponse(claim))
+}
+
+func (ctrl *PixController) CancelClaim(c *gin.Context) {
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	claim, err := ctrl.claimService.Cancel(c.Request.Context(), userPublicID, c.Param("id"))
+	if err != nil {
+		respondError(c, err)
+		return
+	}
+
+	c.JSON(http.StatusOK, claimResponse(claim))
+}
+
+func respondError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, models.ErrInvalidKey):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrUserNotFound),
+		errors.Is(err, services.ErrAccountNotFound),
+		errors.Is(err, services.ErrKeyNotFound),
+		errors.Is(err, services.ErrClaimNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrNotDonor):
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrKeyAlreadyRegistered),
+		errors.Is(err, services.ErrConfirmationPending),
+		errors.Is(err, services.ErrClaimInProgress),
+		errors.Is(err, services.ErrKeyNotPending),
+		errors.Is(err, services.ErrClaimNotOpen):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrKeyNotOwned),
+		errors.Is(err, services.ErrKeyLimitReached),
+		errors.Is(err, services.ErrInvalidCode),
+		errors.Is(err, services.ErrExpiredCode),
+		errors.Is(err, services.ErrCodeAttemptsExceeded):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+	}
+}
+
+func keyResponse(pixKey *models.PixKey) gin.H {
+	response := gin.H{
+		"id":        pixKey.PublicID,
+		"accountId": pixKey.Account.PublicID,
+		"keyType":   pixKey.KeyType,
+		"key":       pixKey.Key,
+		"status":    pixKey.Status,
+		"createdAt": pixKey.CreatedAt.Format(time.RFC3339),
+	}
+	if pixKey.ActivatedAt != nil {
+		response["activatedAt"] = pixKey.ActivatedAt.Format(time.RFC3339)
+	}
+	return response
+}
+
+func claimResponse(claim *models.PixClaim) gin.H {
+	response := gin.H{
+		"id":                 claim.PublicID,
+		"keyId":              claim.PixKey.PublicID,
+		"key":                claim.PixKey.Key,
+		"type":               claim.Type,
+		"role":               claim.Role,
+		"status":             claim.Status,
+		"resolutionDeadline": claim.ResolutionDeadline.Format(time.RFC3339),
+		"createdAt":          claim.CreatedAt.Format(time.RFC3339),
+	}
+	if claim.ResolvedAt != nil {
+		response["resolvedAt"] = claim.ResolvedAt.Format(time.RFC3339)
+	}
+	return response
+}
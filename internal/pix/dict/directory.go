@@ -0,0 +1,101 @@
+// Package dict defines the contract with the Pix key directory (DICT) run by the Central Bank and
+// an in-memory stand-in used until the bank is connected to the real one.
+package dict
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+var (
+	ErrEntryNotFound        = errors.New("dict: key not found")
+	ErrKeyAlreadyRegistered = errors.New("dict: key already registered")
+	ErrClaimNotFound        = errors.New("dict: claim not found")
+	ErrClaimAlreadyOpen     = errors.New("dict: key already has an open claim")
+	ErrClaimNotOpen         = errors.New("dict: claim is not open")
+	ErrInvalidClaim         = errors.New("dict: claim type does not apply to this key")
+	ErrNotParticipant       = errors.New("dict: participant does not hold the entry or claim")
+)
+
+// OwnerType distingue titulares pessoa física de pessoa jurídica.
+type OwnerType string
+
+const (
+	NaturalPerson OwnerType = "NATURAL_PERSON"
+	LegalPerson   OwnerType = "LEGAL_PERSON"
+)
+
+// ClaimType distingue os dois tipos de reivindicação de uma chave já registrada.
+type ClaimType string
+
+const (
+	// ClaimPortability move a chave entre contas do mesmo titular em participantes diferentes.
+	ClaimPortability ClaimType = "PORTABILITY"
+	// ClaimOwnership transfere uma chave de e-mail ou celular para quem comprovou tê-lo.
+	ClaimOwnership ClaimType = "OWNERSHIP"
+)
+
+// ClaimStatus define os possíveis status de uma reivindicação.
+type ClaimStatus string
+
+const (
+	ClaimOpen      ClaimStatus = "OPEN"
+	ClaimCompleted ClaimStatus = "COMPLETED"
+	ClaimCancelled ClaimStatus = "CANCELLED"
+)
+
+// Entry é o vínculo de uma chave a uma conta. KeyType segue os tipos do DICT: CPF, CNPJ, EMAIL,
+// PHONE e EVP.
+type Entry struct {
+	Key             string
+	KeyType         string
+	ParticipantISPB string
+	Branch          string
+	AccountNumber   string
+	OwnerType       OwnerType
+	OwnerDocument   string
+	OwnerName       string
+	CreatedAt       time.Time
+}
+
+// Claim é um pedido para mover uma chave para a conta de Claimer. O participante doador
+// (DonorISPB) tem até ResolutionDeadline para confirmar ou cancelar.
+type Claim struct {
+	ID                 string
+	Type               ClaimType
+	Status             ClaimStatus
+	Key                string
+	Claimer            Entry
+	DonorISPB          string
+	ResolutionDeadline time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// Directory é o diretório central de chaves Pix. Os participantes são identificados pelo ISPB.
+type Directory interface {
+	CreateEntry(ctx context.Context, entry Entry) error
+	GetEntry(ctx context.Context, key string) (*Entry, error)
+	DeleteEntry(ctx context.Context, key, participantISPB string) error
+	CreateClaim(ctx context.Context, claimType ClaimType, claimer Entry) (*Claim, error)
+	ConfirmClaim(ctx context.Context, claimID, participantISPB string) (*Claim, error)
+	CancelClaim(ctx context.Context, claimID, participantISPB string) (*Claim, error)
+	ListClaims(ctx context.Context, participantISPB string, updatedSince time.Time) ([]Claim, error)
+}
+
+// ParticipantISPBFromEnv lê PIX_ISPB, os 8 dígitos que identificam o GoBank no Pix.
+func ParticipantISPBFromEnv() (string, error) {
+	ispb := os.Getenv("PIX_ISPB")
+	if len(ispb) != 8 {
+		return "", fmt.Errorf("invalid PIX_ISPB %q: expected 8 digits", ispb)
+	}
+	for _, r := range ispb {
+		if r < '0' || r > '9' {
+			return "", fmt.Errorf("invalid PIX_ISPB %q: expected 8 digits", ispb)
+		}
+	}
+	return ispb, nil
+}
@@ -0,0 +1,259 @@
+package dict
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/oklog/ulid/v2"
+)
+
+// DefaultResolutionPeriod é o prazo que o participante doador tem para responder a uma
+// reivindicação.
+const DefaultResolutionPeriod = 7 * 24 * time.Hour
+
+// MemoryDirectory guarda as chaves e reivindicações em memória. Ao fim do prazo de resolução,
+// portabilidades sem resposta são canceladas e reivindicações de posse são concluídas a favor de
+// quem reivindicou, como no DICT. É seguro para uso concorrente; o conteúdo se perde ao reiniciar.
+type MemoryDirectory struct {
+	mu               sync.Mutex
+	entries          map[string]Entry
+	claims           map[string]*Claim
+	resolutionPeriod time.Duration
+	now              func() time.Time
+}
+
+func NewMemoryDirectory(resolutionPeriod time.Duration) *MemoryDirectory {
+	return &MemoryDirectory{
+		entries:          make(map[string]Entry),
+		claims:           make(map[string]*Claim),
+		resolutionPeriod: resolutionPeriod,
+		now:              time.Now,
+	}
+}
+
+// SetClock troca o relógio usado nos prazos; útil nos testes.
+func (d *MemoryDirectory) SetClock(now func() time.Time) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.now = now
+}
+
+func (d *MemoryDirectory) CreateEntry(ctx context.Context, entry Entry) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resolveExpiredLocked()
+
+	if _, exists := d.entries[entry.Key]; exists {
+		return ErrKeyAlreadyRegistered
+	}
+	entry.CreatedAt = d.now()
+	d.entries[entry.Key] = entry
+	return nil
+}
+
+func (d *MemoryDirectory) GetEntry(ctx context.Context, key string) (*Entry, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resolveExpiredLocked()
+
+	entry, exists := d.entries[key]
+	if !exists {
+		return nil, ErrEntryNotFound
+	}
+	return &entry, nil
+}
+
+// DeleteEntry remove a chave. Chaves com reivindicação em aberto não podem ser removidas.
+func (d *MemoryDirectory) DeleteEntry(ctx context.Context, key, participantISPB string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resolveExpiredLocked()
+
+	entry, exists := d.entries[key]
+	if !exists {
+		return ErrEntryNotFound
+	}
+	if entry.ParticipantISPB != participantISPB {
+		return ErrNotParticipant
+	}
+	if d.openClaimLocked(key) != nil {
+		return ErrClaimAlreadyOpen
+	}
+
+	delete(d.entries, key)
+	return nil
+}
+
+// CreateClaim abre uma reivindicação da chave para a conta de claimer. Portabilidade exige o
+// mesmo titular em outro participante; reivindicação de posse, um titular diferente e uma chave
+// de e-mail ou celular.
+func (d *MemoryDirectory) CreateClaim(ctx context.Context, claimType ClaimType, claimer Entry) (*Claim, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resolveExpiredLocked()
+
+	entry, exists := d.entries[claimer.Key]
+	if !exists {
+		return nil, ErrEntryNotFound
+	}
+	if d.openClaimLocked(claimer.Key) != nil {
+		return nil, ErrClaimAlreadyOpen
+	}
+
+	sameOwner := entry.OwnerDocument == claimer.OwnerDocument
+	switch claimType {
+	case ClaimPortability:
+		if !sameOwner || entry.ParticipantISPB == claimer.ParticipantISPB {
+			return nil, ErrInvalidClaim
+		}
+	case ClaimOwnership:
+		if sameOwner || (entry.KeyType != "EMAIL" && entry.KeyType != "PHONE") {
+			return nil, ErrInvalidClaim
+		}
+	default:
+		return nil, ErrInvalidClaim
+	}
+
+	now := d.now()
+	claim := &Claim{
+		ID:                 ulid.Make().String(),
+		Type:               claimType,
+		Status:             ClaimOpen,
+		Key:                claimer.Key,
+		Claimer:            claimer,
+		DonorISPB:          entry.ParticipantISPB,
+		ResolutionDeadline: now.Add(d.resolutionPeriod),
+		CreatedAt:          now,
+		UpdatedAt:          now,
+	}
+	d.claims[claim.ID] = claim
+
+	copied := *claim
+	return &copied, nil
+}
+
+// ConfirmClaim é chamado pelo doador para liberar a chave: ela passa imediatamente para a conta
+// de quem reivindicou.
+func (d *MemoryDirectory) ConfirmClaim(ctx context.Context, claimID, participantISPB string) (*Claim, error) {
+	return d.resolve(ctx, claimID, func(claim *Claim) error {
+		if claim.DonorISPB != participantISPB {
+			return ErrNotParticipant
+		}
+		d.completeLocked(claim)
+		return nil
+	})
+}
+
+// CancelClaim encerra a reivindicação sem mover a chave. Pode ser chamado por qualquer uma das
+// partes.
+func (d *MemoryDirectory) CancelClaim(ctx context.Context, claimID, participantISPB string) (*Claim, error) {
+	return d.resolve(ctx, claimID, func(claim *Claim) error {
+		if claim.DonorISPB != participantISPB && claim.Claimer.ParticipantISPB != participantISPB {
+			return ErrNotParticipant
+		}
+		claim.Status = ClaimCancelled
+		claim.UpdatedAt = d.now()
+		return nil
+	})
+}
+
+// ListClaims retorna, da mais antiga para a mais recente alteração, as reivindicações em que o
+// participante é doador ou reivindicador e que mudaram desde updatedSince.
+func (d *MemoryDirectory) ListClaims(ctx context.Context, participantISPB string, updatedSince time.Time) ([]Claim, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resolveExpiredLocked()
+
+	var claims []Claim
+	for _, claim := range d.claims {
+		involved := claim.DonorISPB == participantISPB || claim.Claimer.ParticipantISPB == participantISPB
+		if involved && !claim.UpdatedAt.Before(updatedSince) {
+			claims = append(claims, *claim)
+		}
+	}
+	slices.SortFunc(claims, func(a, b Claim) int {
+		return a.UpdatedAt.Compare(b.UpdatedAt)
+	})
+	return claims, nil
+}
+
+func (d *MemoryDirectory) resolve(ctx context.Context, claimID string, apply func(*Claim) error) (*Claim, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.resolveExpiredLocked()
+
+	claim, exists := d.claims[claimID]
+	if !exists {
+		return nil, ErrClaimNotFound
+	}
+	if claim.Status != ClaimOpen {
+		return nil, ErrClaimNotOpen
+	}
+	if err := apply(claim); err != nil {
+		return nil, err
+	}
+
+	copied := *claim
+	return &copied, nil
+}
+
+func (d *MemoryDirectory) openClaimLocked(key string) *Claim {
+	for _, claim := range d.claims {
+		if claim.Key == key && claim.Status == ClaimOpen {
+			return claim
+		}
+	}
+	return nil
+}
+
+func (d *MemoryDirectory) completeLocked(claim *Claim) {
+	now := d.now()
+	entry := claim.Claimer
+	entry.CreatedAt = now
+	d.entries[claim.Key] = entry
+
+	claim.Status = ClaimCompleted
+	claim.UpdatedAt = now
+}
+
+// resolveExpiredLocked aplica o resultado padrão às reivindicações cujo prazo terminou.
+func (d *MemoryDirectory) resolveExpiredLocked() {
+	now := d.now()
+	for _, claim := range d.claims {
+		if claim.Status != ClaimOpen || now.Before(claim.ResolutionDeadline) {
+			continue
+		}
+		if claim.Type == ClaimOwnership {
+			d.completeLocked(claim)
+			continue
+		}
+		claim.Status = ClaimCancelled
+		claim.UpdatedAt = now
+	}
+}
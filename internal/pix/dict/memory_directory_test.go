@@ -0,0 +1,191 @@
+package dict_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/dict"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	goBankISPB = "12345678"
+	otherISPB  = "87654321"
+)
+
+func entry(key, keyType, ispb, document string) dict.Entry {
+	return dict.Entry{
+		Key:             key,
+		KeyType:         keyType,
+		ParticipantISPB: ispb,
+		Branch:          "0001",
+		AccountNumber:   "122504005",
+		OwnerType:       dict.NaturalPerson,
+		OwnerDocument:   document,
+		OwnerName:       "John Doe",
+	}
+}
+
+// newDirectory devolve um diretório com relógio controlado pelo teste.
+func newDirectory() (*dict.MemoryDirectory, *time.Time) {
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+	directory := dict.NewMemoryDirectory(dict.DefaultResolutionPeriod)
+	directory.SetClock(func() time.Time { return now })
+	return directory, &now
+}
+
+func TestMemoryDirectory_Entries(t *testing.T) {
+	ctx := context.Background()
+	directory, _ := newDirectory()
+
+	require.NoError(t, directory.CreateEntry(ctx, entry("john@example.com", "EMAIL", otherISPB, "52998224725")))
+	assert.ErrorIs(t, directory.CreateEntry(ctx, entry("john@example.com", "EMAIL", goBankISPB, "52998224725")), dict.ErrKeyAlreadyRegistered)
+
+	found, err := directory.GetEntry(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, otherISPB, found.ParticipantISPB)
+
+	assert.ErrorIs(t, directory.DeleteEntry(ctx, "john@example.com", goBankISPB), dict.ErrNotParticipant)
+	require.NoError(t, directory.DeleteEntry(ctx, "john@example.com", otherISPB))
+
+	_, err = directory.GetEntry(ctx, "john@example.com")
+	assert.ErrorIs(t, err, dict.ErrEntryNotFound)
+}
+
+func TestMemoryDirectory_CreateClaim_Rules(t *testing.T) {
+	ctx := context.Background()
+	directory, _ := newDirectory()
+	require.NoError(t, directory.CreateEntry(ctx, entry("52998224725", "CPF", otherISPB, "52998224725")))
+	require.NoError(t, directory.CreateEntry(ctx, entry("john@example.com", "EMAIL", otherISPB, "52998224725")))
+
+	_, err := directory.CreateClaim(ctx, dict.ClaimPortability, entry("52998224725", "CPF", otherISPB, "52998224725"))
+	assert.ErrorIs(t, err, dict.ErrInvalidClaim, "portability needs another participant")
+
+	_, err = directory.CreateClaim(ctx, dict.ClaimOwnership, entry("52998224725", "CPF", goBankISPB, "11144477735"))
+	assert.ErrorIs(t, err, dict.ErrInvalidClaim, "ownership only applies to e-mail and phone keys")
+
+	_, err = directory.CreateClaim(ctx, dict.ClaimOwnership, entry("john@example.com", "EMAIL", goBankISPB, "52998224725"))
+	assert.ErrorIs(t, err, dict.ErrInvalidClaim, "ownership needs another owner")
+
+	_, err = directory.CreateClaim(ctx, dict.ClaimPortability, entry("unknown@example.com", "EMAIL", goBankISPB, "52998224725"))
+	assert.ErrorIs(t, err, dict.ErrEntryNotFound)
+
+	claim, err := directory.CreateClaim(ctx, dict.ClaimPortability, entry("john@example.com", "EMAIL", goBankISPB, "52998224725"))
+	require.NoError(t, err)
+	assert.Equal(t, dict.ClaimOpen, claim.Status)
+	assert.Equal(t, otherISPB, claim.DonorISPB)
+
+	_, err = directory.CreateClaim(ctx, dict.ClaimPortability, entry("john@example.com", "EMAIL", goBankISPB, "52998224725"))
+	assert.ErrorIs(t, err, dict.ErrClaimAlreadyOpen)
+	assert.ErrorIs(t, directory.DeleteEntry(ctx, "john@example.com", otherISPB), dict.ErrClaimAlreadyOpen)
+}
+
+func TestMemoryDirectory_ConfirmClaim_MovesTheKey(t *testing.T) {
+	ctx := context.Background()
+	directory, _ := newDirectory()
+	require.NoError(t, directory.CreateEntry(ctx, entry("john@example.com", "EMAIL", otherISPB, "52998224725")))
+	claim, err := directory.CreateClaim(ctx, dict.ClaimOwnership, entry("john@example.com", "EMAIL", goBankISPB, "11144477735"))
+	require.NoError(t, err)
+
+	_, err = directory.ConfirmClaim(ctx, claim.ID, goBankISPB)
+	assert.ErrorIs(t, err, dict.ErrNotParticipant, "only the donor confirms")
+
+	confirmed, err := directory.ConfirmClaim(ctx, claim.ID, otherISPB)
+	require.NoError(t, err)
+	assert.Equal(t, dict.ClaimCompleted, confirmed.Status)
+
+	found, err := directory.GetEntry(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, goBankISPB, found.ParticipantISPB)
+	assert.Equal(t, "11144477735", found.OwnerDocument)
+
+	_, err = directory.CancelClaim(ctx, claim.ID, goBankISPB)
+	assert.ErrorIs(t, err, dict.ErrClaimNotOpen)
+}
+
+func TestMemoryDirectory_CancelClaim_KeepsTheKey(t *testing.T) {
+	ctx := context.Background()
+	directory, _ := newDirectory()
+	require.NoError(t, directory.CreateEntry(ctx, entry("john@example.com", "EMAIL", otherISPB, "52998224725")))
+	claim, err := directory.CreateClaim(ctx, dict.ClaimPortability, entry("john@example.com", "EMAIL", goBankISPB, "52998224725"))
+	require.NoError(t, err)
+
+	_, err = directory.CancelClaim(ctx, claim.ID, "11111111")
+	assert.ErrorIs(t, err, dict.ErrNotParticipant)
+
+	cancelled, err := directory.CancelClaim(ctx, claim.ID, goBankISPB)
+	require.NoError(t, err)
+	assert.Equal(t, dict.ClaimCancelled, cancelled.Status)
+
+	found, err := directory.GetEntry(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, otherISPB, found.ParticipantISPB)
+}
+
+func TestMemoryDirectory_ExpiredClaims(t *testing.T) {
+	ctx := context.Background()
+	directory, now := newDirectory()
+	require.NoError(t, directory.CreateEntry(ctx, entry("john@example.com", "EMAIL", otherISPB, "52998224725")))
+	require.NoError(t, directory.CreateEntry(ctx, entry("+5511912345678", "PHONE", otherISPB, "52998224725")))
+
+	ownership, err := directory.CreateClaim(ctx, dict.ClaimOwnership, entry("john@example.com", "EMAIL", goBankISPB, "11144477735"))
+	require.NoError(t, err)
+	portability, err := directory.CreateClaim(ctx, dict.ClaimPortability, entry("+5511912345678", "PHONE", goBankISPB, "52998224725"))
+	require.NoError(t, err)
+
+	*now = now.Add(dict.DefaultResolutionPeriod)
+
+	claims, err := directory.ListClaims(ctx, goBankISPB, now.Add(-time.Second))
+	require.NoError(t, err)
+	require.Len(t, claims, 2)
+
+	statuses := map[string]dict.ClaimStatus{}
+	for _, claim := range claims {
+		statuses[claim.ID] = claim.Status
+	}
+	assert.Equal(t, dict.ClaimCompleted, statuses[ownership.ID], "unanswered ownership claims favor the claimer")
+	assert.Equal(t, dict.ClaimCancelled, statuses[portability.ID], "unanswered portability requests are cancelled")
+
+	email, err := directory.GetEntry(ctx, "john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, goBankISPB, email.ParticipantISPB)
+
+	phone, err := directory.GetEntry(ctx, "+5511912345678")
+	require.NoError(t, err)
+	assert.Equal(t, otherISPB, phone.ParticipantISPB)
+}
+
+func TestMemoryDirectory_ListClaims_FiltersByParticipantAndTime(t *testing.T) {
+	ctx := context.Background()
+	directory, now := newDirectory()
+	require.NoError(t, directory.CreateEntry(ctx, entry("john@example.com", "EMAIL", otherISPB, "52998224725")))
+	_, err := directory.CreateClaim(ctx, dict.ClaimPortability, entry("john@example.com", "EMAIL", goBankISPB, "52998224725"))
+	require.NoError(t, err)
+
+	claims, err := directory.ListClaims(ctx, otherISPB, time.Time{})
+	require.NoError(t, err)
+	assert.Len(t, claims, 1, "the donor sees the claim")
+
+	claims, err = directory.ListClaims(ctx, "11111111", time.Time{})
+	require.NoError(t, err)
+	assert.Empty(t, claims)
+
+	claims, err = directory.ListClaims(ctx, goBankISPB, now.Add(time.Second))
+	require.NoError(t, err)
+	assert.Empty(t, claims)
+}
+
+func TestParticipantISPBFromEnv(t *testing.T) {
+	t.Setenv("PIX_ISPB", goBankISPB)
+	ispb, err := dict.ParticipantISPBFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, goBankISPB, ispb)
+
+	for _, invalid := range []string{"", "1234567", "1234567a"} {
+		t.Setenv("PIX_ISPB", invalid)
+		_, err := dict.ParticipantISPBFromEnv()
+		assert.Error(t, err, invalid)
+	}
+}
@@ -0,0 +1,50 @@
+package models
+
+import (
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/dict"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// ClaimRole indica de que lado da reivindicação está a chave local.
+type ClaimRole string
+
+const (
+	// RoleClaimer indica que o cliente do GoBank pediu a chave.
+	RoleClaimer ClaimRole = "CLAIMER"
+	// RoleDonor indica que a chave do cliente do GoBank foi pedida por outra conta.
+	RoleDonor ClaimRole = "DONOR"
+)
+
+// PixClaim é a cópia local de uma reivindicação do DICT que envolve uma chave do GoBank. Quando
+// as duas contas são do GoBank, a mesma reivindicação tem uma linha para cada papel.
+type PixClaim struct {
+	ID                 int64            `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID           string           `gorm:"type:varchar(26);unique;not null"`
+	PixKeyID           int64            `gorm:"not null;column:pix_key_id"`
+	PixKey             PixKey           `gorm:"foreignKey:PixKeyID"`
+	DICTClaimID        string           `gorm:"type:varchar(64);not null;column:dict_claim_id"`
+	Type               dict.ClaimType   `gorm:"type:varchar(20);not null;column:claim_type"`
+	Role               ClaimRole        `gorm:"type:varchar(10);not null"`
+	Status             dict.ClaimStatus `gorm:"type:varchar(20);not null"`
+	ResolutionDeadline time.Time        `gorm:"not null"`
+	ResolvedAt         *time.Time       `gorm:"column:resolved_at"`
+	CreatedAt          time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt          time.Time        `gorm:"autoUpdateTime"`
+}
+
+func (PixClaim) TableName() string {
+	return "pix.claims"
+}
+
+func (c *PixClaim) BeforeCreate(_ *gorm.DB) (err error) {
+	c.PublicID = ulid.Make().String()
+	return
+}
+
+// IsOpen indica se a reivindicação ainda aguarda resposta.
+func (c *PixClaim) IsOpen() bool {
+	return c.Status == dict.ClaimOpen
+}
@@ -0,0 +1,216 @@
+// Package models define the Pix keys registered for GoBank accounts and their claims.
+package models
+
+import (
+	"errors"
+	"net/mail"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/otp"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/validators"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+var ErrInvalidKey = errors.New("chave Pix inválida")
+
+// KeyType é o tipo da chave, com os mesmos nomes usados pelo DICT.
+type KeyType string
+
+const (
+	KeyCPF   KeyType = "CPF"
+	KeyCNPJ  KeyType = "CNPJ"
+	KeyEmail KeyType = "EMAIL"
+	KeyPhone KeyType = "PHONE"
+	// KeyEVP é a chave aleatória: um UUID gerado pelo banco.
+	KeyEVP KeyType = "EVP"
+)
+
+// NeedsOwnershipConfirmation indica se o cliente precisa provar, com um código, que é dono do
+// e-mail ou celular antes de a chave ser registrada.
+func (t KeyType) NeedsOwnershipConfirmation() bool {
+	return t == KeyEmail || t == KeyPhone
+}
+
+// KeyStatus define os possíveis status de uma chave.
+type KeyStatus string
+
+const (
+	// KeyPendingConfirmation indica uma chave de e-mail ou celular aguardando o código enviado.
+	KeyPendingConfirmation KeyStatus = "PENDING_CONFIRMATION"
+	// KeyActive indica uma chave registrada no DICT, que já recebe Pix.
+	KeyActive KeyStatus = "ACTIVE"
+	// KeyClaimPending indica uma chave registrada em outra conta que o cliente pediu para trazer
+	// (portabilidade) ou reivindicou (posse) e aguarda a resposta do doador.
+	KeyClaimPending KeyStatus = "CLAIM_PENDING"
+	// KeyClaimCancelled indica uma portabilidade ou reivindicação que não foi concluída.
+	KeyClaimCancelled KeyStatus = "CLAIM_CANCELLED"
+	// KeyTransferred indica uma chave levada para outra conta por portabilidade ou reivindicação.
+	KeyTransferred KeyStatus = "TRANSFERRED"
+	// KeyDeleted indica uma chave excluída pelo cliente.
+	KeyDeleted KeyStatus = "DELETED"
+)
+
+// OpenKeyStatuses são os status que contam no limite de chaves da conta.
+var OpenKeyStatuses = []KeyStatus{KeyPendingConfirmation, KeyActive, KeyClaimPending}
+
+func (s KeyStatus) IsOpen() bool {
+	return slices.Contains(OpenKeyStatuses, s)
+}
+
+// Limite de chaves por conta definido pelo regulamento do Pix.
+const (
+	MaxKeysPerIndividualAccount = 5
+	MaxKeysPerBusinessAccount   = 20
+)
+
+// maxEmailKeyLength é o tamanho máximo de uma chave de e-mail aceito pelo DICT.
+const maxEmailKeyLength = 77
+
+// PixKey é uma chave Pix de uma conta. O valor da chave é gravado cifrado e as buscas usam o
+// blind index KeyIndex, calculado em BeforeSave. OTPHash guarda o código de confirmação das chaves
+// de e-mail e celular.
+type PixKey struct {
+	ID           int64               `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID     string              `gorm:"type:varchar(26);unique;not null"`
+	AccountID    int64               `gorm:"not null"`
+	Account      user_models.Account `gorm:"foreignKey:AccountID"`
+	KeyType      KeyType             `gorm:"type:varchar(5);not null"`
+	Key          string              `gorm:"type:text;not null;column:key_value;serializer:encrypted"`
+	KeyIndex     string              `gorm:"type:varchar(64);not null;column:key_index"`
+	Status       KeyStatus           `gorm:"type:varchar(30);not null"`
+	OTPHash      string              `gorm:"type:varchar(64);column:otp_hash"`
+	OTPExpiresAt *time.Time          `gorm:"column:otp_expires_at"`
+	OTPAttempts  int                 `gorm:"not null;default:0;column:otp_attempts"`
+	ActivatedAt  *time.Time          `gorm:"column:activated_at"`
+	RemovedAt    *time.Time          `gorm:"column:removed_at"`
+	CreatedAt    time.Time           `gorm:"autoCreateTime"`
+	UpdatedAt    time.Time           `gorm:"autoUpdateTime"`
+}
+
+func (PixKey) TableName() string {
+	return "pix.keys"
+}
+
+func (k *PixKey) BeforeCreate(_ *gorm.DB) (err error) {
+	k.PublicID = ulid.Make().String()
+	return
+}
+
+// BeforeSave recalcula o blind index a partir do valor em claro.
+func (k *PixKey) BeforeSave(_ *gorm.DB) (err error) {
+	k.KeyIndex, err = fieldcrypto.BlindIndex(k.Key)
+	return err
+}
+
+// OTP expõe os campos do código de confirmação da chave.
+func (k *PixKey) OTP() otp.Challenge {
+	return otp.Challenge{Hash: &k.OTPHash, ExpiresAt: &k.OTPExpiresAt, Attempts: &k.OTPAttempts}
+}
+
+// Remove encerra a chave com o status final informado.
+func (k *PixKey) Remove(status KeyStatus, now time.Time) {
+	k.Status = status
+	k.RemovedAt = &now
+	k.OTPHash = ""
+	k.OTPExpiresAt = nil
+}
+
+var (
+	cpfFormattingRegex = regexp.MustCompile(`[.\-\s]+`)
+	digitsRegex        = regexp.MustCompile(`^\d+$`)
+)
+
+// NormalizeKey valida a chave e a coloca no formato registrado no DICT: CPF com 11 dígitos, CNPJ
+// sem pontuação, e-mail em minúsculas, celular em E.164 (+5511912345678) e EVP em minúsculas.
+func NormalizeKey(keyType KeyType, raw string) (string, error) {
+	value := strings.TrimSpace(raw)
+
+	switch keyType {
+	case KeyCPF:
+		value = cpfFormattingRegex.ReplaceAllString(value, "")
+		if len(value) == 11 && digitsRegex.MatchString(value) && validators.IsValidCPF(value) {
+			return value, nil
+		}
+	case KeyCNPJ:
+		value = validators.NormalizeCNPJ(value)
+		if validators.IsValidCNPJ(value) {
+			return value, nil
+		}
+	case KeyEmail:
+		value = strings.ToLower(value)
+		address, err := mail.ParseAddress(value)
+		if err == nil && address.Address == value && len(value) <= maxEmailKeyLength {
+			return value, nil
+		}
+	case KeyPhone:
+		if validators.IsValidBrazilianMobile(value) {
+			return value, nil
+		}
+	case KeyEVP:
+		parsed, err := uuid.Parse(value)
+		if err == nil && parsed.Version() == 4 && len(value) == 36 {
+			return strings.ToLower(value), nil
+		}
+	}
+
+	return "", ErrInvalidKey
+}
+
+// ParseKey descobre o tipo de uma chave informada sem tipo, como na busca antes de um Pix.
+// Onze dígitos são sempre tratados como CPF: celulares precisam do +55.
+func ParseKey(raw string) (KeyType, string, error) {
+	value := strings.TrimSpace(raw)
+
+	var keyType KeyType
+	switch {
+	case strings.Contains(value, "@"):
+		keyType = KeyEmail
+	case strings.HasPrefix(value, "+"):
+		keyType = KeyPhone
+	case len(value) == 36 && strings.Count(value, "-") == 4:
+		keyType = KeyEVP
+	case len(cpfFormattingRegex.ReplaceAllString(value, "")) == 11:
+		keyType = KeyCPF
+	default:
+		keyType = KeyCNPJ
+	}
+
+	key, err := NormalizeKey(keyType, value)
+	if err != nil {
+		return "", "", err
+	}
+	return keyType, key, nil
+}
+
+// NewEVP gera uma chave aleatória.
+func NewEVP() string {
+	return uuid.NewString()
+}
+
+// MaskDocument oculta parte do documento do titular exibido na consulta de uma chave: o CPF
+// aparece como ***.456.789-** e o CNPJ, que é público, aparece formatado por inteiro.
+func MaskDocument(document string) string {
+	switch len(document) {
+	case 11:
+		return "***." + document[3:6] + "." + document[6:9] + "-**"
+	case 14:
+		return document[:2] + "." + document[2:5] + "." + document[5:8] + "/" + document[8:12] + "-" + document[12:]
+	default:
+		return strings.Repeat("*", len(document))
+	}
+}
+
+// MaskAccountNumber mostra só os quatro últimos dígitos do número da conta.
+func MaskAccountNumber(accountNumber string) string {
+	if len(accountNumber) <= 4 {
+		return accountNumber
+	}
+	return strings.Repeat("*", len(accountNumber)-4) + accountNumber[len(accountNumber)-4:]
+}
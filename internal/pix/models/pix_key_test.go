@@ -0,0 +1,88 @@
+package models_test
+
+import (
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		keyType  models.KeyType
+		raw      string
+		expected string
+		err      error
+	}{
+		{"formatted CPF", models.KeyCPF, "529.982.247-25", "52998224725", nil},
+		{"invalid CPF", models.KeyCPF, "529.982.247-26", "", models.ErrInvalidKey},
+		{"formatted CNPJ", models.KeyCNPJ, "11.222.333/0001-81", "11222333000181", nil},
+		{"invalid CNPJ", models.KeyCNPJ, "11.222.333/0001-82", "", models.ErrInvalidKey},
+		{"e-mail is lowercased", models.KeyEmail, " John.Doe@Example.com ", "john.doe@example.com", nil},
+		{"e-mail with display name", models.KeyEmail, "John <john@example.com>", "", models.ErrInvalidKey},
+		{"mobile in E.164", models.KeyPhone, "+5511912345678", "+5511912345678", nil},
+		{"mobile without country code", models.KeyPhone, "11912345678", "", models.ErrInvalidKey},
+		{"EVP is lowercased", models.KeyEVP, "123E4567-E89B-42D3-A456-426614174000", "123e4567-e89b-42d3-a456-426614174000", nil},
+		{"EVP must be a v4 UUID", models.KeyEVP, "123e4567-e89b-12d3-a456-426614174000", "", models.ErrInvalidKey},
+		{"unknown type", models.KeyType("IBAN"), "whatever", "", models.ErrInvalidKey},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, err := models.NormalizeKey(tt.keyType, tt.raw)
+			assert.ErrorIs(t, err, tt.err)
+			assert.Equal(t, tt.expected, key)
+		})
+	}
+}
+
+func TestParseKey(t *testing.T) {
+	tests := []struct {
+		raw          string
+		expectedType models.KeyType
+		expectedKey  string
+	}{
+		{"john@example.com", models.KeyEmail, "john@example.com"},
+		{"+5511912345678", models.KeyPhone, "+5511912345678"},
+		{"123e4567-e89b-42d3-a456-426614174000", models.KeyEVP, "123e4567-e89b-42d3-a456-426614174000"},
+		{"529.982.247-25", models.KeyCPF, "52998224725"},
+		{"11.222.333/0001-81", models.KeyCNPJ, "11222333000181"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			keyType, key, err := models.ParseKey(tt.raw)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedType, keyType)
+			assert.Equal(t, tt.expectedKey, key)
+		})
+	}
+
+	t.Run("eleven digits are never a phone", func(t *testing.T) {
+		_, _, err := models.ParseKey("11912345678")
+		assert.ErrorIs(t, err, models.ErrInvalidKey)
+	})
+}
+
+func TestNewEVP(t *testing.T) {
+	key, err := models.NormalizeKey(models.KeyEVP, models.NewEVP())
+	assert.NoError(t, err)
+	assert.Len(t, key, 36)
+}
+
+func TestMasking(t *testing.T) {
+	assert.Equal(t, "***.982.247-**", models.MaskDocument("52998224725"))
+	assert.Equal(t, "11.222.333/0001-81", models.MaskDocument("11222333000181"))
+	assert.Equal(t, "*****4005", models.MaskAccountNumber("122504005"))
+	assert.Equal(t, "12", models.MaskAccountNumber("12"))
+}
+
+func TestKeyStatus_IsOpen(t *testing.T) {
+	assert.True(t, models.KeyPendingConfirmation.IsOpen())
+	assert.True(t, models.KeyActive.IsOpen())
+	assert.True(t, models.KeyClaimPending.IsOpen())
+	assert.False(t, models.KeyDeleted.IsOpen())
+	assert.False(t, models.KeyTransferred.IsOpen())
+	assert.False(t, models.KeyClaimCancelled.IsOpen())
+}
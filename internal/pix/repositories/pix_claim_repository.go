@@ -0,0 +1,111 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/dict"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PixClaimRepository interface {
+	Create(ctx context.Context, claim *models.PixClaim) error
+	Update(ctx context.Context, claim *models.PixClaim) error
+	FindByPublicIDForUpdate(ctx context.Context, publicID string) (*models.PixClaim, error)
+	FindByDICTClaimIDForUpdate(ctx context.Context, dictClaimID string, role models.ClaimRole) (*models.PixClaim, error)
+	FindOpenByPixKeyID(ctx context.Context, pixKeyID int64) (*models.PixClaim, error)
+	ListByAccountIDs(ctx context.Context, accountIDs []int64) ([]models.PixClaim, error)
+	WithTx(tx *gorm.DB) PixClaimRepository
+}
+
+type pixClaimRepository struct {
+	db *gorm.DB
+}
+
+func NewPixClaimRepository(db *gorm.DB) PixClaimRepository {
+	return &pixClaimRepository{db: db}
+}
+
+// Create grava a reivindicação sem tocar na chave associada, que já existe.
+func (r *pixClaimRepository) Create(ctx context.Context, claim *models.PixClaim) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Omit("PixKey").Create(claim).Error
+}
+
+func (r *pixClaimRepository) Update(ctx context.Context, claim *models.PixClaim) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Omit("PixKey").Save(claim).Error
+}
+
+// FindByPublicIDForUpdate busca e bloqueia a reivindicação, com a chave, a conta e o titular.
+// Deve ser usado dentro de WithTx.
+func (r *pixClaimRepository) FindByPublicIDForUpdate(ctx context.Context, publicID string) (*models.PixClaim, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var claim models.PixClaim
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("PixKey.Account.User").
+		Where("public_id = ?", publicID).
+		First(&claim)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &claim, nil
+}
+
+// FindByDICTClaimIDForUpdate busca e bloqueia o lado local (doador ou reivindicador) de uma
+// reivindicação do DICT. Deve ser usado dentro de WithTx.
+func (r *pixClaimRepository) FindByDICTClaimIDForUpdate(ctx context.Context, dictClaimID string, role models.ClaimRole) (*models.PixClaim, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var claim models.PixClaim
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("PixKey.Account.User").
+		Where("dict_claim_id = ? AND role = ?", dictClaimID, role).
+		First(&claim)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &claim, nil
+}
+
+// FindOpenByPixKeyID busca a reivindicação em aberto da chave, se houver.
+func (r *pixClaimRepository) FindOpenByPixKeyID(ctx context.Context, pixKeyID int64) (*models.PixClaim, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var claim models.PixClaim
+	result := r.db.WithContext(ctx).Where("pix_key_id = ? AND status = ?", pixKeyID, dict.ClaimOpen).First(&claim)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &claim, nil
+}
+
+// ListByAccountIDs lista as reivindicações das chaves das contas, das mais recentes para as mais
+// antigas.
+func (r *pixClaimRepository) ListByAccountIDs(ctx context.Context, accountIDs []int64) ([]models.PixClaim, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var claims []models.PixClaim
+	result := r.db.WithContext(ctx).Preload("PixKey").
+		Joins("JOIN pix.keys ON pix.keys.id = pix.claims.pix_key_id").
+		Where("pix.keys.account_id IN ?", accountIDs).
+		Order("pix.claims.id DESC").
+		Find(&claims)
+	return claims, result.Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *pixClaimRepository) WithTx(tx *gorm.DB) PixClaimRepository {
+	return &pixClaimRepository{db: tx}
+}
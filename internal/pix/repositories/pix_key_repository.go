@@ -0,0 +1,178 @@
+// Package repositories define the data access layer for Pix keys and claims.
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/models"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type PixKeyRepository interface {
+	Create(ctx context.Context, key *models.PixKey) error
+	Update(ctx context.Context, key *models.PixKey) error
+	FindByPublicIDForUpdate(ctx context.Context, publicID string) (*models.PixKey, error)
+	FindActiveByKey(ctx context.Context, key string) (*models.PixKey, error)
+	FindOpenByAccountAndKey(ctx context.Context, accountID int64, key string) (*models.PixKey, error)
+	CountOpenByAccountID(ctx context.Context, accountID int64, now time.Time) (int64, error)
+	ListByAccountIDs(ctx context.Context, accountIDs []int64) ([]models.PixKey, error)
+	ListOpenByAccountIDsForUpdate(ctx context.Context, accountIDs []int64) ([]models.PixKey, error)
+	AnonymizeByUserID(ctx context.Context, userID int64) error
+	LockAccount(ctx context.Context, accountID int64) error
+	WithTx(tx *gorm.DB) PixKeyRepository
+}
+
+type pixKeyRepository struct {
+	db *gorm.DB
+}
+
+func NewPixKeyRepository(db *gorm.DB) PixKeyRepository {
+	return &pixKeyRepository{db: db}
+}
+
+// Create grava a chave sem tocar na conta associada, que já existe.
+func (r *pixKeyRepository) Create(ctx context.Context, key *models.PixKey) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Omit("Account").Create(key).Error
+}
+
+func (r *pixKeyRepository) Update(ctx context.Context, key *models.PixKey) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Omit("Account").Save(key).Error
+}
+
+// FindByPublicIDForUpdate busca e bloqueia (SELECT ... FOR UPDATE) a chave, com a conta e o
+// titular. Deve ser usado dentro de WithTx.
+func (r *pixKeyRepository) FindByPublicIDForUpdate(ctx context.Context, publicID string) (*models.PixKey, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var key models.PixKey
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Account.User").
+		Where("public_id = ?", publicID).
+		First(&key)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &key, nil
+}
+
+// FindActiveByKey busca, pelo blind index, a chave ativa em qualquer conta do GoBank.
+func (r *pixKeyRepository) FindActiveByKey(ctx context.Context, key string) (*models.PixKey, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	keyIndex, err := fieldcrypto.BlindIndex(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var pixKey models.PixKey
+	result := r.db.WithContext(ctx).Preload("Account.User").
+		Where("key_index = ? AND status = ?", keyIndex, models.KeyActive).
+		First(&pixKey)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &pixKey, nil
+}
+
+// FindOpenByAccountAndKey busca a chave da conta que ainda não foi encerrada, se houver.
+func (r *pixKeyRepository) FindOpenByAccountAndKey(ctx context.Context, accountID int64, key string) (*models.PixKey, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	keyIndex, err := fieldcrypto.BlindIndex(key)
+	if err != nil {
+		return nil, err
+	}
+
+	var pixKey models.PixKey
+	result := r.db.WithContext(ctx).
+		Where("account_id = ? AND key_index = ? AND status IN ?", accountID, keyIndex, models.OpenKeyStatuses).
+		First(&pixKey)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &pixKey, nil
+}
+
+// CountOpenByAccountID conta as chaves que ocupam o limite da conta. Chaves pendentes cujo código
+// já expirou ou foi descartado não contam, pois só voltam a valer se forem cadastradas de novo.
+func (r *pixKeyRepository) CountOpenByAccountID(ctx context.Context, accountID int64, now time.Time) (int64, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var count int64
+	result := r.db.WithContext(ctx).Model(&models.PixKey{}).
+		Where("account_id = ? AND status IN ?", accountID, models.OpenKeyStatuses).
+		Where("NOT (status = ? AND (otp_expires_at IS NULL OR otp_expires_at <= ?))", models.KeyPendingConfirmation, now).
+		Count(&count)
+	return count, result.Error
+}
+
+// ListByAccountIDs lista as chaves não encerradas das contas, com a conta de cada uma.
+func (r *pixKeyRepository) ListByAccountIDs(ctx context.Context, accountIDs []int64) ([]models.PixKey, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var keys []models.PixKey
+	result := r.db.WithContext(ctx).Preload("Account").
+		Where("account_id IN ? AND status IN ?", accountIDs, models.OpenKeyStatuses).
+		Order("id").
+		Find(&keys)
+	return keys, result.Error
+}
+
+// ListOpenByAccountIDsForUpdate busca e bloqueia as chaves não encerradas das contas. Deve ser
+// usado dentro de WithTx.
+func (r *pixKeyRepository) ListOpenByAccountIDsForUpdate(ctx context.Context, accountIDs []int64) ([]models.PixKey, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var keys []models.PixKey
+	result := r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("account_id IN ? AND status IN ?", accountIDs, models.OpenKeyStatuses).
+		Order("id").
+		Find(&keys)
+	return keys, result.Error
+}
+
+// AnonymizeByUserID apaga o valor de todas as chaves, encerradas ou não, das contas do usuário.
+func (r *pixKeyRepository) AnonymizeByUserID(ctx context.Context, userID int64) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	accountIDs := r.db.Model(&user_models.Account{}).Select("id").Where("user_id = ?", userID)
+	return r.db.WithContext(ctx).Model(&models.PixKey{}).
+		Where("account_id IN (?)", accountIDs).
+		Updates(map[string]any{"key_value": "", "key_index": "", "otp_hash": ""}).Error
+}
+
+// LockAccount bloqueia a conta corrente até o fim da transação, para que cadastros simultâneos
+// na mesma conta não ultrapassem o limite de chaves. Deve ser usado dentro de WithTx.
+func (r *pixKeyRepository) LockAccount(ctx context.Context, accountID int64) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var account user_models.Account
+	return r.db.WithContext(ctx).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Select("id").
+		Where("id = ?", accountID).
+		First(&account).Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *pixKeyRepository) WithTx(tx *gorm.DB) PixKeyRepository {
+	return &pixKeyRepository{db: tx}
+}
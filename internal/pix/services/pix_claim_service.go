@@ -0,0 +1,360 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	outbox_repositories "github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/dict"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/repositories"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrClaimNotFound = errors.New("reivindicação não encontrada")
+	ErrClaimNotOpen  = errors.New("a reivindicação já foi encerrada")
+	ErrNotDonor      = errors.New("só o titular da chave reivindicada pode confirmar a reivindicação")
+)
+
+// claimDeadlineLayout é o formato do prazo de resposta nos e-mails.
+const claimDeadlineLayout = "02/01/2006 15:04"
+
+type PixClaimService interface {
+	List(ctx context.Context, userPublicID string) ([]models.PixClaim, error)
+	Confirm(ctx context.Context, userPublicID, claimPublicID string) (*models.PixClaim, error)
+	Cancel(ctx context.Context, userPublicID, claimPublicID string) (*models.PixClaim, error)
+	SyncClaims(ctx context.Context, since time.Time) (time.Time, error)
+}
+
+type pixClaimService struct {
+	transactor database.Transactor
+	keyRepo    repositories.PixKeyRepository
+	claimRepo  repositories.PixClaimRepository
+	userRepo   user_repositories.UserRepository
+	outboxRepo outbox_repositories.OutboxRepository
+	directory  dict.Directory
+	ispb       string
+}
+
+func NewPixClaimService(
+	transactor database.Transactor,
+	keyRepo repositories.PixKeyRepository,
+	claimRepo repositories.PixClaimRepository,
+	userRepo user_repositories.UserRepository,
+	outboxRepo outbox_repositories.OutboxRepository,
+	directory dict.Directory,
+	ispb string,
+) PixClaimService {
+	return &pixClaimService{
+		transactor: transactor,
+		keyRepo:    keyRepo,
+		claimRepo:  claimRepo,
+		userRepo:   userRepo,
+		outboxRepo: outboxRepo,
+		directory:  directory,
+		ispb:       ispb,
+	}
+}
+
+// List retorna as reivindicações, abertas ou encerradas, das chaves das contas do usuário.
+func (s *pixClaimService) List(ctx context.Context, userPublicID string) ([]models.PixClaim, error) {
+	user, err := findActiveUser(ctx, s.userRepo, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	accounts, err := s.userRepo.ListAccountsByUserID(ctx, user.ID)
+	if err != nil {
+		log.Printf("Error listing accounts of user %s: %v", user.PublicID, err)
+		return nil, ErrInternalServer
+	}
+	if len(accounts) == 0 {
+		return []models.PixClaim{}, nil
+	}
+
+	accountIDs := make([]int64, len(accounts))
+	for i, account := range accounts {
+		accountIDs[i] = account.ID
+	}
+
+	claims, err := s.claimRepo.ListByAccountIDs(ctx, accountIDs)
+	if err != nil {
+		log.Printf("Error listing Pix claims: %v", err)
+		return nil, ErrInternalServer
+	}
+	return claims, nil
+}
+
+// Confirm libera a chave para quem a reivindicou. Só o doador pode confirmar; a chave sai da conta
+// do usuário na hora.
+func (s *pixClaimService) Confirm(ctx context.Context, userPublicID, claimPublicID string) (*models.PixClaim, error) {
+	return s.resolve(ctx, userPublicID, claimPublicID, func(claim *models.PixClaim) (*dict.Claim, error) {
+		if claim.Role != models.RoleDonor {
+			return nil, ErrNotDonor
+		}
+		return s.directory.ConfirmClaim(ctx, claim.DICTClaimID, s.ispb)
+	})
+}
+
+// Cancel encerra a reivindicação sem mover a chave. Pode ser chamado pelo doador, para manter a
+// chave, ou por quem reivindicou, para desistir.
+func (s *pixClaimService) Cancel(ctx context.Context, userPublicID, claimPublicID string) (*models.PixClaim, error) {
+	return s.resolve(ctx, userPublicID, claimPublicID, func(claim *models.PixClaim) (*dict.Claim, error) {
+		return s.directory.CancelClaim(ctx, claim.DICTClaimID, s.ispb)
+	})
+}
+
+func (s *pixClaimService) resolve(ctx context.Context, userPublicID, claimPublicID string, apply func(*models.PixClaim) (*dict.Claim, error)) (*models.PixClaim, error) {
+	user, err := findActiveUser(ctx, s.userRepo, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	var claim *models.PixClaim
+	var resolveErr error
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		claim, resolveErr = s.resolveWithinTx(ctx, tx, user, claimPublicID, apply)
+		return resolveErr
+	})
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	if err != nil {
+		log.Printf("Error committing Pix claim resolution: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return claim, nil
+}
+
+func (s *pixClaimService) resolveWithinTx(ctx context.Context, tx *gorm.DB, user *user_models.User, claimPublicID string, apply func(*models.PixClaim) (*dict.Claim, error)) (*models.PixClaim, error) {
+	claimRepo := s.claimRepo.WithTx(tx)
+
+	claim, err := claimRepo.FindByPublicIDForUpdate(ctx, claimPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrClaimNotFound
+		}
+		log.Printf("Error finding Pix claim by public ID: %v", err)
+		return nil, ErrInternalServer
+	}
+	if claim.PixKey.Account.UserID != user.ID {
+		return nil, ErrClaimNotFound
+	}
+	if !claim.IsOpen() {
+		return nil, ErrClaimNotOpen
+	}
+
+	dictClaim, err := apply(claim)
+	if err != nil {
+		switch {
+		case errors.Is(err, ErrNotDonor):
+			return nil, err
+		case errors.Is(err, dict.ErrClaimNotOpen):
+			return nil, ErrClaimNotOpen
+		}
+		log.Printf("Error resolving Pix claim in the DICT: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	if err := s.applyWithinTx(ctx, tx, dictClaim); err != nil {
+		return nil, err
+	}
+
+	updated, err := claimRepo.FindByPublicIDForUpdate(ctx, claimPublicID)
+	if err != nil {
+		log.Printf("Error reloading Pix claim: %v", err)
+		return nil, ErrInternalServer
+	}
+	return updated, nil
+}
+
+// SyncClaims traz do DICT as reivindicações alteradas desde since e aplica cada uma às chaves
+// locais: reivindicações novas contra chaves do GoBank geram o aviso ao doador, e as encerradas
+// movem ou liberam a chave. Retorna o instante da última alteração aplicada, a ser usado como
+// since na próxima chamada. Reaplicar uma reivindicação já sincronizada não tem efeito.
+func (s *pixClaimService) SyncClaims(ctx context.Context, since time.Time) (time.Time, error) {
+	claims, err := s.directory.ListClaims(ctx, s.ispb, since)
+	if err != nil {
+		return since, err
+	}
+
+	for _, claim := range claims {
+		err := s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+			return s.applyWithinTx(ctx, tx, &claim)
+		})
+		if err != nil {
+			// As próximas só são aplicadas depois desta, para não pular alterações.
+			return since, err
+		}
+		since = claim.UpdatedAt
+	}
+
+	return since, nil
+}
+
+// applyWithinTx atualiza os lados locais da reivindicação. O doador é tratado primeiro: quando
+// as duas contas são do GoBank, a chave precisa sair da conta doadora antes de ficar ativa na
+// conta de quem reivindicou.
+func (s *pixClaimService) applyWithinTx(ctx context.Context, tx *gorm.DB, claim *dict.Claim) error {
+	if claim.DonorISPB == s.ispb {
+		if err := s.applyDonorWithinTx(ctx, tx, claim); err != nil {
+			return err
+		}
+	}
+	if claim.Claimer.ParticipantISPB == s.ispb {
+		return s.applyClaimerWithinTx(ctx, tx, claim)
+	}
+	return nil
+}
+
+func (s *pixClaimService) applyDonorWithinTx(ctx context.Context, tx *gorm.DB, claim *dict.Claim) error {
+	claimRepo := s.claimRepo.WithTx(tx)
+
+	localClaim, err := claimRepo.FindByDICTClaimIDForUpdate(ctx, claim.ID, models.RoleDonor)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		localClaim, err = s.createDonorClaimWithinTx(ctx, tx, claim)
+		if localClaim == nil || err != nil {
+			return err
+		}
+	} else if err != nil {
+		log.Printf("Error finding donor Pix claim: %v", err)
+		return ErrInternalServer
+	}
+
+	return s.updateWithinTx(ctx, tx, localClaim, claim, func(pixKey *models.PixKey, now time.Time) {
+		if claim.Status == dict.ClaimCompleted {
+			pixKey.Remove(models.KeyTransferred, now)
+		}
+	})
+}
+
+// createDonorClaimWithinTx grava o lado doador de uma reivindicação contra uma chave ativa do
+// GoBank e avisa o titular. Retorna nil se a chave não estiver mais ativa aqui ou se o titular
+// não estiver ativo.
+func (s *pixClaimService) createDonorClaimWithinTx(ctx context.Context, tx *gorm.DB, claim *dict.Claim) (*models.PixClaim, error) {
+	pixKey, err := s.keyRepo.WithTx(tx).FindActiveByKey(ctx, claim.Key)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Pix claim %s targets a key that is not active in GoBank, skipping", claim.ID)
+			return nil, nil
+		}
+		log.Printf("Error finding Pix key of donor claim: %v", err)
+		return nil, ErrInternalServer
+	}
+	if pixKey.Account.User.Status != user_models.StatusActive {
+		log.Printf("Pix claim %s targets a key of an inactive customer, skipping", claim.ID)
+		return nil, nil
+	}
+
+	localClaim := &models.PixClaim{
+		PixKeyID:           pixKey.ID,
+		PixKey:             *pixKey,
+		DICTClaimID:        claim.ID,
+		Type:               claim.Type,
+		Role:               models.RoleDonor,
+		Status:             dict.ClaimOpen,
+		ResolutionDeadline: claim.ResolutionDeadline,
+	}
+	if err := s.claimRepo.WithTx(tx).Create(ctx, localClaim); err != nil {
+		log.Printf("Error creating donor Pix claim: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	if claim.Status == dict.ClaimOpen {
+		if err := s.enqueueClaimReceivedEmail(ctx, tx, localClaim); err != nil {
+			return nil, err
+		}
+	}
+
+	return localClaim, nil
+}
+
+func (s *pixClaimService) applyClaimerWithinTx(ctx context.Context, tx *gorm.DB, claim *dict.Claim) error {
+	localClaim, err := s.claimRepo.WithTx(tx).FindByDICTClaimIDForUpdate(ctx, claim.ID, models.RoleClaimer)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Pix claim %s opened by GoBank has no local record, skipping", claim.ID)
+			return nil
+		}
+		log.Printf("Error finding claimer Pix claim: %v", err)
+		return ErrInternalServer
+	}
+
+	return s.updateWithinTx(ctx, tx, localClaim, claim, func(pixKey *models.PixKey, now time.Time) {
+		switch claim.Status {
+		case dict.ClaimCompleted:
+			pixKey.Status = models.KeyActive
+			pixKey.ActivatedAt = &now
+		case dict.ClaimCancelled:
+			pixKey.Remove(models.KeyClaimCancelled, now)
+		}
+	})
+}
+
+// updateWithinTx grava o novo status da reivindicação e aplica à chave o efeito do encerramento.
+func (s *pixClaimService) updateWithinTx(ctx context.Context, tx *gorm.DB, localClaim *models.PixClaim, claim *dict.Claim, applyToKey func(*models.PixKey, time.Time)) error {
+	if localClaim.Status == claim.Status {
+		return nil
+	}
+
+	keyRepo := s.keyRepo.WithTx(tx)
+	pixKey, err := keyRepo.FindByPublicIDForUpdate(ctx, localClaim.PixKey.PublicID)
+	if err != nil {
+		log.Printf("Error locking Pix key of claim: %v", err)
+		return ErrInternalServer
+	}
+
+	now := time.Now()
+	applyToKey(pixKey, now)
+	if err := keyRepo.Update(ctx, pixKey); err != nil {
+		log.Printf("Error updating Pix key of claim: %v", err)
+		return ErrInternalServer
+	}
+
+	localClaim.Status = claim.Status
+	localClaim.ResolvedAt = &now
+	if err := s.claimRepo.WithTx(tx).Update(ctx, localClaim); err != nil {
+		log.Printf("Error updating Pix claim: %v", err)
+		return ErrInternalServer
+	}
+
+	log.Printf("Pix claim %s (%s) is now %s", localClaim.PublicID, localClaim.Role, localClaim.Status)
+	return nil
+}
+
+func (s *pixClaimService) enqueueClaimReceivedEmail(ctx context.Context, tx *gorm.DB, claim *models.PixClaim) error {
+	user := &claim.PixKey.Account.User
+	templateData := struct {
+		FullName  string
+		Key       string
+		ClaimType string
+		Deadline  string
+	}{
+		FullName:  user.FullName,
+		Key:       claim.PixKey.Key,
+		ClaimType: string(claim.Type),
+		Deadline:  claim.ResolutionDeadline.Format(claimDeadlineLayout),
+	}
+
+	err := s.outboxRepo.WithTx(tx).EnqueueEmail(ctx, &notification.EmailRequest{
+		From:         os.Getenv("EMAIL_FROM"),
+		To:           user.Email,
+		TemplateID:   notification.TemplatePixClaimReceived,
+		Locale:       user.Locale,
+		TemplateData: templateData,
+	})
+	if err != nil {
+		log.Printf("Error enqueuing Pix claim email: %v", err)
+		return ErrInternalServer
+	}
+	return nil
+}
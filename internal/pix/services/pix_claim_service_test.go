@@ -0,0 +1,191 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/dict"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func newPixClaimService() (services.PixClaimService, *pixMocks) {
+	m := newPixMocks()
+	service := services.NewPixClaimService(m.transactor, m.keyRepo, m.claimRepo, m.userRepo, m.outboxRepo, m.directory, goBankISPB)
+	return service, m
+}
+
+// donorKey é uma chave de e-mail ativa no GoBank, registrada também no DICT.
+func donorKey(t *testing.T, m *pixMocks) *models.PixKey {
+	account := ownerAccount()
+	account.User = *keyOwner()
+	pixKey := &models.PixKey{ID: 100, PublicID: "key-public-id", AccountID: 10, Account: account, KeyType: models.KeyEmail, Key: "john@example.com", Status: models.KeyActive}
+
+	require.NoError(t, m.directory.CreateEntry(context.Background(), dict.Entry{
+		Key: "john@example.com", KeyType: "EMAIL", ParticipantISPB: goBankISPB, OwnerDocument: "52998224725",
+	}))
+	return pixKey
+}
+
+// openOwnershipClaim abre no DICT uma reivindicação de posse feita por um cliente de outro banco.
+func openOwnershipClaim(t *testing.T, m *pixMocks) *dict.Claim {
+	claim, err := m.directory.CreateClaim(context.Background(), dict.ClaimOwnership, dict.Entry{
+		Key: "john@example.com", KeyType: "EMAIL", ParticipantISPB: otherISPB, OwnerDocument: "11144477735",
+	})
+	require.NoError(t, err)
+	return claim
+}
+
+func TestPixClaimService_SyncClaims_NotifiesTheDonor(t *testing.T) {
+	service, m := newPixClaimService()
+	pixKey := donorKey(t, m)
+	claim := openOwnershipClaim(t, m)
+
+	m.claimRepo.On("FindByDICTClaimIDForUpdate", claim.ID, models.RoleDonor).Return(nil, gorm.ErrRecordNotFound)
+	m.keyRepo.On("FindActiveByKey", "john@example.com").Return(pixKey, nil)
+	m.claimRepo.On("Create", mock.MatchedBy(func(localClaim *models.PixClaim) bool {
+		return localClaim.PixKeyID == 100 && localClaim.DICTClaimID == claim.ID &&
+			localClaim.Role == models.RoleDonor && localClaim.Status == dict.ClaimOpen
+	})).Return(nil)
+	m.outboxRepo.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.To == "john@example.com" && req.TemplateID == notification.TemplatePixClaimReceived
+	})).Return(nil)
+
+	since, err := service.SyncClaims(context.Background(), time.Time{})
+
+	require.NoError(t, err)
+	assert.Equal(t, claim.UpdatedAt, since)
+	m.claimRepo.AssertExpectations(t)
+	m.outboxRepo.AssertExpectations(t)
+	m.keyRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestPixClaimService_SyncClaims_IgnoresClaimsAlreadyApplied(t *testing.T) {
+	service, m := newPixClaimService()
+	donorKey(t, m)
+	claim := openOwnershipClaim(t, m)
+
+	m.claimRepo.On("FindByDICTClaimIDForUpdate", claim.ID, models.RoleDonor).Return(&models.PixClaim{Status: dict.ClaimOpen}, nil)
+
+	_, err := service.SyncClaims(context.Background(), time.Time{})
+
+	require.NoError(t, err)
+	m.claimRepo.AssertNotCalled(t, "Create", mock.Anything)
+	m.claimRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestPixClaimService_SyncClaims_IgnoresKeysOfInactiveCustomers(t *testing.T) {
+	service, m := newPixClaimService()
+	pixKey := donorKey(t, m)
+	pixKey.Account.User.Status = user_models.StatusInactive
+	claim := openOwnershipClaim(t, m)
+
+	m.claimRepo.On("FindByDICTClaimIDForUpdate", claim.ID, models.RoleDonor).Return(nil, gorm.ErrRecordNotFound)
+	m.keyRepo.On("FindActiveByKey", "john@example.com").Return(pixKey, nil)
+
+	_, err := service.SyncClaims(context.Background(), time.Time{})
+
+	require.NoError(t, err)
+	m.claimRepo.AssertNotCalled(t, "Create", mock.Anything)
+	m.outboxRepo.AssertNotCalled(t, "EnqueueEmail", mock.Anything)
+}
+
+func TestPixClaimService_Confirm_TransfersTheKey(t *testing.T) {
+	service, m := newPixClaimService()
+	pixKey := donorKey(t, m)
+	claim := openOwnershipClaim(t, m)
+	localClaim := &models.PixClaim{PublicID: "claim-public-id", PixKey: *pixKey, DICTClaimID: claim.ID, Type: claim.Type, Role: models.RoleDonor, Status: dict.ClaimOpen}
+
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+	m.claimRepo.On("FindByPublicIDForUpdate", "claim-public-id").Return(localClaim, nil)
+	m.claimRepo.On("FindByDICTClaimIDForUpdate", claim.ID, models.RoleDonor).Return(localClaim, nil)
+	m.keyRepo.On("FindByPublicIDForUpdate", "key-public-id").Return(pixKey, nil)
+	m.keyRepo.On("Update", pixKey).Return(nil)
+	m.claimRepo.On("Update", localClaim).Return(nil)
+
+	confirmed, err := service.Confirm(context.Background(), "owner-public-id", "claim-public-id")
+
+	require.NoError(t, err)
+	assert.Equal(t, dict.ClaimCompleted, confirmed.Status)
+	assert.NotNil(t, confirmed.ResolvedAt)
+	assert.Equal(t, models.KeyTransferred, pixKey.Status)
+
+	entry, err := m.directory.GetEntry(context.Background(), "john@example.com")
+	require.NoError(t, err)
+	assert.Equal(t, otherISPB, entry.ParticipantISPB)
+}
+
+func TestPixClaimService_Confirm_OnlyTheDonor(t *testing.T) {
+	service, m := newPixClaimService()
+	account := ownerAccount()
+	localClaim := &models.PixClaim{PixKey: models.PixKey{Account: account}, Role: models.RoleClaimer, Status: dict.ClaimOpen}
+
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+	m.claimRepo.On("FindByPublicIDForUpdate", "claim-public-id").Return(localClaim, nil)
+
+	_, err := service.Confirm(context.Background(), "owner-public-id", "claim-public-id")
+
+	assert.ErrorIs(t, err, services.ErrNotDonor)
+}
+
+func TestPixClaimService_Cancel_ByTheClaimer(t *testing.T) {
+	service, m := newPixClaimService()
+	require.NoError(t, m.directory.CreateEntry(context.Background(), dict.Entry{
+		Key: "52998224725", KeyType: "CPF", ParticipantISPB: otherISPB, OwnerDocument: "52998224725",
+	}))
+	claim, err := m.directory.CreateClaim(context.Background(), dict.ClaimPortability, dict.Entry{
+		Key: "52998224725", KeyType: "CPF", ParticipantISPB: goBankISPB, OwnerDocument: "52998224725",
+	})
+	require.NoError(t, err)
+
+	pixKey := &models.PixKey{ID: 100, PublicID: "key-public-id", Account: ownerAccount(), KeyType: models.KeyCPF, Key: "52998224725", Status: models.KeyClaimPending}
+	localClaim := &models.PixClaim{PublicID: "claim-public-id", PixKey: *pixKey, DICTClaimID: claim.ID, Type: claim.Type, Role: models.RoleClaimer, Status: dict.ClaimOpen}
+
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+	m.claimRepo.On("FindByPublicIDForUpdate", "claim-public-id").Return(localClaim, nil)
+	m.claimRepo.On("FindByDICTClaimIDForUpdate", claim.ID, models.RoleClaimer).Return(localClaim, nil)
+	m.keyRepo.On("FindByPublicIDForUpdate", "key-public-id").Return(pixKey, nil)
+	m.keyRepo.On("Update", pixKey).Return(nil)
+	m.claimRepo.On("Update", localClaim).Return(nil)
+
+	cancelled, err := service.Cancel(context.Background(), "owner-public-id", "claim-public-id")
+
+	require.NoError(t, err)
+	assert.Equal(t, dict.ClaimCancelled, cancelled.Status)
+	assert.Equal(t, models.KeyClaimCancelled, pixKey.Status)
+	assert.NotNil(t, pixKey.RemovedAt)
+}
+
+func TestPixClaimService_Cancel_ClaimOfAnotherUser(t *testing.T) {
+	service, m := newPixClaimService()
+	account := ownerAccount()
+	account.UserID = 2
+
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+	m.claimRepo.On("FindByPublicIDForUpdate", "claim-public-id").Return(&models.PixClaim{PixKey: models.PixKey{Account: account}, Status: dict.ClaimOpen}, nil)
+
+	_, err := service.Cancel(context.Background(), "owner-public-id", "claim-public-id")
+
+	assert.ErrorIs(t, err, services.ErrClaimNotFound)
+}
+
+func TestPixClaimService_List(t *testing.T) {
+	service, m := newPixClaimService()
+	claims := []models.PixClaim{{PublicID: "claim-public-id"}}
+
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+	m.userRepo.On("ListAccountsByUserID", int64(1)).Return([]user_models.Account{ownerAccount()}, nil)
+	m.claimRepo.On("ListByAccountIDs", []int64{10}).Return(claims, nil)
+
+	result, err := service.List(context.Background(), "owner-public-id")
+
+	require.NoError(t, err)
+	assert.Equal(t, claims, result)
+}
@@ -0,0 +1,819 @@
+// Package services define the business logic for Pix keys and their claims.
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	outbox_repositories "github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/dict"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/repositories"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/otp"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound         = errors.New("usuário não encontrado")
+	ErrAccountNotFound      = errors.New("conta não encontrada")
+	ErrKeyNotOwned          = errors.New("chaves de CPF e CNPJ só podem ser o documento do titular da conta")
+	ErrKeyLimitReached      = errors.New("a conta atingiu o limite de chaves Pix")
+	ErrKeyAlreadyRegistered = errors.New("a chave já está registrada")
+	ErrConfirmationPending  = errors.New("a chave aguarda a confirmação do código enviado")
+	ErrClaimInProgress      = errors.New("a chave tem uma portabilidade ou reivindicação em andamento")
+	ErrKeyNotFound          = errors.New("chave Pix não encontrada")
+	ErrKeyNotPending        = errors.New("a chave não aguarda confirmação")
+	ErrInvalidCode          = errors.New("código de confirmação inválido")
+	ErrExpiredCode          = errors.New("código de confirmação expirado, cadastre a chave novamente")
+	ErrCodeAttemptsExceeded = errors.New("número máximo de tentativas atingido, cadastre a chave novamente")
+	ErrInternalServer       = errors.New("ocorreu um erro inesperado")
+)
+
+// keyOTP é o código enviado para o e-mail ou celular que se quer cadastrar como chave.
+var keyOTP = otp.Policy{Digits: 6, TTL: 10 * time.Minute, MaxAttempts: 5}
+
+// RegisterKeyInput é o pedido de cadastro de uma chave. Sem AccountPublicID, a primeira conta do
+// usuário é usada. Key é ignorada nas chaves aleatórias (EVP), geradas pelo banco.
+type RegisterKeyInput struct {
+	AccountPublicID string
+	KeyType         models.KeyType
+	Key             string
+}
+
+// KeyOwner é o resultado da consulta de uma chave, com os dados do titular mascarados.
+// AccountPublicID só é preenchido quando a chave aponta para uma conta do GoBank.
+type KeyOwner struct {
+	KeyType         models.KeyType
+	Key             string
+	OwnerName       string
+	OwnerDocument   string
+	OwnerType       dict.OwnerType
+	ParticipantISPB string
+	Branch          string
+	AccountNumber   string
+	AccountPublicID string
+}
+
+type PixKeyService interface {
+	Register(ctx context.Context, userPublicID string, input RegisterKeyInput) (*models.PixKey, error)
+	Confirm(ctx context.Context, userPublicID, keyPublicID, code string) (*models.PixKey, error)
+	Delete(ctx context.Context, userPublicID, keyPublicID string) error
+	List(ctx context.Context, userPublicID string) ([]models.PixKey, error)
+	Lookup(ctx context.Context, rawKey string) (*KeyOwner, error)
+	RemoveAllByUser(ctx context.Context, user *user_models.User) (*DirectoryCleanup, error)
+	CleanUpDirectory(ctx context.Context, cleanup *DirectoryCleanup)
+	WithTx(tx *gorm.DB) PixKeyService
+}
+
+type pixKeyService struct {
+	transactor database.Transactor
+	keyRepo    repositories.PixKeyRepository
+	claimRepo  repositories.PixClaimRepository
+	userRepo   user_repositories.UserRepository
+	outboxRepo outbox_repositories.OutboxRepository
+	smsSender  notification.SMSSender
+	directory  dict.Directory
+	ispb       string
+	tx         *gorm.DB
+}
+
+func NewPixKeyService(
+	transactor database.Transactor,
+	keyRepo repositories.PixKeyRepository,
+	claimRepo repositories.PixClaimRepository,
+	userRepo user_repositories.UserRepository,
+	outboxRepo outbox_repositories.OutboxRepository,
+	smsSender notification.SMSSender,
+	directory dict.Directory,
+	ispb string,
+) PixKeyService {
+	return &pixKeyService{
+		transactor: transactor,
+		keyRepo:    keyRepo,
+		claimRepo:  claimRepo,
+		userRepo:   userRepo,
+		outboxRepo: outboxRepo,
+		smsSender:  smsSender,
+		directory:  directory,
+		ispb:       ispb,
+	}
+}
+
+// Register cadastra uma chave na conta do usuário. Chaves de CPF, CNPJ e aleatórias são
+// registradas no DICT na hora; chaves de e-mail e celular ficam pendentes até o cliente informar
+// o código enviado para o endereço ou número. Se a chave já estiver registrada em outra conta, é
+// aberta uma portabilidade (mesmo titular) ou uma reivindicação de posse (e-mail ou celular de
+// outro titular) e a chave fica aguardando o doador.
+func (s *pixKeyService) Register(ctx context.Context, userPublicID string, input RegisterKeyInput) (*models.PixKey, error) {
+	var key string
+	if input.KeyType == models.KeyEVP {
+		key = models.NewEVP()
+	} else {
+		var err error
+		if key, err = models.NormalizeKey(input.KeyType, input.Key); err != nil {
+			return nil, err
+		}
+	}
+
+	user, err := findActiveUser(ctx, s.userRepo, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch input.KeyType {
+	case models.KeyCPF:
+		if user.CustomerType != user_models.CustomerTypeIndividual || user.DocumentNumber != key {
+			return nil, ErrKeyNotOwned
+		}
+	case models.KeyCNPJ:
+		if user.CustomerType != user_models.CustomerTypeBusiness || user.DocumentNumber != key {
+			return nil, ErrKeyNotOwned
+		}
+	}
+
+	account, err := s.findAccount(ctx, user, input.AccountPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	pixKey := &models.PixKey{
+		AccountID: account.ID,
+		Account:   *account,
+		KeyType:   input.KeyType,
+		Key:       key,
+	}
+
+	var code string
+	if input.KeyType.NeedsOwnershipConfirmation() {
+		if code, err = keyOTP.Generate(); err != nil {
+			log.Printf("Error generating Pix key confirmation code: %v", err)
+			return nil, ErrInternalServer
+		}
+	}
+
+	var registered bool
+	var registerErr error
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		registered, registerErr = s.registerWithinTx(ctx, tx, pixKey, code)
+		return registerErr
+	})
+	if registerErr != nil || err != nil {
+		if registered {
+			s.rollbackEntry(ctx, pixKey.Key)
+		}
+		if registerErr != nil {
+			return nil, registerErr
+		}
+		log.Printf("Error committing Pix key registration: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	// O SMS só sai após o commit, para que um cadastro desfeito não envie o código de uma chave que
+	// não existe. Se o envio falhar, o código é descartado e o cliente pode cadastrar a chave de
+	// novo para receber outro.
+	if pixKey.KeyType == models.KeyPhone {
+		err = s.smsSender.SendSMS(ctx, &notification.SMSRequest{
+			To:   pixKey.Key,
+			Body: fmt.Sprintf("GoBank: seu código para cadastrar este celular como chave Pix é %s. Ele expira em %d minutos. Não compartilhe este código.", code, int(keyOTP.TTL.Minutes())),
+		})
+		if err != nil {
+			log.Printf("Error sending Pix key confirmation code: %v", err)
+			s.discardCode(ctx, pixKey)
+			return nil, ErrInternalServer
+		}
+	}
+
+	return pixKey, nil
+}
+
+// registerWithinTx bloqueia a conta antes de contar as chaves, para que cadastros simultâneos
+// não ultrapassem o limite. Uma chave pendente cujo código expirou ou esgotou as tentativas é
+// reaproveitada com um novo código. Retorna true se a chave foi registrada no DICT.
+func (s *pixKeyService) registerWithinTx(ctx context.Context, tx *gorm.DB, pixKey *models.PixKey, code string) (bool, error) {
+	keyRepo := s.keyRepo.WithTx(tx)
+
+	if err := keyRepo.LockAccount(ctx, pixKey.AccountID); err != nil {
+		log.Printf("Error locking account for Pix key registration: %v", err)
+		return false, ErrInternalServer
+	}
+
+	now := time.Now()
+	existing, err := keyRepo.FindOpenByAccountAndKey(ctx, pixKey.AccountID, pixKey.Key)
+	switch {
+	case err == nil && existing.Status != models.KeyPendingConfirmation:
+		return false, ErrKeyAlreadyRegistered
+	case err == nil && !keyOTP.IsSpent(existing.OTP(), now):
+		return false, ErrConfirmationPending
+	case err == nil:
+		existing.Account = pixKey.Account
+		*pixKey = *existing
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		log.Printf("Error finding Pix key of account: %v", err)
+		return false, ErrInternalServer
+	}
+
+	count, err := keyRepo.CountOpenByAccountID(ctx, pixKey.AccountID, now)
+	if err != nil {
+		log.Printf("Error counting Pix keys of account: %v", err)
+		return false, ErrInternalServer
+	}
+	if count >= int64(maxKeysFor(&pixKey.Account.User)) {
+		return false, ErrKeyLimitReached
+	}
+
+	if pixKey.KeyType.NeedsOwnershipConfirmation() {
+		pixKey.Status = models.KeyPendingConfirmation
+		keyOTP.Issue(pixKey.OTP(), keyOTPSubject(pixKey), code, now)
+
+		if err := s.savePending(ctx, keyRepo, pixKey); err != nil {
+			return false, err
+		}
+
+		if pixKey.KeyType == models.KeyEmail {
+			if err := s.enqueueConfirmationEmail(ctx, tx, pixKey, code); err != nil {
+				return false, err
+			}
+		}
+		return false, nil
+	}
+
+	claim, registered, err := s.activate(ctx, pixKey)
+	if err != nil {
+		return registered, err
+	}
+	if err := keyRepo.Create(ctx, pixKey); err != nil {
+		log.Printf("Error creating Pix key: %v", err)
+		return registered, ErrInternalServer
+	}
+	return registered, s.createClaimerClaim(ctx, tx, pixKey, claim)
+}
+
+// Confirm confere o código enviado para o e-mail ou celular e registra a chave. Cada erro consome
+// uma tentativa; ao atingir o limite ou o prazo, é preciso cadastrar a chave de novo para receber
+// outro código.
+func (s *pixKeyService) Confirm(ctx context.Context, userPublicID, keyPublicID, code string) (*models.PixKey, error) {
+	if len(code) != keyOTP.Digits {
+		return nil, ErrInvalidCode
+	}
+
+	user, err := findActiveUser(ctx, s.userRepo, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	var pixKey *models.PixKey
+	var registered bool
+	var confirmErr error
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		pixKey, registered, confirmErr = s.confirmWithinTx(ctx, tx, user, keyPublicID, code)
+		// A tentativa errada já foi contabilizada e precisa ser gravada mesmo com o código inválido.
+		if errors.Is(confirmErr, ErrInvalidCode) {
+			return nil
+		}
+		return confirmErr
+	})
+	if confirmErr != nil || err != nil {
+		if registered {
+			s.rollbackEntry(ctx, pixKey.Key)
+		}
+		if confirmErr != nil {
+			return nil, confirmErr
+		}
+		log.Printf("Error committing Pix key confirmation: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return pixKey, nil
+}
+
+func (s *pixKeyService) confirmWithinTx(ctx context.Context, tx *gorm.DB, user *user_models.User, keyPublicID, code string) (*models.PixKey, bool, error) {
+	keyRepo := s.keyRepo.WithTx(tx)
+
+	pixKey, err := s.findOwnedKeyForUpdate(ctx, keyRepo, user, keyPublicID)
+	if err != nil {
+		return nil, false, err
+	}
+	if pixKey.Status != models.KeyPendingConfirmation {
+		return nil, false, ErrKeyNotPending
+	}
+
+	switch err := keyOTP.Verify(pixKey.OTP(), keyOTPSubject(pixKey), code, time.Now()); {
+	case errors.Is(err, otp.ErrNotIssued):
+		return nil, false, ErrKeyNotPending
+	case errors.Is(err, otp.ErrAttemptsExceeded):
+		return nil, false, ErrCodeAttemptsExceeded
+	case errors.Is(err, otp.ErrExpired):
+		return nil, false, ErrExpiredCode
+	case errors.Is(err, otp.ErrMismatch):
+		if err := keyRepo.Update(ctx, pixKey); err != nil {
+			log.Printf("Error recording Pix key confirmation attempt: %v", err)
+			return nil, false, ErrInternalServer
+		}
+		return nil, false, ErrInvalidCode
+	}
+
+	claim, registered, err := s.activate(ctx, pixKey)
+	if err != nil {
+		return pixKey, registered, err
+	}
+	if err := keyRepo.Update(ctx, pixKey); err != nil {
+		log.Printf("Error confirming Pix key: %v", err)
+		return pixKey, registered, ErrInternalServer
+	}
+	return pixKey, registered, s.createClaimerClaim(ctx, tx, pixKey, claim)
+}
+
+// activate registra a chave no DICT e a deixa ativa. Se a chave já pertencer a outra conta, abre a
+// reivindicação cabível e a deixa aguardando o doador. Retorna true se a chave foi registrada.
+func (s *pixKeyService) activate(ctx context.Context, pixKey *models.PixKey) (*dict.Claim, bool, error) {
+	entry := s.entryFor(pixKey)
+
+	err := s.directory.CreateEntry(ctx, entry)
+	if err == nil {
+		now := time.Now()
+		pixKey.Status = models.KeyActive
+		pixKey.ActivatedAt = &now
+		return nil, true, nil
+	}
+	if !errors.Is(err, dict.ErrKeyAlreadyRegistered) {
+		log.Printf("Error registering Pix key in the DICT: %v", err)
+		return nil, false, ErrInternalServer
+	}
+
+	current, err := s.directory.GetEntry(ctx, entry.Key)
+	if err != nil {
+		log.Printf("Error finding Pix key in the DICT: %v", err)
+		return nil, false, ErrInternalServer
+	}
+
+	var claimType dict.ClaimType
+	switch {
+	case current.OwnerDocument == entry.OwnerDocument && current.ParticipantISPB != s.ispb:
+		claimType = dict.ClaimPortability
+	case current.OwnerDocument != entry.OwnerDocument && pixKey.KeyType.NeedsOwnershipConfirmation():
+		claimType = dict.ClaimOwnership
+	default:
+		return nil, false, ErrKeyAlreadyRegistered
+	}
+
+	claim, err := s.directory.CreateClaim(ctx, claimType, entry)
+	if err != nil {
+		switch {
+		case errors.Is(err, dict.ErrClaimAlreadyOpen):
+			return nil, false, ErrClaimInProgress
+		case errors.Is(err, dict.ErrInvalidClaim):
+			return nil, false, ErrKeyAlreadyRegistered
+		}
+		log.Printf("Error opening Pix key claim in the DICT: %v", err)
+		return nil, false, ErrInternalServer
+	}
+
+	pixKey.Status = models.KeyClaimPending
+	return claim, false, nil
+}
+
+// createClaimerClaim grava o lado reivindicador da reivindicação aberta por activate, se houver.
+func (s *pixKeyService) createClaimerClaim(ctx context.Context, tx *gorm.DB, pixKey *models.PixKey, claim *dict.Claim) error {
+	if claim == nil {
+		return nil
+	}
+
+	localClaim := &models.PixClaim{
+		PixKeyID:           pixKey.ID,
+		DICTClaimID:        claim.ID,
+		Type:               claim.Type,
+		Role:               models.RoleClaimer,
+		Status:             claim.Status,
+		ResolutionDeadline: claim.ResolutionDeadline,
+	}
+	if err := s.claimRepo.WithTx(tx).Create(ctx, localClaim); err != nil {
+		log.Printf("Error creating Pix claim: %v", err)
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// Delete exclui a chave da conta e, se ela estiver ativa, do DICT. Chaves com portabilidade ou
+// reivindicação em andamento só podem ser excluídas depois que ela for resolvida.
+func (s *pixKeyService) Delete(ctx context.Context, userPublicID, keyPublicID string) error {
+	user, err := findActiveUser(ctx, s.userRepo, userPublicID)
+	if err != nil {
+		return err
+	}
+
+	var deleteErr error
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		deleteErr = s.deleteWithinTx(ctx, tx, user, keyPublicID)
+		return deleteErr
+	})
+	if deleteErr != nil {
+		return deleteErr
+	}
+	if err != nil {
+		log.Printf("Error committing Pix key deletion: %v", err)
+		return ErrInternalServer
+	}
+
+	return nil
+}
+
+func (s *pixKeyService) deleteWithinTx(ctx context.Context, tx *gorm.DB, user *user_models.User, keyPublicID string) error {
+	keyRepo := s.keyRepo.WithTx(tx)
+
+	pixKey, err := s.findOwnedKeyForUpdate(ctx, keyRepo, user, keyPublicID)
+	if err != nil {
+		return err
+	}
+
+	switch pixKey.Status {
+	case models.KeyPendingConfirmation:
+	case models.KeyClaimPending:
+		return ErrClaimInProgress
+	case models.KeyActive:
+		_, err := s.claimRepo.WithTx(tx).FindOpenByPixKeyID(ctx, pixKey.ID)
+		if err == nil {
+			return ErrClaimInProgress
+		}
+		if !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Error finding open claim of Pix key: %v", err)
+			return ErrInternalServer
+		}
+
+		err = s.directory.DeleteEntry(ctx, pixKey.Key, s.ispb)
+		if errors.Is(err, dict.ErrClaimAlreadyOpen) {
+			return ErrClaimInProgress
+		}
+		if err != nil && !errors.Is(err, dict.ErrEntryNotFound) {
+			log.Printf("Error deleting Pix key from the DICT: %v", err)
+			return ErrInternalServer
+		}
+	default:
+		return ErrKeyNotFound
+	}
+
+	pixKey.Remove(models.KeyDeleted, time.Now())
+	if err := keyRepo.Update(ctx, pixKey); err != nil {
+		log.Printf("Error deleting Pix key: %v", err)
+		return ErrInternalServer
+	}
+
+	log.Printf("Pix key %s deleted", pixKey.PublicID)
+	return nil
+}
+
+// List retorna as chaves em uso ou em andamento de todas as contas do usuário.
+func (s *pixKeyService) List(ctx context.Context, userPublicID string) ([]models.PixKey, error) {
+	user, err := findActiveUser(ctx, s.userRepo, userPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	accountIDs, err := s.accountIDsOf(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if len(accountIDs) == 0 {
+		return []models.PixKey{}, nil
+	}
+
+	keys, err := s.keyRepo.ListByAccountIDs(ctx, accountIDs)
+	if err != nil {
+		log.Printf("Error listing Pix keys: %v", err)
+		return nil, ErrInternalServer
+	}
+	return keys, nil
+}
+
+// Lookup consulta a chave no DICT antes de um Pix. O documento e o número da conta do titular
+// vêm mascarados.
+func (s *pixKeyService) Lookup(ctx context.Context, rawKey string) (*KeyOwner, error) {
+	keyType, key, err := models.ParseKey(rawKey)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.directory.GetEntry(ctx, key)
+	if err != nil {
+		if errors.Is(err, dict.ErrEntryNotFound) {
+			return nil, ErrKeyNotFound
+		}
+		log.Printf("Error looking up Pix key in the DICT: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	owner := &KeyOwner{
+		KeyType:         keyType,
+		Key:             entry.Key,
+		OwnerName:       entry.OwnerName,
+		OwnerDocument:   models.MaskDocument(entry.OwnerDocument),
+		OwnerType:       entry.OwnerType,
+		ParticipantISPB: entry.ParticipantISPB,
+		Branch:          entry.Branch,
+		AccountNumber:   models.MaskAccountNumber(entry.AccountNumber),
+	}
+
+	// Chaves do GoBank cujo titular não está mais ativo não recebem Pix e são tratadas como inexistentes.
+	if entry.ParticipantISPB == s.ispb {
+		account, err := s.userRepo.FindAccountByNumber(ctx, entry.Branch, entry.AccountNumber)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrKeyNotFound
+			}
+			log.Printf("Error finding account of Pix key: %v", err)
+			return nil, ErrInternalServer
+		}
+		if account.User.Status != user_models.StatusActive {
+			return nil, ErrKeyNotFound
+		}
+		owner.AccountPublicID = account.PublicID
+	}
+
+	return owner, nil
+}
+
+// DirectoryCleanup guarda as chamadas ao DICT de RemoveAllByUser, que só podem ser feitas depois
+// do commit: uma transação desfeita não deve ter tirado do DICT chaves que continuam ativas aqui.
+type DirectoryCleanup struct {
+	claimIDs []string
+	keys     []string
+}
+
+// RemoveAllByUser marca como DELETED todas as chaves em uso ou em andamento das contas do usuário
+// e cancela as reivindicações abertas sobre elas. É chamado no pedido de exclusão dos dados, para
+// que a chave deixe de apontar para o titular. As exclusões no DICT ficam no DirectoryCleanup
+// devolvido, a ser passado a CleanUpDirectory após o commit.
+func (s *pixKeyService) RemoveAllByUser(ctx context.Context, user *user_models.User) (*DirectoryCleanup, error) {
+	keyRepo, claimRepo := s.keyRepo, s.claimRepo
+	if s.tx != nil {
+		keyRepo, claimRepo = keyRepo.WithTx(s.tx), claimRepo.WithTx(s.tx)
+	}
+
+	accountIDs, err := s.accountIDsOf(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	cleanup := &DirectoryCleanup{}
+	if len(accountIDs) == 0 {
+		return cleanup, nil
+	}
+
+	keys, err := keyRepo.ListOpenByAccountIDsForUpdate(ctx, accountIDs)
+	if err != nil {
+		log.Printf("Error listing Pix keys of user %s: %v", user.PublicID, err)
+		return nil, ErrInternalServer
+	}
+
+	now := time.Now()
+	for i := range keys {
+		pixKey := &keys[i]
+
+		claim, err := claimRepo.FindOpenByPixKeyID(ctx, pixKey.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			log.Printf("Error finding open claim of Pix key: %v", err)
+			return nil, ErrInternalServer
+		}
+		if err == nil {
+			claim.Status = dict.ClaimCancelled
+			claim.ResolvedAt = &now
+			if err := claimRepo.Update(ctx, claim); err != nil {
+				log.Printf("Error cancelling Pix claim: %v", err)
+				return nil, ErrInternalServer
+			}
+			cleanup.claimIDs = append(cleanup.claimIDs, claim.DICTClaimID)
+		}
+
+		if pixKey.Status == models.KeyActive {
+			cleanup.keys = append(cleanup.keys, pixKey.Key)
+		}
+
+		pixKey.Remove(models.KeyDeleted, now)
+		if err := keyRepo.Update(ctx, pixKey); err != nil {
+			log.Printf("Error deleting Pix key: %v", err)
+			return nil, ErrInternalServer
+		}
+	}
+
+	log.Printf("Removed %d Pix keys of user %s", len(keys), user.PublicID)
+	return cleanup, nil
+}
+
+// CleanUpDirectory cancela no DICT as reivindicações e exclui as chaves removidas por
+// RemoveAllByUser. As falhas só são registradas: a chave já está DELETED aqui, então uma entrada
+// que sobrar no DICT não é mais resolvida para o titular pelo Lookup.
+func (s *pixKeyService) CleanUpDirectory(ctx context.Context, cleanup *DirectoryCleanup) {
+	if cleanup == nil {
+		return
+	}
+	ctx = context.WithoutCancel(ctx)
+
+	for _, claimID := range cleanup.claimIDs {
+		if _, err := s.directory.CancelClaim(ctx, claimID, s.ispb); err != nil && !errors.Is(err, dict.ErrClaimNotOpen) {
+			log.Printf("Error cancelling Pix claim %s in the DICT: %v", claimID, err)
+		}
+	}
+	for _, key := range cleanup.keys {
+		if err := s.directory.DeleteEntry(ctx, key, s.ispb); err != nil && !errors.Is(err, dict.ErrEntryNotFound) {
+			log.Printf("Error deleting Pix key from the DICT: %v", err)
+		}
+	}
+}
+
+// WithTx retorna uma cópia do serviço que executa RemoveAllByUser na transação informada. Os
+// demais métodos continuam abrindo a própria transação.
+func (s *pixKeyService) WithTx(tx *gorm.DB) PixKeyService {
+	clone := *s
+	clone.tx = tx
+	return &clone
+}
+
+// findActiveUser busca o usuário autenticado; usuários inativos ou bloqueados são tratados como
+// inexistentes.
+func findActiveUser(ctx context.Context, userRepo user_repositories.UserRepository, userPublicID string) (*user_models.User, error) {
+	user, err := userRepo.FindByPublicID(ctx, userPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		log.Printf("Error finding user by public ID: %v", err)
+		return nil, ErrInternalServer
+	}
+	if user.Status != user_models.StatusActive {
+		return nil, ErrUserNotFound
+	}
+	return user, nil
+}
+
+// findAccount só aceita contas do próprio usuário; contas de terceiros são tratadas como
+// inexistentes para não revelar quais IDs são válidos.
+func (s *pixKeyService) findAccount(ctx context.Context, user *user_models.User, publicID string) (*user_models.Account, error) {
+	if publicID == "" {
+		accounts, err := s.userRepo.ListAccountsByUserID(ctx, user.ID)
+		if err != nil {
+			log.Printf("Error listing accounts of user %s: %v", user.PublicID, err)
+			return nil, ErrInternalServer
+		}
+		if len(accounts) == 0 {
+			return nil, ErrAccountNotFound
+		}
+		account := accounts[0]
+		account.User = *user
+		return &account, nil
+	}
+
+	account, err := s.userRepo.FindAccountByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccountNotFound
+		}
+		log.Printf("Error finding account by public ID: %v", err)
+		return nil, ErrInternalServer
+	}
+	if account.UserID != user.ID {
+		return nil, ErrAccountNotFound
+	}
+	return account, nil
+}
+
+func (s *pixKeyService) accountIDsOf(ctx context.Context, user *user_models.User) ([]int64, error) {
+	accounts, err := s.userRepo.ListAccountsByUserID(ctx, user.ID)
+	if err != nil {
+		log.Printf("Error listing accounts of user %s: %v", user.PublicID, err)
+		return nil, ErrInternalServer
+	}
+
+	accountIDs := make([]int64, len(accounts))
+	for i, account := range accounts {
+		accountIDs[i] = account.ID
+	}
+	return accountIDs, nil
+}
+
+// findOwnedKeyForUpdate bloqueia a chave e garante que ela é de uma conta do usuário; chaves de
+// terceiros são tratadas como inexistentes.
+func (s *pixKeyService) findOwnedKeyForUpdate(ctx context.Context, keyRepo repositories.PixKeyRepository, user *user_models.User, keyPublicID string) (*models.PixKey, error) {
+	pixKey, err := keyRepo.FindByPublicIDForUpdate(ctx, keyPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrKeyNotFound
+		}
+		log.Printf("Error finding Pix key by public ID: %v", err)
+		return nil, ErrInternalServer
+	}
+	if pixKey.Account.UserID != user.ID {
+		return nil, ErrKeyNotFound
+	}
+	return pixKey, nil
+}
+
+func (s *pixKeyService) enqueueConfirmationEmail(ctx context.Context, tx *gorm.DB, pixKey *models.PixKey, code string) error {
+	templateData := struct {
+		FullName string
+		Key      string
+		Code     string
+		Minutes  int
+	}{
+		FullName: pixKey.Account.User.FullName,
+		Key:      pixKey.Key,
+		Code:     code,
+		Minutes:  int(keyOTP.TTL.Minutes()),
+	}
+
+	err := s.outboxRepo.WithTx(tx).EnqueueEmail(ctx, &notification.EmailRequest{
+		From:         os.Getenv("EMAIL_FROM"),
+		To:           pixKey.Key,
+		TemplateID:   notification.TemplatePixKeyConfirmation,
+		Locale:       pixKey.Account.User.Locale,
+		TemplateData: templateData,
+	})
+	if err != nil {
+		log.Printf("Error enqueuing Pix key confirmation email: %v", err)
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// rollbackEntry desfaz o registro no DICT de uma chave cuja gravação local falhou.
+func (s *pixKeyService) rollbackEntry(ctx context.Context, key string) {
+	if err := s.directory.DeleteEntry(context.WithoutCancel(ctx), key, s.ispb); err != nil {
+		log.Printf("Error rolling back Pix key registration in the DICT: %v", err)
+	}
+}
+
+func (s *pixKeyService) entryFor(pixKey *models.PixKey) dict.Entry {
+	user := &pixKey.Account.User
+	ownerType := dict.NaturalPerson
+	if user.CustomerType == user_models.CustomerTypeBusiness {
+		ownerType = dict.LegalPerson
+	}
+
+	return dict.Entry{
+		Key:             pixKey.Key,
+		KeyType:         string(pixKey.KeyType),
+		ParticipantISPB: s.ispb,
+		Branch:          pixKey.Account.AgencyNumber,
+		AccountNumber:   pixKey.Account.AccountNumber,
+		OwnerType:       ownerType,
+		OwnerDocument:   user.DocumentNumber,
+		OwnerName:       user.FullName,
+	}
+}
+
+func maxKeysFor(user *user_models.User) int {
+	if user.CustomerType == user_models.CustomerTypeBusiness {
+		return models.MaxKeysPerBusinessAccount
+	}
+	return models.MaxKeysPerIndividualAccount
+}
+
+// discardCode invalida o código que não chegou ao cliente, para que o próximo cadastro da chave
+// envie outro em vez de esbarrar em ErrConfirmationPending. Um código reemitido nesse meio-tempo
+// é mantido.
+func (s *pixKeyService) discardCode(ctx context.Context, pixKey *models.PixKey) {
+	err := s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		keyRepo := s.keyRepo.WithTx(tx)
+
+		current, err := keyRepo.FindByPublicIDForUpdate(ctx, pixKey.PublicID)
+		if err != nil {
+			return err
+		}
+		if current.Status != models.KeyPendingConfirmation || current.OTPHash != pixKey.OTPHash {
+			return nil
+		}
+
+		otp.Clear(current.OTP())
+		return keyRepo.Update(ctx, current)
+	})
+	if err != nil {
+		log.Printf("Error discarding undelivered Pix key confirmation code: %v", err)
+	}
+}
+
+// savePending grava uma chave pendente: cria a chave nova ou atualiza a que recebeu outro código.
+func (s *pixKeyService) savePending(ctx context.Context, keyRepo repositories.PixKeyRepository, pixKey *models.PixKey) error {
+	if pixKey.ID != 0 {
+		if err := keyRepo.Update(ctx, pixKey); err != nil {
+			log.Printf("Error reissuing Pix key confirmation code: %v", err)
+			return ErrInternalServer
+		}
+		return nil
+	}
+	if err := keyRepo.Create(ctx, pixKey); err != nil {
+		log.Printf("Error creating Pix key: %v", err)
+		return ErrInternalServer
+	}
+	return nil
+}
+
+// keyOTPSubject vincula o código à conta e à chave.
+func keyOTPSubject(pixKey *models.PixKey) string {
+	return pixKey.Account.PublicID + ":" + pixKey.Key
+}
@@ -0,0 +1,458 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/dict"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+const (
+	goBankISPB = "12345678"
+	otherISPB  = "87654321"
+)
+
+type pixMocks struct {
+	transactor *mocks.MockTransactor
+	keyRepo    *mocks.MockPixKeyRepository
+	claimRepo  *mocks.MockPixClaimRepository
+	userRepo   *mocks.MockUserRepository
+	outboxRepo *mocks.MockOutboxRepository
+	smsSender  *notification.StubSMSSender
+	directory  *dict.MemoryDirectory
+}
+
+func newPixMocks() *pixMocks {
+	return &pixMocks{
+		transactor: new(mocks.MockTransactor),
+		keyRepo:    new(mocks.MockPixKeyRepository),
+		claimRepo:  new(mocks.MockPixClaimRepository),
+		userRepo:   new(mocks.MockUserRepository),
+		outboxRepo: new(mocks.MockOutboxRepository),
+		smsSender:  notification.NewStubSMSSender(),
+		directory:  dict.NewMemoryDirectory(dict.DefaultResolutionPeriod),
+	}
+}
+
+func newPixKeyService() (services.PixKeyService, *pixMocks) {
+	m := newPixMocks()
+	service := services.NewPixKeyService(m.transactor, m.keyRepo, m.claimRepo, m.userRepo, m.outboxRepo, m.smsSender, m.directory, goBankISPB)
+	return service, m
+}
+
+func keyOwner() *user_models.User {
+	return &user_models.User{
+		ID:             1,
+		PublicID:       "owner-public-id",
+		FullName:       "John Doe",
+		Email:          "john@example.com",
+		DocumentNumber: "52998224725",
+		CustomerType:   user_models.CustomerTypeIndividual,
+		Status:         user_models.StatusActive,
+	}
+}
+
+func ownerAccount() user_models.Account {
+	return user_models.Account{ID: 10, PublicID: "owner-account", UserID: 1, AgencyNumber: "0001", AccountNumber: "122504005"}
+}
+
+// expectRegistration prepara as consultas feitas antes de gravar uma nova chave na conta.
+func expectRegistration(m *pixMocks, key string, openKeys int64) {
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+	m.userRepo.On("ListAccountsByUserID", int64(1)).Return([]user_models.Account{ownerAccount()}, nil)
+	m.keyRepo.On("LockAccount", int64(10)).Return(nil)
+	m.keyRepo.On("FindOpenByAccountAndKey", int64(10), key).Return(nil, gorm.ErrRecordNotFound)
+	m.keyRepo.On("CountOpenByAccountID", int64(10)).Return(openKeys, nil)
+}
+
+// expectCreate simula o INSERT, atribuindo os IDs que o banco geraria.
+func expectCreate(m *pixMocks) {
+	m.keyRepo.On("Create", mock.Anything).Run(func(args mock.Arguments) {
+		pixKey := args.Get(0).(*models.PixKey)
+		pixKey.ID = 100
+		pixKey.PublicID = "key-public-id"
+	}).Return(nil)
+}
+
+func TestPixKeyService_Register_CPF(t *testing.T) {
+	service, m := newPixKeyService()
+	expectRegistration(m, "52998224725", 0)
+	expectCreate(m)
+
+	pixKey, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyCPF, Key: "529.982.247-25"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.KeyActive, pixKey.Status)
+	assert.NotNil(t, pixKey.ActivatedAt)
+
+	entry, err := m.directory.GetEntry(context.Background(), "52998224725")
+	require.NoError(t, err)
+	assert.Equal(t, goBankISPB, entry.ParticipantISPB)
+	assert.Equal(t, "122504005", entry.AccountNumber)
+	assert.Equal(t, dict.NaturalPerson, entry.OwnerType)
+}
+
+func TestPixKeyService_Register_RejectsDocumentOfAnotherPerson(t *testing.T) {
+	service, m := newPixKeyService()
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+
+	_, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyCPF, Key: "111.444.777-35"})
+	assert.ErrorIs(t, err, services.ErrKeyNotOwned)
+
+	_, err = service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyCNPJ, Key: "11.222.333/0001-81"})
+	assert.ErrorIs(t, err, services.ErrKeyNotOwned)
+
+	_, err = service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyCPF, Key: "529.982.247-26"})
+	assert.ErrorIs(t, err, models.ErrInvalidKey)
+
+	assert.Equal(t, 0, m.transactor.Calls)
+}
+
+func TestPixKeyService_Register_LimitReached(t *testing.T) {
+	service, m := newPixKeyService()
+	expectRegistration(m, "john@example.com", models.MaxKeysPerIndividualAccount)
+
+	_, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyEmail, Key: "john@example.com"})
+
+	assert.ErrorIs(t, err, services.ErrKeyLimitReached)
+	m.keyRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestPixKeyService_Register_EmailWaitsForTheCode(t *testing.T) {
+	service, m := newPixKeyService()
+	expectRegistration(m, "john@example.com", 0)
+	expectCreate(m)
+	m.outboxRepo.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.To == "john@example.com" && req.TemplateID == notification.TemplatePixKeyConfirmation
+	})).Return(nil)
+
+	pixKey, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyEmail, Key: "John@Example.com"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.KeyPendingConfirmation, pixKey.Status)
+	assert.NotEmpty(t, pixKey.OTPHash)
+	m.outboxRepo.AssertExpectations(t)
+
+	_, err = m.directory.GetEntry(context.Background(), "john@example.com")
+	assert.ErrorIs(t, err, dict.ErrEntryNotFound, "the key is only registered after the code is confirmed")
+}
+
+func TestPixKeyService_ConfirmPhone(t *testing.T) {
+	service, m := newPixKeyService()
+	expectRegistration(m, "+5511912345678", 0)
+	expectCreate(m)
+
+	pixKey, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyPhone, Key: "+5511912345678"})
+	require.NoError(t, err)
+
+	messages := m.smsSender.Messages()
+	require.Len(t, messages, 1)
+	assert.Equal(t, "+5511912345678", messages[0].To)
+	code := regexp.MustCompile(`\d{6}`).FindString(messages[0].Body)
+
+	pixKey.Account.User = *keyOwner()
+	m.keyRepo.On("FindByPublicIDForUpdate", "key-public-id").Return(pixKey, nil)
+	m.keyRepo.On("Update", pixKey).Return(nil)
+
+	wrongCode := "000000"
+	if code == wrongCode {
+		wrongCode = "111111"
+	}
+	_, err = service.Confirm(context.Background(), "owner-public-id", "key-public-id", wrongCode)
+	assert.ErrorIs(t, err, services.ErrInvalidCode)
+	assert.Equal(t, 1, pixKey.OTPAttempts)
+
+	confirmed, err := service.Confirm(context.Background(), "owner-public-id", "key-public-id", code)
+	require.NoError(t, err)
+	assert.Equal(t, models.KeyActive, confirmed.Status)
+	assert.Empty(t, confirmed.OTPHash)
+	m.keyRepo.AssertNumberOfCalls(t, "Update", 2)
+
+	_, err = m.directory.GetEntry(context.Background(), "+5511912345678")
+	assert.NoError(t, err)
+}
+
+func TestPixKeyService_Confirm_AttemptsExceeded(t *testing.T) {
+	service, m := newPixKeyService()
+	expectRegistration(m, "+5511912345678", 0)
+	expectCreate(m)
+
+	pixKey, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyPhone, Key: "+5511912345678"})
+	require.NoError(t, err)
+	pixKey.OTPAttempts = 5
+	m.keyRepo.On("FindByPublicIDForUpdate", "key-public-id").Return(pixKey, nil)
+
+	_, err = service.Confirm(context.Background(), "owner-public-id", "key-public-id", "123456")
+
+	assert.ErrorIs(t, err, services.ErrCodeAttemptsExceeded)
+	m.keyRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+// pendingPhoneKey é uma chave de celular cadastrada e ainda não confirmada.
+func pendingPhoneKey(expiresAt time.Time, attempts int) *models.PixKey {
+	return &models.PixKey{
+		ID: 100, PublicID: "key-public-id", AccountID: 10, Account: ownerAccount(), KeyType: models.KeyPhone, Key: "+5511912345678",
+		Status: models.KeyPendingConfirmation, OTPHash: "old-hash", OTPExpiresAt: &expiresAt, OTPAttempts: attempts,
+	}
+}
+
+func expectPendingKey(m *pixMocks, pending *models.PixKey) {
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+	m.userRepo.On("ListAccountsByUserID", int64(1)).Return([]user_models.Account{ownerAccount()}, nil)
+	m.keyRepo.On("LockAccount", int64(10)).Return(nil)
+	m.keyRepo.On("FindOpenByAccountAndKey", int64(10), "+5511912345678").Return(pending, nil)
+	m.keyRepo.On("CountOpenByAccountID", int64(10)).Return(int64(0), nil)
+}
+
+func TestPixKeyService_Register_ReissuesTheCodeOfAnExpiredPendingKey(t *testing.T) {
+	service, m := newPixKeyService()
+	expectPendingKey(m, pendingPhoneKey(time.Now().Add(-time.Minute), 2))
+	m.keyRepo.On("Update", mock.Anything).Return(nil)
+
+	pixKey, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyPhone, Key: "+5511912345678"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "key-public-id", pixKey.PublicID)
+	assert.Equal(t, models.KeyPendingConfirmation, pixKey.Status)
+	assert.NotEqual(t, "old-hash", pixKey.OTPHash)
+	assert.Zero(t, pixKey.OTPAttempts)
+	assert.True(t, pixKey.OTPExpiresAt.After(time.Now()))
+	assert.Len(t, m.smsSender.Messages(), 1)
+	m.keyRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+// failingSMSSender simula um provedor de SMS fora do ar.
+type failingSMSSender struct{}
+
+func (failingSMSSender) SendSMS(context.Context, *notification.SMSRequest) error {
+	return errors.New("provider unavailable")
+}
+
+func TestPixKeyService_Register_DiscardsTheCodeWhenTheSMSFails(t *testing.T) {
+	m := newPixMocks()
+	service := services.NewPixKeyService(m.transactor, m.keyRepo, m.claimRepo, m.userRepo, m.outboxRepo, failingSMSSender{}, m.directory, goBankISPB)
+	expectPendingKey(m, pendingPhoneKey(time.Now().Add(-time.Minute), 0))
+
+	saved := &models.PixKey{}
+	m.keyRepo.On("Update", mock.Anything).Run(func(args mock.Arguments) {
+		*saved = *args.Get(0).(*models.PixKey)
+	}).Return(nil)
+	m.keyRepo.On("FindByPublicIDForUpdate", "key-public-id").Return(saved, nil)
+
+	_, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyPhone, Key: "+5511912345678"})
+
+	assert.ErrorIs(t, err, services.ErrInternalServer)
+	m.keyRepo.AssertNumberOfCalls(t, "Update", 2)
+	assert.Equal(t, models.KeyPendingConfirmation, saved.Status)
+	assert.Empty(t, saved.OTPHash)
+	assert.Nil(t, saved.OTPExpiresAt)
+
+	// O cliente tenta de novo e recebe outro código, em vez de ErrConfirmationPending.
+	retry, rm := newPixKeyService()
+	expectPendingKey(rm, saved)
+	rm.keyRepo.On("Update", mock.Anything).Return(nil)
+
+	pixKey, err := retry.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyPhone, Key: "+5511912345678"})
+
+	require.NoError(t, err)
+	assert.NotEmpty(t, pixKey.OTPHash)
+	assert.Len(t, rm.smsSender.Messages(), 1)
+}
+
+func TestPixKeyService_Register_PendingKeyWithValidCode(t *testing.T) {
+	service, m := newPixKeyService()
+	expectPendingKey(m, pendingPhoneKey(time.Now().Add(time.Minute), 0))
+
+	_, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyPhone, Key: "+5511912345678"})
+
+	assert.ErrorIs(t, err, services.ErrConfirmationPending)
+	m.keyRepo.AssertNotCalled(t, "Update", mock.Anything)
+	assert.Empty(t, m.smsSender.Messages())
+}
+
+func TestPixKeyService_Register_OpensPortabilityForKeyAtAnotherParticipant(t *testing.T) {
+	service, m := newPixKeyService()
+	require.NoError(t, m.directory.CreateEntry(context.Background(), dict.Entry{
+		Key: "52998224725", KeyType: "CPF", ParticipantISPB: otherISPB, OwnerDocument: "52998224725", OwnerName: "John Doe",
+	}))
+	expectRegistration(m, "52998224725", 0)
+	expectCreate(m)
+	m.claimRepo.On("Create", mock.MatchedBy(func(claim *models.PixClaim) bool {
+		return claim.PixKeyID == 100 && claim.Role == models.RoleClaimer &&
+			claim.Type == dict.ClaimPortability && claim.Status == dict.ClaimOpen
+	})).Return(nil)
+
+	pixKey, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyCPF, Key: "52998224725"})
+
+	require.NoError(t, err)
+	assert.Equal(t, models.KeyClaimPending, pixKey.Status)
+	m.claimRepo.AssertExpectations(t)
+}
+
+func TestPixKeyService_Register_CPFOfAnotherPersonCannotBeClaimed(t *testing.T) {
+	service, m := newPixKeyService()
+	require.NoError(t, m.directory.CreateEntry(context.Background(), dict.Entry{
+		Key: "52998224725", KeyType: "CPF", ParticipantISPB: otherISPB, OwnerDocument: "11144477735",
+	}))
+	expectRegistration(m, "52998224725", 0)
+
+	_, err := service.Register(context.Background(), "owner-public-id", services.RegisterKeyInput{KeyType: models.KeyCPF, Key: "52998224725"})
+
+	assert.ErrorIs(t, err, services.ErrKeyAlreadyRegistered)
+	m.keyRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestPixKeyService_Delete_RemovesTheEntry(t *testing.T) {
+	service, m := newPixKeyService()
+	require.NoError(t, m.directory.CreateEntry(context.Background(), dict.Entry{Key: "52998224725", KeyType: "CPF", ParticipantISPB: goBankISPB}))
+	pixKey := &models.PixKey{ID: 100, PublicID: "key-public-id", Account: ownerAccount(), KeyType: models.KeyCPF, Key: "52998224725", Status: models.KeyActive}
+
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+	m.keyRepo.On("FindByPublicIDForUpdate", "key-public-id").Return(pixKey, nil)
+	m.claimRepo.On("FindOpenByPixKeyID", int64(100)).Return(nil, gorm.ErrRecordNotFound)
+	m.keyRepo.On("Update", pixKey).Return(nil)
+
+	err := service.Delete(context.Background(), "owner-public-id", "key-public-id")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.KeyDeleted, pixKey.Status)
+	assert.NotNil(t, pixKey.RemovedAt)
+	_, err = m.directory.GetEntry(context.Background(), "52998224725")
+	assert.ErrorIs(t, err, dict.ErrEntryNotFound)
+}
+
+func TestPixKeyService_Delete_RejectsKeyWithOpenClaim(t *testing.T) {
+	service, m := newPixKeyService()
+	pixKey := &models.PixKey{ID: 100, PublicID: "key-public-id", Account: ownerAccount(), Key: "john@example.com", Status: models.KeyActive}
+
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+	m.keyRepo.On("FindByPublicIDForUpdate", "key-public-id").Return(pixKey, nil)
+	m.claimRepo.On("FindOpenByPixKeyID", int64(100)).Return(&models.PixClaim{Status: dict.ClaimOpen}, nil)
+
+	err := service.Delete(context.Background(), "owner-public-id", "key-public-id")
+
+	assert.ErrorIs(t, err, services.ErrClaimInProgress)
+	m.keyRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestPixKeyService_Delete_KeyOfAnotherUser(t *testing.T) {
+	service, m := newPixKeyService()
+	account := ownerAccount()
+	account.UserID = 2
+
+	m.userRepo.On("FindByPublicID", "owner-public-id").Return(keyOwner(), nil)
+	m.keyRepo.On("FindByPublicIDForUpdate", "key-public-id").Return(&models.PixKey{Account: account, Status: models.KeyActive}, nil)
+
+	err := service.Delete(context.Background(), "owner-public-id", "key-public-id")
+
+	assert.ErrorIs(t, err, services.ErrKeyNotFound)
+}
+
+func TestPixKeyService_Lookup_MasksOwnerData(t *testing.T) {
+	service, m := newPixKeyService()
+	require.NoError(t, m.directory.CreateEntry(context.Background(), dict.Entry{
+		Key: "john@example.com", KeyType: "EMAIL", ParticipantISPB: goBankISPB, Branch: "0001", AccountNumber: "122504005",
+		OwnerType: dict.NaturalPerson, OwnerDocument: "52998224725", OwnerName: "John Doe",
+	}))
+	account := ownerAccount()
+	account.User = *keyOwner()
+	m.userRepo.On("FindAccountByNumber", "0001", "122504005").Return(&account, nil)
+
+	owner, err := service.Lookup(context.Background(), " John@Example.com ")
+
+	require.NoError(t, err)
+	assert.Equal(t, models.KeyEmail, owner.KeyType)
+	assert.Equal(t, "John Doe", owner.OwnerName)
+	assert.Equal(t, "***.982.247-**", owner.OwnerDocument)
+	assert.Equal(t, "*****4005", owner.AccountNumber)
+	assert.Equal(t, "owner-account", owner.AccountPublicID)
+
+	_, err = service.Lookup(context.Background(), "jane@example.com")
+	assert.ErrorIs(t, err, services.ErrKeyNotFound)
+}
+
+func TestPixKeyService_Lookup_InactiveOwnerIsNotFound(t *testing.T) {
+	service, m := newPixKeyService()
+	require.NoError(t, m.directory.CreateEntry(context.Background(), dict.Entry{
+		Key: "john@example.com", KeyType: "EMAIL", ParticipantISPB: goBankISPB, Branch: "0001", AccountNumber: "122504005",
+		OwnerType: dict.NaturalPerson, OwnerDocument: "52998224725", OwnerName: "John Doe",
+	}))
+	account := ownerAccount()
+	account.User = *keyOwner()
+	account.User.Status = user_models.StatusInactive
+	m.userRepo.On("FindAccountByNumber", "0001", "122504005").Return(&account, nil)
+
+	owner, err := service.Lookup(context.Background(), "john@example.com")
+
+	assert.ErrorIs(t, err, services.ErrKeyNotFound)
+	assert.Nil(t, owner)
+}
+
+func TestPixKeyService_RemoveAllByUser(t *testing.T) {
+	service, m := newPixKeyService()
+	ctx := context.Background()
+
+	// Chave ativa no GoBank, com uma reivindicação de posse aberta por outro banco.
+	require.NoError(t, m.directory.CreateEntry(ctx, dict.Entry{
+		Key: "john@example.com", KeyType: "EMAIL", ParticipantISPB: goBankISPB, OwnerDocument: "52998224725",
+	}))
+	claim, err := m.directory.CreateClaim(ctx, dict.ClaimOwnership, dict.Entry{
+		Key: "john@example.com", KeyType: "EMAIL", ParticipantISPB: otherISPB, OwnerDocument: "11144477735",
+	})
+	require.NoError(t, err)
+	require.NoError(t, m.directory.CreateEntry(ctx, dict.Entry{
+		Key: "52998224725", KeyType: "CPF", ParticipantISPB: goBankISPB, OwnerDocument: "52998224725",
+	}))
+
+	emailKey := models.PixKey{ID: 100, AccountID: 10, KeyType: models.KeyEmail, Key: "john@example.com", Status: models.KeyActive}
+	cpfKey := models.PixKey{ID: 101, AccountID: 10, KeyType: models.KeyCPF, Key: "52998224725", Status: models.KeyActive}
+	pendingKey := models.PixKey{ID: 102, AccountID: 10, KeyType: models.KeyPhone, Key: "+5511912345678", Status: models.KeyPendingConfirmation}
+	localClaim := &models.PixClaim{PixKeyID: 100, DICTClaimID: claim.ID, Role: models.RoleDonor, Status: dict.ClaimOpen}
+
+	m.userRepo.On("ListAccountsByUserID", int64(1)).Return([]user_models.Account{ownerAccount()}, nil)
+	m.keyRepo.On("ListOpenByAccountIDsForUpdate", []int64{10}).Return([]models.PixKey{emailKey, cpfKey, pendingKey}, nil)
+	m.claimRepo.On("FindOpenByPixKeyID", int64(100)).Return(localClaim, nil)
+	m.claimRepo.On("FindOpenByPixKeyID", mock.Anything).Return(nil, gorm.ErrRecordNotFound)
+	m.claimRepo.On("Update", localClaim).Return(nil)
+	var removed []models.PixKey
+	m.keyRepo.On("Update", mock.Anything).Run(func(args mock.Arguments) {
+		removed = append(removed, *args.Get(0).(*models.PixKey))
+	}).Return(nil)
+
+	cleanup, err := service.WithTx(nil).RemoveAllByUser(ctx, keyOwner())
+
+	require.NoError(t, err)
+	assert.Equal(t, dict.ClaimCancelled, localClaim.Status)
+	require.Len(t, removed, 3)
+	for _, pixKey := range removed {
+		assert.Equal(t, models.KeyDeleted, pixKey.Status)
+		assert.NotNil(t, pixKey.RemovedAt)
+	}
+
+	// O DICT só é alterado depois do commit.
+	_, err = m.directory.GetEntry(ctx, "john@example.com")
+	require.NoError(t, err, "the DICT must not change inside the transaction")
+
+	service.CleanUpDirectory(ctx, cleanup)
+
+	claims, err := m.directory.ListClaims(ctx, goBankISPB, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+	assert.Equal(t, dict.ClaimCancelled, claims[0].Status)
+	_, err = m.directory.GetEntry(ctx, "john@example.com")
+	assert.ErrorIs(t, err, dict.ErrEntryNotFound)
+	_, err = m.directory.GetEntry(ctx, "52998224725")
+	assert.ErrorIs(t, err, dict.ErrEntryNotFound)
+}
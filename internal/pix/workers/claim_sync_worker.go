@@ -0,0 +1,71 @@
+// Package workers defines the background jobs of the Pix keys.
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/services"
+)
+
+const DefaultClaimSyncInterval = 1 * time.Minute
+
+// ClaimSyncWorker acompanha periodicamente as reivindicações do DICT que envolvem o GoBank: avisa
+// os titulares de chaves reivindicadas e aplica às chaves locais as reivindicações encerradas,
+// inclusive as resolvidas pelo fim do prazo.
+type ClaimSyncWorker struct {
+	service  services.PixClaimService
+	interval time.Duration
+	since    time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewClaimSyncWorker(service services.PixClaimService, interval time.Duration) *ClaimSyncWorker {
+	return &ClaimSyncWorker{service: service, interval: interval}
+}
+
+// Start inicia o worker em background. Ele roda uma vez imediatamente, trazendo todas as
+// reivindicações, e depois a cada intervalo só as alteradas desde a última sincronização.
+func (w *ClaimSyncWorker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			if err := w.RunOnce(ctx); err != nil {
+				log.Printf("Error syncing Pix claims: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop sinaliza o fim do worker e aguarda a sincronização em andamento terminar.
+func (w *ClaimSyncWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// RunOnce aplica as reivindicações alteradas desde a última sincronização. Em caso de erro, a
+// próxima execução recomeça da última reivindicação aplicada.
+func (w *ClaimSyncWorker) RunOnce(ctx context.Context) error {
+	since, err := w.service.SyncClaims(ctx, w.since)
+	w.since = since
+	return err
+}
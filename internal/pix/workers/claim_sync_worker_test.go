@@ -0,0 +1,38 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/workers"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClaimSyncWorker_RunOnce_ResumesFromLastSync(t *testing.T) {
+	mockService := new(mocks.MockPixClaimService)
+	worker := workers.NewClaimSyncWorker(mockService, time.Minute)
+	lastChange := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	mockService.On("SyncClaims", time.Time{}).Return(lastChange, nil).Once()
+	mockService.On("SyncClaims", lastChange).Return(lastChange, nil).Once()
+
+	assert.NoError(t, worker.RunOnce(context.Background()))
+	assert.NoError(t, worker.RunOnce(context.Background()))
+	mockService.AssertExpectations(t)
+}
+
+func TestClaimSyncWorker_RunOnce_KeepsProgressOnError(t *testing.T) {
+	mockService := new(mocks.MockPixClaimService)
+	worker := workers.NewClaimSyncWorker(mockService, time.Minute)
+	applied := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	mockService.On("SyncClaims", time.Time{}).Return(applied, errors.New("db error")).Once()
+	mockService.On("SyncClaims", applied).Return(applied, nil).Once()
+
+	assert.Error(t, worker.RunOnce(context.Background()))
+	assert.NoError(t, worker.RunOnce(context.Background()))
+	mockService.AssertExpectations(t)
+}
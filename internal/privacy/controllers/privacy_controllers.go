@@ -0,0 +1,133 @@
+// Package controllers define the HTTP handlers for the LGPD data subject rights.
+package controllers
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/http_helpers"
+)
+
+type DeletionRequest struct {
+	Password string `json:"password" binding:"required"`
+}
+
+type PrivacyController struct {
+	exportService   services.DataExportService
+	deletionService services.DeletionService
+}
+
+func NewPrivacyController(exportService services.DataExportService, deletionService services.DeletionService) *PrivacyController {
+	return &PrivacyController{exportService: exportService, deletionService: deletionService}
+}
+
+// ExportData devolve os dados do titular em JSON ou, com ?format=zip, em um arquivo ZIP com o
+// mesmo JSON dentro.
+func (ctrl *PrivacyController) ExportData(c *gin.Context) {
+	format := c.DefaultQuery("format", "json")
+	if format != "json" && format != "zip" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "O parâmetro 'format' deve ser um dos valores: json, zip."})
+		return
+	}
+
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	export, err := ctrl.exportService.Export(c.Request.Context(), userPublicID)
+	if err != nil {
+		if errors.Is(err, services.ErrUserNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+
+	c.Header("Cache-Control", "no-store")
+	if format == "json" {
+		c.JSON(http.StatusOK, export)
+		return
+	}
+
+	archive, err := zipExport(export)
+	if err != nil {
+		log.Printf("Error building data export archive: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+		return
+	}
+
+	filename := fmt.Sprintf("gobank-dados-%s.zip", export.GeneratedAt.Format(time.DateOnly))
+	c.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Data(http.StatusOK, "application/zip", archive)
+}
+
+func (ctrl *PrivacyController) RequestDeletion(c *gin.Context) {
+	var req DeletionRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	request, err := ctrl.deletionService.RequestDeletion(c.Request.Context(), userPublicID, req.Password, services.ClientInfo{
+		UserAgent: c.Request.UserAgent(),
+		IPAddress: c.ClientIP(),
+	})
+	if err == nil {
+		c.JSON(http.StatusAccepted, gin.H{
+			"id":             request.PublicID,
+			"message":        "Sua conta foi desativada. Os dados pessoais serão anonimizados ao fim do prazo legal de guarda.",
+			"requestedAt":    request.RequestedAt,
+			"anonymizeAfter": request.AnonymizeAfter,
+		})
+		return
+	}
+
+	if errors.Is(err, services.ErrInvalidPassword) {
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrDeletionAlreadyRequested) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+}
+
+func zipExport(export *services.DataExport) ([]byte, error) {
+	content, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	var buffer bytes.Buffer
+	writer := zip.NewWriter(&buffer)
+
+	file, err := writer.Create("dados.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(content); err != nil {
+		return nil, err
+	}
+
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
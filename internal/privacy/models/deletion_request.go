@@ -0,0 +1,46 @@
+// Package models define the data structures for LGPD data subject requests.
+package models
+
+import (
+	"time"
+
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// DeletionStatus define os possíveis status de um pedido de exclusão.
+type DeletionStatus string
+
+const (
+	// DeletionScheduled indica que o usuário já foi desativado e aguarda o fim do prazo de guarda.
+	DeletionScheduled DeletionStatus = "SCHEDULED"
+	// DeletionCompleted indica que os dados pessoais já foram anonimizados.
+	DeletionCompleted DeletionStatus = "COMPLETED"
+)
+
+// DeletionRequest é o pedido de exclusão de dados feito pelo titular. Cada usuário tem no máximo
+// um pedido, já que o usuário é desativado ao fazê-lo.
+type DeletionRequest struct {
+	ID             int64            `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID       string           `gorm:"type:varchar(26);unique;not null"`
+	UserID         int64            `gorm:"unique;not null"`
+	User           user_models.User `gorm:"foreignKey:UserID"`
+	Status         DeletionStatus   `gorm:"type:varchar(20);not null;default:'SCHEDULED'"`
+	IPAddress      string           `gorm:"type:varchar(45);not null"`
+	UserAgent      string           `gorm:"type:varchar(512);not null"`
+	RequestedAt    time.Time        `gorm:"not null"`
+	AnonymizeAfter time.Time        `gorm:"not null"`
+	CompletedAt    *time.Time       `gorm:"column:completed_at"`
+	CreatedAt      time.Time        `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time        `gorm:"autoUpdateTime"`
+}
+
+func (DeletionRequest) TableName() string {
+	return "user.deletion_requests"
+}
+
+func (d *DeletionRequest) BeforeCreate(_ *gorm.DB) (err error) {
+	d.PublicID = ulid.Make().String()
+	return
+}
@@ -0,0 +1,76 @@
+// Package repositories define the data access layer for LGPD data subject requests.
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type DeletionRequestRepository interface {
+	Create(ctx context.Context, request *models.DeletionRequest) error
+	FindByUserID(ctx context.Context, userID int64) (*models.DeletionRequest, error)
+	ListDueForUpdate(ctx context.Context, now time.Time, limit int) ([]models.DeletionRequest, error)
+	Update(ctx context.Context, request *models.DeletionRequest) error
+	WithTx(tx *gorm.DB) DeletionRequestRepository
+}
+
+type deletionRequestRepository struct {
+	db *gorm.DB
+}
+
+func NewDeletionRequestRepository(db *gorm.DB) DeletionRequestRepository {
+	return &deletionRequestRepository{db: db}
+}
+
+func (r *deletionRequestRepository) Create(ctx context.Context, request *models.DeletionRequest) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Omit(clause.Associations).Create(request).Error
+}
+
+func (r *deletionRequestRepository) FindByUserID(ctx context.Context, userID int64) (*models.DeletionRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var request models.DeletionRequest
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&request)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &request, nil
+}
+
+// ListDueForUpdate bloqueia até limit pedidos cujo prazo de guarda terminou, com o usuário
+// carregado. Pedidos bloqueados por outra instância são ignorados e ficam para a próxima execução.
+func (r *deletionRequestRepository) ListDueForUpdate(ctx context.Context, now time.Time, limit int) ([]models.DeletionRequest, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var requests []models.DeletionRequest
+	result := r.db.WithContext(ctx).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Preload("User").
+		Where("status = ? AND anonymize_after <= ?", models.DeletionScheduled, now).
+		Order("anonymize_after, id").
+		Limit(limit).
+		Find(&requests)
+	return requests, result.Error
+}
+
+func (r *deletionRequestRepository) Update(ctx context.Context, request *models.DeletionRequest) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Omit(clause.Associations).Save(request).Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *deletionRequestRepository) WithTx(tx *gorm.DB) DeletionRequestRepository {
+	return &deletionRequestRepository{db: tx}
+}
@@ -0,0 +1,248 @@
+// Package services define the LGPD data subject rights: data export and deletion.
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	consent_repositories "github.com/high-effort-low-stress/go-bank-api/internal/consents/repositories"
+	onboarding_repositories "github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound   = errors.New("usuário não encontrado")
+	ErrInternalServer = errors.New("ocorreu um erro inesperado")
+)
+
+// DataExport é a cópia dos dados do titular (LGPD, art. 18, II e V). Segredos como o hash da
+// senha e os tokens de verificação nunca são exportados.
+type DataExport struct {
+	GeneratedAt        time.Time                   `json:"generatedAt"`
+	User               ExportedUser                `json:"user"`
+	Accounts           []ExportedAccount           `json:"accounts"`
+	OnboardingRequests []ExportedOnboardingRequest `json:"onboardingRequests"`
+	Consents           []ExportedConsent           `json:"consents"`
+}
+
+type ExportedUser struct {
+	ID                 string                   `json:"id"`
+	FullName           string                   `json:"fullName"`
+	Email              string                   `json:"email"`
+	DocumentNumber     string                   `json:"documentNumber"`
+	CustomerType       user_models.CustomerType `json:"customerType"`
+	TradeName          string                   `json:"tradeName,omitempty"`
+	BirthDate          string                   `json:"birthDate,omitempty"`
+	MotherName         string                   `json:"motherName,omitempty"`
+	PoliticallyExposed bool                     `json:"politicallyExposed"`
+	PhoneNumber        string                   `json:"phoneNumber"`
+	Address            ExportedAddress          `json:"address"`
+	Status             user_models.UserStatus   `json:"status"`
+	CreatedAt          time.Time                `json:"createdAt"`
+	DeactivatedAt      *time.Time               `json:"deactivatedAt,omitempty"`
+}
+
+type ExportedAddress struct {
+	CEP          string `json:"cep"`
+	Street       string `json:"street"`
+	Number       string `json:"number"`
+	Complement   string `json:"complement,omitempty"`
+	Neighborhood string `json:"neighborhood"`
+	City         string `json:"city"`
+	State        string `json:"state"`
+}
+
+type ExportedAccount struct {
+	ID            string    `json:"id"`
+	AgencyNumber  string    `json:"agencyNumber"`
+	AccountNumber string    `json:"accountNumber"`
+	CreatedAt     time.Time `json:"createdAt"`
+}
+
+type ExportedOnboardingRequest struct {
+	ID          string                    `json:"id"`
+	FullName    string                    `json:"fullName"`
+	Email       string                    `json:"email"`
+	PhoneNumber string                    `json:"phoneNumber"`
+	Status      string                    `json:"status"`
+	CreatedAt   time.Time                 `json:"createdAt"`
+	History     []ExportedOnboardingEvent `json:"history"`
+}
+
+type ExportedOnboardingEvent struct {
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason,omitempty"`
+	At         time.Time `json:"at"`
+}
+
+type ExportedConsent struct {
+	DocumentKind    string    `json:"documentKind"`
+	DocumentVersion string    `json:"documentVersion"`
+	DocumentURL     string    `json:"documentUrl"`
+	Source          string    `json:"source"`
+	IPAddress       string    `json:"ipAddress"`
+	UserAgent       string    `json:"userAgent"`
+	AcceptedAt      time.Time `json:"acceptedAt"`
+}
+
+type DataExportService interface {
+	Export(ctx context.Context, userPublicID string) (*DataExport, error)
+}
+
+type dataExportService struct {
+	userRepo       user_repositories.UserRepository
+	onboardingRepo onboarding_repositories.OnboardingRequestRepository
+	consentRepo    consent_repositories.ConsentRepository
+}
+
+func NewDataExportService(
+	userRepo user_repositories.UserRepository,
+	onboardingRepo onboarding_repositories.OnboardingRequestRepository,
+	consentRepo consent_repositories.ConsentRepository,
+) DataExportService {
+	return &dataExportService{userRepo: userRepo, onboardingRepo: onboardingRepo, consentRepo: consentRepo}
+}
+
+// Export reúne o cadastro, as contas, as solicitações de onboarding com o histórico de status e
+// os consentimentos do usuário. As solicitações são encontradas pelo CPF/CNPJ do cadastro.
+func (s *dataExportService) Export(ctx context.Context, userPublicID string) (*DataExport, error) {
+	user, err := s.userRepo.FindByPublicID(ctx, userPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		log.Printf("Error finding user by public ID: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	accounts, err := s.userRepo.ListAccountsByUserID(ctx, user.ID)
+	if err != nil {
+		log.Printf("Error listing accounts for data export: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	onboardingRequests, err := s.exportOnboardingRequests(ctx, user.DocumentNumber)
+	if err != nil {
+		return nil, err
+	}
+
+	consents, err := s.consentRepo.ListByUserID(ctx, user.ID)
+	if err != nil {
+		log.Printf("Error listing consents for data export: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	export := &DataExport{
+		GeneratedAt:        time.Now(),
+		User:               exportUser(user),
+		Accounts:           make([]ExportedAccount, 0, len(accounts)),
+		OnboardingRequests: onboardingRequests,
+		Consents:           make([]ExportedConsent, 0, len(consents)),
+	}
+	for _, account := range accounts {
+		export.Accounts = append(export.Accounts, ExportedAccount{
+			ID:            account.PublicID,
+			AgencyNumber:  account.AgencyNumber,
+			AccountNumber: account.FormattedAccountNumber(),
+			CreatedAt:     account.CreatedAt,
+		})
+	}
+	for _, consent := range consents {
+		export.Consents = append(export.Consents, ExportedConsent{
+			DocumentKind:    string(consent.LegalDocument.Kind),
+			DocumentVersion: consent.LegalDocument.Version,
+			DocumentURL:     consent.LegalDocument.URL,
+			Source:          string(consent.Source),
+			IPAddress:       consent.IPAddress,
+			UserAgent:       consent.UserAgent,
+			AcceptedAt:      consent.AcceptedAt,
+		})
+	}
+
+	return export, nil
+}
+
+func (s *dataExportService) exportOnboardingRequests(ctx context.Context, document string) ([]ExportedOnboardingRequest, error) {
+	requests, err := s.onboardingRepo.ListByDocument(ctx, document)
+	if err != nil {
+		log.Printf("Error listing onboarding requests for data export: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	requestIDs := make([]int64, 0, len(requests))
+	for _, request := range requests {
+		requestIDs = append(requestIDs, request.ID)
+	}
+
+	events, err := s.onboardingRepo.ListEventsByRequestIDs(ctx, requestIDs)
+	if err != nil {
+		log.Printf("Error listing onboarding events for data export: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	history := make(map[int64][]ExportedOnboardingEvent, len(requests))
+	for _, event := range events {
+		history[event.OnboardingRequestID] = append(history[event.OnboardingRequestID], ExportedOnboardingEvent{
+			FromStatus: string(event.FromStatus),
+			ToStatus:   string(event.ToStatus),
+			Actor:      event.Actor,
+			Reason:     event.Reason,
+			At:         event.CreatedAt,
+		})
+	}
+
+	exported := make([]ExportedOnboardingRequest, 0, len(requests))
+	for _, request := range requests {
+		requestHistory := history[request.ID]
+		if requestHistory == nil {
+			requestHistory = []ExportedOnboardingEvent{}
+		}
+		exported = append(exported, ExportedOnboardingRequest{
+			ID:          request.PublicID,
+			FullName:    request.FullName,
+			Email:       request.Email,
+			PhoneNumber: request.PhoneNumber,
+			Status:      string(request.Status),
+			CreatedAt:   request.CreatedAt,
+			History:     requestHistory,
+		})
+	}
+
+	return exported, nil
+}
+
+func exportUser(user *user_models.User) ExportedUser {
+	exported := ExportedUser{
+		ID:                 user.PublicID,
+		FullName:           user.FullName,
+		Email:              user.Email,
+		DocumentNumber:     user.DocumentNumber,
+		CustomerType:       user.CustomerType,
+		TradeName:          user.TradeName,
+		MotherName:         user.MotherName,
+		PoliticallyExposed: user.PoliticallyExposed,
+		PhoneNumber:        user.PhoneNumber,
+		Address: ExportedAddress{
+			CEP:          user.Address.CEP,
+			Street:       user.Address.Street,
+			Number:       user.Address.Number,
+			Complement:   user.Address.Complement,
+			Neighborhood: user.Address.Neighborhood,
+			City:         user.Address.City,
+			State:        user.Address.State,
+		},
+		Status:        user.Status,
+		CreatedAt:     user.CreatedAt,
+		DeactivatedAt: user.DeactivatedAt,
+	}
+	if user.BirthDate != nil {
+		exported.BirthDate = user.BirthDate.Format(time.DateOnly)
+	}
+	return exported
+}
@@ -0,0 +1,76 @@
+package services_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	consent_models "github.com/high-effort-low-stress/go-bank-api/internal/consents/models"
+	onboarding_models "github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestDataExportService_Export(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	onboardingRepo := new(mocks.MockOnboardingRepository)
+	consentRepo := new(mocks.MockConsentRepository)
+	service := services.NewDataExportService(userRepo, onboardingRepo, consentRepo)
+
+	birthDate := time.Date(1990, time.May, 10, 0, 0, 0, 0, time.UTC)
+	user := &user_models.User{
+		ID:             42,
+		PublicID:       "01K7ZA0000000000000000USER",
+		FullName:       "John Doe",
+		Email:          "john@example.com",
+		DocumentNumber: "12345678909",
+		BirthDate:      &birthDate,
+		PasswordHash:   "$argon2id$secret",
+	}
+
+	userRepo.On("FindByPublicID", user.PublicID).Return(user, nil)
+	userRepo.On("ListAccountsByUserID", int64(42)).Return([]user_models.Account{{PublicID: "ACCOUNT", AgencyNumber: "0001", AccountNumber: "122504005"}}, nil)
+	onboardingRepo.On("ListByDocument", "12345678909").Return([]onboarding_models.OnboardingRequest{
+		{ID: 6, PublicID: "EXPIRED", Status: onboarding_models.StatusExpired, VerificationTokenHash: "token-hash"},
+		{ID: 7, PublicID: "COMPLETED", Status: onboarding_models.StatusCompleted},
+	}, nil)
+	onboardingRepo.On("ListEventsByRequestIDs", []int64{6, 7}).Return([]onboarding_models.OnboardingRequestEvent{
+		{OnboardingRequestID: 7, FromStatus: onboarding_models.StatusPending, ToStatus: onboarding_models.StatusVerified, Actor: onboarding_models.ActorCustomer},
+		{OnboardingRequestID: 7, FromStatus: onboarding_models.StatusApproved, ToStatus: onboarding_models.StatusCompleted, Actor: onboarding_models.ActorCustomer},
+	}, nil)
+	consentRepo.On("ListByUserID", int64(42)).Return([]consent_models.Consent{{
+		LegalDocument: consent_models.LegalDocument{Kind: consent_models.KindTermsOfUse, Version: "1.0"},
+		Source:        consent_models.SourceOnboarding,
+	}}, nil)
+
+	export, err := service.Export(context.Background(), user.PublicID)
+
+	require.NoError(t, err)
+	assert.Equal(t, "1990-05-10", export.User.BirthDate)
+	assert.Equal(t, "12250400-5", export.Accounts[0].AccountNumber)
+	require.Len(t, export.OnboardingRequests, 2)
+	assert.Empty(t, export.OnboardingRequests[0].History)
+	assert.Len(t, export.OnboardingRequests[1].History, 2)
+	assert.Equal(t, "TERMS_OF_USE", export.Consents[0].DocumentKind)
+
+	content, err := json.Marshal(export)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "argon2id", "the password hash must never be exported")
+	assert.NotContains(t, string(content), "token-hash", "verification tokens must never be exported")
+}
+
+func TestDataExportService_Export_UserNotFound(t *testing.T) {
+	userRepo := new(mocks.MockUserRepository)
+	service := services.NewDataExportService(userRepo, nil, nil)
+
+	userRepo.On("FindByPublicID", "missing").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.Export(context.Background(), "missing")
+
+	assert.ErrorIs(t, err, services.ErrUserNotFound)
+}
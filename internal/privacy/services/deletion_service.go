@@ -0,0 +1,341 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	auth_repositories "github.com/high-effort-low-stress/go-bank-api/internal/auth/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	idempotency_repositories "github.com/high-effort-low-stress/go-bank-api/internal/idempotency/repositories"
+	onboarding_repositories "github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
+	outbox_repositories "github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	pix_repositories "github.com/high-effort-low-stress/go-bank-api/internal/pix/repositories"
+	pix_services "github.com/high-effort-low-stress/go-bank-api/internal/pix/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/storage"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrInvalidPassword          = errors.New("senha incorreta")
+	ErrDeletionAlreadyRequested = errors.New("a exclusão dos dados já foi solicitada")
+)
+
+// DefaultRetentionPeriod é o prazo de guarda dos dados cadastrais após o fim do relacionamento,
+// exigido pela legislação de prevenção à lavagem de dinheiro (Lei 9.613/98, art. 10).
+const DefaultRetentionPeriod = 5 * 365 * 24 * time.Hour
+
+// RetentionPeriodFromEnv lê PII_RETENTION_PERIOD (opcional, ex: "43800h").
+func RetentionPeriodFromEnv() (time.Duration, error) {
+	raw := os.Getenv("PII_RETENTION_PERIOD")
+	if raw == "" {
+		return DefaultRetentionPeriod, nil
+	}
+
+	period, err := time.ParseDuration(raw)
+	if err != nil || period < 0 {
+		return 0, fmt.Errorf("invalid PII_RETENTION_PERIOD %q", raw)
+	}
+	return period, nil
+}
+
+// ClientInfo identifica o dispositivo de onde partiu o pedido de exclusão.
+type ClientInfo struct {
+	UserAgent string
+	IPAddress string
+}
+
+type DeletionService interface {
+	RequestDeletion(ctx context.Context, userPublicID, password string, client ClientInfo) (*models.DeletionRequest, error)
+	AnonymizeDue(ctx context.Context, now time.Time, batchSize int) (int, error)
+}
+
+type deletionService struct {
+	transactor         database.Transactor
+	deletionRepo       repositories.DeletionRequestRepository
+	userRepo           user_repositories.UserRepository
+	onboardingRepo     onboarding_repositories.OnboardingRequestRepository
+	kycDocumentRepo    onboarding_repositories.KYCDocumentRepository
+	representativeRepo onboarding_repositories.LegalRepresentativeRepository
+	sessionRepo        auth_repositories.SessionRepository
+	outboxRepo         outbox_repositories.OutboxRepository
+	idempotencyRepo    idempotency_repositories.IdempotencyKeyRepository
+	pixKeyRepo         pix_repositories.PixKeyRepository
+	pixKeyService      pix_services.PixKeyService
+	blobStore          storage.BlobStore
+	retentionPeriod    time.Duration
+}
+
+func NewDeletionService(
+	transactor database.Transactor,
+	deletionRepo repositories.DeletionRequestRepository,
+	userRepo user_repositories.UserRepository,
+	onboardingRepo onboarding_repositories.OnboardingRequestRepository,
+	kycDocumentRepo onboarding_repositories.KYCDocumentRepository,
+	representativeRepo onboarding_repositories.LegalRepresentativeRepository,
+	sessionRepo auth_repositories.SessionRepository,
+	outboxRepo outbox_repositories.OutboxRepository,
+	idempotencyRepo idempotency_repositories.IdempotencyKeyRepository,
+	pixKeyRepo pix_repositories.PixKeyRepository,
+	pixKeyService pix_services.PixKeyService,
+	blobStore storage.BlobStore,
+	retentionPeriod time.Duration,
+) DeletionService {
+	return &deletionService{
+		transactor:         transactor,
+		deletionRepo:       deletionRepo,
+		userRepo:           userRepo,
+		onboardingRepo:     onboardingRepo,
+		kycDocumentRepo:    kycDocumentRepo,
+		representativeRepo: representativeRepo,
+		sessionRepo:        sessionRepo,
+		outboxRepo:         outboxRepo,
+		idempotencyRepo:    idempotencyRepo,
+		pixKeyRepo:         pixKeyRepo,
+		pixKeyService:      pixKeyService,
+		blobStore:          blobStore,
+		retentionPeriod:    retentionPeriod,
+	}
+}
+
+// RequestDeletion desativa o usuário, exclui suas chaves Pix (do DICT, após o commit), encerra suas
+// sessões e agenda a anonimização para o fim do prazo de guarda. A senha é exigida para que um access token vazado não encerre a conta.
+func (s *deletionService) RequestDeletion(ctx context.Context, userPublicID, password string, client ClientInfo) (*models.DeletionRequest, error) {
+	user, err := s.userRepo.FindByPublicID(ctx, userPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		log.Printf("Error finding user by public ID: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	match, err := crypto.VerifyPassword(password, user.PasswordHash)
+	if err != nil {
+		log.Printf("Error verifying password hash for user %s: %v", user.PublicID, err)
+		return nil, ErrInternalServer
+	}
+	if !match {
+		return nil, ErrInvalidPassword
+	}
+
+	now := time.Now()
+	request := &models.DeletionRequest{
+		UserID:         user.ID,
+		Status:         models.DeletionScheduled,
+		IPAddress:      client.IPAddress,
+		UserAgent:      truncate(client.UserAgent, 512),
+		RequestedAt:    now,
+		AnonymizeAfter: now.Add(s.retentionPeriod),
+	}
+
+	var requestErr error
+	var cleanup *pix_services.DirectoryCleanup
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		cleanup, requestErr = s.scheduleWithinTx(ctx, tx, user, request, now)
+		return requestErr
+	})
+	if requestErr != nil {
+		return nil, requestErr
+	}
+	if err != nil {
+		log.Printf("Error committing deletion request: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	s.pixKeyService.CleanUpDirectory(ctx, cleanup)
+
+	// O refresh já recusa usuários inativos; revogar as sessões só as tira da listagem.
+	if err := s.sessionRepo.RevokeAllByUserID(ctx, user.ID); err != nil {
+		log.Printf("Error revoking sessions of user %s after deletion request: %v", user.PublicID, err)
+	}
+
+	log.Printf("User %s requested data deletion, anonymization scheduled for %s", user.PublicID, request.AnonymizeAfter.Format(time.RFC3339))
+	return request, nil
+}
+
+func (s *deletionService) scheduleWithinTx(ctx context.Context, tx *gorm.DB, user *user_models.User, request *models.DeletionRequest, now time.Time) (*pix_services.DirectoryCleanup, error) {
+	deletionRepo := s.deletionRepo.WithTx(tx)
+
+	_, err := deletionRepo.FindByUserID(ctx, user.ID)
+	if err == nil {
+		return nil, ErrDeletionAlreadyRequested
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		log.Printf("Error finding deletion request: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	user.Deactivate(now)
+	if err := s.userRepo.WithTx(tx).Update(ctx, user); err != nil {
+		log.Printf("Error deactivating user: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	cleanup, err := s.pixKeyService.WithTx(tx).RemoveAllByUser(ctx, user)
+	if err != nil {
+		log.Printf("Error removing Pix keys of user %s: %v", user.PublicID, err)
+		return nil, ErrInternalServer
+	}
+
+	if err := deletionRepo.Create(ctx, request); err != nil {
+		log.Printf("Error creating deletion request: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	return cleanup, nil
+}
+
+// AnonymizeDue anonimiza um lote de pedidos cujo prazo de guarda terminou: o cadastro do usuário,
+// suas solicitações de onboarding e os documentos do KYC, as indicações como representante legal,
+// o IP e o user agent das sessões e o valor das chaves Pix, e apaga os e-mails do outbox e as
+// respostas guardadas pela idempotência. Contas e consentimentos são mantidos. Os arquivos do KYC
+// e as chaves Pix no DICT só são apagados depois do commit.
+func (s *deletionService) AnonymizeDue(ctx context.Context, now time.Time, batchSize int) (int, error) {
+	var anonymized int
+	var blobKeys []string
+	var cleanups []*pix_services.DirectoryCleanup
+
+	err := s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		requests, err := s.deletionRepo.WithTx(tx).ListDueForUpdate(ctx, now, batchSize)
+		if err != nil {
+			return err
+		}
+
+		for i := range requests {
+			keys, cleanup, err := s.anonymizeWithinTx(ctx, tx, &requests[i], now)
+			if err != nil {
+				return err
+			}
+			blobKeys = append(blobKeys, keys...)
+			cleanups = append(cleanups, cleanup)
+		}
+
+		anonymized = len(requests)
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, cleanup := range cleanups {
+		s.pixKeyService.CleanUpDirectory(ctx, cleanup)
+	}
+	for _, key := range blobKeys {
+		if err := s.blobStore.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrBlobNotFound) {
+			log.Printf("Error deleting KYC blob %s of an anonymized user: %v", key, err)
+		}
+	}
+
+	return anonymized, nil
+}
+
+func (s *deletionService) anonymizeWithinTx(ctx context.Context, tx *gorm.DB, request *models.DeletionRequest, now time.Time) ([]string, *pix_services.DirectoryCleanup, error) {
+	onboardingRepo := s.onboardingRepo.WithTx(tx)
+	kycDocumentRepo := s.kycDocumentRepo.WithTx(tx)
+	user := &request.User
+
+	onboardingRequests, err := onboardingRepo.ListByDocument(ctx, user.DocumentNumber)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// O e-mail pode ter mudado desde o onboarding; as mensagens de cada endereço usado são apagadas.
+	recipients := map[string]bool{user.Email: true}
+
+	var blobKeys []string
+	for i := range onboardingRequests {
+		onboardingRequest := &onboardingRequests[i]
+		recipients[onboardingRequest.Email] = true
+
+		documents, err := kycDocumentRepo.ListByRequest(ctx, onboardingRequest.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, document := range documents {
+			blobKeys = append(blobKeys, document.BlobKey)
+		}
+		if err := kycDocumentRepo.DeleteByRequest(ctx, onboardingRequest.ID); err != nil {
+			return nil, nil, err
+		}
+
+		onboardingRequest.Anonymize()
+		if err := onboardingRepo.Update(ctx, onboardingRequest); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	representatives, err := s.representativeRepo.WithTx(tx).ListByUser(ctx, user.ID)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range representatives {
+		representatives[i].Anonymize()
+		if err := s.representativeRepo.WithTx(tx).Update(ctx, &representatives[i]); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for recipient := range recipients {
+		if err := s.outboxRepo.WithTx(tx).DeleteByRecipient(ctx, recipient); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := s.sessionRepo.WithTx(tx).AnonymizeByUserID(ctx, user.ID); err != nil {
+		return nil, nil, err
+	}
+
+	if err := s.idempotencyRepo.WithTx(tx).DeleteByScope(ctx, user.PublicID); err != nil {
+		return nil, nil, err
+	}
+
+	// As chaves já saíram do DICT no pedido de exclusão; a chamada cobre pedidos anteriores a isso.
+	cleanup, err := s.pixKeyService.WithTx(tx).RemoveAllByUser(ctx, user)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := s.pixKeyRepo.WithTx(tx).AnonymizeByUserID(ctx, user.ID); err != nil {
+		return nil, nil, err
+	}
+
+	user.Anonymize()
+	if err := s.userRepo.WithTx(tx).Update(ctx, user); err != nil {
+		return nil, nil, err
+	}
+
+	request.Status = models.DeletionCompleted
+	request.CompletedAt = &now
+	if err := s.deletionRepo.WithTx(tx).Update(ctx, request); err != nil {
+		return nil, nil, err
+	}
+
+	return blobKeys, cleanup, nil
+}
+
+// truncate limita value a maxLength bytes sem cortar um caractere pela metade, o que o Postgres
+// recusaria como UTF-8 inválido. Bytes inválidos na entrada viram U+FFFD pelo mesmo motivo.
+func truncate(value string, maxLength int) string {
+	if len(value) <= maxLength && utf8.ValidString(value) {
+		return value
+	}
+
+	var builder strings.Builder
+	for _, r := range value {
+		if builder.Len()+utf8.RuneLen(r) > maxLength {
+			break
+		}
+		builder.WriteRune(r)
+	}
+	return builder.String()
+}
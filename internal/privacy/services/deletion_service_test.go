@@ -0,0 +1,308 @@
+package services_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	onboarding_models "github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	pix_services "github.com/high-effort-low-stress/go-bank-api/internal/pix/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type deletionMocks struct {
+	transactor         *mocks.MockTransactor
+	deletionRepo       *mocks.MockDeletionRequestRepository
+	userRepo           *mocks.MockUserRepository
+	onboardingRepo     *mocks.MockOnboardingRepository
+	kycRepo            *mocks.MockKYCDocumentRepository
+	representativeRepo *mocks.MockLegalRepresentativeRepository
+	sessionRepo        *mocks.MockSessionRepository
+	outboxRepo         *mocks.MockOutboxRepository
+	idempotencyRepo    *mocks.MockIdempotencyKeyRepository
+	pixKeyRepo         *mocks.MockPixKeyRepository
+	pixKeyService      *mocks.MockPixKeyService
+	blobStore          *mocks.MockBlobStore
+}
+
+func newDeletionService(retention time.Duration) (services.DeletionService, *deletionMocks) {
+	m := &deletionMocks{
+		transactor:         new(mocks.MockTransactor),
+		deletionRepo:       new(mocks.MockDeletionRequestRepository),
+		userRepo:           new(mocks.MockUserRepository),
+		onboardingRepo:     new(mocks.MockOnboardingRepository),
+		kycRepo:            new(mocks.MockKYCDocumentRepository),
+		representativeRepo: new(mocks.MockLegalRepresentativeRepository),
+		sessionRepo:        new(mocks.MockSessionRepository),
+		outboxRepo:         new(mocks.MockOutboxRepository),
+		idempotencyRepo:    new(mocks.MockIdempotencyKeyRepository),
+		pixKeyRepo:         new(mocks.MockPixKeyRepository),
+		pixKeyService:      new(mocks.MockPixKeyService),
+		blobStore:          new(mocks.MockBlobStore),
+	}
+	service := services.NewDeletionService(
+		m.transactor, m.deletionRepo, m.userRepo, m.onboardingRepo, m.kycRepo, m.representativeRepo,
+		m.sessionRepo, m.outboxRepo, m.idempotencyRepo, m.pixKeyRepo, m.pixKeyService, m.blobStore, retention,
+	)
+	return service, m
+}
+
+func activeUser(t *testing.T, password string) *user_models.User {
+	hash, err := crypto.HashPassword(password)
+	require.NoError(t, err)
+	return &user_models.User{ID: 42, PublicID: "01K7ZA0000000000000000USER", PasswordHash: hash, Status: user_models.StatusActive}
+}
+
+func TestDeletionService_RequestDeletion_Success(t *testing.T) {
+	service, m := newDeletionService(24 * time.Hour)
+	cleanup := &pix_services.DirectoryCleanup{}
+	user := activeUser(t, "StrongPassword123!")
+	client := services.ClientInfo{UserAgent: "GoBank/1.0", IPAddress: "203.0.113.10"}
+
+	m.userRepo.On("FindByPublicID", user.PublicID).Return(user, nil)
+	m.deletionRepo.On("FindByUserID", int64(42)).Return(nil, gorm.ErrRecordNotFound)
+	m.userRepo.On("Update", mock.MatchedBy(func(u *user_models.User) bool {
+		return u.Status == user_models.StatusInactive && u.DeactivatedAt != nil
+	})).Return(nil)
+	m.deletionRepo.On("Create", mock.MatchedBy(func(request *models.DeletionRequest) bool {
+		return request.UserID == 42 && request.Status == models.DeletionScheduled && request.IPAddress == client.IPAddress &&
+			request.AnonymizeAfter.Sub(request.RequestedAt) == 24*time.Hour
+	})).Return(nil)
+	m.pixKeyService.On("RemoveAllByUser", user).Return(cleanup, nil)
+	m.pixKeyService.On("CleanUpDirectory", cleanup).Return()
+	m.sessionRepo.On("RevokeAllByUserID", int64(42)).Return(nil)
+
+	request, err := service.RequestDeletion(context.Background(), user.PublicID, "StrongPassword123!", client)
+
+	assert.NoError(t, err)
+	assert.Equal(t, models.DeletionScheduled, request.Status)
+	assert.Equal(t, 1, m.transactor.Calls)
+	m.userRepo.AssertExpectations(t)
+	m.deletionRepo.AssertExpectations(t)
+	m.pixKeyService.AssertExpectations(t)
+	m.sessionRepo.AssertExpectations(t)
+}
+
+func TestDeletionService_RequestDeletion_KeepsUserWhenPixKeysCannotBeRemoved(t *testing.T) {
+	service, m := newDeletionService(24 * time.Hour)
+	user := activeUser(t, "StrongPassword123!")
+
+	m.userRepo.On("FindByPublicID", user.PublicID).Return(user, nil)
+	m.deletionRepo.On("FindByUserID", int64(42)).Return(nil, gorm.ErrRecordNotFound)
+	m.userRepo.On("Update", mock.Anything).Return(nil)
+	m.pixKeyService.On("RemoveAllByUser", user).Return(nil, pix_services.ErrInternalServer)
+
+	_, err := service.RequestDeletion(context.Background(), user.PublicID, "StrongPassword123!", services.ClientInfo{})
+
+	assert.ErrorIs(t, err, services.ErrInternalServer)
+	m.deletionRepo.AssertNotCalled(t, "Create", mock.Anything)
+	m.sessionRepo.AssertNotCalled(t, "RevokeAllByUserID", mock.Anything)
+}
+
+func TestDeletionService_RequestDeletion_InvalidPassword(t *testing.T) {
+	service, m := newDeletionService(24 * time.Hour)
+	user := activeUser(t, "StrongPassword123!")
+
+	m.userRepo.On("FindByPublicID", user.PublicID).Return(user, nil)
+
+	_, err := service.RequestDeletion(context.Background(), user.PublicID, "WrongPassword123!", services.ClientInfo{})
+
+	assert.ErrorIs(t, err, services.ErrInvalidPassword)
+	assert.Equal(t, user_models.StatusActive, user.Status)
+	m.userRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestDeletionService_RequestDeletion_AlreadyRequested(t *testing.T) {
+	service, m := newDeletionService(24 * time.Hour)
+	user := activeUser(t, "StrongPassword123!")
+
+	m.userRepo.On("FindByPublicID", user.PublicID).Return(user, nil)
+	m.deletionRepo.On("FindByUserID", int64(42)).Return(&models.DeletionRequest{}, nil)
+
+	_, err := service.RequestDeletion(context.Background(), user.PublicID, "StrongPassword123!", services.ClientInfo{})
+
+	assert.ErrorIs(t, err, services.ErrDeletionAlreadyRequested)
+	m.deletionRepo.AssertNotCalled(t, "Create", mock.Anything)
+	m.sessionRepo.AssertNotCalled(t, "RevokeAllByUserID", mock.Anything)
+}
+
+func TestDeletionService_AnonymizeDue(t *testing.T) {
+	service, m := newDeletionService(24 * time.Hour)
+	cleanup := &pix_services.DirectoryCleanup{}
+	now := time.Now()
+	birthDate := time.Date(1990, time.May, 10, 0, 0, 0, 0, time.UTC)
+
+	due := []models.DeletionRequest{{
+		ID:     1,
+		UserID: 42,
+		Status: models.DeletionScheduled,
+		User: user_models.User{
+			ID:             42,
+			PublicID:       "01K7ZA0000000000000000USER",
+			FullName:       "John Doe",
+			Email:          "john@example.com",
+			DocumentNumber: "12345678909",
+			BirthDate:      &birthDate,
+			MotherName:     "Maria Doe",
+			PhoneNumber:    "+5511912345678",
+			Address:        user_models.Address{CEP: "01310100", City: "São Paulo", State: "SP"},
+			Status:         user_models.StatusInactive,
+		},
+	}}
+	onboardingRequests := []onboarding_models.OnboardingRequest{{
+		ID:             7,
+		PublicID:       "01K7ZA00000000000000000REQ",
+		FullName:       "John Doe",
+		Email:          "john@example.com",
+		DocumentNumber: "12345678909",
+		PhoneNumber:    "+5511912345678",
+		Status:         onboarding_models.StatusCompleted,
+	}}
+
+	m.deletionRepo.On("ListDueForUpdate", now, 10).Return(due, nil)
+	m.onboardingRepo.On("ListByDocument", "12345678909").Return(onboardingRequests, nil)
+	m.kycRepo.On("ListByRequest", int64(7)).Return([]onboarding_models.KYCDocument{{BlobKey: "kyc/7/selfie"}}, nil)
+	m.kycRepo.On("DeleteByRequest", int64(7)).Return(nil)
+	m.onboardingRepo.On("Update", mock.MatchedBy(func(request *onboarding_models.OnboardingRequest) bool {
+		return request.FullName == user_models.AnonymizedName && request.DocumentNumber == "" && request.PhoneNumber == "" &&
+			request.Email == "anonimizado-01K7ZA00000000000000000REQ@anonimizado.invalid" && request.Status == onboarding_models.StatusCompleted
+	})).Return(nil)
+	m.userRepo.On("Update", mock.MatchedBy(func(user *user_models.User) bool {
+		return user.FullName == user_models.AnonymizedName && user.DocumentNumber == "X0000000000042" && user.BirthDate == nil &&
+			user.MotherName == "" && user.Address == (user_models.Address{}) && user.PasswordHash == "" &&
+			user.Email == "anonimizado-01K7ZA0000000000000000USER@anonimizado.invalid"
+	})).Return(nil)
+	m.deletionRepo.On("Update", mock.MatchedBy(func(request *models.DeletionRequest) bool {
+		return request.Status == models.DeletionCompleted && request.CompletedAt != nil
+	})).Return(nil)
+	m.representativeRepo.On("ListByUser", int64(42)).Return([]onboarding_models.LegalRepresentative{}, nil)
+	m.outboxRepo.On("DeleteByRecipient", "john@example.com").Return(nil)
+	m.sessionRepo.On("AnonymizeByUserID", int64(42)).Return(nil)
+	m.idempotencyRepo.On("DeleteByScope", "01K7ZA0000000000000000USER").Return(nil)
+	m.pixKeyService.On("RemoveAllByUser", mock.Anything).Return(cleanup, nil)
+	m.pixKeyService.On("CleanUpDirectory", cleanup).Return()
+	m.pixKeyRepo.On("AnonymizeByUserID", int64(42)).Return(nil)
+	m.blobStore.On("Delete", "kyc/7/selfie").Return(nil)
+
+	anonymized, err := service.AnonymizeDue(context.Background(), now, 10)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, anonymized)
+	m.onboardingRepo.AssertExpectations(t)
+	m.userRepo.AssertExpectations(t)
+	m.kycRepo.AssertExpectations(t)
+	m.blobStore.AssertExpectations(t)
+	m.pixKeyService.AssertExpectations(t)
+}
+
+func TestDeletionService_AnonymizeDue_KeepsBlobsWhenTransactionFails(t *testing.T) {
+	service, m := newDeletionService(24 * time.Hour)
+	cleanup := &pix_services.DirectoryCleanup{}
+	now := time.Now()
+
+	due := []models.DeletionRequest{{ID: 1, UserID: 42, User: user_models.User{ID: 42, DocumentNumber: "12345678909"}}}
+
+	m.deletionRepo.On("ListDueForUpdate", now, 10).Return(due, nil)
+	m.onboardingRepo.On("ListByDocument", "12345678909").Return([]onboarding_models.OnboardingRequest{{ID: 7}}, nil)
+	m.kycRepo.On("ListByRequest", int64(7)).Return([]onboarding_models.KYCDocument{{BlobKey: "kyc/7/selfie"}}, nil)
+	m.kycRepo.On("DeleteByRequest", int64(7)).Return(nil)
+	m.onboardingRepo.On("Update", mock.Anything).Return(nil)
+	m.representativeRepo.On("ListByUser", int64(42)).Return([]onboarding_models.LegalRepresentative{}, nil)
+	m.outboxRepo.On("DeleteByRecipient", mock.Anything).Return(nil)
+	m.sessionRepo.On("AnonymizeByUserID", int64(42)).Return(nil)
+	m.idempotencyRepo.On("DeleteByScope", mock.Anything).Return(nil)
+	m.pixKeyService.On("RemoveAllByUser", mock.Anything).Return(cleanup, nil)
+	m.pixKeyRepo.On("AnonymizeByUserID", int64(42)).Return(nil)
+	m.userRepo.On("Update", mock.Anything).Return(errors.New("db error"))
+
+	_, err := service.AnonymizeDue(context.Background(), now, 10)
+
+	assert.Error(t, err)
+	m.blobStore.AssertNotCalled(t, "Delete", mock.Anything)
+	m.pixKeyService.AssertNotCalled(t, "CleanUpDirectory", mock.Anything)
+}
+
+// TestDeletionService_AnonymizeDue_LeavesNoPersonalData grava tudo o que o serviço escreve e
+// verifica que nenhuma cópia do nome, do CPF ou do e-mail sobrevive à anonimização.
+func TestDeletionService_AnonymizeDue_LeavesNoPersonalData(t *testing.T) {
+	service, m := newDeletionService(24 * time.Hour)
+	cleanup := &pix_services.DirectoryCleanup{}
+	now := time.Now()
+	const (
+		name     = "John Doe"
+		document = "12345678909"
+		email    = "john@example.com"
+		oldEmail = "john.doe@example.com"
+	)
+
+	due := []models.DeletionRequest{{ID: 1, UserID: 42, User: user_models.User{
+		ID: 42, PublicID: "01K7ZA0000000000000000USER", FullName: name, Email: email, DocumentNumber: document,
+	}}}
+	representatives := []onboarding_models.LegalRepresentative{
+		{ID: 3, OnboardingRequestID: 8, UserID: 42, FullName: name, DocumentNumber: document, Status: onboarding_models.RepresentativeApproved},
+	}
+
+	var written []any
+	record := func(args mock.Arguments) { written = append(written, args.Get(0)) }
+
+	m.deletionRepo.On("ListDueForUpdate", now, 10).Return(due, nil)
+	m.onboardingRepo.On("ListByDocument", document).Return([]onboarding_models.OnboardingRequest{
+		{ID: 7, PublicID: "01K7ZA00000000000000000REQ", FullName: name, Email: oldEmail, DocumentNumber: document},
+	}, nil)
+	m.kycRepo.On("ListByRequest", int64(7)).Return([]onboarding_models.KYCDocument{}, nil)
+	m.kycRepo.On("DeleteByRequest", int64(7)).Return(nil)
+	m.onboardingRepo.On("Update", mock.Anything).Run(record).Return(nil)
+	m.representativeRepo.On("ListByUser", int64(42)).Return(representatives, nil)
+	m.representativeRepo.On("Update", mock.Anything).Run(record).Return(nil)
+	m.outboxRepo.On("DeleteByRecipient", email).Return(nil)
+	m.outboxRepo.On("DeleteByRecipient", oldEmail).Return(nil)
+	m.sessionRepo.On("AnonymizeByUserID", int64(42)).Return(nil)
+	m.idempotencyRepo.On("DeleteByScope", "01K7ZA0000000000000000USER").Return(nil)
+	m.pixKeyService.On("RemoveAllByUser", mock.Anything).Return(cleanup, nil)
+	m.pixKeyService.On("CleanUpDirectory", cleanup).Return()
+	m.pixKeyRepo.On("AnonymizeByUserID", int64(42)).Return(nil)
+	m.userRepo.On("Update", mock.Anything).Run(record).Return(nil)
+	m.deletionRepo.On("Update", mock.Anything).Run(record).Return(nil)
+
+	_, err := service.AnonymizeDue(context.Background(), now, 10)
+
+	require.NoError(t, err)
+	require.Len(t, written, 4)
+	for _, value := range written {
+		dump := fmt.Sprintf("%+v", value)
+		for _, personalData := range []string{name, document, email, oldEmail} {
+			assert.NotContains(t, dump, personalData)
+		}
+	}
+	m.representativeRepo.AssertExpectations(t)
+	m.outboxRepo.AssertExpectations(t)
+	m.sessionRepo.AssertExpectations(t)
+	m.idempotencyRepo.AssertExpectations(t)
+	m.pixKeyRepo.AssertExpectations(t)
+}
+
+func TestRetentionPeriodFromEnv(t *testing.T) {
+	t.Setenv("PII_RETENTION_PERIOD", "")
+	period, err := services.RetentionPeriodFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, services.DefaultRetentionPeriod, period)
+
+	t.Setenv("PII_RETENTION_PERIOD", "720h")
+	period, err = services.RetentionPeriodFromEnv()
+	assert.NoError(t, err)
+	assert.Equal(t, 720*time.Hour, period)
+
+	t.Setenv("PII_RETENTION_PERIOD", "cinco anos")
+	_, err = services.RetentionPeriodFromEnv()
+	assert.Error(t, err)
+}
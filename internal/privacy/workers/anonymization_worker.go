@@ -0,0 +1,87 @@
+// Package workers defines the background jobs of the LGPD data subject requests.
+package workers
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/services"
+)
+
+const (
+	DefaultAnonymizationInterval  = 1 * time.Hour
+	DefaultAnonymizationBatchSize = 100
+)
+
+// AnonymizationWorker anonimiza periodicamente os dados dos usuários que pediram a exclusão e
+// cujo prazo de guarda já terminou.
+type AnonymizationWorker struct {
+	service   services.DeletionService
+	interval  time.Duration
+	batchSize int
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewAnonymizationWorker(service services.DeletionService, interval time.Duration, batchSize int) *AnonymizationWorker {
+	return &AnonymizationWorker{service: service, interval: interval, batchSize: batchSize}
+}
+
+// Start inicia o worker em background. Ele roda uma vez imediatamente e depois a cada intervalo.
+func (w *AnonymizationWorker) Start(ctx context.Context) {
+	ctx, w.cancel = context.WithCancel(ctx)
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+
+		for {
+			if _, err := w.RunOnce(ctx); err != nil {
+				log.Printf("Error anonymizing user data: %v", err)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop sinaliza o fim do worker e aguarda o lote em andamento terminar.
+func (w *AnonymizationWorker) Stop() {
+	if w.cancel != nil {
+		w.cancel()
+	}
+	w.wg.Wait()
+}
+
+// RunOnce anonimiza lotes de pedidos até não restar nenhum vencido ou o contexto ser cancelado.
+func (w *AnonymizationWorker) RunOnce(ctx context.Context) (int, error) {
+	var total int
+
+	for ctx.Err() == nil {
+		anonymized, err := w.service.AnonymizeDue(ctx, time.Now(), w.batchSize)
+		if err != nil {
+			return total, err
+		}
+
+		total += anonymized
+		if anonymized < w.batchSize {
+			break
+		}
+	}
+
+	if total > 0 {
+		log.Printf("Anonymized the data of %d users", total)
+	}
+
+	return total, nil
+}
@@ -0,0 +1,62 @@
+package workers_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/workers"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestAnonymizationWorker_RunOnce_ProcessesBatchesUntilEmpty(t *testing.T) {
+	mockService := new(mocks.MockDeletionService)
+	worker := workers.NewAnonymizationWorker(mockService, time.Minute, 2)
+
+	mockService.On("AnonymizeDue", mock.AnythingOfType("time.Time"), 2).Return(2, nil).Once()
+	mockService.On("AnonymizeDue", mock.AnythingOfType("time.Time"), 2).Return(1, nil).Once()
+
+	total, err := worker.RunOnce(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, 3, total)
+	mockService.AssertNumberOfCalls(t, "AnonymizeDue", 2)
+}
+
+func TestAnonymizationWorker_RunOnce_StopsOnError(t *testing.T) {
+	mockService := new(mocks.MockDeletionService)
+	worker := workers.NewAnonymizationWorker(mockService, time.Minute, 2)
+
+	mockService.On("AnonymizeDue", mock.Anything, 2).Return(0, errors.New("db error"))
+
+	_, err := worker.RunOnce(context.Background())
+
+	assert.Error(t, err)
+	mockService.AssertNumberOfCalls(t, "AnonymizeDue", 1)
+}
+
+func TestAnonymizationWorker_StartAndStop(t *testing.T) {
+	mockService := new(mocks.MockDeletionService)
+	worker := workers.NewAnonymizationWorker(mockService, time.Hour, 2)
+
+	called := make(chan struct{}, 1)
+	mockService.On("AnonymizeDue", mock.Anything, 2).Run(func(_ mock.Arguments) {
+		select {
+		case called <- struct{}{}:
+		default:
+		}
+	}).Return(0, nil)
+
+	worker.Start(context.Background())
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("worker did not run on start")
+	}
+
+	worker.Stop()
+}
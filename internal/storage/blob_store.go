@@ -0,0 +1,22 @@
+// Package storage provides binary object storage for files uploaded by customers.
+// BlobStore hides the backend; the local filesystem implementation is used until an
+// object storage service is configured.
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+var (
+	ErrBlobNotFound   = errors.New("blob not found")
+	ErrInvalidBlobKey = errors.New("invalid blob key")
+)
+
+// BlobStore guarda arquivos identificados por uma chave no formato "pasta/subpasta/arquivo".
+type BlobStore interface {
+	Put(ctx context.Context, key string, content io.Reader) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
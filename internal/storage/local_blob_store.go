@@ -0,0 +1,112 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DefaultLocalBlobDir é usado quando BLOB_STORAGE_DIR não está definido.
+const DefaultLocalBlobDir = "tmp/blobs"
+
+// LocalBlobStore grava cada blob como um arquivo abaixo de um diretório raiz.
+type LocalBlobStore struct {
+	root string
+}
+
+// NewLocalBlobStore cria o diretório raiz, se necessário. Sem diretório informado, usa DefaultLocalBlobDir.
+func NewLocalBlobStore(root string) (*LocalBlobStore, error) {
+	if root == "" {
+		root = DefaultLocalBlobDir
+	}
+
+	if err := os.MkdirAll(root, 0o750); err != nil {
+		return nil, fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	return &LocalBlobStore{root: root}, nil
+}
+
+// Put grava o conteúdo em um arquivo temporário e o renomeia ao final, para que leitores
+// nunca vejam um blob pela metade.
+func (s *LocalBlobStore) Put(ctx context.Context, key string, content io.Reader) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+		return fmt.Errorf("failed to create blob directory: %w", err)
+	}
+
+	file, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("failed to create blob file: %w", err)
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := io.Copy(file, &contextReader{ctx: ctx, reader: content}); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write blob: %w", err)
+	}
+
+	return os.Rename(file.Name(), path)
+}
+
+func (s *LocalBlobStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrBlobNotFound
+	}
+	return file, err
+}
+
+// Delete remove o blob. Apagar uma chave inexistente não é erro.
+func (s *LocalBlobStore) Delete(_ context.Context, key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// path converte a chave em um caminho dentro da raiz, recusando chaves que escapariam dela.
+func (s *LocalBlobStore) path(key string) (string, error) {
+	if key == "" || strings.HasPrefix(key, "/") || !fs.ValidPath(key) {
+		return "", ErrInvalidBlobKey
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(key)), nil
+}
+
+// contextReader interrompe a cópia quando o contexto é cancelado.
+type contextReader struct {
+	ctx    context.Context
+	reader io.Reader
+}
+
+func (r *contextReader) Read(p []byte) (int, error) {
+	if err := r.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return r.reader.Read(p)
+}
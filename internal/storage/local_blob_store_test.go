@@ -0,0 +1,59 @@
+package storage_test
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalBlobStore_PutGetDelete(t *testing.T) {
+	store, err := storage.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	key := "kyc/01JAX5T0K1D6S0ZB7W3Q8YV2NM/selfie.jpg"
+
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("image-bytes")))
+
+	reader, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	content, err := io.ReadAll(reader)
+	require.NoError(t, reader.Close())
+	require.NoError(t, err)
+	assert.Equal(t, "image-bytes", string(content))
+
+	require.NoError(t, store.Delete(ctx, key))
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+
+	assert.NoError(t, store.Delete(ctx, key), "deleting a missing blob is not an error")
+}
+
+func TestLocalBlobStore_RejectsKeysOutsideRoot(t *testing.T) {
+	store, err := storage.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	for _, key := range []string{"", "../escape.txt", "/etc/passwd", "kyc/../../escape.txt"} {
+		err := store.Put(context.Background(), key, strings.NewReader("x"))
+		assert.ErrorIs(t, err, storage.ErrInvalidBlobKey, "key: %q", key)
+	}
+}
+
+func TestLocalBlobStore_PutHonorsCancelledContext(t *testing.T) {
+	store, err := storage.NewLocalBlobStore(t.TempDir())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err = store.Put(ctx, "kyc/cancelled.jpg", strings.NewReader("x"))
+
+	assert.ErrorIs(t, err, context.Canceled)
+	_, err = store.Get(context.Background(), "kyc/cancelled.jpg")
+	assert.ErrorIs(t, err, storage.ErrBlobNotFound)
+}
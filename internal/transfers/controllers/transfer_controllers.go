@@ -0,0 +1,97 @@
+// Package controllers define the HTTP handlers for transfers between GoBank accounts.
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/middlewares"
+	"github.com/high-effort-low-stress/go-bank-api/internal/transfers/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/http_helpers"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/money"
+)
+
+// DestinationRequest identifica a conta de destino pelo ID público ou pela agência e número.
+type DestinationRequest struct {
+	AccountID     string `json:"accountId"`
+	Agency        string `json:"agency" binding:"required_without=AccountID"`
+	AccountNumber string `json:"accountNumber" binding:"required_without=AccountID"`
+}
+
+// TransferRequest é o corpo de POST /transfers. O valor é uma string decimal em reais ("10.50").
+type TransferRequest struct {
+	SourceAccountID string             `json:"sourceAccountId"`
+	Destination     DestinationRequest `json:"destination"`
+	Amount          money.Money        `json:"amount"`
+	Description     string             `json:"description" binding:"max=140"`
+}
+
+type TransferController struct {
+	transferService services.TransferService
+}
+
+func NewTransferController(transferService services.TransferService) *TransferController {
+	return &TransferController{transferService: transferService}
+}
+
+func (ctrl *TransferController) CreateTransfer(c *gin.Context) {
+	var req TransferRequest
+
+	if response := http_helpers.ValidateJsonRequest(c, &req); response != nil {
+		c.JSON(http.StatusBadRequest, response)
+		return
+	}
+
+	userPublicID, _ := middlewares.GetUserPublicID(c)
+
+	transfer, err := ctrl.transferService.Transfer(c.Request.Context(), userPublicID, services.TransferInput{
+		SourceAccountPublicID: req.SourceAccountID,
+		Destination: services.AccountRef{
+			PublicID:      req.Destination.AccountID,
+			AgencyNumber:  req.Destination.Agency,
+			AccountNumber: req.Destination.AccountNumber,
+		},
+		Amount:      req.Amount,
+		Description: req.Description,
+	})
+	if err == nil {
+		c.JSON(http.StatusCreated, gin.H{
+			"id":          transfer.PublicID,
+			"amount":      transfer.Amount,
+			"description": transfer.Description,
+			"source":      receiptParty(&transfer.SourceAccount),
+			"destination": receiptParty(&transfer.DestinationAccount),
+			"createdAt":   transfer.CreatedAt.Format(time.RFC3339),
+		})
+		return
+	}
+
+	switch {
+	case errors.Is(err, services.ErrUserNotFound),
+		errors.Is(err, services.ErrSourceAccountNotFound),
+		errors.Is(err, services.ErrDestinationAccountNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrInvalidAmount),
+		errors.Is(err, services.ErrSameAccount),
+		errors.Is(err, services.ErrDestinationUnavailable),
+		errors.Is(err, services.ErrInsufficientFunds):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrConcurrentUpdate):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": services.ErrInternalServer.Error()})
+	}
+}
+
+// receiptParty descreve uma das pontas no comprovante; o documento do titular não é exposto.
+func receiptParty(account *user_models.Account) gin.H {
+	return gin.H{
+		"accountId":     account.PublicID,
+		"name":          account.User.FullName,
+		"agency":        account.AgencyNumber,
+		"accountNumber": account.FormattedAccountNumber(),
+	}
+}
@@ -0,0 +1,35 @@
+// Package models define the data structures for transfers between GoBank accounts.
+package models
+
+import (
+	"time"
+
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/money"
+	"github.com/oklog/ulid/v2"
+	"gorm.io/gorm"
+)
+
+// Transfer é o comprovante de uma transferência entre duas contas do GoBank. A movimentação em si
+// é o lançamento JournalEntryID no razão.
+type Transfer struct {
+	ID                   int64               `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID             string              `gorm:"type:varchar(26);unique;not null"`
+	SourceAccountID      int64               `gorm:"not null;column:source_account_id"`
+	SourceAccount        user_models.Account `gorm:"foreignKey:SourceAccountID"`
+	DestinationAccountID int64               `gorm:"not null;column:destination_account_id"`
+	DestinationAccount   user_models.Account `gorm:"foreignKey:DestinationAccountID"`
+	Amount               money.Money         `gorm:"type:bigint;not null"`
+	Description          string              `gorm:"type:varchar(140);not null;default:''"`
+	JournalEntryID       int64               `gorm:"unique;not null;column:journal_entry_id"`
+	CreatedAt            time.Time           `gorm:"autoCreateTime"`
+}
+
+func (Transfer) TableName() string {
+	return "payments.transfers"
+}
+
+func (t *Transfer) BeforeCreate(_ *gorm.DB) (err error) {
+	t.PublicID = ulid.Make().String()
+	return
+}
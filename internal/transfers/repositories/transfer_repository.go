@@ -0,0 +1,36 @@
+// Package repositories define the data access layer for transfers between GoBank accounts.
+package repositories
+
+import (
+	"context"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	"github.com/high-effort-low-stress/go-bank-api/internal/transfers/models"
+	"gorm.io/gorm"
+)
+
+type TransferRepository interface {
+	Create(ctx context.Context, transfer *models.Transfer) error
+	WithTx(tx *gorm.DB) TransferRepository
+}
+
+type transferRepository struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) TransferRepository {
+	return &transferRepository{db: db}
+}
+
+// Create grava o comprovante sem tocar nas contas associadas, que já existem.
+func (r *transferRepository) Create(ctx context.Context, transfer *models.Transfer) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Omit("SourceAccount", "DestinationAccount").Create(transfer).Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *transferRepository) WithTx(tx *gorm.DB) TransferRepository {
+	return &transferRepository{db: tx}
+}
@@ -0,0 +1,296 @@
+// Package services define the business logic for transfers between GoBank accounts.
+package services
+
+import (
+	"context"
+	"errors"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	ledger_models "github.com/high-effort-low-stress/go-bank-api/internal/ledger/models"
+	ledger_repositories "github.com/high-effort-low-stress/go-bank-api/internal/ledger/repositories"
+	ledger_services "github.com/high-effort-low-stress/go-bank-api/internal/ledger/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	outbox_repositories "github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/transfers/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/transfers/repositories"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	user_repositories "github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/money"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrUserNotFound               = errors.New("usuário não encontrado")
+	ErrSourceAccountNotFound      = errors.New("conta de origem não encontrada")
+	ErrDestinationAccountNotFound = errors.New("conta de destino não encontrada")
+	ErrDestinationUnavailable     = errors.New("a conta de destino não pode receber transferências")
+	ErrSameAccount                = errors.New("a conta de destino deve ser diferente da conta de origem")
+	ErrInvalidAmount              = errors.New("o valor da transferência deve ser maior que zero")
+	ErrInsufficientFunds          = errors.New("saldo insuficiente")
+	ErrConcurrentUpdate           = errors.New("o saldo foi alterado por outra operação, tente novamente")
+	ErrInternalServer             = errors.New("ocorreu um erro inesperado")
+)
+
+// receiptTimeLayout é o formato da data da transferência nos e-mails.
+const receiptTimeLayout = "02/01/2006 15:04"
+
+// AccountRef identifica uma conta pelo ID público ou pela agência e número.
+type AccountRef struct {
+	PublicID      string
+	AgencyNumber  string
+	AccountNumber string
+}
+
+// TransferInput é o pedido de transferência. Sem SourceAccountPublicID, a primeira conta do
+// usuário é usada como origem.
+type TransferInput struct {
+	SourceAccountPublicID string
+	Destination           AccountRef
+	Amount                money.Money
+	Description           string
+}
+
+type TransferService interface {
+	Transfer(ctx context.Context, userPublicID string, input TransferInput) (*models.Transfer, error)
+}
+
+type transferService struct {
+	transactor    database.Transactor
+	transferRepo  repositories.TransferRepository
+	userRepo      user_repositories.UserRepository
+	ledgerRepo    ledger_repositories.LedgerAccountRepository
+	ledgerService ledger_services.LedgerService
+	outboxRepo    outbox_repositories.OutboxRepository
+}
+
+func NewTransferService(
+	transactor database.Transactor,
+	transferRepo repositories.TransferRepository,
+	userRepo user_repositories.UserRepository,
+	ledgerRepo ledger_repositories.LedgerAccountRepository,
+	ledgerService ledger_services.LedgerService,
+	outboxRepo outbox_repositories.OutboxRepository,
+) TransferService {
+	return &transferService{
+		transactor:    transactor,
+		transferRepo:  transferRepo,
+		userRepo:      userRepo,
+		ledgerRepo:    ledgerRepo,
+		ledgerService: ledgerService,
+		outboxRepo:    outboxRepo,
+	}
+}
+
+// Transfer move o valor da conta do usuário para a conta de destino. Na mesma transação, as duas
+// contas do razão são bloqueadas, o saldo disponível é conferido, o lançamento é postado, o
+// comprovante é gravado e os e-mails para pagador e recebedor entram no outbox.
+func (s *transferService) Transfer(ctx context.Context, userPublicID string, input TransferInput) (*models.Transfer, error) {
+	if !input.Amount.IsPositive() || input.Amount.Currency() != money.BRL {
+		return nil, ErrInvalidAmount
+	}
+
+	user, err := s.userRepo.FindByPublicID(ctx, userPublicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		log.Printf("Error finding user by public ID: %v", err)
+		return nil, ErrInternalServer
+	}
+	if user.Status != user_models.StatusActive {
+		return nil, ErrUserNotFound
+	}
+
+	source, err := s.findSourceAccount(ctx, user, input.SourceAccountPublicID)
+	if err != nil {
+		return nil, err
+	}
+
+	destination, err := s.findDestinationAccount(ctx, input.Destination)
+	if err != nil {
+		return nil, err
+	}
+	if destination.ID == source.ID {
+		return nil, ErrSameAccount
+	}
+	if destination.User.Status != user_models.StatusActive {
+		return nil, ErrDestinationUnavailable
+	}
+
+	transfer := &models.Transfer{
+		SourceAccountID:      source.ID,
+		SourceAccount:        *source,
+		DestinationAccountID: destination.ID,
+		DestinationAccount:   *destination,
+		Amount:               input.Amount,
+		Description:          strings.TrimSpace(input.Description),
+		CreatedAt:            time.Now(),
+	}
+
+	var transferErr error
+	err = s.transactor.WithinTransaction(ctx, func(tx *gorm.DB) error {
+		transferErr = s.transferWithinTx(ctx, tx, transfer)
+		return transferErr
+	})
+	if transferErr != nil {
+		return nil, transferErr
+	}
+	if err != nil {
+		log.Printf("Error committing transfer: %v", err)
+		return nil, ErrInternalServer
+	}
+
+	log.Printf("Transfer %s of %d centavos from account %s to account %s completed", transfer.PublicID, transfer.Amount.Centavos(), source.PublicID, destination.PublicID)
+	return transfer, nil
+}
+
+// transferWithinTx bloqueia as contas do razão antes de conferir o saldo, para que duas
+// transferências simultâneas da mesma conta não gastem o mesmo dinheiro.
+func (s *transferService) transferWithinTx(ctx context.Context, tx *gorm.DB, transfer *models.Transfer) error {
+	ledgerAccounts, err := s.ledgerRepo.WithTx(tx).LockByAccountIDs(ctx, []int64{transfer.SourceAccountID, transfer.DestinationAccountID})
+	if err != nil {
+		log.Printf("Error locking ledger accounts: %v", err)
+		return ErrInternalServer
+	}
+
+	var sourceLedger, destinationLedger *ledger_models.LedgerAccount
+	for i := range ledgerAccounts {
+		switch *ledgerAccounts[i].AccountID {
+		case transfer.SourceAccountID:
+			sourceLedger = &ledgerAccounts[i]
+		case transfer.DestinationAccountID:
+			destinationLedger = &ledgerAccounts[i]
+		}
+	}
+	if sourceLedger == nil || destinationLedger == nil {
+		log.Printf("Ledger account missing for account %d or %d", transfer.SourceAccountID, transfer.DestinationAccountID)
+		return ErrInternalServer
+	}
+
+	amount := transfer.Amount.Centavos()
+	if sourceLedger.Balance < amount {
+		return ErrInsufficientFunds
+	}
+
+	entry := &ledger_models.JournalEntry{
+		Kind:        ledger_models.EntryTransfer,
+		Description: transfer.Description,
+		Postings: []ledger_models.Posting{
+			ledger_models.Debit(sourceLedger.ID, amount),
+			ledger_models.Credit(destinationLedger.ID, amount),
+		},
+	}
+	if err := s.ledgerService.WithTx(tx).Post(ctx, entry); err != nil {
+		switch {
+		case errors.Is(err, ledger_services.ErrInsufficientFunds):
+			return ErrInsufficientFunds
+		case errors.Is(err, ledger_services.ErrConcurrentUpdate):
+			return ErrConcurrentUpdate
+		}
+		log.Printf("Error posting transfer journal entry: %v", err)
+		return ErrInternalServer
+	}
+
+	transfer.JournalEntryID = entry.ID
+	if err := s.transferRepo.WithTx(tx).Create(ctx, transfer); err != nil {
+		log.Printf("Error creating transfer: %v", err)
+		return ErrInternalServer
+	}
+
+	outboxRepo := s.outboxRepo.WithTx(tx)
+	emails := []*notification.EmailRequest{
+		newTransferEmail(notification.TemplateTransferSent, transfer, &transfer.SourceAccount, &transfer.DestinationAccount),
+		newTransferEmail(notification.TemplateTransferReceived, transfer, &transfer.DestinationAccount, &transfer.SourceAccount),
+	}
+	for _, email := range emails {
+		if err := outboxRepo.EnqueueEmail(ctx, email); err != nil {
+			log.Printf("Error enqueuing transfer email: %v", err)
+			return ErrInternalServer
+		}
+	}
+
+	return nil
+}
+
+// findSourceAccount só aceita contas do próprio usuário; contas de terceiros são tratadas como
+// inexistentes para não revelar quais IDs são válidos.
+func (s *transferService) findSourceAccount(ctx context.Context, user *user_models.User, publicID string) (*user_models.Account, error) {
+	if publicID == "" {
+		accounts, err := s.userRepo.ListAccountsByUserID(ctx, user.ID)
+		if err != nil {
+			log.Printf("Error listing accounts of user %s: %v", user.PublicID, err)
+			return nil, ErrInternalServer
+		}
+		if len(accounts) == 0 {
+			return nil, ErrSourceAccountNotFound
+		}
+		account := accounts[0]
+		account.User = *user
+		return &account, nil
+	}
+
+	account, err := s.userRepo.FindAccountByPublicID(ctx, publicID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSourceAccountNotFound
+		}
+		log.Printf("Error finding source account by public ID: %v", err)
+		return nil, ErrInternalServer
+	}
+	if account.UserID != user.ID {
+		return nil, ErrSourceAccountNotFound
+	}
+	return account, nil
+}
+
+func (s *transferService) findDestinationAccount(ctx context.Context, ref AccountRef) (*user_models.Account, error) {
+	var account *user_models.Account
+	var err error
+	if ref.PublicID != "" {
+		account, err = s.userRepo.FindAccountByPublicID(ctx, ref.PublicID)
+	} else {
+		account, err = s.userRepo.FindAccountByNumber(ctx, ref.AgencyNumber, ref.AccountNumber)
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrDestinationAccountNotFound
+		}
+		log.Printf("Error finding destination account: %v", err)
+		return nil, ErrInternalServer
+	}
+	return account, nil
+}
+
+// newTransferEmail monta o aviso da transferência para o titular de account; counterparty é a
+// outra ponta da operação.
+func newTransferEmail(templateID notification.TemplateID, transfer *models.Transfer, account, counterparty *user_models.Account) *notification.EmailRequest {
+	templateData := struct {
+		FullName            string
+		Amount              string
+		CounterpartyName    string
+		CounterpartyAccount string
+		Description         string
+		TransferID          string
+		Date                string
+	}{
+		FullName:            account.User.FullName,
+		Amount:              transfer.Amount.Format(),
+		CounterpartyName:    counterparty.User.FullName,
+		CounterpartyAccount: counterparty.AgencyNumber + " / " + counterparty.FormattedAccountNumber(),
+		Description:         transfer.Description,
+		TransferID:          transfer.PublicID,
+		Date:                transfer.CreatedAt.Format(receiptTimeLayout),
+	}
+
+	return &notification.EmailRequest{
+		From:         os.Getenv("EMAIL_FROM"),
+		To:           account.User.Email,
+		TemplateID:   templateID,
+		Locale:       account.User.Locale,
+		TemplateData: templateData,
+	}
+}
@@ -0,0 +1,196 @@
+package services_test
+
+import (
+	"context"
+	"testing"
+
+	ledger_models "github.com/high-effort-low-stress/go-bank-api/internal/ledger/models"
+	ledger_services "github.com/high-effort-low-stress/go-bank-api/internal/ledger/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/transfers/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/transfers/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/money"
+	"github.com/high-effort-low-stress/go-bank-api/testutil/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+type transferMocks struct {
+	transactor    *mocks.MockTransactor
+	transferRepo  *mocks.MockTransferRepository
+	userRepo      *mocks.MockUserRepository
+	ledgerRepo    *mocks.MockLedgerAccountRepository
+	ledgerService *mocks.MockLedgerService
+	outboxRepo    *mocks.MockOutboxRepository
+}
+
+func newTransferService() (services.TransferService, *transferMocks) {
+	m := &transferMocks{
+		transactor:    new(mocks.MockTransactor),
+		transferRepo:  new(mocks.MockTransferRepository),
+		userRepo:      new(mocks.MockUserRepository),
+		ledgerRepo:    new(mocks.MockLedgerAccountRepository),
+		ledgerService: new(mocks.MockLedgerService),
+		outboxRepo:    new(mocks.MockOutboxRepository),
+	}
+	service := services.NewTransferService(m.transactor, m.transferRepo, m.userRepo, m.ledgerRepo, m.ledgerService, m.outboxRepo)
+	return service, m
+}
+
+func payer() *user_models.User {
+	return &user_models.User{ID: 1, PublicID: "payer-public-id", FullName: "John Doe", Email: "john@example.com", Status: user_models.StatusActive}
+}
+
+func payerAccount() user_models.Account {
+	return user_models.Account{ID: 10, PublicID: "payer-account", UserID: 1, AgencyNumber: "0001", AccountNumber: "122504005"}
+}
+
+func payeeAccount() *user_models.Account {
+	return &user_models.Account{
+		ID:            20,
+		PublicID:      "payee-account",
+		UserID:        2,
+		User:          user_models.User{ID: 2, FullName: "Jane Roe", Email: "jane@example.com", Status: user_models.StatusActive},
+		AgencyNumber:  "0001",
+		AccountNumber: "122504013",
+	}
+}
+
+func lockedLedgerAccounts(payerBalance int64) []ledger_models.LedgerAccount {
+	payerAccountID, payeeAccountID := int64(10), int64(20)
+	return []ledger_models.LedgerAccount{
+		{ID: 100, AccountID: &payerAccountID, NormalBalance: ledger_models.NormalCredit, Balance: payerBalance},
+		{ID: 200, AccountID: &payeeAccountID, NormalBalance: ledger_models.NormalCredit, Balance: 0},
+	}
+}
+
+func transferInput(centavos int64) services.TransferInput {
+	return services.TransferInput{
+		Destination: services.AccountRef{AgencyNumber: "0001", AccountNumber: "12250401-3"},
+		Amount:      money.FromCentavos(centavos),
+		Description: " aluguel ",
+	}
+}
+
+func TestTransferService_Transfer_Success(t *testing.T) {
+	service, m := newTransferService()
+
+	m.userRepo.On("FindByPublicID", "payer-public-id").Return(payer(), nil)
+	m.userRepo.On("ListAccountsByUserID", int64(1)).Return([]user_models.Account{payerAccount()}, nil)
+	m.userRepo.On("FindAccountByNumber", "0001", "12250401-3").Return(payeeAccount(), nil)
+	m.ledgerRepo.On("LockByAccountIDs", []int64{10, 20}).Return(lockedLedgerAccounts(5000), nil)
+	m.ledgerService.On("Post", mock.MatchedBy(func(entry *ledger_models.JournalEntry) bool {
+		return entry.Kind == ledger_models.EntryTransfer &&
+			assert.ObjectsAreEqual([]ledger_models.Posting{ledger_models.Debit(100, 2500), ledger_models.Credit(200, 2500)}, entry.Postings)
+	})).Run(func(args mock.Arguments) {
+		args.Get(0).(*ledger_models.JournalEntry).ID = 99
+	}).Return(nil)
+	m.transferRepo.On("Create", mock.MatchedBy(func(transfer *models.Transfer) bool {
+		return transfer.SourceAccountID == 10 && transfer.DestinationAccountID == 20 &&
+			transfer.JournalEntryID == 99 && transfer.Description == "aluguel"
+	})).Return(nil)
+	m.outboxRepo.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.To == "john@example.com" && req.TemplateID == notification.TemplateTransferSent
+	})).Return(nil)
+	m.outboxRepo.On("EnqueueEmail", mock.MatchedBy(func(req *notification.EmailRequest) bool {
+		return req.To == "jane@example.com" && req.TemplateID == notification.TemplateTransferReceived
+	})).Return(nil)
+
+	transfer, err := service.Transfer(context.Background(), "payer-public-id", transferInput(2500))
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2500), transfer.Amount.Centavos())
+	assert.Equal(t, "John Doe", transfer.SourceAccount.User.FullName)
+	assert.Equal(t, "Jane Roe", transfer.DestinationAccount.User.FullName)
+	assert.Equal(t, 1, m.transactor.Calls)
+	m.ledgerService.AssertExpectations(t)
+	m.transferRepo.AssertExpectations(t)
+	m.outboxRepo.AssertExpectations(t)
+}
+
+func TestTransferService_Transfer_InsufficientFunds(t *testing.T) {
+	service, m := newTransferService()
+
+	m.userRepo.On("FindByPublicID", "payer-public-id").Return(payer(), nil)
+	m.userRepo.On("ListAccountsByUserID", int64(1)).Return([]user_models.Account{payerAccount()}, nil)
+	m.userRepo.On("FindAccountByNumber", "0001", "12250401-3").Return(payeeAccount(), nil)
+	m.ledgerRepo.On("LockByAccountIDs", []int64{10, 20}).Return(lockedLedgerAccounts(2499), nil)
+
+	_, err := service.Transfer(context.Background(), "payer-public-id", transferInput(2500))
+
+	assert.ErrorIs(t, err, services.ErrInsufficientFunds)
+	m.ledgerService.AssertNotCalled(t, "Post", mock.Anything)
+	m.transferRepo.AssertNotCalled(t, "Create", mock.Anything)
+	m.outboxRepo.AssertNotCalled(t, "EnqueueEmail", mock.Anything)
+}
+
+func TestTransferService_Transfer_MapsLedgerErrors(t *testing.T) {
+	service, m := newTransferService()
+
+	m.userRepo.On("FindByPublicID", "payer-public-id").Return(payer(), nil)
+	m.userRepo.On("ListAccountsByUserID", int64(1)).Return([]user_models.Account{payerAccount()}, nil)
+	m.userRepo.On("FindAccountByNumber", "0001", "12250401-3").Return(payeeAccount(), nil)
+	m.ledgerRepo.On("LockByAccountIDs", []int64{10, 20}).Return(lockedLedgerAccounts(5000), nil)
+	m.ledgerService.On("Post", mock.Anything).Return(ledger_services.ErrConcurrentUpdate)
+
+	_, err := service.Transfer(context.Background(), "payer-public-id", transferInput(2500))
+
+	assert.ErrorIs(t, err, services.ErrConcurrentUpdate)
+	m.transferRepo.AssertNotCalled(t, "Create", mock.Anything)
+}
+
+func TestTransferService_Transfer_RejectsSourceAccountOfAnotherUser(t *testing.T) {
+	service, m := newTransferService()
+	input := transferInput(2500)
+	input.SourceAccountPublicID = "payee-account"
+
+	m.userRepo.On("FindByPublicID", "payer-public-id").Return(payer(), nil)
+	m.userRepo.On("FindAccountByPublicID", "payee-account").Return(payeeAccount(), nil)
+
+	_, err := service.Transfer(context.Background(), "payer-public-id", input)
+
+	assert.ErrorIs(t, err, services.ErrSourceAccountNotFound)
+	assert.Equal(t, 0, m.transactor.Calls)
+}
+
+func TestTransferService_Transfer_RejectsSameAccount(t *testing.T) {
+	service, m := newTransferService()
+	input := transferInput(2500)
+	input.Destination = services.AccountRef{PublicID: "payer-account"}
+	ownAccount := payerAccount()
+
+	m.userRepo.On("FindByPublicID", "payer-public-id").Return(payer(), nil)
+	m.userRepo.On("ListAccountsByUserID", int64(1)).Return([]user_models.Account{ownAccount}, nil)
+	m.userRepo.On("FindAccountByPublicID", "payer-account").Return(&ownAccount, nil)
+
+	_, err := service.Transfer(context.Background(), "payer-public-id", input)
+
+	assert.ErrorIs(t, err, services.ErrSameAccount)
+	assert.Equal(t, 0, m.transactor.Calls)
+}
+
+func TestTransferService_Transfer_DestinationNotFound(t *testing.T) {
+	service, m := newTransferService()
+
+	m.userRepo.On("FindByPublicID", "payer-public-id").Return(payer(), nil)
+	m.userRepo.On("ListAccountsByUserID", int64(1)).Return([]user_models.Account{payerAccount()}, nil)
+	m.userRepo.On("FindAccountByNumber", "0001", "12250401-3").Return(nil, gorm.ErrRecordNotFound)
+
+	_, err := service.Transfer(context.Background(), "payer-public-id", transferInput(2500))
+
+	assert.ErrorIs(t, err, services.ErrDestinationAccountNotFound)
+	assert.Equal(t, 0, m.transactor.Calls)
+}
+
+func TestTransferService_Transfer_RejectsNonPositiveAmounts(t *testing.T) {
+	service, m := newTransferService()
+
+	for _, centavos := range []int64{0, -100} {
+		_, err := service.Transfer(context.Background(), "payer-public-id", transferInput(centavos))
+		assert.ErrorIs(t, err, services.ErrInvalidAmount)
+	}
+	m.userRepo.AssertNotCalled(t, "FindByPublicID", mock.Anything)
+}
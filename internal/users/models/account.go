@@ -27,3 +27,12 @@ func (a *Account) BeforeCreate(_ *gorm.DB) (err error) {
 	a.PublicID = ulid.Make().String()
 	return
 }
+
+// FormattedAccountNumber retorna o número da conta no formato "12250400-5".
+func (a *Account) FormattedAccountNumber() string {
+	if len(a.AccountNumber) < 2 {
+		return a.AccountNumber
+	}
+	last := len(a.AccountNumber) - 1
+	return a.AccountNumber[:last] + "-" + a.AccountNumber[last:]
+}
@@ -0,0 +1,13 @@
+package models
+
+// Address é o endereço residencial do cliente (ou a sede, para pessoa jurídica). É embutido nas
+// tabelas de usuários e de onboarding com o prefixo "address_". State guarda a sigla da UF.
+type Address struct {
+	CEP          string `gorm:"type:varchar(8);not null;default:''"`
+	Street       string `gorm:"type:varchar(255);not null;default:''"`
+	Number       string `gorm:"type:varchar(20);not null;default:''"`
+	Complement   string `gorm:"type:varchar(100);not null;default:''"`
+	Neighborhood string `gorm:"type:varchar(100);not null;default:''"`
+	City         string `gorm:"type:varchar(100);not null;default:''"`
+	State        string `gorm:"type:varchar(2);not null;default:''"`
+}
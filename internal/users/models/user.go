@@ -1,8 +1,10 @@
 package models
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
 	"github.com/oklog/ulid/v2"
 	"gorm.io/gorm"
 )
@@ -15,24 +17,90 @@ const (
 	StatusBlocked  UserStatus = "BLOCKED"
 )
 
+// CustomerType distingue clientes pessoa física (CPF) de pessoa jurídica (CNPJ).
+type CustomerType string
+
+const (
+	CustomerTypeIndividual CustomerType = "INDIVIDUAL"
+	CustomerTypeBusiness   CustomerType = "BUSINESS"
+)
+
+// User é o cliente do banco. Para pessoa jurídica, FullName guarda a razão social e
+// DocumentNumber o CNPJ. PoliticallyExposed é a autodeclaração de pessoa politicamente exposta
+// feita no onboarding. Nome, e-mail, documento, nome da mãe e celular são gravados cifrados; as
+// buscas por e-mail, documento e celular usam os blind indexes EmailIndex, DocumentNumberIndex e
+// PhoneNumberIndex, calculados em BeforeSave. Locale é o idioma dos e-mails enviados ao cliente,
+// herdado da solicitação de onboarding.
 type User struct {
-	ID             int64      `gorm:"primaryKey;autoIncrement;column:id"`
-	PublicID       string     `gorm:"type:varchar(26);unique;not null"`
-	FullName       string     `gorm:"type:varchar(255);not null"`
-	Email          string     `gorm:"type:varchar(255);unique;not null"`
-	DocumentNumber string     `gorm:"type:varchar(11);unique;not null;column:document_number"`
-	PasswordHash   string     `gorm:"type:varchar(255);not null"`
-	Status         UserStatus `gorm:"type:user_status;not null;default:'ACTIVE'"`
-	CreatedAt      time.Time  `gorm:"autoCreateTime"`
-	UpdatedAt      time.Time  `gorm:"autoUpdateTime"`
-	DeactivatedAt  *time.Time `gorm:"column:deactivated_at"`
+	ID                  int64        `gorm:"primaryKey;autoIncrement;column:id"`
+	PublicID            string       `gorm:"type:varchar(26);unique;not null"`
+	FullName            string       `gorm:"type:text;not null;serializer:encrypted"`
+	Email               string       `gorm:"type:text;not null;serializer:encrypted"`
+	EmailIndex          string       `gorm:"type:varchar(64);not null;column:email_index"`
+	DocumentNumber      string       `gorm:"type:text;not null;column:document_number;serializer:encrypted"`
+	DocumentNumberIndex string       `gorm:"type:varchar(64);not null;column:document_number_index"`
+	CustomerType        CustomerType `gorm:"type:varchar(10);not null;default:'INDIVIDUAL';column:customer_type"`
+	TradeName           string       `gorm:"type:varchar(255);column:trade_name"`
+	BirthDate           *time.Time   `gorm:"type:date;column:birth_date"`
+	MotherName          string       `gorm:"type:text;not null;default:'';column:mother_name;serializer:encrypted"`
+	PoliticallyExposed  bool         `gorm:"not null;default:false;column:politically_exposed"`
+	PhoneNumber         string       `gorm:"type:text;column:phone_number;serializer:encrypted"`
+	PhoneNumberIndex    string       `gorm:"type:varchar(64);not null;default:'';column:phone_number_index"`
+	Address             Address      `gorm:"embedded;embeddedPrefix:address_"`
+	Locale              string       `gorm:"type:varchar(10);not null;default:'pt-BR';column:locale"`
+	PasswordHash        string       `gorm:"type:varchar(255);not null"`
+	Status              UserStatus   `gorm:"type:user_status;not null;default:'ACTIVE'"`
+	CreatedAt           time.Time    `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time    `gorm:"autoUpdateTime"`
+	DeactivatedAt       *time.Time   `gorm:"column:deactivated_at"`
 }
 
 func (User) TableName() string {
 	return "user.users"
 }
 
+// AnonymizedName substitui o nome dos titulares cujos dados foram anonimizados.
+const AnonymizedName = "Titular anonimizado"
+
+// AnonymizedEmail gera um e-mail único e não entregável para um registro anonimizado.
+func AnonymizedEmail(publicID string) string {
+	return fmt.Sprintf("anonimizado-%s@anonimizado.invalid", publicID)
+}
+
+// Deactivate encerra o relacionamento: o usuário não consegue mais entrar nem renovar sessões.
+func (u *User) Deactivate(now time.Time) {
+	u.Status = StatusInactive
+	u.DeactivatedAt = &now
+}
+
+// Anonymize apaga os dados pessoais do usuário. ID e PublicID são mantidos para que as contas e
+// os registros financeiros continuem ligados ao titular. O documento vira um marcador único,
+// liberando o CPF/CNPJ para um novo cadastro.
+func (u *User) Anonymize() {
+	u.FullName = AnonymizedName
+	u.Email = AnonymizedEmail(u.PublicID)
+	u.DocumentNumber = fmt.Sprintf("X%013d", u.ID)
+	u.TradeName = ""
+	u.BirthDate = nil
+	u.MotherName = ""
+	u.PhoneNumber = ""
+	u.Address = Address{}
+	u.PasswordHash = ""
+}
+
 func (u *User) BeforeCreate(_ *gorm.DB) (err error) {
 	u.PublicID = ulid.Make().String()
 	return
 }
+
+// BeforeSave recalcula os blind indexes a partir dos valores em claro.
+func (u *User) BeforeSave(_ *gorm.DB) (err error) {
+	if u.EmailIndex, err = fieldcrypto.BlindIndex(u.Email); err != nil {
+		return err
+	}
+	if u.DocumentNumberIndex, err = fieldcrypto.BlindIndex(u.DocumentNumber); err != nil {
+		return err
+	}
+	u.PhoneNumberIndex, err = fieldcrypto.BlindIndex(u.PhoneNumber)
+	return err
+}
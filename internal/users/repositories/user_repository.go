@@ -2,14 +2,30 @@
 package repositories
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/database"
+	ledger_models "github.com/high-effort-low-stress/go-bank-api/internal/ledger/models"
 	"github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	accounthelpers "github.com/high-effort-low-stress/go-bank-api/internal/utils/account_helpers"
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
 	"gorm.io/gorm"
 )
 
 const AgencyNumber = "0001"
 
 type UserRepository interface {
-	CreateUserWithAccount(user *models.User) (*models.User, *models.Account, error)
+	CreateUserWithAccount(ctx context.Context, user *models.User) (*models.User, *models.Account, error)
+	FindByDocument(ctx context.Context, document string) (*models.User, error)
+	FindByEmail(ctx context.Context, email string) (*models.User, error)
+	FindByPublicID(ctx context.Context, publicID string) (*models.User, error)
+	ListAccountsByUserID(ctx context.Context, userID int64) ([]models.Account, error)
+	FindAccountByPublicID(ctx context.Context, publicID string) (*models.Account, error)
+	FindAccountByNumber(ctx context.Context, agencyNumber, accountNumber string) (*models.Account, error)
+	Update(ctx context.Context, user *models.User) error
+	WithTx(tx *gorm.DB) UserRepository
 }
 
 type userRepository struct {
@@ -20,40 +36,159 @@ func NewUserRepository(db *gorm.DB) UserRepository {
 	return &userRepository{db: db}
 }
 
-// todo criar conta
-func (r *userRepository) CreateUserWithAccount(user *models.User) (*models.User, *models.Account, error) {
+// CreateUserWithAccount cria o usuário, sua conta corrente e a conta do razão correspondente na
+// mesma transação.
+func (r *userRepository) CreateUserWithAccount(ctx context.Context, user *models.User) (*models.User, *models.Account, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
 	var createdUser *models.User
 	var createdAccount *models.Account
 
-	err := r.db.Transaction(func(tx *gorm.DB) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(user).Error; err != nil {
 			return err
 		}
 
-		// account := &models.Account{
-		// 	UserID:        user.ID,
-		// 	AgencyNumber:  "0001", // Agência padrão
-		// 	AccountNumber: generateAccountNumber(),
-		// }
+		accountNumber, err := generateAccountNumber(tx)
+		if err != nil {
+			return err
+		}
 
-		// if err := tx.Create(account).Error; err != nil {
-		// 	return err
-		// }
+		account := &models.Account{
+			UserID:        user.ID,
+			AgencyNumber:  AgencyNumber,
+			AccountNumber: accountNumber,
+		}
+
+		if err := tx.Create(account).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Create(ledger_models.NewCustomerAccount(account.ID, account.AccountNumber)).Error; err != nil {
+			return err
+		}
 
 		createdUser = user
-		// createdAccount = account
+		createdAccount = account
 		return nil
 	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return createdUser, createdAccount, nil
+}
+
+func (r *userRepository) FindByDocument(ctx context.Context, document string) (*models.User, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	documentIndex, err := fieldcrypto.BlindIndex(document)
+	if err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	result := r.db.WithContext(ctx).Where("document_number_index = ?", documentIndex).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+func (r *userRepository) FindByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	emailIndex, err := fieldcrypto.BlindIndex(email)
+	if err != nil {
+		return nil, err
+	}
+
+	var user models.User
+	result := r.db.WithContext(ctx).Where("email_index = ?", emailIndex).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
 
-	return createdUser, createdAccount, err
+func (r *userRepository) FindByPublicID(ctx context.Context, publicID string) (*models.User, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var user models.User
+	result := r.db.WithContext(ctx).Where("public_id = ?", publicID).First(&user)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &user, nil
+}
+
+func (r *userRepository) ListAccountsByUserID(ctx context.Context, userID int64) ([]models.Account, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var accounts []models.Account
+	result := r.db.WithContext(ctx).Where("user_id = ?", userID).Order("id").Find(&accounts)
+	return accounts, result.Error
+}
+
+// FindAccountByPublicID busca a conta com seu titular.
+func (r *userRepository) FindAccountByPublicID(ctx context.Context, publicID string) (*models.Account, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var account models.Account
+	result := r.db.WithContext(ctx).Preload("User").Where("public_id = ?", publicID).First(&account)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &account, nil
 }
 
+// FindAccountByNumber busca a conta com seu titular pela agência e pelo número, aceito com ou sem
+// o hífen do dígito verificador ("12250400-5" ou "122504005").
+func (r *userRepository) FindAccountByNumber(ctx context.Context, agencyNumber, accountNumber string) (*models.Account, error) {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	var account models.Account
+	result := r.db.WithContext(ctx).Preload("User").
+		Where("agency_number = ? AND account_number = ?", agencyNumber, strings.ReplaceAll(accountNumber, "-", "")).
+		First(&account)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	return &account, nil
+}
+
+func (r *userRepository) Update(ctx context.Context, user *models.User) error {
+	ctx, cancel := database.WithQueryTimeout(ctx)
+	defer cancel()
+
+	return r.db.WithContext(ctx).Save(user).Error
+}
+
+// WithTx retorna uma cópia do repositório que executa suas operações na transação informada.
+func (r *userRepository) WithTx(tx *gorm.DB) UserRepository {
+	return &userRepository{db: tx}
+}
+
+// generateAccountNumber obtém o próximo número da sequência e anexa o dígito verificador.
 func generateAccountNumber(tx *gorm.DB) (string, error) {
 	var nextVal int64
-	err := tx.Raw("SELECT nextval('account_number_seq')").Scan(&nextVal).Error
+	err := tx.Raw(`SELECT nextval('"user".account_number_seq')`).Scan(&nextVal).Error
 	if err != nil {
 		return "", err // Falha ao obter o número, a transação será revertida.
 	}
 
-	return "", nil
+	number := fmt.Sprintf("%d", nextVal)
+	digit, err := accounthelpers.GenerateDigit(number)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s%d", number, digit), nil
 }
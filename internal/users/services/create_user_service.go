@@ -2,20 +2,33 @@
 package services
 
 import (
+	"context"
+	"time"
+
 	"github.com/high-effort-low-stress/go-bank-api/internal/users/models"
 	"github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
 	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+	"gorm.io/gorm"
 )
 
 type CreateServiceRequest struct {
-	FullName       string
-	Email          string
-	DocumentNumber string
-	Password       string
+	FullName           string
+	Email              string
+	DocumentNumber     string
+	CustomerType       models.CustomerType
+	TradeName          string
+	PhoneNumber        string
+	BirthDate          *time.Time
+	MotherName         string
+	PoliticallyExposed bool
+	Address            models.Address
+	Locale             string
+	Password           string
 }
 
 type CreateUserService interface {
-	Execute(request *CreateServiceRequest) (*models.User, *models.Account, error)
+	Execute(ctx context.Context, request *CreateServiceRequest) (*models.User, *models.Account, error)
+	WithTx(tx *gorm.DB) CreateUserService
 }
 
 type createUserService struct {
@@ -26,18 +39,31 @@ func NewCreateUserService(userRepo repositories.UserRepository) CreateUserServic
 	return &createUserService{userRepo: userRepo}
 }
 
-func (s *createUserService) Execute(request *CreateServiceRequest) (*models.User, *models.Account, error) {
+func (s *createUserService) Execute(ctx context.Context, request *CreateServiceRequest) (*models.User, *models.Account, error) {
 	passwordHash, err := crypto.HashPassword(request.Password)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	user := &models.User{
-		FullName:       request.FullName,
-		Email:          request.Email,
-		DocumentNumber: request.DocumentNumber,
-		PasswordHash:   passwordHash,
+		FullName:           request.FullName,
+		Email:              request.Email,
+		DocumentNumber:     request.DocumentNumber,
+		CustomerType:       request.CustomerType,
+		TradeName:          request.TradeName,
+		PhoneNumber:        request.PhoneNumber,
+		BirthDate:          request.BirthDate,
+		MotherName:         request.MotherName,
+		PoliticallyExposed: request.PoliticallyExposed,
+		Address:            request.Address,
+		Locale:             request.Locale,
+		PasswordHash:       passwordHash,
 	}
 
-	return s.userRepo.CreateUserWithAccount(user)
+	return s.userRepo.CreateUserWithAccount(ctx, user)
+}
+
+// WithTx retorna uma cópia do serviço que cria o usuário na transação informada.
+func (s *createUserService) WithTx(tx *gorm.DB) CreateUserService {
+	return &createUserService{userRepo: s.userRepo.WithTx(tx)}
 }
@@ -5,6 +5,8 @@ import (
 	"crypto/rand"
 	"crypto/sha256"
 	"encoding/hex"
+	"fmt"
+	"math/big"
 )
 
 func HashTokenSHA256(token string) string {
@@ -24,3 +26,16 @@ func GenerateVerificationToken() (rawToken string, hashedToken string, err error
 
 	return rawToken, hashedToken, nil
 }
+
+// GenerateNumericCode gera um código numérico aleatório com a quantidade de dígitos informada,
+// preservando zeros à esquerda (ex: "042917").
+func GenerateNumericCode(digits int) (string, error) {
+	limit := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(digits)), nil)
+
+	n, err := rand.Int(rand.Reader, limit)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%0*d", digits, n), nil
+}
@@ -48,3 +48,14 @@ func TestGenerateVerificationToken(t *testing.T) {
 		assert.NotEqual(t, raw1, raw2)
 	})
 }
+
+func TestGenerateNumericCode(t *testing.T) {
+	t.Run("Should generate a code with the requested number of digits", func(t *testing.T) {
+		for range 100 {
+			code, err := crypto.GenerateNumericCode(6)
+
+			assert.NoError(t, err)
+			assert.Regexp(t, `^\d{6}$`, code)
+		}
+	})
+}
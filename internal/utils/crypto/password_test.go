@@ -52,3 +52,42 @@ func TestHashPassword(t *testing.T) {
 		assert.True(t, strings.HasPrefix(hash, "$argon2id$"))
 	})
 }
+
+func TestVerifyPassword(t *testing.T) {
+	password := "StrongPassword123!"
+	hash, err := crypto.HashPassword(password)
+	assert.NoError(t, err)
+
+	t.Run("Should return true for the correct password", func(t *testing.T) {
+		match, err := crypto.VerifyPassword(password, hash)
+
+		assert.NoError(t, err)
+		assert.True(t, match)
+	})
+
+	t.Run("Should return false for a wrong password", func(t *testing.T) {
+		match, err := crypto.VerifyPassword("WrongPassword123!", hash)
+
+		assert.NoError(t, err)
+		assert.False(t, match)
+	})
+
+	t.Run("Should return error for a malformed hash", func(t *testing.T) {
+		match, err := crypto.VerifyPassword(password, "$argon2id$v=19$invalid")
+
+		assert.ErrorIs(t, err, crypto.ErrInvalidHash)
+		assert.False(t, match)
+	})
+
+	t.Run("Should return error for another algorithm", func(t *testing.T) {
+		_, err := crypto.VerifyPassword(password, strings.Replace(hash, "argon2id", "argon2i", 1))
+
+		assert.ErrorIs(t, err, crypto.ErrInvalidHash)
+	})
+
+	t.Run("Should return error for an incompatible version", func(t *testing.T) {
+		_, err := crypto.VerifyPassword(password, strings.Replace(hash, "v=19", "v=16", 1))
+
+		assert.ErrorIs(t, err, crypto.ErrIncompatibleVersion)
+	})
+}
@@ -0,0 +1,188 @@
+// Package fieldcrypto provides field-level encryption for personal data stored in the database,
+// with rotating keys identified by key IDs and an HMAC blind index for exact-match lookups.
+package fieldcrypto
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ciphertextPrefix marca os valores cifrados. Valores sem o prefixo são texto puro gravado antes
+// da criptografia e continuam legíveis até o comando de recriptografia processá-los.
+const ciphertextPrefix = "enc:"
+
+const (
+	keySize      = 32
+	minIndexSize = 32
+)
+
+var (
+	ErrKeyringNotConfigured = errors.New("field encryption keyring not configured")
+	ErrUnknownKeyID         = errors.New("unknown field encryption key ID")
+	ErrMalformedCiphertext  = errors.New("malformed field ciphertext")
+)
+
+// Keyring guarda as chaves AES-256 indexadas por ID. Novos valores são sempre cifrados com a chave
+// ativa; as demais só servem para ler dados ainda não recriptografados.
+type Keyring struct {
+	keys        map[string]cipher.AEAD
+	activeKeyID string
+	indexKey    []byte
+}
+
+// NewKeyring valida as chaves e monta o keyring. activeKeyID deve estar entre as chaves e indexKey
+// é a chave do HMAC do blind index, que não rotaciona junto com as chaves de criptografia.
+func NewKeyring(keys map[string][]byte, activeKeyID string, indexKey []byte) (*Keyring, error) {
+	if len(keys) == 0 {
+		return nil, errors.New("at least one field encryption key is required")
+	}
+	if _, ok := keys[activeKeyID]; !ok {
+		return nil, fmt.Errorf("active field encryption key %q not found", activeKeyID)
+	}
+	if len(indexKey) < minIndexSize {
+		return nil, fmt.Errorf("blind index key must be at least %d bytes long", minIndexSize)
+	}
+
+	keyring := &Keyring{keys: make(map[string]cipher.AEAD, len(keys)), activeKeyID: activeKeyID, indexKey: indexKey}
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid field encryption key ID %q", id)
+		}
+		if len(key) != keySize {
+			return nil, fmt.Errorf("field encryption key %q must be %d bytes long", id, keySize)
+		}
+
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		aead, err := cipher.NewGCM(block)
+		if err != nil {
+			return nil, err
+		}
+		keyring.keys[id] = aead
+	}
+
+	return keyring, nil
+}
+
+// KeyringFromEnv lê FIELD_ENCRYPTION_KEYS (obrigatório, ex: "2025-01:<base64>,2026-10:<base64>"),
+// FIELD_ENCRYPTION_ACTIVE_KEY (opcional, padrão é a última chave da lista) e BLIND_INDEX_KEY
+// (obrigatório, em base64).
+func KeyringFromEnv() (*Keyring, error) {
+	rawKeys := os.Getenv("FIELD_ENCRYPTION_KEYS")
+	if rawKeys == "" {
+		return nil, errors.New("FIELD_ENCRYPTION_KEYS environment variable not set")
+	}
+
+	keys := make(map[string][]byte)
+	var lastKeyID string
+	for _, entry := range strings.Split(rawKeys, ",") {
+		id, encoded, found := strings.Cut(strings.TrimSpace(entry), ":")
+		if !found {
+			return nil, fmt.Errorf("invalid FIELD_ENCRYPTION_KEYS entry %q, expected <id>:<base64>", entry)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("invalid base64 for field encryption key %q", id)
+		}
+		keys[id] = key
+		lastKeyID = id
+	}
+
+	activeKeyID := os.Getenv("FIELD_ENCRYPTION_ACTIVE_KEY")
+	if activeKeyID == "" {
+		activeKeyID = lastKeyID
+	}
+
+	indexKey, err := base64.StdEncoding.DecodeString(os.Getenv("BLIND_INDEX_KEY"))
+	if err != nil {
+		return nil, errors.New("invalid base64 for BLIND_INDEX_KEY")
+	}
+
+	return NewKeyring(keys, activeKeyID, indexKey)
+}
+
+// ActiveKeyID retorna o ID da chave usada para cifrar novos valores.
+func (k *Keyring) ActiveKeyID() string {
+	return k.activeKeyID
+}
+
+// Encrypt cifra o valor com a chave ativa no formato "enc:<keyID>:<base64(nonce|ciphertext)>".
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	aead := k.keys[k.activeKeyID]
+
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return "", err
+	}
+
+	sealed := aead.Seal(nonce, nonce, []byte(plaintext), nil)
+	return ciphertextPrefix + k.activeKeyID + ":" + base64.StdEncoding.EncodeToString(sealed), nil
+}
+
+// Decrypt decifra um valor produzido por Encrypt com qualquer chave do keyring. Valores sem o
+// prefixo são devolvidos como estão, pois foram gravados antes da criptografia.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	if !IsEncrypted(value) {
+		return value, nil
+	}
+
+	keyID, encoded, found := strings.Cut(strings.TrimPrefix(value, ciphertextPrefix), ":")
+	if !found {
+		return "", ErrMalformedCiphertext
+	}
+
+	aead, ok := k.keys[keyID]
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrUnknownKeyID, keyID)
+	}
+
+	sealed, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(sealed) < aead.NonceSize() {
+		return "", ErrMalformedCiphertext
+	}
+
+	nonce, ciphertext := sealed[:aead.NonceSize()], sealed[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return "", fmt.Errorf("decrypting field with key %s: %w", keyID, err)
+	}
+	return string(plaintext), nil
+}
+
+// IsCurrent indica se o valor já está cifrado com a chave ativa. Valores vazios não são cifrados
+// e, por isso, estão sempre em dia.
+func (k *Keyring) IsCurrent(value string) bool {
+	if value == "" {
+		return true
+	}
+	return strings.HasPrefix(value, ciphertextPrefix+k.activeKeyID+":")
+}
+
+// BlindIndex calcula o HMAC-SHA256 do valor normalizado (sem espaços nas pontas e em minúsculas),
+// permitindo buscas por igualdade sem decifrar a coluna. Valores vazios geram um índice vazio.
+func (k *Keyring) BlindIndex(value string) string {
+	normalized := strings.ToLower(strings.TrimSpace(value))
+	if normalized == "" {
+		return ""
+	}
+
+	mac := hmac.New(sha256.New, k.indexKey)
+	mac.Write([]byte(normalized))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// IsEncrypted indica se o valor foi gravado por Encrypt.
+func IsEncrypted(value string) bool {
+	return strings.HasPrefix(value, ciphertextPrefix)
+}
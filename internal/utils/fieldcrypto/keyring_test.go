@@ -0,0 +1,153 @@
+package fieldcrypto_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"strings"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	oldKey   = bytes.Repeat([]byte{0x01}, 32)
+	newKey   = bytes.Repeat([]byte{0x02}, 32)
+	indexKey = bytes.Repeat([]byte{0x03}, 32)
+)
+
+func newKeyring(t *testing.T, keys map[string][]byte, activeKeyID string) *fieldcrypto.Keyring {
+	keyring, err := fieldcrypto.NewKeyring(keys, activeKeyID, indexKey)
+	require.NoError(t, err)
+	return keyring
+}
+
+func TestKeyring_EncryptDecrypt(t *testing.T) {
+	t.Run("Should round trip with the active key and a random nonce", func(t *testing.T) {
+		keyring := newKeyring(t, map[string][]byte{"k1": oldKey}, "k1")
+
+		first, err := keyring.Encrypt("12345678909")
+		require.NoError(t, err)
+		second, err := keyring.Encrypt("12345678909")
+		require.NoError(t, err)
+
+		assert.True(t, strings.HasPrefix(first, "enc:k1:"))
+		assert.NotEqual(t, first, second, "the same plaintext must not produce the same ciphertext")
+
+		plaintext, err := keyring.Decrypt(first)
+		require.NoError(t, err)
+		assert.Equal(t, "12345678909", plaintext)
+	})
+
+	t.Run("Should read values encrypted with a rotated key", func(t *testing.T) {
+		before := newKeyring(t, map[string][]byte{"k1": oldKey}, "k1")
+		after := newKeyring(t, map[string][]byte{"k1": oldKey, "k2": newKey}, "k2")
+
+		ciphertext, err := before.Encrypt("john@example.com")
+		require.NoError(t, err)
+
+		plaintext, err := after.Decrypt(ciphertext)
+		require.NoError(t, err)
+		assert.Equal(t, "john@example.com", plaintext)
+		assert.False(t, after.IsCurrent(ciphertext))
+
+		reencrypted, err := after.Encrypt(plaintext)
+		require.NoError(t, err)
+		assert.True(t, after.IsCurrent(reencrypted))
+	})
+
+	t.Run("Should return legacy plaintext as is", func(t *testing.T) {
+		keyring := newKeyring(t, map[string][]byte{"k1": oldKey}, "k1")
+
+		plaintext, err := keyring.Decrypt("Jane Doe")
+
+		require.NoError(t, err)
+		assert.Equal(t, "Jane Doe", plaintext)
+		assert.False(t, keyring.IsCurrent("Jane Doe"))
+	})
+
+	t.Run("Should fail for unknown keys and tampered ciphertexts", func(t *testing.T) {
+		before := newKeyring(t, map[string][]byte{"k1": oldKey}, "k1")
+		after := newKeyring(t, map[string][]byte{"k2": newKey}, "k2")
+
+		ciphertext, err := before.Encrypt("John Doe")
+		require.NoError(t, err)
+
+		_, err = after.Decrypt(ciphertext)
+		assert.ErrorIs(t, err, fieldcrypto.ErrUnknownKeyID)
+
+		sealed, err := base64.StdEncoding.DecodeString(strings.TrimPrefix(ciphertext, "enc:k1:"))
+		require.NoError(t, err)
+		sealed[len(sealed)-1] ^= 0xff
+		_, err = before.Decrypt("enc:k1:" + base64.StdEncoding.EncodeToString(sealed))
+		assert.Error(t, err)
+
+		_, err = before.Decrypt("enc:k1")
+		assert.ErrorIs(t, err, fieldcrypto.ErrMalformedCiphertext)
+	})
+}
+
+func TestKeyring_BlindIndex(t *testing.T) {
+	keyring := newKeyring(t, map[string][]byte{"k1": oldKey}, "k1")
+	rotated := newKeyring(t, map[string][]byte{"k1": oldKey, "k2": newKey}, "k2")
+
+	index := keyring.BlindIndex("john@example.com")
+
+	assert.Len(t, index, 64)
+	assert.Equal(t, index, keyring.BlindIndex("  John@Example.com "), "the index must be normalized")
+	assert.Equal(t, index, rotated.BlindIndex("john@example.com"), "rotating encryption keys must not change the index")
+	assert.NotEqual(t, index, keyring.BlindIndex("jane@example.com"))
+	assert.Empty(t, keyring.BlindIndex(""))
+}
+
+func TestNewKeyring_Validation(t *testing.T) {
+	_, err := fieldcrypto.NewKeyring(map[string][]byte{"k1": oldKey}, "k2", indexKey)
+	assert.Error(t, err, "the active key must exist")
+
+	_, err = fieldcrypto.NewKeyring(map[string][]byte{"k1": oldKey[:16]}, "k1", indexKey)
+	assert.Error(t, err, "keys must be 32 bytes long")
+
+	_, err = fieldcrypto.NewKeyring(map[string][]byte{"k:1": oldKey}, "k:1", indexKey)
+	assert.Error(t, err, "key IDs must not contain the separator")
+
+	_, err = fieldcrypto.NewKeyring(map[string][]byte{"k1": oldKey}, "k1", indexKey[:8])
+	assert.Error(t, err, "the blind index key must be at least 32 bytes long")
+}
+
+func TestKeyringFromEnv(t *testing.T) {
+	encode := base64.StdEncoding.EncodeToString
+
+	t.Run("Should default the active key to the last one", func(t *testing.T) {
+		t.Setenv("FIELD_ENCRYPTION_KEYS", "2025-01:"+encode(oldKey)+", 2026-10:"+encode(newKey))
+		t.Setenv("FIELD_ENCRYPTION_ACTIVE_KEY", "")
+		t.Setenv("BLIND_INDEX_KEY", encode(indexKey))
+
+		keyring, err := fieldcrypto.KeyringFromEnv()
+
+		require.NoError(t, err)
+		assert.Equal(t, "2026-10", keyring.ActiveKeyID())
+	})
+
+	t.Run("Should honour the configured active key", func(t *testing.T) {
+		t.Setenv("FIELD_ENCRYPTION_KEYS", "2025-01:"+encode(oldKey)+",2026-10:"+encode(newKey))
+		t.Setenv("FIELD_ENCRYPTION_ACTIVE_KEY", "2025-01")
+		t.Setenv("BLIND_INDEX_KEY", encode(indexKey))
+
+		keyring, err := fieldcrypto.KeyringFromEnv()
+
+		require.NoError(t, err)
+		assert.Equal(t, "2025-01", keyring.ActiveKeyID())
+	})
+
+	t.Run("Should require the keys and the blind index key", func(t *testing.T) {
+		t.Setenv("FIELD_ENCRYPTION_KEYS", "")
+		_, err := fieldcrypto.KeyringFromEnv()
+		assert.Error(t, err)
+
+		t.Setenv("FIELD_ENCRYPTION_KEYS", "2026-10:"+encode(newKey))
+		t.Setenv("BLIND_INDEX_KEY", "")
+		_, err = fieldcrypto.KeyringFromEnv()
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,112 @@
+package fieldcrypto
+
+import (
+	"context"
+	"fmt"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// EncryptedColumn descreve uma coluna cifrada e, se houver, a coluna com seu blind index.
+type EncryptedColumn struct {
+	Name        string
+	IndexColumn string
+}
+
+// Table descreve uma tabela com colunas cifradas e chave primária "id".
+type Table struct {
+	Name    string
+	Columns []EncryptedColumn
+}
+
+// Reencrypt percorre a tabela em lotes e regrava, com a chave ativa, os valores cifrados com
+// chaves antigas ou ainda em texto puro, recalculando os blind indexes. Cada lote roda em uma
+// transação com as linhas bloqueadas, para não sobrescrever uma alteração concorrente da API.
+// Retorna quantas linhas foram regravadas.
+func Reencrypt(ctx context.Context, db *gorm.DB, keyring *Keyring, table Table, batchSize int) (int, error) {
+	columns := []string{"id"}
+	for _, column := range table.Columns {
+		columns = append(columns, column.Name)
+		if column.IndexColumn != "" {
+			columns = append(columns, column.IndexColumn)
+		}
+	}
+
+	var total int
+	var lastID int64
+	for {
+		var rewritten, read int
+		err := db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			var rows []map[string]any
+			result := tx.Table(table.Name).
+				Select(columns).
+				Where("id > ?", lastID).
+				Order("id").
+				Limit(batchSize).
+				Clauses(clause.Locking{Strength: "UPDATE"}).
+				Find(&rows)
+			if result.Error != nil {
+				return result.Error
+			}
+			read = len(rows)
+
+			for _, row := range rows {
+				lastID = row["id"].(int64)
+
+				updates, err := reencryptRow(keyring, table.Columns, row)
+				if err != nil {
+					return fmt.Errorf("%s id %d: %w", table.Name, lastID, err)
+				}
+				if len(updates) == 0 {
+					continue
+				}
+
+				if err := tx.Table(table.Name).Where("id = ?", lastID).UpdateColumns(updates).Error; err != nil {
+					return err
+				}
+				rewritten++
+			}
+			return nil
+		})
+		if err != nil {
+			return total, err
+		}
+
+		total += rewritten
+		if read < batchSize {
+			return total, nil
+		}
+	}
+}
+
+// reencryptRow retorna as colunas a regravar, ou nada se a linha já está com a chave ativa e
+// com os blind indexes preenchidos.
+func reencryptRow(keyring *Keyring, columns []EncryptedColumn, row map[string]any) (map[string]any, error) {
+	updates := make(map[string]any)
+
+	for _, column := range columns {
+		stored, _ := row[column.Name].(string)
+		plaintext, err := keyring.Decrypt(stored)
+		if err != nil {
+			return nil, err
+		}
+
+		if !keyring.IsCurrent(stored) {
+			ciphertext, err := keyring.Encrypt(plaintext)
+			if err != nil {
+				return nil, err
+			}
+			updates[column.Name] = ciphertext
+		}
+
+		if column.IndexColumn != "" {
+			index := keyring.BlindIndex(plaintext)
+			if current, _ := row[column.IndexColumn].(string); current != index {
+				updates[column.IndexColumn] = index
+			}
+		}
+	}
+
+	return updates, nil
+}
@@ -0,0 +1,92 @@
+package fieldcrypto
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"gorm.io/gorm/schema"
+)
+
+// SerializerName é o nome usado na tag dos modelos: `gorm:"serializer:encrypted"`.
+const SerializerName = "encrypted"
+
+var defaultKeyring atomic.Pointer[Keyring]
+
+func init() {
+	schema.RegisterSerializer(SerializerName, EncryptedSerializer{})
+}
+
+// SetKeyring define o keyring usado pelo serializer e pelos blind indexes dos modelos. Deve ser
+// chamado na inicialização, antes de qualquer acesso ao banco.
+func SetKeyring(keyring *Keyring) {
+	defaultKeyring.Store(keyring)
+}
+
+// CurrentKeyring retorna o keyring configurado por SetKeyring.
+func CurrentKeyring() (*Keyring, error) {
+	keyring := defaultKeyring.Load()
+	if keyring == nil {
+		return nil, ErrKeyringNotConfigured
+	}
+	return keyring, nil
+}
+
+// BlindIndex calcula o blind index do valor com o keyring configurado.
+func BlindIndex(value string) (string, error) {
+	keyring, err := CurrentKeyring()
+	if err != nil {
+		return "", err
+	}
+	return keyring.BlindIndex(value), nil
+}
+
+// EncryptedSerializer cifra campos string ao gravar e os decifra ao ler. Strings vazias são
+// gravadas como estão, para não cifrar campos apagados pela anonimização.
+type EncryptedSerializer struct{}
+
+// Scan implementa schema.SerializerInterface.
+func (EncryptedSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var stored string
+	switch v := dbValue.(type) {
+	case nil:
+	case string:
+		stored = v
+	case []byte:
+		stored = string(v)
+	default:
+		return fmt.Errorf("unsupported type %T for encrypted field %s", dbValue, field.Name)
+	}
+
+	plaintext := stored
+	if IsEncrypted(stored) {
+		keyring, err := CurrentKeyring()
+		if err != nil {
+			return err
+		}
+		if plaintext, err = keyring.Decrypt(stored); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+
+	field.ReflectValueOf(ctx, dst).SetString(plaintext)
+	return nil
+}
+
+// Value implementa schema.SerializerValuerInterface.
+func (EncryptedSerializer) Value(_ context.Context, field *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	plaintext, ok := fieldValue.(string)
+	if !ok {
+		return nil, fmt.Errorf("encrypted field %s must be a string, got %T", field.Name, fieldValue)
+	}
+	if plaintext == "" {
+		return "", nil
+	}
+
+	keyring, err := CurrentKeyring()
+	if err != nil {
+		return nil, err
+	}
+	return keyring.Encrypt(plaintext)
+}
@@ -0,0 +1,60 @@
+package fieldcrypto_test
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/fieldcrypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+type customer struct {
+	ID    int64
+	Email string `gorm:"serializer:encrypted"`
+}
+
+func TestEncryptedSerializer(t *testing.T) {
+	keyring := newKeyring(t, map[string][]byte{"k1": oldKey}, "k1")
+	fieldcrypto.SetKeyring(keyring)
+	t.Cleanup(func() { fieldcrypto.SetKeyring(nil) })
+
+	parsed, err := schema.Parse(&customer{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	field := parsed.LookUpField("Email")
+	serializer := fieldcrypto.EncryptedSerializer{}
+
+	t.Run("Should encrypt on write and decrypt on read", func(t *testing.T) {
+		stored, err := serializer.Value(context.Background(), field, reflect.Value{}, "john@example.com")
+		require.NoError(t, err)
+		assert.True(t, fieldcrypto.IsEncrypted(stored.(string)))
+
+		var loaded customer
+		require.NoError(t, serializer.Scan(context.Background(), field, reflect.ValueOf(&loaded).Elem(), []byte(stored.(string))))
+		assert.Equal(t, "john@example.com", loaded.Email)
+	})
+
+	t.Run("Should keep empty values and read legacy plaintext", func(t *testing.T) {
+		stored, err := serializer.Value(context.Background(), field, reflect.Value{}, "")
+		require.NoError(t, err)
+		assert.Equal(t, "", stored)
+
+		var loaded customer
+		require.NoError(t, serializer.Scan(context.Background(), field, reflect.ValueOf(&loaded).Elem(), "jane@example.com"))
+		assert.Equal(t, "jane@example.com", loaded.Email)
+	})
+
+	t.Run("Should fail without a keyring", func(t *testing.T) {
+		fieldcrypto.SetKeyring(nil)
+		defer fieldcrypto.SetKeyring(keyring)
+
+		_, err := serializer.Value(context.Background(), field, reflect.Value{}, "john@example.com")
+		assert.ErrorIs(t, err, fieldcrypto.ErrKeyringNotConfigured)
+
+		_, err = fieldcrypto.BlindIndex("john@example.com")
+		assert.ErrorIs(t, err, fieldcrypto.ErrKeyringNotConfigured)
+	})
+}
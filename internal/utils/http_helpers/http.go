@@ -38,6 +38,16 @@ func FormatValidationErrors(errs validator.ValidationErrors) map[string]string {
 			errorMessages[jsonField] = "O formato do e-mail é inválido."
 		case "numeric":
 			errorMessages[jsonField] = fmt.Sprintf("O campo '%s' deve conter apenas números.", jsonField)
+		case "e164":
+			errorMessages[jsonField] = fmt.Sprintf("O campo '%s' deve estar no formato internacional, por exemplo +5511912345678.", jsonField)
+		case "datetime":
+			errorMessages[jsonField] = fmt.Sprintf("O campo '%s' deve ser uma data no formato AAAA-MM-DD.", jsonField)
+		case "max":
+			errorMessages[jsonField] = fmt.Sprintf("O campo '%s' deve ter no máximo %s caracteres.", jsonField, err.Param())
+		case "len":
+			errorMessages[jsonField] = fmt.Sprintf("O campo '%s' deve ter exatamente %s caracteres.", jsonField, err.Param())
+		case "oneof":
+			errorMessages[jsonField] = fmt.Sprintf("O campo '%s' deve ser um dos valores: %s.", jsonField, strings.ReplaceAll(err.Param(), " ", ", "))
 		default:
 			errorMessages[jsonField] = fmt.Sprintf("O campo '%s' é inválido.", jsonField)
 		}
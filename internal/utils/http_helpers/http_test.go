@@ -111,4 +111,21 @@ func TestFormatValidationErrors(t *testing.T) {
 		// Teste do caso default
 		assert.Equal(t, "O campo 'other' é inválido.", formatted["other"])
 	})
+
+	t.Run("Should include the tag parameter in the message", func(t *testing.T) {
+		type sample struct {
+			BirthDate string `validate:"datetime=2006-01-02"`
+			Name      string `validate:"max=3"`
+			State     string `validate:"len=2"`
+			Channel   string `validate:"oneof=sms whatsapp"`
+		}
+
+		err := validate.Struct(sample{BirthDate: "31/12/1990", Name: "John", State: "SPX", Channel: "email"})
+		formatted := http_helpers.FormatValidationErrors(err.(validator.ValidationErrors))
+
+		assert.Equal(t, "O campo 'birthDate' deve ser uma data no formato AAAA-MM-DD.", formatted["birthDate"])
+		assert.Equal(t, "O campo 'name' deve ter no máximo 3 caracteres.", formatted["name"])
+		assert.Equal(t, "O campo 'state' deve ter exatamente 2 caracteres.", formatted["state"])
+		assert.Equal(t, "O campo 'channel' deve ser um dos valores: sms, whatsapp.", formatted["channel"])
+	})
 }
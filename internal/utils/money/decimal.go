@@ -0,0 +1,107 @@
+package money
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+var (
+	ErrInvalidAmount = errors.New("money: invalid amount")
+	ErrInexactAmount = errors.New("money: amount has fractions of a centavo")
+)
+
+var bigHundred = big.NewInt(100)
+
+// ParseDecimal lê um valor decimal com ponto ("1234.56", "-0.5", "10") em reais. Casas além dos
+// centavos só são aceitas se forem zeros, como as de uma coluna NUMERIC(20,4).
+func ParseDecimal(value string) (Money, error) {
+	numerator, denominator, err := parseDecimal(value)
+	if err != nil {
+		return Money{}, err
+	}
+
+	scaled := new(big.Int).Mul(numerator, bigHundred)
+	quotient, remainder := new(big.Int).QuoRem(scaled, denominator, new(big.Int))
+	if remainder.Sign() != 0 {
+		return Money{}, ErrInexactAmount
+	}
+	if !quotient.IsInt64() {
+		return Money{}, ErrOverflow
+	}
+	return FromCentavos(quotient.Int64()), nil
+}
+
+// ParseDecimalRounded lê um valor decimal com qualquer número de casas, arredondando ao centavo.
+func ParseDecimalRounded(value string, mode RoundingMode) (Money, error) {
+	numerator, denominator, err := parseDecimal(value)
+	if err != nil {
+		return Money{}, err
+	}
+
+	centavos, err := roundQuotient(new(big.Int).Mul(numerator, bigHundred), denominator, mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return FromCentavos(centavos), nil
+}
+
+// parseDecimal converte "123.4567" em 1234567/10000, sem passar por ponto flutuante.
+func parseDecimal(value string) (*big.Int, *big.Int, error) {
+	value = strings.TrimSpace(value)
+
+	digits := value
+	if strings.HasPrefix(digits, "-") || strings.HasPrefix(digits, "+") {
+		digits = digits[1:]
+	}
+	integer, fraction, _ := strings.Cut(digits, ".")
+	if integer == "" || !isDigits(integer) || (strings.Contains(digits, ".") && (fraction == "" || !isDigits(fraction))) {
+		return nil, nil, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	numerator, _ := new(big.Int).SetString(integer+fraction, 10)
+	if strings.HasPrefix(value, "-") {
+		numerator.Neg(numerator)
+	}
+	denominator := new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(len(fraction))), nil)
+	return numerator, denominator, nil
+}
+
+func isDigits(value string) bool {
+	for _, r := range value {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// Decimal retorna o valor como decimal com ponto e duas casas ("1234.56", "-0.05").
+func (m Money) Decimal() string {
+	sign, integer, centavos := m.parts()
+	return sign + integer + "." + centavos
+}
+
+// MarshalJSON codifica o valor como string decimal ("1234.56"), que os clientes leem sem perder
+// precisão. A moeda não é incluída.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Decimal())
+}
+
+// UnmarshalJSON aceita apenas strings decimais exatas em centavos; números JSON são recusados
+// para que nenhum cliente envie valores em ponto flutuante. O valor lido é em reais.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var value string
+	if err := json.Unmarshal(data, &value); err != nil {
+		return fmt.Errorf("%w: expected a decimal string", ErrInvalidAmount)
+	}
+
+	parsed, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
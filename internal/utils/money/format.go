@@ -0,0 +1,84 @@
+package money
+
+import (
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// Format retorna o valor no formato brasileiro: "R$ 1.234,56" e "-R$ 0,05".
+func (m Money) Format() string {
+	sign, integer, centavos := m.parts()
+	return sign + "R$ " + groupThousands(integer) + "," + centavos
+}
+
+// ParseBRL lê valores no formato brasileiro, com ou sem o símbolo e os separadores de milhar:
+// "R$ 1.234,56", "1234,56", "-R$ 10", "R$ 0,5". Mais de duas casas decimais são recusadas.
+func ParseBRL(value string) (Money, error) {
+	normalized := strings.TrimSpace(strings.ReplaceAll(value, "\u00a0", " "))
+
+	negative := strings.HasPrefix(normalized, "-")
+	normalized = strings.TrimPrefix(normalized, "-")
+	normalized = strings.TrimSpace(strings.TrimPrefix(normalized, "R$"))
+	if !negative && strings.HasPrefix(normalized, "-") {
+		negative = true
+		normalized = strings.TrimSpace(normalized[1:])
+	}
+
+	integer, fraction, hasFraction := strings.Cut(normalized, ",")
+	if hasFraction && (len(fraction) == 0 || len(fraction) > 2 || !isDigits(fraction)) {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	integer, ok := ungroupThousands(integer)
+	if !ok {
+		return Money{}, fmt.Errorf("%w: %q", ErrInvalidAmount, value)
+	}
+
+	decimal := integer
+	if hasFraction {
+		decimal += "." + fraction
+	}
+	if negative {
+		decimal = "-" + decimal
+	}
+	return ParseDecimal(decimal)
+}
+
+// parts decompõe o valor em sinal, parte inteira em reais e os dois dígitos dos centavos.
+func (m Money) parts() (sign, integer, centavos string) {
+	abs := new(big.Int).Abs(big.NewInt(m.amount))
+	if m.amount < 0 {
+		sign = "-"
+	}
+
+	quotient, remainder := new(big.Int).QuoRem(abs, bigHundred, new(big.Int))
+	return sign, quotient.String(), strconv.FormatInt(remainder.Int64()+100, 10)[1:]
+}
+
+// groupThousands insere os pontos de milhar em uma sequência de dígitos.
+func groupThousands(digits string) string {
+	var builder strings.Builder
+	for i, digit := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			builder.WriteByte('.')
+		}
+		builder.WriteRune(digit)
+	}
+	return builder.String()
+}
+
+// ungroupThousands remove os pontos de milhar, exigindo grupos de três dígitos depois do primeiro.
+func ungroupThousands(value string) (string, bool) {
+	groups := strings.Split(value, ".")
+	if groups[0] == "" || (len(groups[0]) > 3 && len(groups) > 1) {
+		return "", false
+	}
+	for i, group := range groups {
+		if !isDigits(group) || (i > 0 && len(group) != 3) {
+			return "", false
+		}
+	}
+	return strings.Join(groups, ""), true
+}
@@ -0,0 +1,152 @@
+package money_test
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/schema"
+)
+
+func TestMoney_Format(t *testing.T) {
+	tests := []struct {
+		centavos int64
+		expected string
+	}{
+		{0, "R$ 0,00"},
+		{5, "R$ 0,05"},
+		{123456, "R$ 1.234,56"},
+		{100000000, "R$ 1.000.000,00"},
+		{-5, "-R$ 0,05"},
+		{-123456, "-R$ 1.234,56"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expected, func(t *testing.T) {
+			assert.Equal(t, tt.expected, money.FromCentavos(tt.centavos).Format())
+		})
+	}
+}
+
+func TestParseBRL(t *testing.T) {
+	valid := map[string]int64{
+		"R$ 1.234,56":      123456,
+		"R$1.234,56":       123456,
+		"R$\u00a01.234,56": 123456,
+		"1234,56":          123456,
+		"1.234":            123400,
+		"R$ 0,5":           50,
+		"R$ 10":            1000,
+		"-R$ 10,00":        -1000,
+		"R$ -10,00":        -1000,
+		"1.000.000,01":     100000001,
+	}
+	for input, expected := range valid {
+		t.Run(input, func(t *testing.T) {
+			parsed, err := money.ParseBRL(input)
+			require.NoError(t, err)
+			assert.Equal(t, expected, parsed.Centavos())
+		})
+	}
+
+	for _, input := range []string{"", "R$", "1234.56", "1,234", "12.34,00", "R$ 1,", "10,001", "abc", "1.2345,00"} {
+		t.Run("invalid "+input, func(t *testing.T) {
+			_, err := money.ParseBRL(input)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestParseDecimal(t *testing.T) {
+	parsed, err := money.ParseDecimal("1234.56")
+	require.NoError(t, err)
+	assert.Equal(t, int64(123456), parsed.Centavos())
+
+	parsed, err = money.ParseDecimal("-0.5")
+	require.NoError(t, err)
+	assert.Equal(t, int64(-50), parsed.Centavos())
+
+	parsed, err = money.ParseDecimal("10.5000")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1050), parsed.Centavos(), "trailing zeros from NUMERIC columns are accepted")
+
+	_, err = money.ParseDecimal("10.005")
+	assert.ErrorIs(t, err, money.ErrInexactAmount)
+
+	_, err = money.ParseDecimal("92233720368547758.08")
+	assert.ErrorIs(t, err, money.ErrOverflow)
+
+	for _, input := range []string{"", "1.", ".5", "1,5", "1e3", "--1"} {
+		_, err = money.ParseDecimal(input)
+		assert.ErrorIs(t, err, money.ErrInvalidAmount, input)
+	}
+
+	rounded, err := money.ParseDecimalRounded("10.005", money.RoundHalfUp)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1001), rounded.Centavos())
+
+	rounded, err = money.ParseDecimalRounded("10.005", money.RoundHalfEven)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1000), rounded.Centavos())
+}
+
+func TestMoney_JSON(t *testing.T) {
+	type payload struct {
+		Amount money.Money `json:"amount"`
+	}
+
+	encoded, err := json.Marshal(payload{Amount: money.FromCentavos(-123456)})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"amount":"-1234.56"}`, string(encoded))
+
+	var decoded payload
+	require.NoError(t, json.Unmarshal([]byte(`{"amount":"10.50"}`), &decoded))
+	assert.Equal(t, int64(1050), decoded.Amount.Centavos())
+
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":10.5}`), &decoded), "JSON numbers must be refused")
+	assert.Error(t, json.Unmarshal([]byte(`{"amount":"10.505"}`), &decoded))
+}
+
+func TestMoney_Scan(t *testing.T) {
+	var value money.Money
+
+	require.NoError(t, value.Scan(int64(1050)))
+	assert.Equal(t, int64(1050), value.Centavos(), "BIGINT columns hold centavos")
+
+	require.NoError(t, value.Scan([]byte("1234.5600")))
+	assert.Equal(t, int64(123456), value.Centavos(), "NUMERIC columns hold reais")
+
+	require.NoError(t, value.Scan(nil))
+	assert.True(t, value.IsZero())
+
+	assert.Error(t, value.Scan(10.5), "floats are never accepted")
+
+	stored, err := money.FromCentavos(1050).Value()
+	require.NoError(t, err)
+	assert.Equal(t, int64(1050), stored)
+}
+
+func TestNumericSerializer(t *testing.T) {
+	type fee struct {
+		ID     int64
+		Amount money.Money `gorm:"type:numeric(20,2);serializer:money_numeric"`
+	}
+
+	parsed, err := schema.Parse(&fee{}, &sync.Map{}, schema.NamingStrategy{})
+	require.NoError(t, err)
+	field := parsed.LookUpField("Amount")
+	serializer := money.NumericSerializer{}
+
+	stored, err := serializer.Value(context.Background(), field, reflect.Value{}, money.FromCentavos(123456))
+	require.NoError(t, err)
+	assert.Equal(t, "1234.56", stored)
+
+	var loaded fee
+	require.NoError(t, serializer.Scan(context.Background(), field, reflect.ValueOf(&loaded).Elem(), "1234.56"))
+	assert.Equal(t, int64(123456), loaded.Amount.Centavos())
+}
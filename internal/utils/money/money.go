@@ -0,0 +1,215 @@
+// Package money provides the Money value type: an amount in centavos (int64) and a currency code,
+// with overflow-checked arithmetic, allocation without losing centavos, explicit rounding modes,
+// pt-BR formatting and database/JSON encoding. Floating point is never used.
+package money
+
+import (
+	"errors"
+	"math"
+	"math/big"
+)
+
+// Currency é o código ISO 4217 da moeda.
+type Currency string
+
+const BRL Currency = "BRL"
+
+var (
+	ErrOverflow         = errors.New("money: amount overflows int64 centavos")
+	ErrCurrencyMismatch = errors.New("money: currencies do not match")
+	ErrInvalidRatios    = errors.New("money: allocation ratios must be non-negative and sum to more than zero")
+	ErrDivisionByZero   = errors.New("money: division by zero")
+)
+
+// RoundingMode define como arredondar frações de centavo.
+type RoundingMode int
+
+const (
+	// RoundHalfUp arredonda o meio centavo para longe do zero (0,125 → 0,13; -0,125 → -0,13).
+	RoundHalfUp RoundingMode = iota
+	// RoundHalfEven arredonda o meio centavo para o centavo par, o arredondamento bancário
+	// (0,125 → 0,12; 0,135 → 0,14), que não acumula viés em somas de muitos valores.
+	RoundHalfEven
+)
+
+// Money é um valor monetário imutável em centavos. O valor zero é R$ 0,00.
+type Money struct {
+	amount   int64
+	currency Currency
+}
+
+// New cria um valor de centavos na moeda informada.
+func New(centavos int64, currency Currency) Money {
+	return Money{amount: centavos, currency: currency}
+}
+
+// FromCentavos cria um valor em reais a partir de centavos.
+func FromCentavos(centavos int64) Money {
+	return New(centavos, BRL)
+}
+
+// Centavos retorna o valor em centavos.
+func (m Money) Centavos() int64 {
+	return m.amount
+}
+
+// Currency retorna a moeda do valor; valores sem moeda são considerados em reais.
+func (m Money) Currency() Currency {
+	if m.currency == "" {
+		return BRL
+	}
+	return m.currency
+}
+
+func (m Money) IsZero() bool     { return m.amount == 0 }
+func (m Money) IsPositive() bool { return m.amount > 0 }
+func (m Money) IsNegative() bool { return m.amount < 0 }
+
+// Equal indica se os dois valores têm a mesma moeda e o mesmo valor.
+func (m Money) Equal(other Money) bool {
+	return m.Currency() == other.Currency() && m.amount == other.amount
+}
+
+// Compare retorna -1, 0 ou 1 conforme m seja menor, igual ou maior que other.
+func (m Money) Compare(other Money) (int, error) {
+	if m.Currency() != other.Currency() {
+		return 0, ErrCurrencyMismatch
+	}
+	switch {
+	case m.amount < other.amount:
+		return -1, nil
+	case m.amount > other.amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Add soma os valores, recusando moedas diferentes e resultados fora do int64.
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency() != other.Currency() {
+		return Money{}, ErrCurrencyMismatch
+	}
+	if (other.amount > 0 && m.amount > math.MaxInt64-other.amount) ||
+		(other.amount < 0 && m.amount < math.MinInt64-other.amount) {
+		return Money{}, ErrOverflow
+	}
+	return New(m.amount+other.amount, m.Currency()), nil
+}
+
+// Sub subtrai os valores, recusando moedas diferentes e resultados fora do int64.
+func (m Money) Sub(other Money) (Money, error) {
+	if m.Currency() != other.Currency() {
+		return Money{}, ErrCurrencyMismatch
+	}
+	if (other.amount < 0 && m.amount > math.MaxInt64+other.amount) ||
+		(other.amount > 0 && m.amount < math.MinInt64+other.amount) {
+		return Money{}, ErrOverflow
+	}
+	return New(m.amount-other.amount, m.Currency()), nil
+}
+
+// Negate inverte o sinal do valor.
+func (m Money) Negate() (Money, error) {
+	if m.amount == math.MinInt64 {
+		return Money{}, ErrOverflow
+	}
+	return New(-m.amount, m.Currency()), nil
+}
+
+// MulRat multiplica o valor pela fração numerator/denominator (ex: 15/1000 para 1,5%) e arredonda
+// o resultado ao centavo com o modo informado.
+func (m Money) MulRat(numerator, denominator int64, mode RoundingMode) (Money, error) {
+	if denominator == 0 {
+		return Money{}, ErrDivisionByZero
+	}
+
+	product := new(big.Int).Mul(big.NewInt(m.amount), big.NewInt(numerator))
+	result, err := roundQuotient(product, big.NewInt(denominator), mode)
+	if err != nil {
+		return Money{}, err
+	}
+	return New(result, m.Currency()), nil
+}
+
+// Allocate divide o valor proporcionalmente aos pesos sem perder centavos: cada parte recebe o
+// quociente inteiro e os centavos que sobram vão, um a um, para as primeiras partes.
+// Ex: R$ 0,05 em [1, 1] resulta em [R$ 0,03, R$ 0,02].
+func (m Money) Allocate(ratios ...int64) ([]Money, error) {
+	var total int64
+	for _, ratio := range ratios {
+		if ratio < 0 || total > math.MaxInt64-ratio {
+			return nil, ErrInvalidRatios
+		}
+		total += ratio
+	}
+	if total == 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	amount := big.NewInt(m.amount)
+	bigTotal := big.NewInt(total)
+
+	parts := make([]Money, len(ratios))
+	remainder := m.amount
+	for i, ratio := range ratios {
+		share := new(big.Int).Mul(amount, big.NewInt(ratio))
+		share.Quo(share, bigTotal)
+
+		parts[i] = New(share.Int64(), m.Currency())
+		remainder -= share.Int64()
+	}
+
+	step := int64(1)
+	if remainder < 0 {
+		step = -1
+	}
+	for i := 0; remainder != 0; i = (i + 1) % len(parts) {
+		if ratios[i] == 0 {
+			continue
+		}
+		parts[i].amount += step
+		remainder -= step
+	}
+
+	return parts, nil
+}
+
+// Split divide o valor em n partes iguais, distribuindo os centavos que sobram entre as primeiras.
+func (m Money) Split(n int) ([]Money, error) {
+	if n <= 0 {
+		return nil, ErrInvalidRatios
+	}
+
+	ratios := make([]int64, n)
+	for i := range ratios {
+		ratios[i] = 1
+	}
+	return m.Allocate(ratios...)
+}
+
+// roundQuotient divide numerator por denominator e arredonda o quociente para um inteiro.
+func roundQuotient(numerator, denominator *big.Int, mode RoundingMode) (int64, error) {
+	quotient, remainder := new(big.Int).QuoRem(numerator, denominator, new(big.Int))
+
+	if remainder.Sign() != 0 {
+		// Compara o dobro do resto com o divisor, em módulo, para saber se passou do meio.
+		doubled := new(big.Int).Abs(remainder)
+		doubled.Lsh(doubled, 1)
+		cmp := doubled.Cmp(new(big.Int).Abs(denominator))
+
+		roundAway := cmp > 0 || (cmp == 0 && (mode == RoundHalfUp || quotient.Bit(0) == 1))
+		if roundAway {
+			if numerator.Sign()*denominator.Sign() < 0 {
+				quotient.Sub(quotient, big.NewInt(1))
+			} else {
+				quotient.Add(quotient, big.NewInt(1))
+			}
+		}
+	}
+
+	if !quotient.IsInt64() {
+		return 0, ErrOverflow
+	}
+	return quotient.Int64(), nil
+}
@@ -0,0 +1,149 @@
+package money_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/money"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func centavos(values ...int64) []money.Money {
+	parts := make([]money.Money, len(values))
+	for i, value := range values {
+		parts[i] = money.FromCentavos(value)
+	}
+	return parts
+}
+
+func TestMoney_AddAndSub(t *testing.T) {
+	t.Run("Should add and subtract centavos", func(t *testing.T) {
+		sum, err := money.FromCentavos(1050).Add(money.FromCentavos(250))
+		require.NoError(t, err)
+		assert.Equal(t, int64(1300), sum.Centavos())
+
+		difference, err := money.FromCentavos(1050).Sub(money.FromCentavos(2000))
+		require.NoError(t, err)
+		assert.Equal(t, int64(-950), difference.Centavos())
+	})
+
+	t.Run("Should detect overflow", func(t *testing.T) {
+		_, err := money.FromCentavos(math.MaxInt64).Add(money.FromCentavos(1))
+		assert.ErrorIs(t, err, money.ErrOverflow)
+
+		_, err = money.FromCentavos(math.MinInt64).Add(money.FromCentavos(-1))
+		assert.ErrorIs(t, err, money.ErrOverflow)
+
+		_, err = money.FromCentavos(math.MinInt64).Sub(money.FromCentavos(1))
+		assert.ErrorIs(t, err, money.ErrOverflow)
+
+		_, err = money.FromCentavos(math.MaxInt64).Sub(money.FromCentavos(-1))
+		assert.ErrorIs(t, err, money.ErrOverflow)
+
+		_, err = money.FromCentavos(math.MinInt64).Negate()
+		assert.ErrorIs(t, err, money.ErrOverflow)
+	})
+
+	t.Run("Should refuse different currencies", func(t *testing.T) {
+		_, err := money.FromCentavos(100).Add(money.New(100, "USD"))
+		assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+
+		_, err = money.FromCentavos(100).Compare(money.New(100, "USD"))
+		assert.ErrorIs(t, err, money.ErrCurrencyMismatch)
+	})
+
+	t.Run("Should treat the zero value as BRL", func(t *testing.T) {
+		var zero money.Money
+		assert.Equal(t, money.BRL, zero.Currency())
+		assert.True(t, zero.Equal(money.FromCentavos(0)))
+	})
+}
+
+func TestMoney_Allocate(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   int64
+		ratios   []int64
+		expected []money.Money
+	}{
+		{"even split", 100, []int64{1, 1}, centavos(50, 50)},
+		{"leftover centavo goes to the first part", 5, []int64{1, 1}, centavos(3, 2)},
+		{"thirds", 100, []int64{1, 1, 1}, centavos(34, 33, 33)},
+		{"weighted", 1000, []int64{70, 20, 10}, centavos(700, 200, 100)},
+		{"weighted with leftovers", 1001, []int64{70, 20, 10}, centavos(701, 200, 100)},
+		{"zero ratio receives nothing", 5, []int64{0, 1, 1}, centavos(0, 3, 2)},
+		{"negative amounts", -5, []int64{1, 1}, centavos(-3, -2)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts, err := money.FromCentavos(tt.amount).Allocate(tt.ratios...)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, parts)
+
+			var total int64
+			for _, part := range parts {
+				total += part.Centavos()
+			}
+			assert.Equal(t, tt.amount, total, "no centavo may be lost")
+		})
+	}
+
+	t.Run("Should refuse invalid ratios", func(t *testing.T) {
+		_, err := money.FromCentavos(100).Allocate()
+		assert.ErrorIs(t, err, money.ErrInvalidRatios)
+
+		_, err = money.FromCentavos(100).Allocate(0, 0)
+		assert.ErrorIs(t, err, money.ErrInvalidRatios)
+
+		_, err = money.FromCentavos(100).Allocate(2, -1)
+		assert.ErrorIs(t, err, money.ErrInvalidRatios)
+
+		_, err = money.FromCentavos(100).Split(0)
+		assert.ErrorIs(t, err, money.ErrInvalidRatios)
+	})
+
+	t.Run("Should not overflow with large amounts", func(t *testing.T) {
+		parts, err := money.FromCentavos(math.MaxInt64).Split(3)
+
+		require.NoError(t, err)
+		assert.Equal(t, int64(math.MaxInt64/3+1), parts[0].Centavos())
+	})
+}
+
+func TestMoney_MulRat(t *testing.T) {
+	tests := []struct {
+		name        string
+		amount      int64
+		numerator   int64
+		denominator int64
+		mode        money.RoundingMode
+		expected    int64
+	}{
+		{"half up rounds the tie away from zero", 25, 1, 2, money.RoundHalfUp, 13},
+		{"half even rounds the tie to the even centavo", 25, 1, 2, money.RoundHalfEven, 12},
+		{"half even rounds up to the even centavo", 27, 1, 2, money.RoundHalfEven, 14},
+		{"half up on negative amounts", -25, 1, 2, money.RoundHalfUp, -13},
+		{"half even on negative amounts", -25, 1, 2, money.RoundHalfEven, -12},
+		{"above half rounds up in both modes", 1001, 15, 1000, money.RoundHalfEven, 15},
+		{"below half rounds down in both modes", 1000, 1, 3, money.RoundHalfUp, 333},
+		{"1.5% fee", 123456, 15, 1000, money.RoundHalfUp, 1852},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := money.FromCentavos(tt.amount).MulRat(tt.numerator, tt.denominator, tt.mode)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, result.Centavos())
+		})
+	}
+
+	_, err := money.FromCentavos(100).MulRat(1, 0, money.RoundHalfUp)
+	assert.ErrorIs(t, err, money.ErrDivisionByZero)
+
+	_, err = money.FromCentavos(math.MaxInt64).MulRat(2, 1, money.RoundHalfUp)
+	assert.ErrorIs(t, err, money.ErrOverflow)
+}
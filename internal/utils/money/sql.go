@@ -0,0 +1,74 @@
+package money
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"reflect"
+
+	"gorm.io/gorm/schema"
+)
+
+// NumericSerializerName é o nome do serializer para colunas NUMERIC:
+// `gorm:"type:numeric(20,2);serializer:money_numeric"`.
+const NumericSerializerName = "money_numeric"
+
+func init() {
+	schema.RegisterSerializer(NumericSerializerName, NumericSerializer{})
+}
+
+// Value grava o valor em centavos, para colunas BIGINT. Colunas NUMERIC usam NumericSerializer.
+func (m Money) Value() (driver.Value, error) {
+	return m.amount, nil
+}
+
+// Scan lê colunas BIGINT (em centavos) e NUMERIC (em reais, como string decimal). O valor lido
+// é em reais; colunas NUMERIC com frações de centavo são recusadas em vez de arredondadas.
+func (m *Money) Scan(src any) error {
+	switch v := src.(type) {
+	case int64:
+		*m = FromCentavos(v)
+		return nil
+	case []byte:
+		return m.scanDecimal(string(v))
+	case string:
+		return m.scanDecimal(v)
+	case nil:
+		*m = Money{}
+		return nil
+	default:
+		return fmt.Errorf("money: cannot scan %T, only BIGINT and NUMERIC columns are supported", src)
+	}
+}
+
+func (m *Money) scanDecimal(value string) error {
+	parsed, err := ParseDecimal(value)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// NumericSerializer grava Money em colunas NUMERIC como decimal em reais ("1234.56").
+type NumericSerializer struct{}
+
+// Scan implementa schema.SerializerInterface.
+func (NumericSerializer) Scan(ctx context.Context, field *schema.Field, dst reflect.Value, dbValue interface{}) error {
+	var value Money
+	if err := value.Scan(dbValue); err != nil {
+		return fmt.Errorf("field %s: %w", field.Name, err)
+	}
+
+	field.ReflectValueOf(ctx, dst).Set(reflect.ValueOf(value))
+	return nil
+}
+
+// Value implementa schema.SerializerValuerInterface.
+func (NumericSerializer) Value(_ context.Context, field *schema.Field, _ reflect.Value, fieldValue interface{}) (interface{}, error) {
+	value, ok := fieldValue.(Money)
+	if !ok {
+		return nil, fmt.Errorf("numeric money field %s must be a money.Money, got %T", field.Name, fieldValue)
+	}
+	return value.Decimal(), nil
+}
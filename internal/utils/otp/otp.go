@@ -0,0 +1,88 @@
+// Package otp implementa os códigos numéricos de uso único enviados por SMS ou e-mail para
+// confirmar a posse de um celular ou endereço.
+package otp
+
+import (
+	"crypto/subtle"
+	"errors"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/crypto"
+)
+
+var (
+	ErrNotIssued        = errors.New("otp: no code issued")
+	ErrAttemptsExceeded = errors.New("otp: maximum number of attempts reached")
+	ErrExpired          = errors.New("otp: code expired")
+	// ErrMismatch indica um código errado. A tentativa já foi contada no Challenge, que precisa
+	// ser gravado mesmo assim; caso contrário o limite de tentativas nunca é atingido.
+	ErrMismatch = errors.New("otp: code does not match")
+)
+
+// Policy define o tamanho, a validade e o número de tentativas de um tipo de código.
+type Policy struct {
+	Digits      int
+	TTL         time.Duration
+	MaxAttempts int
+}
+
+// Challenge aponta para os campos do modelo que guardam o código pendente. Só o hash do código
+// é gravado.
+type Challenge struct {
+	Hash      *string
+	ExpiresAt **time.Time
+	Attempts  *int
+}
+
+// Generate sorteia um novo código com a quantidade de dígitos da política.
+func (p Policy) Generate() (string, error) {
+	return crypto.GenerateNumericCode(p.Digits)
+}
+
+// Issue grava o hash do código no desafio, com a validade da política e as tentativas zeradas,
+// invalidando o código anterior.
+func (p Policy) Issue(challenge Challenge, subject, code string, now time.Time) {
+	expiresAt := now.Add(p.TTL)
+	*challenge.Hash = Hash(subject, code)
+	*challenge.ExpiresAt = &expiresAt
+	*challenge.Attempts = 0
+}
+
+// Verify confere o código. As tentativas são verificadas antes do prazo, e um código errado
+// consome uma tentativa. Se o código confere, o desafio é apagado para não ser usado de novo.
+func (p Policy) Verify(challenge Challenge, subject, code string, now time.Time) error {
+	if *challenge.Hash == "" || *challenge.ExpiresAt == nil {
+		return ErrNotIssued
+	}
+	if *challenge.Attempts >= p.MaxAttempts {
+		return ErrAttemptsExceeded
+	}
+	if now.After(**challenge.ExpiresAt) {
+		return ErrExpired
+	}
+
+	if len(code) != p.Digits || subtle.ConstantTimeCompare([]byte(Hash(subject, code)), []byte(*challenge.Hash)) != 1 {
+		*challenge.Attempts++
+		return ErrMismatch
+	}
+
+	Clear(challenge)
+	return nil
+}
+
+// IsSpent indica se o desafio não aceita mais tentativas, por ter expirado ou esgotado o limite.
+func (p Policy) IsSpent(challenge Challenge, now time.Time) bool {
+	return *challenge.ExpiresAt == nil || now.After(**challenge.ExpiresAt) || *challenge.Attempts >= p.MaxAttempts
+}
+
+// Clear apaga o código pendente.
+func Clear(challenge Challenge) {
+	*challenge.Hash = ""
+	*challenge.ExpiresAt = nil
+}
+
+// Hash vincula o código ao que ele confirma (subject), de modo que o mesmo código gerado para
+// outro registro produza um hash diferente.
+func Hash(subject, code string) string {
+	return crypto.HashTokenSHA256(subject + ":" + code)
+}
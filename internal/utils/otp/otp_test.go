@@ -0,0 +1,84 @@
+package otp_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/otp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var policy = otp.Policy{Digits: 6, TTL: 5 * time.Minute, MaxAttempts: 3}
+
+type fields struct {
+	hash      string
+	expiresAt *time.Time
+	attempts  int
+}
+
+func (f *fields) challenge() otp.Challenge {
+	return otp.Challenge{Hash: &f.hash, ExpiresAt: &f.expiresAt, Attempts: &f.attempts}
+}
+
+func TestPolicy_Generate(t *testing.T) {
+	code, err := policy.Generate()
+
+	require.NoError(t, err)
+	assert.Regexp(t, `^\d{6}$`, code)
+}
+
+func TestPolicy_Verify(t *testing.T) {
+	now := time.Date(2026, 10, 18, 10, 0, 0, 0, time.UTC)
+
+	t.Run("Should accept the code and clear the challenge", func(t *testing.T) {
+		f := &fields{}
+		policy.Issue(f.challenge(), "subject", "123456", now)
+
+		assert.Equal(t, otp.Hash("subject", "123456"), f.hash)
+		assert.Equal(t, now.Add(policy.TTL), *f.expiresAt)
+
+		require.NoError(t, policy.Verify(f.challenge(), "subject", "123456", now))
+		assert.Empty(t, f.hash)
+		assert.Nil(t, f.expiresAt)
+	})
+
+	t.Run("Should count a wrong code as an attempt", func(t *testing.T) {
+		f := &fields{}
+		policy.Issue(f.challenge(), "subject", "123456", now)
+
+		assert.ErrorIs(t, policy.Verify(f.challenge(), "subject", "654321", now), otp.ErrMismatch)
+		assert.ErrorIs(t, policy.Verify(f.challenge(), "other-subject", "123456", now), otp.ErrMismatch)
+		assert.ErrorIs(t, policy.Verify(f.challenge(), "subject", "12345", now), otp.ErrMismatch)
+		assert.Equal(t, 3, f.attempts)
+
+		assert.ErrorIs(t, policy.Verify(f.challenge(), "subject", "123456", now), otp.ErrAttemptsExceeded)
+		assert.True(t, policy.IsSpent(f.challenge(), now))
+	})
+
+	t.Run("Should reject an expired code without counting an attempt", func(t *testing.T) {
+		f := &fields{}
+		policy.Issue(f.challenge(), "subject", "123456", now)
+		later := now.Add(policy.TTL + time.Second)
+
+		assert.ErrorIs(t, policy.Verify(f.challenge(), "subject", "123456", later), otp.ErrExpired)
+		assert.Zero(t, f.attempts)
+		assert.True(t, policy.IsSpent(f.challenge(), later))
+		assert.False(t, policy.IsSpent(f.challenge(), now))
+	})
+
+	t.Run("Should require an issued code", func(t *testing.T) {
+		f := &fields{}
+
+		assert.ErrorIs(t, policy.Verify(f.challenge(), "subject", "123456", now), otp.ErrNotIssued)
+		assert.True(t, policy.IsSpent(f.challenge(), now))
+	})
+
+	t.Run("Should reset the attempts when a new code is issued", func(t *testing.T) {
+		f := &fields{attempts: 3}
+		policy.Issue(f.challenge(), "subject", "123456", now)
+
+		assert.Zero(t, f.attempts)
+		assert.False(t, policy.IsSpent(f.challenge(), now))
+	})
+}
@@ -0,0 +1,27 @@
+package validators
+
+import "time"
+
+// MinimumAccountHolderAge é a idade mínima para abrir uma conta sem um responsável legal.
+const MinimumAccountHolderAge = 18
+
+// maximumPlausibleAge descarta datas digitadas com o ano errado, como 1099 no lugar de 1999.
+const maximumPlausibleAge = 130
+
+// AgeAt calcula a idade completa, em anos, de quem nasceu em birthDate na data de now.
+// Quem nasceu em 29 de fevereiro faz aniversário em 1º de março nos anos não bissextos.
+func AgeAt(birthDate, now time.Time) int {
+	age := now.Year() - birthDate.Year()
+	if now.Month() < birthDate.Month() || (now.Month() == birthDate.Month() && now.Day() < birthDate.Day()) {
+		age--
+	}
+	return age
+}
+
+// IsPlausibleBirthDate verifica se a data não está no futuro nem é antiga demais para uma pessoa viva.
+func IsPlausibleBirthDate(birthDate, now time.Time) bool {
+	if birthDate.After(now) {
+		return false
+	}
+	return AgeAt(birthDate, now) <= maximumPlausibleAge
+}
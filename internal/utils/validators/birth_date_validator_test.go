@@ -0,0 +1,43 @@
+package validators_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/validators"
+	"github.com/stretchr/testify/assert"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestAgeAt(t *testing.T) {
+	tests := []struct {
+		name      string
+		birthDate time.Time
+		now       time.Time
+		expected  int
+	}{
+		{"Birthday today", date(2008, time.May, 10), date(2026, time.May, 10), 18},
+		{"Day before birthday", date(2008, time.May, 10), date(2026, time.May, 9), 17},
+		{"Month before birthday", date(2008, time.May, 10), date(2026, time.April, 30), 17},
+		{"After birthday", date(2008, time.May, 10), date(2026, time.December, 1), 18},
+		{"Leap day on a common year", date(2008, time.February, 29), date(2026, time.February, 28), 17},
+		{"Leap day on March 1st", date(2008, time.February, 29), date(2026, time.March, 1), 18},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validators.AgeAt(tt.birthDate, tt.now))
+		})
+	}
+}
+
+func TestIsPlausibleBirthDate(t *testing.T) {
+	now := date(2026, time.October, 18)
+
+	assert.True(t, validators.IsPlausibleBirthDate(date(1990, time.January, 1), now))
+	assert.False(t, validators.IsPlausibleBirthDate(date(2027, time.January, 1), now))
+	assert.False(t, validators.IsPlausibleBirthDate(date(1099, time.January, 1), now))
+}
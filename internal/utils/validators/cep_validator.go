@@ -0,0 +1,74 @@
+package validators
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var cepRegex = regexp.MustCompile(`^\d{8}$`)
+
+type cepRange struct {
+	first, last int
+	uf          string
+}
+
+// cepRanges são as faixas de CEP atribuídas pelos Correios a cada unidade da federação.
+var cepRanges = []cepRange{
+	{1000000, 19999999, "SP"},
+	{20000000, 28999999, "RJ"},
+	{29000000, 29999999, "ES"},
+	{30000000, 39999999, "MG"},
+	{40000000, 48999999, "BA"},
+	{49000000, 49999999, "SE"},
+	{50000000, 56999999, "PE"},
+	{57000000, 57999999, "AL"},
+	{58000000, 58999999, "PB"},
+	{59000000, 59999999, "RN"},
+	{60000000, 63999999, "CE"},
+	{64000000, 64999999, "PI"},
+	{65000000, 65999999, "MA"},
+	{66000000, 68899999, "PA"},
+	{68900000, 68999999, "AP"},
+	{69000000, 69299999, "AM"},
+	{69300000, 69399999, "RR"},
+	{69400000, 69899999, "AM"},
+	{69900000, 69999999, "AC"},
+	{70000000, 72799999, "DF"},
+	{72800000, 72999999, "GO"},
+	{73000000, 73699999, "DF"},
+	{73700000, 76799999, "GO"},
+	{76800000, 76999999, "RO"},
+	{77000000, 77999999, "TO"},
+	{78000000, 78899999, "MT"},
+	{79000000, 79999999, "MS"},
+	{80000000, 87999999, "PR"},
+	{88000000, 89999999, "SC"},
+	{90000000, 99999999, "RS"},
+}
+
+// NormalizeCEP remove a pontuação de um CEP, aceitando tanto "01310-100" quanto "01310100".
+func NormalizeCEP(cep string) string {
+	return strings.NewReplacer("-", "", ".", "", " ", "").Replace(cep)
+}
+
+// UFForCEP devolve a unidade da federação a que o CEP pertence, ou "" se o CEP for inválido
+// ou estiver fora das faixas dos Correios.
+func UFForCEP(cep string) string {
+	if !cepRegex.MatchString(cep) {
+		return ""
+	}
+
+	value, _ := strconv.Atoi(cep)
+	for _, r := range cepRanges {
+		if value >= r.first && value <= r.last {
+			return r.uf
+		}
+	}
+	return ""
+}
+
+// IsValidCEP verifica se o CEP tem oito dígitos e pertence a alguma unidade da federação.
+func IsValidCEP(cep string) bool {
+	return UFForCEP(cep) != ""
+}
@@ -0,0 +1,40 @@
+package validators_test
+
+import (
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/validators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUFForCEP(t *testing.T) {
+	tests := []struct {
+		name     string
+		cep      string
+		expected string
+	}{
+		{"São Paulo", "01310100", "SP"},
+		{"Rio de Janeiro", "20040020", "RJ"},
+		{"Brasília", "70040010", "DF"},
+		{"Entorno do DF em Goiás", "72800000", "GO"},
+		{"Roraima inside Amazonas range", "69301000", "RR"},
+		{"Porto Alegre", "90010000", "RS"},
+		{"Below the first range", "00999999", ""},
+		{"Formatted CEP", "01310-100", ""},
+		{"Too short", "0131010", ""},
+		{"Letters", "0131010A", ""},
+		{"Empty string", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, validators.UFForCEP(tt.cep), "CEP: %s", tt.cep)
+			assert.Equal(t, tt.expected != "", validators.IsValidCEP(tt.cep))
+		})
+	}
+}
+
+func TestNormalizeCEP(t *testing.T) {
+	assert.Equal(t, "01310100", validators.NormalizeCEP("01310-100"))
+	assert.Equal(t, "01310100", validators.NormalizeCEP(" 01.310-100 "))
+}
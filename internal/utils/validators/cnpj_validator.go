@@ -0,0 +1,65 @@
+package validators
+
+import (
+	"regexp"
+	"strings"
+)
+
+var (
+	cnpjFormattingRegex = regexp.MustCompile(`[./\-\s]+`)
+	cnpjRegex           = regexp.MustCompile(`^[0-9A-Z]{12}[0-9]{2}$`)
+)
+
+var (
+	cnpjFirstDigitWeights  = []int{5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+	cnpjSecondDigitWeights = []int{6, 5, 4, 3, 2, 9, 8, 7, 6, 5, 4, 3, 2}
+)
+
+// NormalizeCNPJ remove a pontuação do CNPJ e converte as letras para maiúsculas
+// (ex: "12.ABC.345/01DE-35" vira "12ABC34501DE35"). Não valida o resultado.
+func NormalizeCNPJ(cnpj string) string {
+	return strings.ToUpper(cnpjFormattingRegex.ReplaceAllString(cnpj, ""))
+}
+
+// IsValidCNPJ verifica se um CNPJ é válido de acordo com o algoritmo da Receita Federal.
+// Aceita tanto o formato numérico quanto o alfanumérico, em que as 12 primeiras posições
+// podem conter letras e apenas os dois dígitos verificadores são obrigatoriamente numéricos.
+// A função lida com CNPJs formatados (ex: "12.345.678/0001-95") ou sem pontuação.
+func IsValidCNPJ(cnpj string) bool {
+	cnpj = NormalizeCNPJ(cnpj)
+
+	if !cnpjRegex.MatchString(cnpj) {
+		return false
+	}
+
+	// CNPJs com todos os caracteres iguais passam no cálculo, mas não são válidos.
+	if strings.Count(cnpj, cnpj[:1]) == len(cnpj) {
+		return false
+	}
+
+	// No cálculo, cada caractere vale o seu código ASCII menos 48: "0"-"9" valem 0-9 e "A"-"Z" valem 17-42.
+	values := make([]int, len(cnpj))
+	for i := range cnpj {
+		values[i] = int(cnpj[i]) - '0'
+	}
+
+	if values[12] != cnpjCheckDigit(values[:12], cnpjFirstDigitWeights) {
+		return false
+	}
+
+	return values[13] == cnpjCheckDigit(values[:13], cnpjSecondDigitWeights)
+}
+
+// cnpjCheckDigit calcula um dígito verificador pelo módulo 11 com os pesos informados.
+func cnpjCheckDigit(values, weights []int) int {
+	var sum int
+	for i, value := range values {
+		sum += value * weights[i]
+	}
+
+	remainder := sum % 11
+	if remainder < 2 {
+		return 0
+	}
+	return 11 - remainder
+}
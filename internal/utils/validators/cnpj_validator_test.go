@@ -0,0 +1,41 @@
+package validators_test
+
+import (
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/validators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidCNPJ(t *testing.T) {
+	tests := []struct {
+		name     string
+		cnpj     string
+		expected bool
+	}{
+		{"Valid numeric CNPJ", "11222333000181", true},
+		{"Valid formatted CNPJ", "11.222.333/0001-81", true},
+		{"Valid alphanumeric CNPJ", "12ABC34501DE35", true},
+		{"Valid formatted alphanumeric CNPJ", "12.ABC.345/01DE-35", true},
+		{"Lowercase alphanumeric CNPJ", "12.abc.345/01de-35", true},
+		{"Wrong first verifier digit", "11222333000191", false},
+		{"Wrong second verifier digit", "11222333000182", false},
+		{"Letter in verifier digits", "12ABC34501DE3A", false},
+		{"Invalid length", "1122233300018", false},
+		{"All same digits", "00.000.000/0000-00", false},
+		{"Special characters", "12ABC34501D#35", false},
+		{"Empty string", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validators.IsValidCNPJ(tt.cnpj)
+			assert.Equal(t, tt.expected, got, "CNPJ: %s", tt.cnpj)
+		})
+	}
+}
+
+func TestNormalizeCNPJ(t *testing.T) {
+	assert.Equal(t, "12ABC34501DE35", validators.NormalizeCNPJ("12.abc.345/01de-35"))
+	assert.Equal(t, "11222333000181", validators.NormalizeCNPJ("11.222.333/0001-81"))
+}
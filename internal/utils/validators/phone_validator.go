@@ -0,0 +1,30 @@
+package validators
+
+import "regexp"
+
+var brazilianMobileRegex = regexp.MustCompile(`^\+55(\d{2})9\d{8}$`)
+
+// brazilianDDDs lista os códigos de área em uso no Brasil, definidos pela Anatel.
+var brazilianDDDs = map[string]bool{
+	"11": true, "12": true, "13": true, "14": true, "15": true, "16": true, "17": true, "18": true, "19": true,
+	"21": true, "22": true, "24": true, "27": true, "28": true,
+	"31": true, "32": true, "33": true, "34": true, "35": true, "37": true, "38": true,
+	"41": true, "42": true, "43": true, "44": true, "45": true, "46": true, "47": true, "48": true, "49": true,
+	"51": true, "53": true, "54": true, "55": true,
+	"61": true, "62": true, "63": true, "64": true, "65": true, "66": true, "67": true, "68": true, "69": true,
+	"71": true, "73": true, "74": true, "75": true, "77": true, "79": true,
+	"81": true, "82": true, "83": true, "84": true, "85": true, "86": true, "87": true, "88": true, "89": true,
+	"91": true, "92": true, "93": true, "94": true, "95": true, "96": true, "97": true, "98": true, "99": true,
+}
+
+// IsValidBrazilianMobile verifica se o telefone é um celular brasileiro no formato E.164
+// (ex: "+5511912345678"): código do país 55, um DDD existente e nove dígitos começando por 9.
+// Telefones fixos não são aceitos, pois não recebem o código de verificação por SMS.
+func IsValidBrazilianMobile(phone string) bool {
+	matches := brazilianMobileRegex.FindStringSubmatch(phone)
+	if matches == nil {
+		return false
+	}
+
+	return brazilianDDDs[matches[1]]
+}
@@ -0,0 +1,35 @@
+package validators_test
+
+import (
+	"testing"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/utils/validators"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsValidBrazilianMobile(t *testing.T) {
+	tests := []struct {
+		name     string
+		phone    string
+		expected bool
+	}{
+		{"Valid São Paulo mobile", "+5511912345678", true},
+		{"Valid Amazonas mobile", "+5592987654321", true},
+		{"Missing plus sign", "5511912345678", false},
+		{"Missing country code", "+11912345678", false},
+		{"Foreign country code", "+14155552671", false},
+		{"Unassigned DDD", "+5520912345678", false},
+		{"Landline number", "+551132345678", false},
+		{"Mobile without leading nine", "+5511812345678", false},
+		{"Too many digits", "+55119123456789", false},
+		{"Formatted number", "+55 (11) 91234-5678", false},
+		{"Empty string", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := validators.IsValidBrazilianMobile(tt.phone)
+			assert.Equal(t, tt.expected, got, "Phone: %s", tt.phone)
+		})
+	}
+}
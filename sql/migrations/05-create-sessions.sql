@@ -0,0 +1,20 @@
+CREATE TABLE "user".sessions (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    public_id VARCHAR(26) NOT NULL UNIQUE,
+    user_id BIGINT NOT NULL,
+    family_id VARCHAR(26) NOT NULL,
+    refresh_token_hash VARCHAR(64) NOT NULL UNIQUE,
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    started_at TIMESTAMPTZ NOT NULL,
+    last_seen_at TIMESTAMPTZ NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    rotated_at TIMESTAMPTZ,
+    revoked_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES "user".users(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_sessions_family_id ON "user".sessions (family_id);
+CREATE INDEX idx_sessions_user_id_active ON "user".sessions (user_id) WHERE rotated_at IS NULL AND revoked_at IS NULL;
@@ -0,0 +1,2 @@
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN resend_count INT NOT NULL DEFAULT 0;
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN last_email_sent_at TIMESTAMPTZ;
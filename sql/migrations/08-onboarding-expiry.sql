@@ -0,0 +1,11 @@
+-- Solicitações EXPIRED não devem bloquear um novo onboarding para o mesmo CPF ou e-mail.
+ALTER TABLE onboarding.onboarding_requests DROP CONSTRAINT onboarding_requests_email_key;
+ALTER TABLE onboarding.onboarding_requests DROP CONSTRAINT onboarding_requests_document_number_key;
+
+CREATE UNIQUE INDEX uq_onboarding_requests_email_active
+    ON onboarding.onboarding_requests (email) WHERE status <> 'EXPIRED';
+CREATE UNIQUE INDEX uq_onboarding_requests_document_number_active
+    ON onboarding.onboarding_requests (document_number) WHERE status <> 'EXPIRED';
+
+CREATE INDEX idx_onboarding_requests_expirable
+    ON onboarding.onboarding_requests (token_expires_at) WHERE status IN ('PENDING', 'VERIFIED');
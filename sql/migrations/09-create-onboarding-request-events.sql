@@ -0,0 +1,15 @@
+ALTER TYPE onboarding.request_status ADD VALUE IF NOT EXISTS 'REJECTED';
+ALTER TYPE onboarding.request_status ADD VALUE IF NOT EXISTS 'CANCELLED';
+
+CREATE TABLE onboarding.onboarding_request_events (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    onboarding_request_id BIGINT NOT NULL,
+    from_status onboarding.request_status NOT NULL,
+    to_status onboarding.request_status NOT NULL,
+    actor VARCHAR(100) NOT NULL,
+    reason TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (onboarding_request_id) REFERENCES onboarding.onboarding_requests(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_onboarding_request_events_request_id ON onboarding.onboarding_request_events (onboarding_request_id, created_at);
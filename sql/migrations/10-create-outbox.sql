@@ -0,0 +1,20 @@
+create schema outbox;
+
+CREATE TABLE outbox.messages (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    public_id VARCHAR(26) UNIQUE NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PENDING',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    locked_until TIMESTAMPTZ,
+    last_error TEXT,
+    sent_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_outbox_messages_due ON outbox.messages (next_attempt_at) WHERE status IN ('PENDING', 'PROCESSING');
+CREATE INDEX idx_outbox_messages_failed ON outbox.messages (updated_at DESC) WHERE status = 'FAILED';
@@ -0,0 +1,9 @@
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN phone_number VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN phone_verified_at TIMESTAMPTZ;
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN phone_otp_hash VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN phone_otp_expires_at TIMESTAMPTZ;
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN phone_otp_attempts INT NOT NULL DEFAULT 0;
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN phone_otp_send_count INT NOT NULL DEFAULT 0;
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN last_otp_sent_at TIMESTAMPTZ;
+
+ALTER TABLE "user".users ADD COLUMN phone_number VARCHAR(16);
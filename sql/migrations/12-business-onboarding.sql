@@ -0,0 +1,26 @@
+-- Clientes pessoa jurídica usam o CNPJ (14 caracteres, possivelmente alfanumérico) como documento.
+ALTER TABLE onboarding.onboarding_requests ALTER COLUMN document_number SET DATA TYPE VARCHAR(14);
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN customer_type VARCHAR(10) NOT NULL DEFAULT 'INDIVIDUAL';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN trade_name VARCHAR(255) NOT NULL DEFAULT '';
+
+ALTER TABLE "user".users ALTER COLUMN document_number SET DATA TYPE VARCHAR(14);
+ALTER TABLE "user".users ADD COLUMN customer_type VARCHAR(10) NOT NULL DEFAULT 'INDIVIDUAL';
+ALTER TABLE "user".users ADD COLUMN trade_name VARCHAR(255);
+
+CREATE TABLE onboarding.legal_representatives (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    onboarding_request_id BIGINT NOT NULL,
+    user_id BIGINT NOT NULL,
+    document_number VARCHAR(11) NOT NULL,
+    full_name VARCHAR(255) NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'PENDING',
+    decided_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (onboarding_request_id) REFERENCES onboarding.onboarding_requests(id) ON DELETE CASCADE,
+    FOREIGN KEY (user_id) REFERENCES "user".users(id),
+    UNIQUE (onboarding_request_id, user_id)
+);
+
+CREATE INDEX idx_legal_representatives_user_pending
+    ON onboarding.legal_representatives (user_id) WHERE status = 'PENDING';
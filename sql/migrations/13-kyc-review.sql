@@ -0,0 +1,26 @@
+ALTER TYPE onboarding.request_status ADD VALUE IF NOT EXISTS 'UNDER_REVIEW';
+ALTER TYPE onboarding.request_status ADD VALUE IF NOT EXISTS 'APPROVED';
+
+-- Solicitações aprovadas aguardam o cliente concluir o cadastro e expiram junto com o token.
+DROP INDEX onboarding.idx_onboarding_requests_expirable;
+CREATE INDEX idx_onboarding_requests_expirable
+    ON onboarding.onboarding_requests (token_expires_at) WHERE status IN ('PENDING', 'VERIFIED', 'APPROVED');
+
+CREATE INDEX idx_onboarding_requests_under_review
+    ON onboarding.onboarding_requests (updated_at) WHERE status = 'UNDER_REVIEW';
+
+CREATE TABLE onboarding.kyc_documents (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    public_id VARCHAR(26) NOT NULL UNIQUE,
+    onboarding_request_id BIGINT NOT NULL,
+    kind VARCHAR(10) NOT NULL,
+    id_document_type VARCHAR(3),
+    blob_key VARCHAR(255) NOT NULL,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL,
+    sha256 VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (onboarding_request_id) REFERENCES onboarding.onboarding_requests(id) ON DELETE CASCADE,
+    UNIQUE (onboarding_request_id, kind)
+);
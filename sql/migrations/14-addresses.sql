@@ -0,0 +1,16 @@
+-- Endereço residencial (ou sede, para pessoa jurídica), exigido pela regulação para abrir a conta.
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN address_cep VARCHAR(8) NOT NULL DEFAULT '';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN address_street VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN address_number VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN address_complement VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN address_neighborhood VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN address_city VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN address_state VARCHAR(2) NOT NULL DEFAULT '';
+
+ALTER TABLE "user".users ADD COLUMN address_cep VARCHAR(8) NOT NULL DEFAULT '';
+ALTER TABLE "user".users ADD COLUMN address_street VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE "user".users ADD COLUMN address_number VARCHAR(20) NOT NULL DEFAULT '';
+ALTER TABLE "user".users ADD COLUMN address_complement VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE "user".users ADD COLUMN address_neighborhood VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE "user".users ADD COLUMN address_city VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE "user".users ADD COLUMN address_state VARCHAR(2) NOT NULL DEFAULT '';
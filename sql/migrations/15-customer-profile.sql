@@ -0,0 +1,9 @@
+-- Dados cadastrais de pessoa física: data de nascimento (maiores de 18 anos), nome da mãe e a
+-- autodeclaração de pessoa politicamente exposta (PEP).
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN birth_date DATE;
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN mother_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN politically_exposed BOOLEAN NOT NULL DEFAULT FALSE;
+
+ALTER TABLE "user".users ADD COLUMN birth_date DATE;
+ALTER TABLE "user".users ADD COLUMN mother_name VARCHAR(255) NOT NULL DEFAULT '';
+ALTER TABLE "user".users ADD COLUMN politically_exposed BOOLEAN NOT NULL DEFAULT FALSE;
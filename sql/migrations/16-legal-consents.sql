@@ -0,0 +1,39 @@
+create schema legal;
+
+-- Cada versão publicada dos termos de uso e da política de privacidade. A versão vigente de cada
+-- tipo é a publicada mais recentemente.
+CREATE TABLE legal.legal_documents (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    public_id VARCHAR(26) NOT NULL UNIQUE,
+    kind VARCHAR(20) NOT NULL,
+    version VARCHAR(20) NOT NULL,
+    url VARCHAR(512) NOT NULL,
+    published_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (kind, version)
+);
+
+CREATE INDEX idx_legal_documents_current ON legal.legal_documents (kind, published_at DESC);
+
+-- Registro de aceite (LGPD): quem aceitou qual versão, quando e de onde. As linhas nunca são
+-- alteradas nem apagadas junto com o usuário, pois servem de prova do consentimento.
+CREATE TABLE legal.consents (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    public_id VARCHAR(26) NOT NULL UNIQUE,
+    user_id BIGINT NOT NULL,
+    legal_document_id BIGINT NOT NULL,
+    source VARCHAR(20) NOT NULL,
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    accepted_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES "user".users(id),
+    FOREIGN KEY (legal_document_id) REFERENCES legal.legal_documents(id),
+    UNIQUE (user_id, legal_document_id)
+);
+
+CREATE INDEX idx_consents_user_id ON legal.consents (user_id, accepted_at DESC);
+
+INSERT INTO legal.legal_documents (public_id, kind, version, url) VALUES
+    ('01K7Z8Y2M4P6Q8R0S2T4V6W8X0', 'TERMS_OF_USE', '1.0', 'https://gobank.com.br/legal/termos-de-uso/1.0'),
+    ('01K7Z8Y2M4P6Q8R0S2T4V6W8X1', 'PRIVACY_POLICY', '1.0', 'https://gobank.com.br/legal/politica-de-privacidade/1.0');
@@ -0,0 +1,18 @@
+-- Pedidos de exclusão de dados (LGPD, art. 18). O usuário é desativado no pedido e seus dados
+-- pessoais são anonimizados depois do prazo de guarda; contas e consentimentos são mantidos.
+CREATE TABLE "user".deletion_requests (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    public_id VARCHAR(26) NOT NULL UNIQUE,
+    user_id BIGINT NOT NULL UNIQUE,
+    status VARCHAR(20) NOT NULL DEFAULT 'SCHEDULED',
+    ip_address VARCHAR(45) NOT NULL DEFAULT '',
+    user_agent VARCHAR(512) NOT NULL DEFAULT '',
+    requested_at TIMESTAMPTZ NOT NULL,
+    anonymize_after TIMESTAMPTZ NOT NULL,
+    completed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (user_id) REFERENCES "user".users(id)
+);
+
+CREATE INDEX idx_deletion_requests_due ON "user".deletion_requests (anonymize_after) WHERE status = 'SCHEDULED';
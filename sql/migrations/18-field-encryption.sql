@@ -0,0 +1,29 @@
+-- Nome, e-mail e CPF/CNPJ passam a ser gravados cifrados (AES-GCM, formato "enc:<keyID>:<base64>").
+-- As buscas e a unicidade usam o blind index (HMAC-SHA256 do valor normalizado). Os dados já
+-- existentes continuam em texto puro até o comando cmd/reencrypt cifrá-los e preencher os índices.
+ALTER TABLE "user".users DROP CONSTRAINT users_email_key;
+ALTER TABLE "user".users DROP CONSTRAINT users_document_number_key;
+ALTER TABLE "user".users ALTER COLUMN full_name SET DATA TYPE TEXT;
+ALTER TABLE "user".users ALTER COLUMN email SET DATA TYPE TEXT;
+ALTER TABLE "user".users ALTER COLUMN document_number SET DATA TYPE TEXT;
+ALTER TABLE "user".users ADD COLUMN email_index VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE "user".users ADD COLUMN document_number_index VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE UNIQUE INDEX uq_users_email_index ON "user".users (email_index) WHERE email_index <> '';
+CREATE UNIQUE INDEX uq_users_document_number_index ON "user".users (document_number_index) WHERE document_number_index <> '';
+
+DROP INDEX onboarding.uq_onboarding_requests_email_active;
+DROP INDEX onboarding.uq_onboarding_requests_document_number_active;
+ALTER TABLE onboarding.onboarding_requests ALTER COLUMN full_name SET DATA TYPE TEXT;
+ALTER TABLE onboarding.onboarding_requests ALTER COLUMN email SET DATA TYPE TEXT;
+ALTER TABLE onboarding.onboarding_requests ALTER COLUMN document_number SET DATA TYPE TEXT;
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN email_index VARCHAR(64) NOT NULL DEFAULT '';
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN document_number_index VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Solicitações anonimizadas ficam com o índice vazio e não bloqueiam um novo cadastro.
+CREATE UNIQUE INDEX uq_onboarding_requests_email_index_active
+    ON onboarding.onboarding_requests (email_index) WHERE status <> 'EXPIRED' AND email_index <> '';
+CREATE UNIQUE INDEX uq_onboarding_requests_document_number_index_active
+    ON onboarding.onboarding_requests (document_number_index) WHERE status <> 'EXPIRED' AND document_number_index <> '';
+CREATE INDEX idx_onboarding_requests_document_number_index
+    ON onboarding.onboarding_requests (document_number_index);
@@ -0,0 +1,65 @@
+-- Razão contábil em partidas dobradas. Valores em centavos; em cada lançamento, débitos (valores
+-- positivos) e créditos (negativos) somam zero. O saldo em ledger.accounts é um snapshot derivado
+-- das partidas, atualizado com lock otimista (coluna version) a cada lançamento.
+CREATE SCHEMA ledger;
+
+CREATE TABLE ledger.accounts (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    code VARCHAR(64) NOT NULL UNIQUE,
+    account_id BIGINT UNIQUE,
+    normal_balance VARCHAR(6) NOT NULL,
+    allow_negative BOOLEAN NOT NULL DEFAULT FALSE,
+    balance BIGINT NOT NULL DEFAULT 0,
+    version BIGINT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (account_id) REFERENCES "user".accounts(id),
+    CHECK (normal_balance IN ('DEBIT', 'CREDIT')),
+    CHECK (allow_negative OR balance >= 0)
+);
+
+CREATE TABLE ledger.journal_entries (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    public_id VARCHAR(26) NOT NULL UNIQUE,
+    kind VARCHAR(30) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE TABLE ledger.postings (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    journal_entry_id BIGINT NOT NULL,
+    ledger_account_id BIGINT NOT NULL,
+    amount BIGINT NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (journal_entry_id) REFERENCES ledger.journal_entries(id),
+    FOREIGN KEY (ledger_account_id) REFERENCES ledger.accounts(id),
+    CHECK (amount <> 0)
+);
+
+CREATE INDEX idx_postings_journal_entry_id ON ledger.postings (journal_entry_id);
+CREATE INDEX idx_postings_ledger_account_id ON ledger.postings (ledger_account_id, id);
+
+-- Segunda barreira contra lançamentos desbalanceados: verificada no commit, depois de todas as
+-- partidas do lançamento terem sido inseridas.
+CREATE FUNCTION ledger.check_journal_entry_balanced() RETURNS TRIGGER AS $$
+BEGIN
+    IF (SELECT SUM(amount) FROM ledger.postings WHERE journal_entry_id = NEW.journal_entry_id) <> 0 THEN
+        RAISE EXCEPTION 'journal entry % is unbalanced', NEW.journal_entry_id;
+    END IF;
+    RETURN NULL;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE CONSTRAINT TRIGGER trg_postings_balanced
+    AFTER INSERT ON ledger.postings
+    DEFERRABLE INITIALLY DEFERRED
+    FOR EACH ROW EXECUTE FUNCTION ledger.check_journal_entry_balanced();
+
+-- Contas do próprio banco. A conta de liquidação representa o dinheiro que entra e sai do banco
+-- (depósitos, TEDs, Pix) e pode ficar negativa.
+INSERT INTO ledger.accounts (code, normal_balance, allow_negative) VALUES ('SYSTEM:SETTLEMENT', 'DEBIT', TRUE);
+
+-- Contas correntes abertas antes do razão.
+INSERT INTO ledger.accounts (code, account_id, normal_balance)
+SELECT 'CUSTOMER:' || account_number, id, 'CREDIT' FROM "user".accounts;
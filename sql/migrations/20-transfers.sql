@@ -0,0 +1,22 @@
+-- Transferências entre contas do GoBank. O dinheiro é movido pelo lançamento no razão
+-- (journal_entry_id); esta tabela guarda o comprovante da operação.
+CREATE SCHEMA payments;
+
+CREATE TABLE payments.transfers (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    public_id VARCHAR(26) NOT NULL UNIQUE,
+    source_account_id BIGINT NOT NULL,
+    destination_account_id BIGINT NOT NULL,
+    amount BIGINT NOT NULL,
+    description VARCHAR(140) NOT NULL DEFAULT '',
+    journal_entry_id BIGINT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (source_account_id) REFERENCES "user".accounts(id),
+    FOREIGN KEY (destination_account_id) REFERENCES "user".accounts(id),
+    FOREIGN KEY (journal_entry_id) REFERENCES ledger.journal_entries(id),
+    CHECK (amount > 0),
+    CHECK (source_account_id <> destination_account_id)
+);
+
+CREATE INDEX idx_transfers_source_account_id ON payments.transfers (source_account_id, created_at);
+CREATE INDEX idx_transfers_destination_account_id ON payments.transfers (destination_account_id, created_at);
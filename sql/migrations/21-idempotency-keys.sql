@@ -0,0 +1,20 @@
+-- Chaves de idempotência (header Idempotency-Key) das requisições que alteram dados. A resposta
+-- da primeira execução é guardada e devolvida às repetições com a mesma chave por 24 horas.
+CREATE SCHEMA idempotency;
+
+CREATE TABLE idempotency.keys (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    scope VARCHAR(26) NOT NULL DEFAULT '',
+    idempotency_key VARCHAR(255) NOT NULL,
+    fingerprint VARCHAR(64) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'PROCESSING',
+    response_status INT,
+    response_content_type VARCHAR(255),
+    response_body BYTEA,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (scope, idempotency_key)
+);
+
+CREATE INDEX idx_idempotency_keys_expires_at ON idempotency.keys (expires_at);
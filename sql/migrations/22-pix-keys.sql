@@ -0,0 +1,49 @@
+-- Chaves Pix das contas do GoBank. O valor da chave é cifrado (key_value) e as buscas usam o
+-- blind index key_index. Uma chave só pode estar ativa em uma conta; cada conta não repete chaves
+-- em aberto. Chaves excluídas, transferidas ou com reivindicação cancelada ficam como histórico.
+CREATE SCHEMA pix;
+
+CREATE TABLE pix.keys (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    public_id VARCHAR(26) NOT NULL UNIQUE,
+    account_id BIGINT NOT NULL,
+    key_type VARCHAR(5) NOT NULL,
+    key_value TEXT NOT NULL,
+    key_index VARCHAR(64) NOT NULL,
+    status VARCHAR(30) NOT NULL,
+    otp_hash VARCHAR(64),
+    otp_expires_at TIMESTAMPTZ,
+    otp_attempts INT NOT NULL DEFAULT 0,
+    activated_at TIMESTAMPTZ,
+    removed_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (account_id) REFERENCES "user".accounts(id),
+    CHECK (key_type IN ('CPF', 'CNPJ', 'EMAIL', 'PHONE', 'EVP'))
+);
+
+CREATE UNIQUE INDEX uq_pix_keys_active_key ON pix.keys (key_index) WHERE status = 'ACTIVE';
+CREATE UNIQUE INDEX uq_pix_keys_open_account_key ON pix.keys (account_id, key_index)
+    WHERE status IN ('PENDING_CONFIRMATION', 'ACTIVE', 'CLAIM_PENDING');
+CREATE INDEX idx_pix_keys_account_id ON pix.keys (account_id);
+
+-- Portabilidades e reivindicações de posse registradas no DICT que envolvem uma chave do GoBank.
+CREATE TABLE pix.claims (
+    id BIGINT PRIMARY KEY GENERATED ALWAYS AS IDENTITY,
+    public_id VARCHAR(26) NOT NULL UNIQUE,
+    pix_key_id BIGINT NOT NULL,
+    dict_claim_id VARCHAR(64) NOT NULL,
+    claim_type VARCHAR(20) NOT NULL,
+    role VARCHAR(10) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    resolution_deadline TIMESTAMPTZ NOT NULL,
+    resolved_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    FOREIGN KEY (pix_key_id) REFERENCES pix.keys(id),
+    UNIQUE (dict_claim_id, role),
+    CHECK (claim_type IN ('PORTABILITY', 'OWNERSHIP')),
+    CHECK (role IN ('CLAIMER', 'DONOR'))
+);
+
+CREATE INDEX idx_pix_claims_pix_key_id ON pix.claims (pix_key_id);
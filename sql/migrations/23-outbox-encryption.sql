@@ -0,0 +1,8 @@
+-- O destinatário e o payload do outbox carregam e-mail, nome e links com tokens: passam a ser
+-- gravados cifrados, como as colunas da migração 18. O blind index do destinatário permite
+-- localizar as mensagens de um titular na anonimização. O payload é apagado depois do envio.
+ALTER TABLE outbox.messages ALTER COLUMN recipient SET DATA TYPE TEXT;
+ALTER TABLE outbox.messages ALTER COLUMN payload SET DATA TYPE TEXT USING payload::text;
+ALTER TABLE outbox.messages ADD COLUMN recipient_index VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_outbox_messages_recipient_index ON outbox.messages (recipient_index) WHERE recipient_index <> '';
@@ -0,0 +1,3 @@
+-- Cada reserva do ClaimDue grava um token novo. As atualizações do worker só valem enquanto o
+-- token for o dele: se o lease expirou e outro worker reservou a mensagem, o resultado antigo é descartado.
+ALTER TABLE outbox.messages ADD COLUMN claim_token VARCHAR(26);
@@ -0,0 +1,8 @@
+-- Nome e CPF dos representantes legais passam a ser cifrados, como os das solicitações de
+-- onboarding (migração 18). Os dados já existentes são cifrados pelo comando cmd/reencrypt.
+ALTER TABLE onboarding.legal_representatives ALTER COLUMN full_name SET DATA TYPE TEXT;
+ALTER TABLE onboarding.legal_representatives ALTER COLUMN document_number SET DATA TYPE TEXT;
+ALTER TABLE onboarding.legal_representatives ADD COLUMN document_number_index VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_legal_representatives_document_number_index
+    ON onboarding.legal_representatives (document_number_index) WHERE document_number_index <> '';
@@ -0,0 +1,7 @@
+-- O escopo das chaves de idempotência passa a guardar também o blind index da chave de
+-- administrador ou do IP de origem (rotas públicas), com um prefixo que separa os dois casos.
+ALTER TABLE idempotency.keys ALTER COLUMN scope TYPE VARCHAR(80);
+
+-- As chaves gravadas sem escopo eram compartilhadas por todas as rotas públicas e de
+-- administrador e não devem ser repetidas para outro cliente.
+DELETE FROM idempotency.keys WHERE scope = '';
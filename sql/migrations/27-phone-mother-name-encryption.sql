@@ -0,0 +1,15 @@
+-- Nome da mãe e celular passam a ser gravados cifrados, como nome, e-mail e documento (migração 18).
+-- O celular ganha um blind index para as buscas por igualdade. Os dados já existentes são cifrados
+-- pelo comando cmd/reencrypt, que também preenche os índices.
+ALTER TABLE "user".users ALTER COLUMN mother_name SET DATA TYPE TEXT;
+ALTER TABLE "user".users ALTER COLUMN phone_number SET DATA TYPE TEXT;
+ALTER TABLE "user".users ADD COLUMN phone_number_index VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_users_phone_number_index ON "user".users (phone_number_index) WHERE phone_number_index <> '';
+
+ALTER TABLE onboarding.onboarding_requests ALTER COLUMN mother_name SET DATA TYPE TEXT;
+ALTER TABLE onboarding.onboarding_requests ALTER COLUMN phone_number SET DATA TYPE TEXT;
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN phone_number_index VARCHAR(64) NOT NULL DEFAULT '';
+
+CREATE INDEX idx_onboarding_requests_phone_number_index
+    ON onboarding.onboarding_requests (phone_number_index) WHERE phone_number_index <> '';
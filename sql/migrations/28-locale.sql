@@ -0,0 +1,4 @@
+-- Idioma dos e-mails do cliente, escolhido pelo Accept-Language no início do onboarding e
+-- copiado para o usuário quando a conta é aberta. Os registros existentes ficam em pt-BR.
+ALTER TABLE onboarding.onboarding_requests ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT 'pt-BR';
+ALTER TABLE "user".users ADD COLUMN locale VARCHAR(10) NOT NULL DEFAULT 'pt-BR';
@@ -0,0 +1,20 @@
+// Package templates embeds the e-mail templates into the binary, so rendering does not
+// depend on the working directory the API is started from.
+package templates
+
+import (
+	"embed"
+	"io/fs"
+)
+
+//go:embed emails
+var files embed.FS
+
+// Emails devolve os templates de e-mail organizados em <locale>/<id>.html e <locale>/<id>.txt.
+func Emails() fs.FS {
+	emails, err := fs.Sub(files, "emails")
+	if err != nil {
+		panic(err) // só acontece se o diretório embutido for renomeado
+	}
+	return emails
+}
@@ -0,0 +1,165 @@
+// Package mocks provides mock implementations for authentication-related interfaces to be used in tests.
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/services"
+	"github.com/high-effort-low-stress/go-bank-api/internal/auth/tokens"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockAccessTokenManager struct {
+	mock.Mock
+}
+
+func (m *MockAccessTokenManager) Generate(userPublicID, sessionID string) (string, time.Time, error) {
+	args := m.Called(userPublicID, sessionID)
+	return args.String(0), args.Get(1).(time.Time), args.Error(2)
+}
+
+func (m *MockAccessTokenManager) Parse(token string) (*tokens.Claims, error) {
+	args := m.Called(token)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*tokens.Claims), args.Error(1)
+}
+
+type MockSessionRepository struct {
+	mock.Mock
+}
+
+func (m *MockSessionRepository) Create(_ context.Context, session *models.Session) error {
+	args := m.Called(session)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) FindByRefreshTokenHash(_ context.Context, tokenHash string) (*models.Session, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) Rotate(_ context.Context, current *models.Session, next *models.Session) error {
+	args := m.Called(current, next)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) ListActiveByUserID(_ context.Context, userID int64) ([]models.Session, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) FindActiveFamily(_ context.Context, familyID string, _ time.Time) (*models.Session, error) {
+	args := m.Called(familyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Session), args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeFamily(_ context.Context, familyID string) error {
+	args := m.Called(familyID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) RevokeUserFamily(_ context.Context, userID int64, familyID string) (bool, error) {
+	args := m.Called(userID, familyID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionRepository) RevokeAllByUserID(_ context.Context, userID int64) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockSessionRepository) AnonymizeByUserID(_ context.Context, userID int64) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockSessionRepository) WithTx(_ *gorm.DB) repositories.SessionRepository {
+	return m
+}
+
+type MockSessionService struct {
+	mock.Mock
+}
+
+func (m *MockSessionService) Start(_ context.Context, user *user_models.User, client services.ClientInfo) (*services.TokenPair, error) {
+	args := m.Called(user, client)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TokenPair), args.Error(1)
+}
+
+func (m *MockSessionService) Refresh(_ context.Context, refreshToken string, client services.ClientInfo) (*services.TokenPair, error) {
+	args := m.Called(refreshToken, client)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.TokenPair), args.Error(1)
+}
+
+func (m *MockSessionService) ListActive(_ context.Context, userPublicID string) ([]models.Session, error) {
+	args := m.Called(userPublicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Session), args.Error(1)
+}
+
+func (m *MockSessionService) IsActive(_ context.Context, sessionID string) (bool, error) {
+	args := m.Called(sessionID)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockSessionService) Revoke(_ context.Context, userPublicID, sessionID string) error {
+	args := m.Called(userPublicID, sessionID)
+	return args.Error(0)
+}
+
+func (m *MockSessionService) RevokeAll(_ context.Context, userPublicID string) error {
+	args := m.Called(userPublicID)
+	return args.Error(0)
+}
+
+type MockPasswordResetRepository struct {
+	mock.Mock
+}
+
+func (m *MockPasswordResetRepository) Create(_ context.Context, token *models.PasswordResetToken) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockPasswordResetRepository) FindByTokenHash(_ context.Context, tokenHash string) (*models.PasswordResetToken, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PasswordResetToken), args.Error(1)
+}
+
+func (m *MockPasswordResetRepository) ResetPassword(_ context.Context, token *models.PasswordResetToken, passwordHash string) error {
+	args := m.Called(token, passwordHash)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockPasswordResetRepository) WithTx(_ *gorm.DB) repositories.PasswordResetRepository {
+	return m
+}
@@ -0,0 +1,107 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/consents/services"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockLegalDocumentRepository struct {
+	mock.Mock
+}
+
+func (m *MockLegalDocumentRepository) Create(_ context.Context, document *models.LegalDocument) error {
+	args := m.Called(document)
+	return args.Error(0)
+}
+
+func (m *MockLegalDocumentRepository) FindByKindAndVersion(_ context.Context, kind models.LegalDocumentKind, version string) (*models.LegalDocument, error) {
+	args := m.Called(kind, version)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LegalDocument), args.Error(1)
+}
+
+func (m *MockLegalDocumentRepository) ListCurrent(_ context.Context, _ time.Time) ([]models.LegalDocument, error) {
+	args := m.Called()
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LegalDocument), args.Error(1)
+}
+
+func (m *MockLegalDocumentRepository) ListPendingByUserID(_ context.Context, userID int64, _ time.Time) ([]models.LegalDocument, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LegalDocument), args.Error(1)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockLegalDocumentRepository) WithTx(_ *gorm.DB) repositories.LegalDocumentRepository {
+	return m
+}
+
+type MockConsentRepository struct {
+	mock.Mock
+}
+
+func (m *MockConsentRepository) Create(_ context.Context, consents []*models.Consent) error {
+	args := m.Called(consents)
+	return args.Error(0)
+}
+
+func (m *MockConsentRepository) ListByUserID(_ context.Context, userID int64) ([]models.Consent, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Consent), args.Error(1)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockConsentRepository) WithTx(_ *gorm.DB) repositories.ConsentRepository {
+	return m
+}
+
+type MockConsentService struct {
+	mock.Mock
+}
+
+func (m *MockConsentService) AcceptAtOnboarding(_ context.Context, userID int64, termsVersion string, client services.ClientInfo) error {
+	args := m.Called(userID, termsVersion, client)
+	return args.Error(0)
+}
+
+func (m *MockConsentService) Accept(_ context.Context, userPublicID string, documentIDs []string, client services.ClientInfo) error {
+	args := m.Called(userPublicID, documentIDs, client)
+	return args.Error(0)
+}
+
+func (m *MockConsentService) ListPending(_ context.Context, userPublicID string) ([]models.LegalDocument, error) {
+	args := m.Called(userPublicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LegalDocument), args.Error(1)
+}
+
+func (m *MockConsentService) ListHistory(_ context.Context, userPublicID string) ([]models.Consent, error) {
+	args := m.Called(userPublicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Consent), args.Error(1)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockConsentService) WithTx(_ *gorm.DB) services.ConsentService {
+	return m
+}
@@ -0,0 +1,18 @@
+package mocks
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// MockTransactor executa a unidade de trabalho diretamente, sem banco de dados,
+// e conta quantas transações foram abertas.
+type MockTransactor struct {
+	Calls int
+}
+
+func (m *MockTransactor) WithinTransaction(_ context.Context, fn func(tx *gorm.DB) error) error {
+	m.Calls++
+	return fn(nil)
+}
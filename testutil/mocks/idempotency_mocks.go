@@ -0,0 +1,53 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/idempotency/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/idempotency/repositories"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockIdempotencyKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockIdempotencyKeyRepository) Reserve(_ context.Context, key *models.IdempotencyKey, now time.Time) (bool, error) {
+	args := m.Called(key, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockIdempotencyKeyRepository) Find(_ context.Context, scope, key string) (*models.IdempotencyKey, error) {
+	args := m.Called(scope, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.IdempotencyKey), args.Error(1)
+}
+
+func (m *MockIdempotencyKeyRepository) Complete(_ context.Context, key *models.IdempotencyKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) Release(_ context.Context, key *models.IdempotencyKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockIdempotencyKeyRepository) DeleteExpired(_ context.Context, now time.Time, batchSize int) (int64, error) {
+	args := m.Called(now, batchSize)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockIdempotencyKeyRepository) DeleteByScope(_ context.Context, scope string) error {
+	args := m.Called(scope)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockIdempotencyKeyRepository) WithTx(_ *gorm.DB) repositories.IdempotencyKeyRepository {
+	return m
+}
@@ -0,0 +1,113 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/ledger/services"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockLedgerAccountRepository struct {
+	mock.Mock
+}
+
+func (m *MockLedgerAccountRepository) Create(_ context.Context, account *models.LedgerAccount) error {
+	args := m.Called(account)
+	return args.Error(0)
+}
+
+func (m *MockLedgerAccountRepository) FindByAccountID(_ context.Context, accountID int64) (*models.LedgerAccount, error) {
+	args := m.Called(accountID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LedgerAccount), args.Error(1)
+}
+
+func (m *MockLedgerAccountRepository) FindByCode(_ context.Context, code string) (*models.LedgerAccount, error) {
+	args := m.Called(code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LedgerAccount), args.Error(1)
+}
+
+func (m *MockLedgerAccountRepository) ListByIDs(_ context.Context, ids []int64) ([]models.LedgerAccount, error) {
+	args := m.Called(ids)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LedgerAccount), args.Error(1)
+}
+
+func (m *MockLedgerAccountRepository) LockByAccountIDs(_ context.Context, accountIDs []int64) ([]models.LedgerAccount, error) {
+	args := m.Called(accountIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LedgerAccount), args.Error(1)
+}
+
+func (m *MockLedgerAccountRepository) UpdateBalance(_ context.Context, account *models.LedgerAccount, balance int64) error {
+	args := m.Called(account.ID, balance)
+	return args.Error(0)
+}
+
+func (m *MockLedgerAccountRepository) SumPostings(_ context.Context, ledgerAccountID int64) (int64, error) {
+	args := m.Called(ledgerAccountID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockLedgerAccountRepository) WithTx(_ *gorm.DB) repositories.LedgerAccountRepository {
+	return m
+}
+
+type MockJournalEntryRepository struct {
+	mock.Mock
+}
+
+func (m *MockJournalEntryRepository) Create(_ context.Context, entry *models.JournalEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockJournalEntryRepository) FindByPublicID(_ context.Context, publicID string) (*models.JournalEntry, error) {
+	args := m.Called(publicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.JournalEntry), args.Error(1)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockJournalEntryRepository) WithTx(_ *gorm.DB) repositories.JournalEntryRepository {
+	return m
+}
+
+type MockLedgerService struct {
+	mock.Mock
+}
+
+func (m *MockLedgerService) Post(_ context.Context, entry *models.JournalEntry) error {
+	args := m.Called(entry)
+	return args.Error(0)
+}
+
+func (m *MockLedgerService) Balance(_ context.Context, accountID int64) (int64, error) {
+	args := m.Called(accountID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockLedgerService) Reconcile(_ context.Context, ledgerAccountID int64) error {
+	args := m.Called(ledgerAccountID)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockLedgerService) WithTx(_ *gorm.DB) services.LedgerService {
+	return m
+}
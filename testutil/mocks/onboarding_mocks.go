@@ -2,16 +2,21 @@
 package mocks
 
 import (
+	"context"
+	"time"
+
 	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
 	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/onboarding/repositories"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 type MockOnboardingRepository struct {
 	mock.Mock
 }
 
-func (m *MockOnboardingRepository) FindByDocumentOrEmail(document, email string) (*models.OnboardingRequest, error) {
+func (m *MockOnboardingRepository) FindByDocumentOrEmail(_ context.Context, document, email string) (*models.OnboardingRequest, error) {
 	args := m.Called(document, email)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -19,7 +24,23 @@ func (m *MockOnboardingRepository) FindByDocumentOrEmail(document, email string)
 	return args.Get(0).(*models.OnboardingRequest), args.Error(1)
 }
 
-func (m *MockOnboardingRepository) Create(req *models.OnboardingRequest) error {
+func (m *MockOnboardingRepository) FindByEmail(_ context.Context, email string) (*models.OnboardingRequest, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OnboardingRequest), args.Error(1)
+}
+
+func (m *MockOnboardingRepository) FindByEmailForUpdate(_ context.Context, email string) (*models.OnboardingRequest, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OnboardingRequest), args.Error(1)
+}
+
+func (m *MockOnboardingRepository) Create(_ context.Context, req *models.OnboardingRequest) error {
 	args := m.Called(req)
 	return args.Error(0)
 }
@@ -28,12 +49,21 @@ type MockEmailService struct {
 	mock.Mock
 }
 
-func (m *MockEmailService) SendEmail(req *notification.EmailRequest) error {
+func (m *MockEmailService) SendEmail(_ context.Context, req *notification.EmailRequest) error {
 	args := m.Called(req)
 	return args.Error(0)
 }
 
-func (m *MockOnboardingRepository) FindByVerificationTokenHash(tokenHash string) (*models.OnboardingRequest, error) {
+type MockSMSSender struct {
+	mock.Mock
+}
+
+func (m *MockSMSSender) SendSMS(_ context.Context, req *notification.SMSRequest) error {
+	args := m.Called(req)
+	return args.Error(0)
+}
+
+func (m *MockOnboardingRepository) FindByVerificationTokenHash(_ context.Context, tokenHash string) (*models.OnboardingRequest, error) {
 	args := m.Called(tokenHash)
 	if args.Get(0) == nil {
 		return nil, args.Error(1)
@@ -41,7 +71,169 @@ func (m *MockOnboardingRepository) FindByVerificationTokenHash(tokenHash string)
 	return args.Get(0).(*models.OnboardingRequest), args.Error(1)
 }
 
-func (m *MockOnboardingRepository) Update(req *models.OnboardingRequest) error {
+func (m *MockOnboardingRepository) Update(_ context.Context, req *models.OnboardingRequest) error {
 	args := m.Called(req)
 	return args.Error(0)
 }
+
+func (m *MockOnboardingRepository) ExpireStale(_ context.Context, now time.Time, batchSize int) (int64, error) {
+	args := m.Called(now, batchSize)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOnboardingRepository) SaveTransition(_ context.Context, req *models.OnboardingRequest, event *models.OnboardingRequestEvent) error {
+	args := m.Called(req, event)
+	return args.Error(0)
+}
+
+func (m *MockOnboardingRepository) FindByVerificationTokenHashForUpdate(_ context.Context, tokenHash string) (*models.OnboardingRequest, error) {
+	args := m.Called(tokenHash)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OnboardingRequest), args.Error(1)
+}
+
+func (m *MockOnboardingRepository) FindByPublicID(_ context.Context, publicID string) (*models.OnboardingRequest, error) {
+	args := m.Called(publicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OnboardingRequest), args.Error(1)
+}
+
+func (m *MockOnboardingRepository) ListByStatus(_ context.Context, status models.OnboardingStatus, limit, offset int) ([]models.OnboardingRequest, error) {
+	args := m.Called(status, limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OnboardingRequest), args.Error(1)
+}
+
+func (m *MockOnboardingRepository) ListByDocument(_ context.Context, document string) ([]models.OnboardingRequest, error) {
+	args := m.Called(document)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OnboardingRequest), args.Error(1)
+}
+
+func (m *MockOnboardingRepository) ListEventsByRequestIDs(_ context.Context, onboardingRequestIDs []int64) ([]models.OnboardingRequestEvent, error) {
+	args := m.Called(onboardingRequestIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OnboardingRequestEvent), args.Error(1)
+}
+
+func (m *MockOnboardingRepository) FindByPublicIDForUpdate(_ context.Context, publicID string) (*models.OnboardingRequest, error) {
+	args := m.Called(publicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.OnboardingRequest), args.Error(1)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockOnboardingRepository) WithTx(_ *gorm.DB) repositories.OnboardingRequestRepository {
+	return m
+}
+
+type MockLegalRepresentativeRepository struct {
+	mock.Mock
+}
+
+func (m *MockLegalRepresentativeRepository) Create(_ context.Context, representatives []*models.LegalRepresentative) error {
+	args := m.Called(representatives)
+	return args.Error(0)
+}
+
+func (m *MockLegalRepresentativeRepository) ListByRequest(_ context.Context, onboardingRequestID int64) ([]models.LegalRepresentative, error) {
+	args := m.Called(onboardingRequestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LegalRepresentative), args.Error(1)
+}
+
+func (m *MockLegalRepresentativeRepository) ListPendingByUser(_ context.Context, userID int64) ([]models.LegalRepresentative, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LegalRepresentative), args.Error(1)
+}
+
+func (m *MockLegalRepresentativeRepository) ListByUser(_ context.Context, userID int64) ([]models.LegalRepresentative, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.LegalRepresentative), args.Error(1)
+}
+
+func (m *MockLegalRepresentativeRepository) FindByRequestAndUserForUpdate(_ context.Context, onboardingRequestID, userID int64) (*models.LegalRepresentative, error) {
+	args := m.Called(onboardingRequestID, userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.LegalRepresentative), args.Error(1)
+}
+
+func (m *MockLegalRepresentativeRepository) Update(_ context.Context, representative *models.LegalRepresentative) error {
+	args := m.Called(representative)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockLegalRepresentativeRepository) WithTx(_ *gorm.DB) repositories.LegalRepresentativeRepository {
+	return m
+}
+
+type MockKYCDocumentRepository struct {
+	mock.Mock
+}
+
+func (m *MockKYCDocumentRepository) Create(_ context.Context, document *models.KYCDocument) error {
+	args := m.Called(document)
+	return args.Error(0)
+}
+
+func (m *MockKYCDocumentRepository) Update(_ context.Context, document *models.KYCDocument) error {
+	args := m.Called(document)
+	return args.Error(0)
+}
+
+func (m *MockKYCDocumentRepository) FindByRequestAndKind(_ context.Context, onboardingRequestID int64, kind models.KYCDocumentKind) (*models.KYCDocument, error) {
+	args := m.Called(onboardingRequestID, kind)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.KYCDocument), args.Error(1)
+}
+
+func (m *MockKYCDocumentRepository) FindByRequestAndPublicID(_ context.Context, onboardingRequestID int64, publicID string) (*models.KYCDocument, error) {
+	args := m.Called(onboardingRequestID, publicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.KYCDocument), args.Error(1)
+}
+
+func (m *MockKYCDocumentRepository) ListByRequest(_ context.Context, onboardingRequestID int64) ([]models.KYCDocument, error) {
+	args := m.Called(onboardingRequestID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.KYCDocument), args.Error(1)
+}
+
+func (m *MockKYCDocumentRepository) DeleteByRequest(_ context.Context, onboardingRequestID int64) error {
+	args := m.Called(onboardingRequestID)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockKYCDocumentRepository) WithTx(_ *gorm.DB) repositories.KYCDocumentRepository {
+	return m
+}
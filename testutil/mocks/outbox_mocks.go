@@ -0,0 +1,77 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/notification"
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/outbox/repositories"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockOutboxRepository struct {
+	mock.Mock
+}
+
+func (m *MockOutboxRepository) EnqueueEmail(_ context.Context, request *notification.EmailRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) ClaimDue(_ context.Context, now time.Time, batchSize int, lockedUntil time.Time) ([]models.OutboxMessage, error) {
+	args := m.Called(now, batchSize, lockedUntil)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OutboxMessage), args.Error(1)
+}
+
+func (m *MockOutboxRepository) ExtendLease(_ context.Context, id int64, claimToken string, lockedUntil time.Time) error {
+	args := m.Called(id, claimToken, lockedUntil)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkSent(_ context.Context, id int64, claimToken string, sentAt time.Time) error {
+	args := m.Called(id, claimToken, sentAt)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkForRetry(_ context.Context, id int64, claimToken string, lastError string, nextAttemptAt time.Time) error {
+	args := m.Called(id, claimToken, lastError, nextAttemptAt)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) MarkFailed(_ context.Context, id int64, claimToken string, lastError string) error {
+	args := m.Called(id, claimToken, lastError)
+	return args.Error(0)
+}
+
+func (m *MockOutboxRepository) ListFailed(_ context.Context, limit, offset int) ([]models.OutboxMessage, error) {
+	args := m.Called(limit, offset)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.OutboxMessage), args.Error(1)
+}
+
+func (m *MockOutboxRepository) PurgeFailedPayloads(_ context.Context, failedBefore time.Time) (int64, error) {
+	args := m.Called(failedBefore)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockOutboxRepository) Requeue(_ context.Context, publicID string, now time.Time) (bool, error) {
+	args := m.Called(publicID, now)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockOutboxRepository) DeleteByRecipient(_ context.Context, recipient string) error {
+	args := m.Called(recipient)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockOutboxRepository) WithTx(_ *gorm.DB) repositories.OutboxRepository {
+	return m
+}
@@ -0,0 +1,229 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/pix/services"
+	user_models "github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockPixKeyRepository struct {
+	mock.Mock
+}
+
+func (m *MockPixKeyRepository) Create(_ context.Context, key *models.PixKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockPixKeyRepository) Update(_ context.Context, key *models.PixKey) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockPixKeyRepository) FindByPublicIDForUpdate(_ context.Context, publicID string) (*models.PixKey, error) {
+	args := m.Called(publicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PixKey), args.Error(1)
+}
+
+func (m *MockPixKeyRepository) FindActiveByKey(_ context.Context, key string) (*models.PixKey, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PixKey), args.Error(1)
+}
+
+func (m *MockPixKeyRepository) FindOpenByAccountAndKey(_ context.Context, accountID int64, key string) (*models.PixKey, error) {
+	args := m.Called(accountID, key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PixKey), args.Error(1)
+}
+
+func (m *MockPixKeyRepository) CountOpenByAccountID(_ context.Context, accountID int64, _ time.Time) (int64, error) {
+	args := m.Called(accountID)
+	return args.Get(0).(int64), args.Error(1)
+}
+
+func (m *MockPixKeyRepository) ListByAccountIDs(_ context.Context, accountIDs []int64) ([]models.PixKey, error) {
+	args := m.Called(accountIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PixKey), args.Error(1)
+}
+
+func (m *MockPixKeyRepository) ListOpenByAccountIDsForUpdate(_ context.Context, accountIDs []int64) ([]models.PixKey, error) {
+	args := m.Called(accountIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PixKey), args.Error(1)
+}
+
+func (m *MockPixKeyRepository) AnonymizeByUserID(_ context.Context, userID int64) error {
+	args := m.Called(userID)
+	return args.Error(0)
+}
+
+func (m *MockPixKeyRepository) LockAccount(_ context.Context, accountID int64) error {
+	args := m.Called(accountID)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockPixKeyRepository) WithTx(_ *gorm.DB) repositories.PixKeyRepository {
+	return m
+}
+
+type MockPixClaimRepository struct {
+	mock.Mock
+}
+
+func (m *MockPixClaimRepository) Create(_ context.Context, claim *models.PixClaim) error {
+	args := m.Called(claim)
+	return args.Error(0)
+}
+
+func (m *MockPixClaimRepository) Update(_ context.Context, claim *models.PixClaim) error {
+	args := m.Called(claim)
+	return args.Error(0)
+}
+
+func (m *MockPixClaimRepository) FindByPublicIDForUpdate(_ context.Context, publicID string) (*models.PixClaim, error) {
+	args := m.Called(publicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PixClaim), args.Error(1)
+}
+
+func (m *MockPixClaimRepository) FindByDICTClaimIDForUpdate(_ context.Context, dictClaimID string, role models.ClaimRole) (*models.PixClaim, error) {
+	args := m.Called(dictClaimID, role)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PixClaim), args.Error(1)
+}
+
+func (m *MockPixClaimRepository) FindOpenByPixKeyID(_ context.Context, pixKeyID int64) (*models.PixClaim, error) {
+	args := m.Called(pixKeyID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PixClaim), args.Error(1)
+}
+
+func (m *MockPixClaimRepository) ListByAccountIDs(_ context.Context, accountIDs []int64) ([]models.PixClaim, error) {
+	args := m.Called(accountIDs)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PixClaim), args.Error(1)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockPixClaimRepository) WithTx(_ *gorm.DB) repositories.PixClaimRepository {
+	return m
+}
+
+type MockPixKeyService struct {
+	mock.Mock
+}
+
+func (m *MockPixKeyService) Register(_ context.Context, userPublicID string, input services.RegisterKeyInput) (*models.PixKey, error) {
+	args := m.Called(userPublicID, input)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PixKey), args.Error(1)
+}
+
+func (m *MockPixKeyService) Confirm(_ context.Context, userPublicID, keyPublicID, code string) (*models.PixKey, error) {
+	args := m.Called(userPublicID, keyPublicID, code)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PixKey), args.Error(1)
+}
+
+func (m *MockPixKeyService) Delete(_ context.Context, userPublicID, keyPublicID string) error {
+	args := m.Called(userPublicID, keyPublicID)
+	return args.Error(0)
+}
+
+func (m *MockPixKeyService) List(_ context.Context, userPublicID string) ([]models.PixKey, error) {
+	args := m.Called(userPublicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PixKey), args.Error(1)
+}
+
+func (m *MockPixKeyService) Lookup(_ context.Context, rawKey string) (*services.KeyOwner, error) {
+	args := m.Called(rawKey)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.KeyOwner), args.Error(1)
+}
+
+func (m *MockPixKeyService) RemoveAllByUser(_ context.Context, user *user_models.User) (*services.DirectoryCleanup, error) {
+	args := m.Called(user)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*services.DirectoryCleanup), args.Error(1)
+}
+
+func (m *MockPixKeyService) CleanUpDirectory(_ context.Context, cleanup *services.DirectoryCleanup) {
+	m.Called(cleanup)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockPixKeyService) WithTx(_ *gorm.DB) services.PixKeyService {
+	return m
+}
+
+type MockPixClaimService struct {
+	mock.Mock
+}
+
+func (m *MockPixClaimService) List(_ context.Context, userPublicID string) ([]models.PixClaim, error) {
+	args := m.Called(userPublicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.PixClaim), args.Error(1)
+}
+
+func (m *MockPixClaimService) Confirm(_ context.Context, userPublicID, claimPublicID string) (*models.PixClaim, error) {
+	args := m.Called(userPublicID, claimPublicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PixClaim), args.Error(1)
+}
+
+func (m *MockPixClaimService) Cancel(_ context.Context, userPublicID, claimPublicID string) (*models.PixClaim, error) {
+	args := m.Called(userPublicID, claimPublicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.PixClaim), args.Error(1)
+}
+
+func (m *MockPixClaimService) SyncClaims(_ context.Context, since time.Time) (time.Time, error) {
+	args := m.Called(since)
+	return args.Get(0).(time.Time), args.Error(1)
+}
@@ -0,0 +1,64 @@
+package mocks
+
+import (
+	"context"
+	"time"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/repositories"
+	"github.com/high-effort-low-stress/go-bank-api/internal/privacy/services"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockDeletionRequestRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeletionRequestRepository) Create(_ context.Context, request *models.DeletionRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+func (m *MockDeletionRequestRepository) FindByUserID(_ context.Context, userID int64) (*models.DeletionRequest, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeletionRequest), args.Error(1)
+}
+
+func (m *MockDeletionRequestRepository) ListDueForUpdate(_ context.Context, now time.Time, limit int) ([]models.DeletionRequest, error) {
+	args := m.Called(now, limit)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.DeletionRequest), args.Error(1)
+}
+
+func (m *MockDeletionRequestRepository) Update(_ context.Context, request *models.DeletionRequest) error {
+	args := m.Called(request)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockDeletionRequestRepository) WithTx(_ *gorm.DB) repositories.DeletionRequestRepository {
+	return m
+}
+
+type MockDeletionService struct {
+	mock.Mock
+}
+
+func (m *MockDeletionService) RequestDeletion(_ context.Context, userPublicID, password string, client services.ClientInfo) (*models.DeletionRequest, error) {
+	args := m.Called(userPublicID, password, client)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.DeletionRequest), args.Error(1)
+}
+
+func (m *MockDeletionService) AnonymizeDue(_ context.Context, now time.Time, batchSize int) (int, error) {
+	args := m.Called(now, batchSize)
+	return args.Int(0), args.Error(1)
+}
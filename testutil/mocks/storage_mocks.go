@@ -0,0 +1,34 @@
+package mocks
+
+import (
+	"context"
+	"io"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockBlobStore struct {
+	mock.Mock
+}
+
+// Put descarta o conteúdo: as expectativas são definidas apenas sobre a chave.
+func (m *MockBlobStore) Put(_ context.Context, key string, content io.Reader) error {
+	if _, err := io.Copy(io.Discard, content); err != nil {
+		return err
+	}
+	args := m.Called(key)
+	return args.Error(0)
+}
+
+func (m *MockBlobStore) Get(_ context.Context, key string) (io.ReadCloser, error) {
+	args := m.Called(key)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(io.ReadCloser), args.Error(1)
+}
+
+func (m *MockBlobStore) Delete(_ context.Context, key string) error {
+	args := m.Called(key)
+	return args.Error(0)
+}
@@ -0,0 +1,24 @@
+package mocks
+
+import (
+	"context"
+
+	"github.com/high-effort-low-stress/go-bank-api/internal/transfers/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/transfers/repositories"
+	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
+)
+
+type MockTransferRepository struct {
+	mock.Mock
+}
+
+func (m *MockTransferRepository) Create(_ context.Context, transfer *models.Transfer) error {
+	args := m.Called(transfer)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockTransferRepository) WithTx(_ *gorm.DB) repositories.TransferRepository {
+	return m
+}
@@ -1,16 +1,19 @@
 package mocks
 
 import (
+	"context"
 	"github.com/high-effort-low-stress/go-bank-api/internal/users/models"
+	"github.com/high-effort-low-stress/go-bank-api/internal/users/repositories"
 	"github.com/high-effort-low-stress/go-bank-api/internal/users/services"
 	"github.com/stretchr/testify/mock"
+	"gorm.io/gorm"
 )
 
 type MockCreateUserService struct {
 	mock.Mock
 }
 
-func (m *MockCreateUserService) Execute(req *services.CreateServiceRequest) (*models.User, *models.Account, error) {
+func (m *MockCreateUserService) Execute(_ context.Context, req *services.CreateServiceRequest) (*models.User, *models.Account, error) {
 	args := m.Called(req)
 
 	var user *models.User
@@ -25,3 +28,86 @@ func (m *MockCreateUserService) Execute(req *services.CreateServiceRequest) (*mo
 
 	return user, account, args.Error(2)
 }
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockCreateUserService) WithTx(_ *gorm.DB) services.CreateUserService {
+	return m
+}
+
+type MockUserRepository struct {
+	mock.Mock
+}
+
+func (m *MockUserRepository) CreateUserWithAccount(_ context.Context, user *models.User) (*models.User, *models.Account, error) {
+	args := m.Called(user)
+
+	var createdUser *models.User
+	if args.Get(0) != nil {
+		createdUser = args.Get(0).(*models.User)
+	}
+
+	var account *models.Account
+	if args.Get(1) != nil {
+		account = args.Get(1).(*models.Account)
+	}
+
+	return createdUser, account, args.Error(2)
+}
+
+func (m *MockUserRepository) FindByDocument(_ context.Context, document string) (*models.User, error) {
+	args := m.Called(document)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByEmail(_ context.Context, email string) (*models.User, error) {
+	args := m.Called(email)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) FindByPublicID(_ context.Context, publicID string) (*models.User, error) {
+	args := m.Called(publicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.User), args.Error(1)
+}
+
+func (m *MockUserRepository) ListAccountsByUserID(_ context.Context, userID int64) ([]models.Account, error) {
+	args := m.Called(userID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).([]models.Account), args.Error(1)
+}
+
+func (m *MockUserRepository) FindAccountByPublicID(_ context.Context, publicID string) (*models.Account, error) {
+	args := m.Called(publicID)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Account), args.Error(1)
+}
+
+func (m *MockUserRepository) FindAccountByNumber(_ context.Context, agencyNumber, accountNumber string) (*models.Account, error) {
+	args := m.Called(agencyNumber, accountNumber)
+	if args.Get(0) == nil {
+		return nil, args.Error(1)
+	}
+	return args.Get(0).(*models.Account), args.Error(1)
+}
+
+func (m *MockUserRepository) Update(_ context.Context, user *models.User) error {
+	args := m.Called(user)
+	return args.Error(0)
+}
+
+// WithTx retorna o próprio mock, para que as expectativas valham dentro e fora da transação.
+func (m *MockUserRepository) WithTx(_ *gorm.DB) repositories.UserRepository {
+	return m
+}